@@ -6,37 +6,18 @@ import (
 	"strings"
 	"time"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
-	configv1 "github.com/openshift/api/config/v1"
-	configclient "github.com/openshift/client-go/config/clientset/versioned"
-	"github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+	"github.com/openshift/origin/pkg/clusterstability"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 	exutil "github.com/openshift/origin/test/extended/util"
 )
 
-func unstableOperatorReason(operator *configv1.ClusterOperator) string {
-	notableConditions := []string{}
-	if !v1helpers.IsStatusConditionTrue(operator.Status.Conditions, configv1.OperatorAvailable) {
-		notableConditions = append(notableConditions, "unavailable")
-	}
-	if !v1helpers.IsStatusConditionFalse(operator.Status.Conditions, configv1.OperatorProgressing) {
-		notableConditions = append(notableConditions, "progressing")
-	}
-	if !v1helpers.IsStatusConditionFalse(operator.Status.Conditions, configv1.OperatorDegraded) {
-		notableConditions = append(notableConditions, "degraded")
-	}
-
-	return strings.Join(notableConditions, " and ")
-}
-
-func summerizeUnstableOperators(operators map[string]string) string {
+func summarizeCriteria(criteria []*clusterstability.CriterionResult) string {
 	msg := ""
-	for operator, reason := range operators {
-		msg += fmt.Sprintf("\noperator %s is not stable: %s", operator, reason)
+	for _, criterion := range criteria {
+		msg += fmt.Sprintf("\n%s was not met: %s", criterion.Name, strings.Join(criterion.Reasons, "; "))
 	}
 	return msg
 }
@@ -46,6 +27,15 @@ func summerizeUnstableOperators(operators map[string]string) string {
 // It will generate flake junits if some operators recovered from unstable conditions while it waits.
 // It will generate failure junit if any operators are still unstable after timeout.
 func WaitForStableCluster(ctx context.Context, config *rest.Config) ([]*junitapi.JUnitTestCase, error) {
+	_, junits, err := WaitAtStabilityGate(ctx, config, clusterstability.DefaultCriteria)
+	return junits, err
+}
+
+// WaitAtStabilityGate waits for the cluster to meet every criterion for a few minutes, and returns what the gate
+// learned while it waited, or nil when it did not wait. It will generate success junits if all criteria are met on
+// the first check, flake junits if some criteria were met while it waits, and a flake junit naming the criteria that
+// blocked the gate when it gave up.
+func WaitAtStabilityGate(ctx context.Context, config *rest.Config, criteria []clusterstability.CriterionSpec) (*clusterstability.Result, []*junitapi.JUnitTestCase, error) {
 	const testName = "Cluster should be stable after installation is complete"
 	// Create two different junit test name for easy analysis
 	const testNameUnrecovered = "Cluster should be stable before test is started"
@@ -53,7 +43,7 @@ func WaitForStableCluster(ctx context.Context, config *rest.Config) ([]*junitapi
 	// Skip microshift
 	kubeClient, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		return []*junitapi.JUnitTestCase{
+		return nil, []*junitapi.JUnitTestCase{
 			{
 				Name: testName,
 				FailureOutput: &junitapi.FailureOutput{
@@ -70,7 +60,7 @@ func WaitForStableCluster(ctx context.Context, config *rest.Config) ([]*junitapi
 	}
 	isMicroShift, err := exutil.IsMicroShiftCluster(kubeClient)
 	if err != nil {
-		return []*junitapi.JUnitTestCase{
+		return nil, []*junitapi.JUnitTestCase{
 			{
 				Name: testName,
 				FailureOutput: &junitapi.FailureOutput{
@@ -86,7 +76,7 @@ func WaitForStableCluster(ctx context.Context, config *rest.Config) ([]*junitapi
 		}, err
 	}
 	if isMicroShift {
-		return []*junitapi.JUnitTestCase{
+		return nil, []*junitapi.JUnitTestCase{
 			{
 				Name: testName,
 			},
@@ -96,82 +86,45 @@ func WaitForStableCluster(ctx context.Context, config *rest.Config) ([]*junitapi
 		}, nil
 	}
 
-	configClient, err := configclient.NewForConfig(config)
+	gateCriteria, err := clusterstability.NewCriteria(config, criteria)
 	if err != nil {
-		return []*junitapi.JUnitTestCase{
+		return nil, []*junitapi.JUnitTestCase{
 			{
 				Name: testName,
 				FailureOutput: &junitapi.FailureOutput{
-					Output: fmt.Sprintf("error creating config client: %v", err),
+					Output: fmt.Sprintf("error creating stability criteria: %v", err),
 				},
 			},
 			{
 				Name: testNameUnrecovered,
 				FailureOutput: &junitapi.FailureOutput{
-					Output: fmt.Sprintf("error creating config client: %v", err),
+					Output: fmt.Sprintf("error creating stability criteria: %v", err),
 				},
 			},
 		}, err
 	}
 
-	interval := 10 * time.Second
-	timeout := 10 * time.Minute
-	minimumStablePeriod := 3 * time.Minute
-	recoveredOperators := map[string]string{}
-	unstableOperators := map[string]string{}
-	var stabilityStarted *time.Time
-	pollingStarted := time.Now()
-	waitErr := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(waitCtx context.Context) (bool, error) {
-		operators, err := configClient.ConfigV1().ClusterOperators().List(waitCtx, metav1.ListOptions{})
-		if err != nil {
-			if waitCtx.Err() == context.DeadlineExceeded {
-				return false, fmt.Errorf("some operators were unstable for too long")
-			}
-			stabilityStarted = nil
-			return false, fmt.Errorf("failed to list clusteroperators: %v", err)
-		}
-		now := time.Now()
-		for _, operator := range operators.Items {
-			if unstableReason := unstableOperatorReason(&operator); len(unstableReason) > 0 {
-				if _, ok := unstableOperators[operator.Name]; !ok {
-					unstableOperators[operator.Name] = unstableReason
-				}
-			} else {
-				if unstableReason, ok := unstableOperators[operator.Name]; ok {
-					recoveredOperators[operator.Name] = unstableReason
-					delete(unstableOperators, operator.Name)
-				}
-			}
-		}
-		if len(unstableOperators) > 0 {
-			stabilityStarted = nil
-			return false, nil
-		}
-		if stabilityStarted == nil {
-			stabilityStarted = &now
-		}
-		timeStable := now.Sub(*stabilityStarted)
-		if timeStable < minimumStablePeriod {
-			return false, nil
-		}
-		return true, nil
-	})
-	timingMsg := ""
-	if stabilityStarted != nil {
-		timingMsg = fmt.Sprintf(", unstable duration %s", stabilityStarted.Sub(pollingStarted))
+	gate := &clusterstability.Gate{
+		Criteria:            gateCriteria,
+		Interval:            10 * time.Second,
+		MinimumStablePeriod: 3 * time.Minute,
 	}
-	if waitErr != nil {
-		msg := fmt.Sprintf("error waiting for cluster operators to become stable: %v", waitErr)
-		if len(unstableOperators) > 0 {
-			msg += fmt.Sprintf("\nunstable operators that never recovered:\n")
-			msg += summerizeUnstableOperators(unstableOperators)
+	result := gate.Wait(ctx)
+	junits := result.JUnits()
+
+	timingMsg := fmt.Sprintf(", waited %s", result.To.Sub(result.From).Round(time.Second))
+	if result.Err != nil {
+		msg := fmt.Sprintf("error waiting for the cluster to become stable: %v", result.Err)
+		if blocked := result.Blocked(); len(blocked) > 0 {
+			msg += "\ncriteria that blocked the cluster from becoming stable:\n"
+			msg += summarizeCriteria(blocked)
 		}
-		if len(recoveredOperators) > 0 {
-			msg += fmt.Sprintf("\nunstable operators that recovered%s:\n", timingMsg)
-			msg += summerizeUnstableOperators(recoveredOperators)
+		if recovered := result.Recovered(); len(recovered) > 0 {
+			msg += fmt.Sprintf("\ncriteria that were met while waiting%s:\n", timingMsg)
+			msg += summarizeCriteria(recovered)
 		}
 		// Flake for now.
-		return []*junitapi.JUnitTestCase{
+		return result, append([]*junitapi.JUnitTestCase{
 			{
 				Name: testName,
 			},
@@ -190,21 +143,22 @@ func WaitForStableCluster(ctx context.Context, config *rest.Config) ([]*junitapi
 					Output: msg,
 				},
 			},
-		}, waitErr
+		}, junits...), result.Err
 	}
-	if len(recoveredOperators) == 0 {
-		return []*junitapi.JUnitTestCase{
+	recovered := result.Recovered()
+	if len(recovered) == 0 {
+		return result, append([]*junitapi.JUnitTestCase{
 			{
 				Name: testName,
 			},
 			{
 				Name: testNameUnrecovered,
 			},
-		}, nil
+		}, junits...), nil
 	}
-	// Some operators recovered from unstable conditions before timeout
-	msg := fmt.Sprintf("some operators were unstable but recovered%s\n%s", timingMsg, summerizeUnstableOperators(recoveredOperators))
-	return []*junitapi.JUnitTestCase{
+	// Some criteria were not met at first, but were met before they timed out
+	msg := fmt.Sprintf("some criteria were not met at first but were met before timing out%s\n%s", timingMsg, summarizeCriteria(recovered))
+	return result, append([]*junitapi.JUnitTestCase{
 		{
 			Name: testName,
 		},
@@ -217,5 +171,5 @@ func WaitForStableCluster(ctx context.Context, config *rest.Config) ([]*junitapi
 		{
 			Name: testNameUnrecovered,
 		},
-	}, nil
+	}, junits...), nil
 }
@@ -3,6 +3,7 @@ package upgradeoptions
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/openshift/origin/test/e2e/upgrade"
@@ -13,6 +14,44 @@ type UpgradeOptions struct {
 	Suite       string
 	ToImage     string
 	TestOptions []string
+	// Hops are the upgrades to make in order, one for each image in ToImage. They are only set when a hop has
+	// options of its own.
+	Hops []UpgradeHop `json:",omitempty"`
+}
+
+// UpgradeHop is one upgrade of a multi-hop upgrade.
+type UpgradeHop struct {
+	ToImage string
+	// Options are KEY=VALUE options that only apply to this hop.
+	Options []string `json:",omitempty"`
+}
+
+// NewUpgradeHops splits a comma separated list of images into hops and assigns each of the hop options to its hop.
+// Hop options have the form HOP:KEY=VALUE, where HOP is the position of the image in the list starting at 1.
+func NewUpgradeHops(toImage string, hopOptions []string) ([]UpgradeHop, error) {
+	if len(hopOptions) == 0 {
+		return nil, nil
+	}
+
+	var hops []UpgradeHop
+	for _, image := range strings.Split(toImage, ",") {
+		hops = append(hops, UpgradeHop{ToImage: strings.TrimSpace(image)})
+	}
+	for _, opt := range hopOptions {
+		parts := strings.SplitN(opt, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expected hop option of the form HOP:KEY=VALUE instead of %q", opt)
+		}
+		hop, err := strconv.Atoi(parts[0])
+		if err != nil || hop < 1 || hop > len(hops) {
+			return nil, fmt.Errorf("hop option %q must start with a hop between 1 and %d", opt, len(hops))
+		}
+		if !strings.Contains(parts[1], "=") {
+			return nil, fmt.Errorf("expected hop option of the form HOP:KEY=VALUE instead of %q", opt)
+		}
+		hops[hop-1].Options = append(hops[hop-1].Options, parts[1])
+	}
+	return hops, nil
 }
 
 func NewUpgradeOptionsFromYAML(yaml string) (*UpgradeOptions, error) {
@@ -66,6 +105,12 @@ func (o *UpgradeOptions) SetUpgradeGlobals() error {
 		}
 	}
 
+	for i, hop := range o.Hops {
+		if err := upgrade.SetUpgradeHopOptions(i+1, hop.Options); err != nil {
+			return err
+		}
+	}
+
 	upgrade.SetToImage(o.ToImage)
 	switch o.Suite {
 	case "none":
@@ -0,0 +1,77 @@
+package upgradeoptions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUpgradeHops(t *testing.T) {
+	tests := []struct {
+		name        string
+		toImage     string
+		hopOptions  []string
+		expected    []UpgradeHop
+		expectedErr string
+	}{
+		{
+			name:    "no hop options",
+			toImage: "a,b",
+		},
+		{
+			name:       "options per hop",
+			toImage:    "registry/a, registry/b,registry/c",
+			hopOptions: []string{"1:pause-worker-pool=true", "2:pause-worker-pool=true", "2:ack-admin-gates=true"},
+			expected: []UpgradeHop{
+				{ToImage: "registry/a", Options: []string{"pause-worker-pool=true"}},
+				{ToImage: "registry/b", Options: []string{"pause-worker-pool=true", "ack-admin-gates=true"}},
+				{ToImage: "registry/c"},
+			},
+		},
+		{
+			name:        "hop out of range",
+			toImage:     "a,b",
+			hopOptions:  []string{"3:ack-admin-gates=true"},
+			expectedErr: `hop option "3:ack-admin-gates=true" must start with a hop between 1 and 2`,
+		},
+		{
+			name:        "missing hop",
+			toImage:     "a,b",
+			hopOptions:  []string{"ack-admin-gates=true"},
+			expectedErr: `expected hop option of the form HOP:KEY=VALUE instead of "ack-admin-gates=true"`,
+		},
+		{
+			name:        "missing value",
+			toImage:     "a,b",
+			hopOptions:  []string{"1:ack-admin-gates"},
+			expectedErr: `expected hop option of the form HOP:KEY=VALUE instead of "1:ack-admin-gates"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewUpgradeHops(tt.toImage, tt.hopOptions)
+			if len(tt.expectedErr) > 0 {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestUpgradeOptionsRoundTrip(t *testing.T) {
+	opt := &UpgradeOptions{
+		Suite:   "all",
+		ToImage: "a,b",
+		Hops:    []UpgradeHop{{ToImage: "a", Options: []string{"pause-worker-pool=true"}}, {ToImage: "b"}},
+	}
+	actual, err := NewUpgradeOptionsFromYAML(opt.ToEnv())
+	require.NoError(t, err)
+	assert.Equal(t, opt, actual)
+
+	single, err := NewUpgradeOptionsFromYAML((&UpgradeOptions{Suite: "all", ToImage: "a"}).ToEnv())
+	require.NoError(t, err)
+	assert.Nil(t, single.Hops)
+}
@@ -0,0 +1,83 @@
+package clusterstability
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+)
+
+// NewClusterOperatorsCriterion requires every ClusterOperator to be Available, and neither Progressing nor Degraded.
+func NewClusterOperatorsCriterion(client configclient.Interface, timeout time.Duration) Criterion {
+	return Criterion{
+		Name:    ClusterOperatorsStable,
+		Timeout: timeout,
+		Check: func(ctx context.Context) ([]string, error) {
+			operators, err := client.ConfigV1().ClusterOperators().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return unsettledOperators(operators.Items), nil
+		},
+	}
+}
+
+// can be overridden for tests
+var nowFn = realNow
+
+func realNow() time.Time {
+	return time.Now()
+}
+
+func unsettledOperators(operators []configv1.ClusterOperator) []string {
+	unsettledOperatorStatus := []string{}
+	for _, co := range operators {
+		available := findCondition(co.Status.Conditions, configv1.OperatorAvailable)
+		degraded := findCondition(co.Status.Conditions, configv1.OperatorDegraded)
+		progressing := findCondition(co.Status.Conditions, configv1.OperatorProgressing)
+		if conditionHasStatus(available, configv1.ConditionTrue) &&
+			conditionHasStatus(degraded, configv1.ConditionFalse) &&
+			conditionHasStatus(progressing, configv1.ConditionFalse) {
+			continue
+		}
+		if !conditionHasStatus(available, configv1.ConditionTrue) {
+			unsettledOperatorStatus = append(unsettledOperatorStatus, describeCondition(co.Name, "is not Available", available))
+		}
+		if !conditionHasStatus(degraded, configv1.ConditionFalse) {
+			unsettledOperatorStatus = append(unsettledOperatorStatus, describeCondition(co.Name, "is Degraded", degraded))
+		}
+		if !conditionHasStatus(progressing, configv1.ConditionFalse) {
+			unsettledOperatorStatus = append(unsettledOperatorStatus, describeCondition(co.Name, "is Progressing", progressing))
+		}
+	}
+	return unsettledOperatorStatus
+}
+
+// describeCondition explains why the operator is unsettled. An operator that does not report a condition yet, for
+// instance because it just started, is unsettled as well.
+func describeCondition(operator, state string, c *configv1.ClusterOperatorStatusCondition) string {
+	if c == nil {
+		return fmt.Sprintf("clusteroperator/%v %s because the condition is not reported", operator, state)
+	}
+	return fmt.Sprintf("clusteroperator/%v %s for %v because %q", operator, state, nowFn().Sub(c.LastTransitionTime.Time), c.Message)
+}
+
+func findCondition(conditions []configv1.ClusterOperatorStatusCondition, name configv1.ClusterStatusConditionType) *configv1.ClusterOperatorStatusCondition {
+	for i := range conditions {
+		if name == conditions[i].Type {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func conditionHasStatus(c *configv1.ClusterOperatorStatusCondition, status configv1.ConditionStatus) bool {
+	if c == nil {
+		return false
+	}
+	return c.Status == status
+}
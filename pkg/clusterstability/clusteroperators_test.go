@@ -1,4 +1,4 @@
-package operator
+package clusterstability
 
 import (
 	"reflect"
@@ -49,7 +49,7 @@ func newOperator(name string,
 	}
 }
 
-func TestWaitForOperatorsToSettle(t *testing.T) {
+func TestUnsettledOperators(t *testing.T) {
 	nowFn = fakeNow
 	tests := []struct {
 		name      string
@@ -141,6 +141,17 @@ func TestWaitForOperatorsToSettle(t *testing.T) {
 				`clusteroperator/foo is Progressing for 1m0s because "rolling out"`,
 			},
 		},
+		{
+			name: "one without conditions",
+			operators: []configv1.ClusterOperator{
+				{ObjectMeta: metav1.ObjectMeta{Name: "new"}},
+			},
+			expected: []string{
+				`clusteroperator/new is not Available because the condition is not reported`,
+				`clusteroperator/new is Degraded because the condition is not reported`,
+				`clusteroperator/new is Progressing because the condition is not reported`,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,327 @@
+package clusterstability
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	mcfgclient "github.com/openshift/client-go/machineconfiguration/clientset/versioned"
+	operatorclient "github.com/openshift/client-go/operator/clientset/versioned"
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	"github.com/openshift/library-go/test/library/metrics"
+)
+
+// defaultTimeouts are how long the gate waits for each criterion when no timeout is given.
+var defaultTimeouts = map[CriterionName]time.Duration{
+	ClusterOperatorsStable:    10 * time.Minute,
+	MachineConfigPoolsUpdated: 20 * time.Minute,
+	NodesReady:                10 * time.Minute,
+	NoPendingCSRs:             5 * time.Minute,
+	NoFiringCriticalAlerts:    10 * time.Minute,
+	EtcdMembersHealthy:        10 * time.Minute,
+}
+
+// DefaultCriteria only requires ClusterOperators to be stable, which is what was checked before tests started
+// before the other criteria existed.
+var DefaultCriteria = []CriterionSpec{
+	{Name: ClusterOperatorsStable, Timeout: defaultTimeouts[ClusterOperatorsStable]},
+}
+
+// CriterionSpec selects a criterion and how long to wait for it.
+type CriterionSpec struct {
+	Name    CriterionName
+	Timeout time.Duration
+}
+
+// KnownCriteria returns the names of every criterion the gate can require.
+func KnownCriteria() []string {
+	names := []string{}
+	for name := range defaultTimeouts {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseCriteria parses criteria of the form NAME or NAME=TIMEOUT, where TIMEOUT is a duration like 15m.
+func ParseCriteria(values []string) ([]CriterionSpec, error) {
+	specs := []CriterionSpec{}
+	seen := map[CriterionName]bool{}
+	for _, value := range values {
+		name, timeoutValue, hasTimeout := strings.Cut(strings.TrimSpace(value), "=")
+		spec := CriterionSpec{Name: CriterionName(name)}
+		defaultTimeout, ok := defaultTimeouts[spec.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown stability criterion %q, expected one of: %s", name, strings.Join(KnownCriteria(), ", "))
+		}
+		if seen[spec.Name] {
+			return nil, fmt.Errorf("stability criterion %q is given more than once", name)
+		}
+		seen[spec.Name] = true
+
+		spec.Timeout = defaultTimeout
+		if hasTimeout {
+			timeout, err := time.ParseDuration(timeoutValue)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout for stability criterion %q: %v", name, err)
+			}
+			if timeout <= 0 {
+				return nil, fmt.Errorf("timeout for stability criterion %q must be positive", name)
+			}
+			spec.Timeout = timeout
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// NewCriteria creates the criteria for the specs, checking the cluster the config points at.
+func NewCriteria(config *rest.Config, specs []CriterionSpec) ([]Criterion, error) {
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating kube client: %w", err)
+	}
+	criteria := []Criterion{}
+	for _, spec := range specs {
+		var criterion Criterion
+		switch spec.Name {
+		case ClusterOperatorsStable:
+			configClient, err := configclient.NewForConfig(config)
+			if err != nil {
+				return nil, fmt.Errorf("error creating config client: %w", err)
+			}
+			criterion = NewClusterOperatorsCriterion(configClient, spec.Timeout)
+		case MachineConfigPoolsUpdated:
+			mcfgClient, err := mcfgclient.NewForConfig(config)
+			if err != nil {
+				return nil, fmt.Errorf("error creating machine config client: %w", err)
+			}
+			criterion = NewMachineConfigPoolsCriterion(mcfgClient, spec.Timeout)
+		case NodesReady:
+			criterion = NewNodesReadyCriterion(kubeClient, spec.Timeout)
+		case NoPendingCSRs:
+			criterion = NewPendingCSRsCriterion(kubeClient, spec.Timeout)
+		case NoFiringCriticalAlerts:
+			routeClient, err := routeclient.NewForConfig(config)
+			if err != nil {
+				return nil, fmt.Errorf("error creating route client: %w", err)
+			}
+			criterion = NewCriticalAlertsCriterion(kubeClient, routeClient, spec.Timeout)
+		case EtcdMembersHealthy:
+			operatorClient, err := operatorclient.NewForConfig(config)
+			if err != nil {
+				return nil, fmt.Errorf("error creating operator client: %w", err)
+			}
+			criterion = NewEtcdMembersCriterion(operatorClient, spec.Timeout)
+		default:
+			return nil, fmt.Errorf("unknown stability criterion %q", spec.Name)
+		}
+		criteria = append(criteria, criterion)
+	}
+	return criteria, nil
+}
+
+// NewMachineConfigPoolsCriterion requires every MachineConfigPool that is not paused to have rolled out its current
+// configuration to all of its machines without being degraded.
+func NewMachineConfigPoolsCriterion(client mcfgclient.Interface, timeout time.Duration) Criterion {
+	return Criterion{
+		Name:    MachineConfigPoolsUpdated,
+		Timeout: timeout,
+		Check: func(ctx context.Context) ([]string, error) {
+			pools, err := client.MachineconfigurationV1().MachineConfigPools().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return unupdatedMachineConfigPools(pools.Items), nil
+		},
+	}
+}
+
+func unupdatedMachineConfigPools(pools []mcfgv1.MachineConfigPool) []string {
+	reasons := []string{}
+	for _, pool := range pools {
+		if pool.Spec.Paused {
+			continue
+		}
+		if pool.Status.ObservedGeneration != pool.Generation {
+			reasons = append(reasons, fmt.Sprintf("machineconfigpool/%s has not observed generation %d", pool.Name, pool.Generation))
+			continue
+		}
+		for _, condition := range pool.Status.Conditions {
+			switch {
+			case condition.Type == mcfgv1.MachineConfigPoolUpdated && condition.Status != corev1.ConditionTrue:
+				reasons = append(reasons, fmt.Sprintf("machineconfigpool/%s is not Updated, %d of %d machines are updated", pool.Name, pool.Status.UpdatedMachineCount, pool.Status.MachineCount))
+			case condition.Type == mcfgv1.MachineConfigPoolDegraded && condition.Status == corev1.ConditionTrue:
+				reasons = append(reasons, fmt.Sprintf("machineconfigpool/%s is Degraded because %q", pool.Name, condition.Message))
+			}
+		}
+	}
+	return reasons
+}
+
+// NewNodesReadyCriterion requires every node to be Ready.
+func NewNodesReadyCriterion(client kubernetes.Interface, timeout time.Duration) Criterion {
+	return Criterion{
+		Name:    NodesReady,
+		Timeout: timeout,
+		Check: func(ctx context.Context) ([]string, error) {
+			nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return unreadyNodes(nodes.Items), nil
+		},
+	}
+}
+
+func unreadyNodes(nodes []corev1.Node) []string {
+	reasons := []string{}
+	for _, node := range nodes {
+		var ready *corev1.NodeCondition
+		for i := range node.Status.Conditions {
+			if node.Status.Conditions[i].Type == corev1.NodeReady {
+				ready = &node.Status.Conditions[i]
+			}
+		}
+		switch {
+		case ready == nil:
+			reasons = append(reasons, fmt.Sprintf("node/%s has not reported whether it is Ready", node.Name))
+		case ready.Status != corev1.ConditionTrue:
+			reasons = append(reasons, fmt.Sprintf("node/%s is not Ready because %q", node.Name, ready.Message))
+		}
+	}
+	return reasons
+}
+
+// NewPendingCSRsCriterion requires every CertificateSigningRequest to be approved or denied.
+func NewPendingCSRsCriterion(client kubernetes.Interface, timeout time.Duration) Criterion {
+	return Criterion{
+		Name:    NoPendingCSRs,
+		Timeout: timeout,
+		Check: func(ctx context.Context) ([]string, error) {
+			csrs, err := client.CertificatesV1().CertificateSigningRequests().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return pendingCSRs(csrs.Items), nil
+		},
+	}
+}
+
+func pendingCSRs(csrs []certificatesv1.CertificateSigningRequest) []string {
+	reasons := []string{}
+	for _, csr := range csrs {
+		// approving, denying or failing a request adds a condition, so one without any is still waiting for a decision
+		if len(csr.Status.Conditions) > 0 {
+			continue
+		}
+		reasons = append(reasons, fmt.Sprintf("certificatesigningrequest/%s from %s is pending", csr.Name, csr.Spec.Username))
+	}
+	return reasons
+}
+
+// NewCriticalAlertsCriterion requires no alert with critical severity to be firing. Clusters without in-cluster
+// monitoring always meet it.
+func NewCriticalAlertsCriterion(kubeClient kubernetes.Interface, routeClient routeclient.Interface, timeout time.Duration) Criterion {
+	// the route to prometheus may not be admitted yet, so the client is created on the first check that can
+	var lock sync.Mutex
+	var prometheusClient prometheusv1.API
+	return Criterion{
+		Name:    NoFiringCriticalAlerts,
+		Timeout: timeout,
+		Check: func(ctx context.Context) ([]string, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			if prometheusClient == nil {
+				_, err := kubeClient.CoreV1().Namespaces().Get(ctx, "openshift-monitoring", metav1.GetOptions{})
+				if apierrors.IsNotFound(err) {
+					return nil, nil
+				}
+				client, err := metrics.NewPrometheusClient(ctx, kubeClient, routeClient)
+				if err != nil {
+					return nil, err
+				}
+				prometheusClient = client
+			}
+
+			result, _, err := prometheusClient.Query(ctx, `ALERTS{alertstate="firing",severity="critical"}`, time.Time{})
+			if err != nil {
+				return nil, err
+			}
+			vector, ok := result.(model.Vector)
+			if !ok {
+				return nil, fmt.Errorf("expected a vector of firing alerts, got %s", result.Type())
+			}
+			return firingAlerts(vector), nil
+		},
+	}
+}
+
+func firingAlerts(vector model.Vector) []string {
+	reasons := []string{}
+	for _, sample := range vector {
+		reason := fmt.Sprintf("alert/%s is firing", sample.Metric[model.AlertNameLabel])
+		if namespace, ok := sample.Metric["namespace"]; ok {
+			reason += fmt.Sprintf(" in namespace/%s", namespace)
+		}
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	return reasons
+}
+
+// NewEtcdMembersCriterion requires the etcd operator to report every member as available and none as degraded.
+// Clusters whose etcd is not managed by the etcd operator always meet it.
+func NewEtcdMembersCriterion(client operatorclient.Interface, timeout time.Duration) Criterion {
+	return Criterion{
+		Name:    EtcdMembersHealthy,
+		Timeout: timeout,
+		Check: func(ctx context.Context) ([]string, error) {
+			etcd, err := client.OperatorV1().Etcds().Get(ctx, "cluster", metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			return unhealthyEtcdMembers(etcd.Status.Conditions), nil
+		},
+	}
+}
+
+func unhealthyEtcdMembers(conditions []operatorv1.OperatorCondition) []string {
+	reasons := []string{}
+	available := false
+	for _, condition := range conditions {
+		switch condition.Type {
+		case "EtcdMembersAvailable":
+			available = condition.Status == operatorv1.ConditionTrue
+			if !available {
+				reasons = append(reasons, fmt.Sprintf("etcd members are not available because %q", condition.Message))
+			}
+		case "EtcdMembersDegraded":
+			if condition.Status == operatorv1.ConditionTrue {
+				reasons = append(reasons, fmt.Sprintf("etcd members are degraded because %q", condition.Message))
+			}
+		}
+	}
+	if !available && len(reasons) == 0 {
+		reasons = append(reasons, "etcd operator has not reported whether the etcd members are available")
+	}
+	return reasons
+}
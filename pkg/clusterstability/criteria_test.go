@@ -0,0 +1,123 @@
+package clusterstability
+
+import (
+	"reflect"
+	"testing"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestUnupdatedMachineConfigPools(t *testing.T) {
+	newPool := func(name string, paused bool, generation, observedGeneration int64, updated, degraded corev1.ConditionStatus) mcfgv1.MachineConfigPool {
+		return mcfgv1.MachineConfigPool{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Generation: generation},
+			Spec:       mcfgv1.MachineConfigPoolSpec{Paused: paused},
+			Status: mcfgv1.MachineConfigPoolStatus{
+				ObservedGeneration:  observedGeneration,
+				MachineCount:        3,
+				UpdatedMachineCount: 1,
+				Conditions: []mcfgv1.MachineConfigPoolCondition{
+					{Type: mcfgv1.MachineConfigPoolUpdated, Status: updated},
+					{Type: mcfgv1.MachineConfigPoolDegraded, Status: degraded, Message: "node is broken"},
+				},
+			},
+		}
+	}
+	pools := []mcfgv1.MachineConfigPool{
+		newPool("master", false, 2, 2, corev1.ConditionTrue, corev1.ConditionFalse),
+		newPool("worker", false, 2, 2, corev1.ConditionFalse, corev1.ConditionTrue),
+		newPool("infra", false, 3, 2, corev1.ConditionTrue, corev1.ConditionFalse),
+		newPool("paused", true, 3, 2, corev1.ConditionFalse, corev1.ConditionFalse),
+	}
+	expected := []string{
+		"machineconfigpool/worker is not Updated, 1 of 3 machines are updated",
+		`machineconfigpool/worker is Degraded because "node is broken"`,
+		"machineconfigpool/infra has not observed generation 3",
+	}
+	if actual := unupdatedMachineConfigPools(pools); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestUnreadyNodes(t *testing.T) {
+	nodes := []corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready"},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "unready"},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse, Message: "PLEG is not healthy"}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "new"},
+		},
+	}
+	expected := []string{
+		`node/unready is not Ready because "PLEG is not healthy"`,
+		"node/new has not reported whether it is Ready",
+	}
+	if actual := unreadyNodes(nodes); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestPendingCSRs(t *testing.T) {
+	csrs := []certificatesv1.CertificateSigningRequest{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "csr-approved"},
+			Status: certificatesv1.CertificateSigningRequestStatus{
+				Conditions: []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateApproved}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "csr-pending"},
+			Spec:       certificatesv1.CertificateSigningRequestSpec{Username: "system:node:worker-a"},
+		},
+	}
+	expected := []string{"certificatesigningrequest/csr-pending from system:node:worker-a is pending"}
+	if actual := pendingCSRs(csrs); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestUnhealthyEtcdMembers(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []operatorv1.OperatorCondition
+		expected   []string
+	}{
+		{
+			name: "healthy",
+			conditions: []operatorv1.OperatorCondition{
+				{Type: "EtcdMembersAvailable", Status: operatorv1.ConditionTrue},
+				{Type: "EtcdMembersDegraded", Status: operatorv1.ConditionFalse},
+			},
+			expected: []string{},
+		},
+		{
+			name: "degraded",
+			conditions: []operatorv1.OperatorCondition{
+				{Type: "EtcdMembersAvailable", Status: operatorv1.ConditionTrue},
+				{Type: "EtcdMembersDegraded", Status: operatorv1.ConditionTrue, Message: "2 of 3 members are available"},
+			},
+			expected: []string{`etcd members are degraded because "2 of 3 members are available"`},
+		},
+		{
+			name:     "not reported",
+			expected: []string{"etcd operator has not reported whether the etcd members are available"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := unhealthyEtcdMembers(tt.conditions); !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package clusterstability waits for the cluster to meet a configurable set of stability criteria, such as settled
+// ClusterOperators and Ready nodes, and reports how long that took and which criterion held it up.
+package clusterstability
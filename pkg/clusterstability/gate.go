@@ -0,0 +1,227 @@
+package clusterstability
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// CriterionName identifies a condition the cluster has to meet before it is considered stable.
+type CriterionName string
+
+const (
+	ClusterOperatorsStable    CriterionName = "cluster-operators"
+	MachineConfigPoolsUpdated CriterionName = "machine-config-pools"
+	NodesReady                CriterionName = "nodes-ready"
+	NoPendingCSRs             CriterionName = "no-pending-csrs"
+	NoFiringCriticalAlerts    CriterionName = "no-critical-alerts"
+	EtcdMembersHealthy        CriterionName = "etcd-members"
+)
+
+// Criterion is a condition the cluster has to meet, and how long the gate waits for it to be met.
+type Criterion struct {
+	Name    CriterionName
+	Timeout time.Duration
+
+	// Check returns the reasons the cluster does not meet the criterion, or none once it does.
+	Check func(ctx context.Context) ([]string, error)
+}
+
+// Gate waits until every criterion has been met for a minimum period. It gives up as soon as a criterion is still
+// not met after its own timeout, or once the longest timeout passed without the cluster staying stable.
+type Gate struct {
+	Criteria []Criterion
+
+	// Interval is how often the criteria are checked.
+	Interval time.Duration
+
+	// MinimumStablePeriod is how long every criterion has to be met without interruption before the gate opens.
+	// It counts against the timeouts of the criteria.
+	MinimumStablePeriod time.Duration
+}
+
+// CriterionResult is what the gate learned about a single criterion while it waited.
+type CriterionResult struct {
+	Name    CriterionName
+	Timeout time.Duration
+
+	// Met is set when the criterion was met the last time it was checked.
+	Met bool
+	// Reasons are why the criterion was not met the last time it was not. They are kept once the criterion is met,
+	// so a criterion that is Met with Reasons recovered while the gate waited.
+	Reasons []string
+	// UnmetSince is when the criterion was first found not to be met, and is zero when it always was.
+	UnmetSince time.Time
+	// MetSince is when the criterion was last found to be met after not being met, or after the gate started.
+	MetSince time.Time
+	// Blocked is set when the gate gave up waiting because of this criterion.
+	Blocked bool
+}
+
+// Recovered returns true if the criterion was not met at first, but was met when the gate finished.
+func (c *CriterionResult) Recovered() bool {
+	return c.Met && !c.UnmetSince.IsZero()
+}
+
+// Result is the outcome of waiting at the gate.
+type Result struct {
+	From time.Time
+	To   time.Time
+
+	// Criteria are in the order the gate was given them.
+	Criteria []*CriterionResult
+
+	// Err is why the gate gave up, and is nil when the cluster became stable.
+	Err error
+}
+
+// Blocked returns the criteria that kept the cluster from becoming stable.
+func (r *Result) Blocked() []*CriterionResult {
+	var blocked []*CriterionResult
+	for _, criterion := range r.Criteria {
+		if criterion.Blocked {
+			blocked = append(blocked, criterion)
+		}
+	}
+	return blocked
+}
+
+// Recovered returns the criteria that were not met at first, but were met when the gate finished.
+func (r *Result) Recovered() []*CriterionResult {
+	var recovered []*CriterionResult
+	for _, criterion := range r.Criteria {
+		if criterion.Recovered() {
+			recovered = append(recovered, criterion)
+		}
+	}
+	return recovered
+}
+
+// Wait checks the criteria until the cluster is stable, the gate gives up or the context is done.
+func (g *Gate) Wait(ctx context.Context) *Result {
+	state := newGateState(g.Criteria, g.MinimumStablePeriod, time.Now())
+	_ = wait.PollUntilContextCancel(ctx, g.Interval, true, func(ctx context.Context) (bool, error) {
+		for i, criterion := range g.Criteria {
+			reasons, err := criterion.Check(ctx)
+			if err != nil {
+				reasons = []string{fmt.Sprintf("error checking %s: %v", criterion.Name, err)}
+			}
+			state.observe(i, reasons, time.Now())
+		}
+		return state.evaluate(time.Now()), nil
+	})
+	if state.result.Err == nil && ctx.Err() != nil {
+		state.cancel(ctx.Err())
+	}
+	state.result.To = time.Now()
+	return state.result
+}
+
+// gateState tracks the criteria across checks, so the decision to open or give up does not depend on the clock.
+type gateState struct {
+	result              *Result
+	minimumStablePeriod time.Duration
+}
+
+func newGateState(criteria []Criterion, minimumStablePeriod time.Duration, from time.Time) *gateState {
+	state := &gateState{
+		result:              &Result{From: from},
+		minimumStablePeriod: minimumStablePeriod,
+	}
+	for _, criterion := range criteria {
+		state.result.Criteria = append(state.result.Criteria, &CriterionResult{
+			Name:    criterion.Name,
+			Timeout: criterion.Timeout,
+		})
+	}
+	return state
+}
+
+func (s *gateState) observe(i int, reasons []string, now time.Time) {
+	criterion := s.result.Criteria[i]
+	if len(reasons) > 0 {
+		if criterion.UnmetSince.IsZero() {
+			criterion.UnmetSince = now
+		}
+		criterion.Met = false
+		criterion.MetSince = time.Time{}
+		criterion.Reasons = reasons
+		return
+	}
+	if !criterion.Met {
+		criterion.Met = true
+		criterion.MetSince = now
+	}
+}
+
+// evaluate returns true once the gate is done waiting, either because the cluster is stable or because it gave up.
+func (s *gateState) evaluate(now time.Time) bool {
+	elapsed := now.Sub(s.result.From)
+	var stableSince time.Time
+	var longestTimeout time.Duration
+	unmet := false
+	for _, criterion := range s.result.Criteria {
+		if criterion.Timeout > longestTimeout {
+			longestTimeout = criterion.Timeout
+		}
+		if !criterion.Met {
+			unmet = true
+			if elapsed >= criterion.Timeout {
+				criterion.Blocked = true
+			}
+			continue
+		}
+		if criterion.MetSince.After(stableSince) {
+			stableSince = criterion.MetSince
+		}
+	}
+
+	if blocked := s.result.Blocked(); len(blocked) > 0 {
+		s.result.Err = fmt.Errorf("gave up waiting for the cluster to become stable after %s: %s", elapsed.Round(time.Second), describe(blocked))
+		return true
+	}
+	if !unmet && now.Sub(stableSince) >= s.minimumStablePeriod {
+		return true
+	}
+	if elapsed < longestTimeout {
+		return false
+	}
+
+	// every criterion was met at some point, but not for long enough, so blame the ones that interrupted the
+	// stable period last
+	for _, criterion := range s.result.Criteria {
+		if !criterion.Met || (!criterion.UnmetSince.IsZero() && now.Sub(criterion.MetSince) < s.minimumStablePeriod) {
+			criterion.Blocked = true
+		}
+	}
+	s.result.Err = fmt.Errorf("the cluster did not stay stable for %s within %s: %s", s.minimumStablePeriod, longestTimeout, describe(s.result.Blocked()))
+	return true
+}
+
+// cancel gives up waiting because the caller stopped the gate, which is blamed on every criterion not yet met.
+func (s *gateState) cancel(err error) {
+	var unmet []*CriterionResult
+	for _, criterion := range s.result.Criteria {
+		if !criterion.Met {
+			criterion.Blocked = true
+			unmet = append(unmet, criterion)
+		}
+	}
+	s.result.Err = fmt.Errorf("stopped waiting for the cluster to become stable: %w", err)
+	if len(unmet) > 0 {
+		s.result.Err = fmt.Errorf("stopped waiting for the cluster to become stable: %w: %s", err, describe(unmet))
+	}
+}
+
+func describe(criteria []*CriterionResult) string {
+	names := []string{}
+	for _, criterion := range criteria {
+		names = append(names, string(criterion.Name))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,224 @@
+package clusterstability
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type observation struct {
+	at      time.Duration
+	reasons map[CriterionName][]string
+}
+
+func TestGateEvaluate(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	criteria := []Criterion{
+		{Name: ClusterOperatorsStable, Timeout: 10 * time.Minute},
+		{Name: NodesReady, Timeout: 5 * time.Minute},
+	}
+	tests := []struct {
+		name                string
+		minimumStablePeriod time.Duration
+		observations        []observation
+		expectDoneAt        time.Duration
+		expectErr           bool
+		expectBlocked       []CriterionName
+		expectRecovered     []CriterionName
+	}{
+		{
+			name:                "stable from the start",
+			minimumStablePeriod: 3 * time.Minute,
+			observations: []observation{
+				{at: 0},
+				{at: time.Minute},
+				{at: 2 * time.Minute},
+				{at: 3 * time.Minute},
+			},
+			expectDoneAt: 3 * time.Minute,
+		},
+		{
+			name:                "recovered before its timeout",
+			minimumStablePeriod: 3 * time.Minute,
+			observations: []observation{
+				{at: 0, reasons: map[CriterionName][]string{NodesReady: {"node/a is not Ready"}}},
+				{at: 2 * time.Minute},
+				{at: 4 * time.Minute},
+				{at: 5 * time.Minute},
+			},
+			expectDoneAt:    5 * time.Minute,
+			expectRecovered: []CriterionName{NodesReady},
+		},
+		{
+			name:                "blocked by the criterion with the shorter timeout",
+			minimumStablePeriod: 3 * time.Minute,
+			observations: []observation{
+				{at: 0, reasons: map[CriterionName][]string{NodesReady: {"node/a is not Ready"}, ClusterOperatorsStable: {"clusteroperator/a is Degraded"}}},
+				{at: 2 * time.Minute, reasons: map[CriterionName][]string{NodesReady: {"node/a is not Ready"}}},
+				{at: 5 * time.Minute, reasons: map[CriterionName][]string{NodesReady: {"node/a is not Ready"}}},
+			},
+			expectDoneAt:    5 * time.Minute,
+			expectErr:       true,
+			expectBlocked:   []CriterionName{NodesReady},
+			expectRecovered: []CriterionName{ClusterOperatorsStable},
+		},
+		{
+			name:                "never stable for long enough",
+			minimumStablePeriod: 3 * time.Minute,
+			observations: []observation{
+				{at: 0},
+				{at: 2 * time.Minute, reasons: map[CriterionName][]string{ClusterOperatorsStable: {"clusteroperator/a is Progressing"}}},
+				{at: 4 * time.Minute},
+				{at: 6 * time.Minute, reasons: map[CriterionName][]string{ClusterOperatorsStable: {"clusteroperator/a is Progressing"}}},
+				{at: 8 * time.Minute},
+				{at: 10 * time.Minute},
+			},
+			expectDoneAt:    10 * time.Minute,
+			expectErr:       true,
+			expectBlocked:   []CriterionName{ClusterOperatorsStable},
+			expectRecovered: []CriterionName{ClusterOperatorsStable},
+		},
+		{
+			name: "no minimum stable period",
+			observations: []observation{
+				{at: 0, reasons: map[CriterionName][]string{ClusterOperatorsStable: {"clusteroperator/a is Progressing"}}},
+				{at: time.Minute},
+			},
+			expectDoneAt:    time.Minute,
+			expectRecovered: []CriterionName{ClusterOperatorsStable},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newGateState(criteria, tt.minimumStablePeriod, from)
+			var doneAt time.Duration
+			done := false
+			for _, observation := range tt.observations {
+				now := from.Add(observation.at)
+				for i, criterion := range criteria {
+					state.observe(i, observation.reasons[criterion.Name], now)
+				}
+				if state.evaluate(now) {
+					done = true
+					doneAt = observation.at
+					break
+				}
+			}
+			if !done {
+				t.Fatalf("expected the gate to be done at %s", tt.expectDoneAt)
+			}
+			if doneAt != tt.expectDoneAt {
+				t.Errorf("expected the gate to be done at %s, was done at %s", tt.expectDoneAt, doneAt)
+			}
+			if tt.expectErr != (state.result.Err != nil) {
+				t.Errorf("unexpected error: %v", state.result.Err)
+			}
+			if actual := names(state.result.Blocked()); !reflect.DeepEqual(actual, tt.expectBlocked) {
+				t.Errorf("expected blocked %v, got %v", tt.expectBlocked, actual)
+			}
+			if actual := names(state.result.Recovered()); !reflect.DeepEqual(actual, tt.expectRecovered) {
+				t.Errorf("expected recovered %v, got %v", tt.expectRecovered, actual)
+			}
+		})
+	}
+}
+
+func names(criteria []*CriterionResult) []CriterionName {
+	var ret []CriterionName
+	for _, criterion := range criteria {
+		ret = append(ret, criterion.Name)
+	}
+	return ret
+}
+
+func TestGateResultJUnits(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := &Result{
+		From: from,
+		To:   from.Add(5 * time.Minute),
+		Criteria: []*CriterionResult{
+			{Name: ClusterOperatorsStable, Met: true, MetSince: from},
+			{Name: NodesReady, Reasons: []string{"node/a is not Ready"}, UnmetSince: from, Blocked: true},
+		},
+	}
+
+	junits := result.JUnits()
+	if len(junits) != 2 {
+		t.Fatalf("expected the gate junit to flake, got %d junits", len(junits))
+	}
+	if junits[0].Name != JUnitName || junits[0].FailureOutput == nil || !strings.Contains(junits[0].FailureOutput.Output, "nodes-ready: node/a is not Ready") {
+		t.Errorf("expected %q to fail naming the blocking criterion, got %#v", JUnitName, junits[0].FailureOutput)
+	}
+	if junits[1].Name != JUnitName || junits[1].FailureOutput != nil {
+		t.Errorf("expected %q to pass once", JUnitName)
+	}
+
+	// the gate finished before the monitor started
+	monitorStart := result.To.Add(time.Minute)
+	intervals := result.Intervals(monitorStart)
+	if len(intervals) != 2 {
+		t.Fatalf("expected an interval for the gate and one for the unmet criterion, got %d", len(intervals))
+	}
+	for _, interval := range intervals {
+		if !interval.From.Equal(monitorStart) || !interval.To.Equal(monitorStart) {
+			t.Errorf("expected the interval to be moved to the monitor start, got %s to %s", interval.From, interval.To)
+		}
+	}
+	if !strings.Contains(intervals[1].Message.HumanMessage, "not met for 5m0s") {
+		t.Errorf("expected the unmet criterion to last until the gate gave up, got %q", intervals[1].Message.HumanMessage)
+	}
+
+	intervals = result.Intervals(from)
+	if !intervals[1].From.Equal(from) || !intervals[1].To.Equal(result.To) {
+		t.Errorf("expected the unmet criterion to last until the gate gave up, got %s to %s", intervals[1].From, intervals[1].To)
+	}
+}
+
+func TestParseCriteria(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []string
+		expected  []CriterionSpec
+		expectErr bool
+	}{
+		{
+			name:   "default and explicit timeouts",
+			values: []string{"cluster-operators", "nodes-ready=2m"},
+			expected: []CriterionSpec{
+				{Name: ClusterOperatorsStable, Timeout: 10 * time.Minute},
+				{Name: NodesReady, Timeout: 2 * time.Minute},
+			},
+		},
+		{
+			name:     "none",
+			expected: []CriterionSpec{},
+		},
+		{
+			name:      "unknown",
+			values:    []string{"pods-ready"},
+			expectErr: true,
+		},
+		{
+			name:      "duplicate",
+			values:    []string{"nodes-ready", "nodes-ready=1m"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid timeout",
+			values:    []string{"nodes-ready=soon"},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseCriteria(tt.values)
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.expectErr && !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
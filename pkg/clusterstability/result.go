@@ -0,0 +1,106 @@
+package clusterstability
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// Intervals returns an interval for the time spent at the gate, and one for every criterion that was not met while
+// the gate waited, from when it was first found not to be met until it was.  The gate waits before the monitor starts
+// and the monitor drops intervals that end before it started, so none start before monitorStart; the messages keep
+// how long the gate and the criteria actually waited.
+func (r *Result) Intervals(monitorStart time.Time) monitorapi.Intervals {
+	clip := func(t time.Time) time.Time {
+		if t.Before(monitorStart) {
+			return monitorStart
+		}
+		return t
+	}
+
+	level := monitorapi.Info
+	message := "cluster was stable"
+	switch {
+	case r.Err != nil:
+		level = monitorapi.Error
+		message = r.Err.Error()
+	case len(r.Recovered()) > 0:
+		level = monitorapi.Warning
+		message = fmt.Sprintf("cluster became stable after waiting for %s", describe(r.Recovered()))
+	}
+	ret := monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceStabilityGate, level).
+			Locator(monitorapi.NewLocator().StabilityGate("")).
+			Message(monitorapi.NewMessage().
+				Reason(monitorapi.StabilityGateWaitingReason).
+				HumanMessagef("%s, waited %s from %s", message, r.To.Sub(r.From).Round(time.Second), r.From.UTC().Format(time.RFC3339)),
+			).
+			Display().
+			Build(clip(r.From), clip(r.To)),
+	}
+
+	for _, criterion := range r.Criteria {
+		if criterion.UnmetSince.IsZero() {
+			continue
+		}
+		level := monitorapi.Warning
+		if criterion.Blocked {
+			level = monitorapi.Error
+		}
+		to := r.To
+		if criterion.Met {
+			to = criterion.MetSince
+		}
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceStabilityGate, level).
+				Locator(monitorapi.NewLocator().StabilityGate(string(criterion.Name))).
+				Message(monitorapi.NewMessage().
+					Reason(monitorapi.StabilityCriterionUnmetReason).
+					HumanMessagef("not met for %s from %s: %s", to.Sub(criterion.UnmetSince).Round(time.Second),
+						criterion.UnmetSince.UTC().Format(time.RFC3339), strings.Join(criterion.Reasons, "; ")),
+				).
+				Display().
+				Build(clip(criterion.UnmetSince), clip(to)),
+		)
+	}
+	return ret
+}
+
+// JUnitName is the name of the junit reporting whether a criterion blocked the gate.
+const JUnitName = "Cluster should meet the stability gate criteria before test is started"
+
+// JUnits returns a single junit for the gate, which flakes rather than fails when criteria blocked the gate, since
+// the tests still run against a cluster that may not be stable.  The output names the criteria that blocked it.
+func (r *Result) JUnits() []*junitapi.JUnitTestCase {
+	duration := r.To.Sub(r.From).Seconds()
+	success := &junitapi.JUnitTestCase{Name: JUnitName, Duration: duration}
+	recovered := []string{}
+	for _, criterion := range r.Recovered() {
+		recovered = append(recovered, fmt.Sprintf("%s was met after %s, before that: %s",
+			criterion.Name, criterion.MetSince.Sub(r.From).Round(time.Second), strings.Join(criterion.Reasons, "; ")))
+	}
+	success.SystemOut = strings.Join(recovered, "\n")
+
+	blocked := r.Blocked()
+	if len(blocked) == 0 {
+		return []*junitapi.JUnitTestCase{success}
+	}
+	reasons := []string{}
+	for _, criterion := range blocked {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", criterion.Name, strings.Join(criterion.Reasons, "; ")))
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name:     JUnitName,
+			Duration: duration,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("gave up waiting for the cluster to become stable after %s, blocked by %s:\n%s",
+					r.To.Sub(r.From).Round(time.Second), describe(blocked), strings.Join(reasons, "\n")),
+			},
+		},
+		success,
+	}
+}
@@ -1,8 +1,9 @@
 package dev
 
 import (
-	"io/ioutil"
+	"fmt"
 	"os"
+	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/origin/pkg/alerts"
@@ -27,6 +28,7 @@ func NewDevCommand() *cobra.Command {
 	cmd.AddCommand(
 		newRunAlertInvariantsCommand(),
 		newRunDisruptionInvariantsCommand(),
+		newValidateAlertCatalogCommand(),
 	)
 	return cmd
 }
@@ -122,18 +124,7 @@ a running cluster.
 }
 
 func readIntervalsFromFile(intervalsFile string) (monitorapi.Intervals, error) {
-	jsonFile, err := os.Open(intervalsFile)
-	if err != nil {
-		return nil, err
-	}
-	defer jsonFile.Close()
-
-	jsonBytes, err := ioutil.ReadAll(jsonFile)
-	if err != nil {
-		return nil, err
-	}
-
-	return monitorserialization.IntervalsFromJSON(jsonBytes)
+	return monitorserialization.EventsFromFile(intervalsFile)
 }
 
 func newRunDisruptionInvariantsCommand() *cobra.Command {
@@ -204,3 +195,50 @@ a running cluster.
 		"Topology for simulated cluster under test when intervals were gathered (ha, single)")
 	return cmd
 }
+
+func newValidateAlertCatalogCommand() *cobra.Command {
+	var catalogFile string
+
+	cmd := &cobra.Command{
+		Use:   "validate-alert-catalog",
+		Short: "Validate the alert expectations and their exceptions",
+		Long: templates.LongDesc(`
+Validate the alert catalog built into this binary, or the one in --file, which
+holds the expectation for every alert with its own test. Malformed entries,
+duplicated entries and entries or overrides that can never apply because an
+earlier one covers them are errors. Exceptions that expired are reported so
+they can be removed.
+`),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			catalog := allowedalerts.GetAlertCatalog()
+			if len(catalogFile) > 0 {
+				data, err := os.ReadFile(catalogFile)
+				if err != nil {
+					return err
+				}
+				catalog, err = allowedalerts.ParseAlertCatalog(data)
+				if err != nil {
+					return err
+				}
+			}
+
+			for _, expired := range catalog.Expired(time.Now()) {
+				logrus.Warn(expired)
+			}
+			errs := catalog.Validate()
+			for _, err := range errs {
+				logrus.Error(err)
+			}
+			if len(errs) > 0 {
+				return fmt.Errorf("alert catalog has %d problems", len(errs))
+			}
+			logrus.Infof("alert catalog with %d alerts is valid", len(catalog.Alerts))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&catalogFile,
+		"file", catalogFile,
+		"Path to an alert catalog to validate instead of the one built into this binary (pkg/monitortestlibrary/allowedalerts/alert_catalog.json).")
+	return cmd
+}
@@ -0,0 +1,83 @@
+package convert
+
+import (
+	"fmt"
+
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+type convertIntervalsOptions struct {
+	InputFile    string
+	OutputFile   string
+	OutputFormat string
+
+	IOStreams genericclioptions.IOStreams
+}
+
+func NewConvertIntervalsCommand(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := &convertIntervalsOptions{
+		OutputFormat: "columnar",
+		IOStreams:    ioStreams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "convert-intervals",
+		Short: "Convert an intervals file between the JSON and columnar formats",
+		Long: `
+		Convert an e2e-events file between formats.  The input format is detected automatically.
+
+		openshift-tests monitor convert-intervals -f e2e-events.json --output-file e2e-events.columnar -o columnar
+		`,
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.InputFile, "file", "f", o.InputFile, "intervals file to convert, either JSON or columnar.")
+	cmd.Flags().StringVar(&o.OutputFile, "output-file", o.OutputFile, "file to write the converted intervals to.")
+	cmd.Flags().StringVarP(&o.OutputFormat, "output", "o", o.OutputFormat, "format to write: [columnar,json]")
+	return cmd
+}
+
+func (o *convertIntervalsOptions) Validate() error {
+	if len(o.InputFile) == 0 {
+		return fmt.Errorf("missing --file")
+	}
+	if len(o.OutputFile) == 0 {
+		return fmt.Errorf("missing --output-file")
+	}
+	switch o.OutputFormat {
+	case "columnar", "json":
+	default:
+		return fmt.Errorf("unknown output format %q, must be one of [columnar,json]", o.OutputFormat)
+	}
+	return nil
+}
+
+func (o *convertIntervalsOptions) Run() error {
+	intervals, err := monitorserialization.EventsFromFile(o.InputFile)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", o.InputFile, err)
+	}
+
+	switch o.OutputFormat {
+	case "columnar":
+		err = monitorserialization.IntervalsToColumnarFile(o.OutputFile, intervals)
+	case "json":
+		err = monitorserialization.EventsToFile(o.OutputFile, intervals)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write %s: %w", o.OutputFile, err)
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "converted %d intervals from %s to %s\n", len(intervals), o.InputFile, o.OutputFile)
+	return nil
+}
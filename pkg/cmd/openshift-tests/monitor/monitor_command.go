@@ -1,6 +1,7 @@
 package monitor
 
 import (
+	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/convert"
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/run"
 	summarize_audit_logs "github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/summarize-audit-logs"
 	"github.com/openshift/origin/pkg/monitor/apiserveravailability"
@@ -17,6 +18,7 @@ func NewMonitorCommand(streams genericclioptions.IOStreams) *cobra.Command {
 		run.NewRunCommand(streams),
 		summarize_audit_logs.AuditLogSummaryCommand(),
 		apiserveravailability.LogSummaryCommand(),
+		convert.NewConvertIntervalsCommand(streams),
 	)
 	return cmd
 }
@@ -2,9 +2,16 @@ package summarize_audit_logs
 
 import (
 	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/openshift/origin/pkg/monitortestframework"
 	auditloganalyzer2 "github.com/openshift/origin/pkg/monitortests/kubeapiserver/auditloganalyzer"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 
 	"k8s.io/client-go/kubernetes"
 
@@ -16,13 +23,18 @@ import (
 type auditLogSummaryOptions struct {
 	ArtifactDir string
 
+	// AuditLogDir is a must-gather or CI artifact directory to read audit logs from instead of the cluster.
+	AuditLogDir      string
+	ClusterStability string
+
 	ConfigFlags *genericclioptions.ConfigFlags
 	IOStreams   genericclioptions.IOStreams
 }
 
 func AuditLogSummaryCommand() *cobra.Command {
 	o := &auditLogSummaryOptions{
-		ConfigFlags: genericclioptions.NewConfigFlags(true),
+		ClusterStability: string(monitortestframework.Stable),
+		ConfigFlags:      genericclioptions.NewConfigFlags(true),
 		IOStreams: genericclioptions.IOStreams{
 			In:     os.Stdin,
 			Out:    os.Stdout,
@@ -32,15 +44,28 @@ func AuditLogSummaryCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "summarize-audit-logs",
 		Short: "Download and inspect audit logs for interesting things.",
+		Long: `
+		Download and inspect audit logs for interesting things.
+
+		With --audit-log-dir, audit logs are read from a must-gather or CI artifact directory instead of the cluster,
+		and the audit log junits are written to --artifact-dir along with the summaries.
+
+		openshift-tests monitor summarize-audit-logs --audit-log-dir=must-gather/ --artifact-dir=/tmp/audit
+		`,
 
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(o.AuditLogDir) > 0 {
+				return o.RunFromDirectory(context.Background())
+			}
 			return o.Run(context.Background())
 		},
 	}
 
 	cmd.Flags().StringVar(&o.ArtifactDir, "artifact-dir", o.ArtifactDir, "The directory where monitor events will be stored.")
+	cmd.Flags().StringVar(&o.AuditLogDir, "audit-log-dir", o.AuditLogDir, "Read audit logs from this must-gather or CI artifact directory instead of the cluster.")
+	cmd.Flags().StringVar(&o.ClusterStability, "cluster-stability", o.ClusterStability, "The cluster stability the audit logs were gathered under, used with --audit-log-dir: [Stable,Disruptive,SpotCheck]")
 	o.ConfigFlags.AddFlags(cmd.Flags())
 	return cmd
 }
@@ -56,14 +81,52 @@ func (o auditLogSummaryOptions) Run(ctx context.Context) error {
 	}
 
 	summarizer := auditloganalyzer2.NewAuditLogSummarizer()
-	err = auditloganalyzer2.GetKubeAuditLogSummary(ctx, kubeClient, nil, nil, []auditloganalyzer2.AuditEventHandler{summarizer})
+	err = auditloganalyzer2.GetAuditLogSummary(ctx, kubeClient, auditloganalyzer2.AuditedAPIServers, nil, nil, []auditloganalyzer2.AuditEventHandler{summarizer})
+	var flakeErr *monitortestframework.FlakeError
+	switch {
+	case errors.As(err, &flakeErr):
+		// the kube-apiserver logs were read, summarize them along with whatever aggregated apiserver logs were found.
+		fmt.Fprintf(o.IOStreams.ErrOut, "warning: %v\n", flakeErr.Err)
+	case err != nil:
+		return err
+	}
+
+	if err := auditloganalyzer2.WriteAuditLogSummaries(o.ArtifactDir, "", summarizer); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o auditLogSummaryOptions) RunFromDirectory(ctx context.Context) error {
+	timeSuffix := fmt.Sprintf("_%s", time.Now().UTC().Format("20060102-150405"))
+	junits, err := auditloganalyzer2.AnalyzeAuditLogDirectory(ctx, o.AuditLogDir, o.ArtifactDir, timeSuffix,
+		monitortestframework.ClusterStabilityDuringTest(o.ClusterStability), nil, nil)
 	if err != nil {
 		return err
 	}
 
-	if err := auditloganalyzer2.WriteAuditLogSummary(o.ArtifactDir, "", summarizer.GetAuditLogSummary()); err != nil {
+	junitSuite := junitapi.JUnitTestSuite{
+		Name: "audit-log-analysis",
+	}
+	for _, junit := range junits {
+		junitSuite.NumTests++
+		if junit.FailureOutput != nil {
+			junitSuite.NumFailed++
+		} else if junit.SkipMessage != nil {
+			junitSuite.NumSkipped++
+		}
+		junitSuite.TestCases = append(junitSuite.TestCases, junit)
+	}
+	out, err := xml.MarshalIndent(junitSuite, "", "    ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(o.ArtifactDir, fmt.Sprintf("junit_audit-log-analysis%s.xml", timeSuffix))
+	if err := os.WriteFile(path, out, 0640); err != nil {
 		return err
 	}
 
+	fmt.Fprintf(o.IOStreams.Out, "%d tests, %d failed, %d skipped, junit written to %s\n", junitSuite.NumTests, junitSuite.NumFailed, junitSuite.NumSkipped, path)
 	return nil
 }
@@ -45,6 +45,9 @@ func NewTimelineOptions(ioStreams genericclioptions.IOStreams) *TimelineOptions
 		KnownRenderers: map[string]RenderFunc{
 			"json": monitorserialization.IntervalsToJSON,
 			"html": renderHTML,
+			"viewer": func(intervals monitorapi.Intervals) ([]byte, error) {
+				return timelineserializer.RenderOfflineViewer("Timeline", intervals)
+			},
 		},
 		KnownTimelines: map[string]monitorapi.EventIntervalMatchesFunc{
 			"everything":    timelineserializer.BelongsInEverything,
@@ -35,6 +35,17 @@ func NewRunUpgradeCommand(streams genericclioptions.IOStreams) *cobra.Command {
 		the reboot will allow the node to shut down services in an orderly fashion. If set to 'force' the
 		machine will terminate immediately without clean shutdown.
 
+		Pass several comma separated images to --to-image to upgrade through each of them in order
+		under a single monitor session. Options that only apply to one of those upgrades are passed
+		with --hop-options HOP:KEY=VALUE, where HOP is the position of the image starting at 1.
+
+		Supported hop options:
+
+		* pause-worker-pool=true - Pause the worker machine config pool for this upgrade so that
+		workers skip it, like in an EUS to EUS upgrade. The pool is unpaused before the next upgrade
+		that does not pause it, or after the last upgrade, and the workers are waited on.
+		* ack-admin-gates=true - Acknowledge every admin gate before starting this upgrade.
+
 		`) + testsuites.SuitesString(testsuites.UpgradeTestSuites(), "\n\nAvailable upgrade suites:\n\n"),
 
 		SilenceUsage:  true,
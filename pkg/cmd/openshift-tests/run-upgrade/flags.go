@@ -9,6 +9,7 @@ import (
 	"github.com/openshift/origin/pkg/clioptions/clusterdiscovery"
 	"github.com/openshift/origin/pkg/clioptions/iooptions"
 	"github.com/openshift/origin/pkg/clioptions/suiteselection"
+	"github.com/openshift/origin/pkg/clioptions/upgradeoptions"
 	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
 )
 
@@ -26,6 +27,7 @@ type RunUpgradeSuiteFlags struct {
 	UpgradeSuite string
 	ToImage      string
 	TestOptions  []string
+	HopOptions   []string
 
 	// Shared by initialization code
 	config *clusterdiscovery.ClusterConfiguration
@@ -50,6 +52,7 @@ func (f *RunUpgradeSuiteFlags) BindFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&f.ProviderTypeOrJSON, "provider", f.ProviderTypeOrJSON, "The cluster infrastructure provider. Will automatically default to the correct value.")
 	flags.StringVar(&f.ToImage, "to-image", f.ToImage, "Specify the images to test an upgrade to, separated by comma.")
 	flags.StringSliceVar(&f.TestOptions, "options", f.TestOptions, "A set of KEY=VALUE options to control the test. See the help text.")
+	flags.StringArrayVar(&f.HopOptions, "hop-options", f.HopOptions, "A HOP:KEY=VALUE option that only applies to the upgrade to the HOP-th image of --to-image, starting at 1. May be repeated. See the help text.")
 	f.GinkgoRunSuiteOptions.BindFlags(flags)
 	f.TestSuiteSelectionFlags.BindFlags(flags)
 	f.OutputFlags.BindFlags(flags)
@@ -76,6 +79,11 @@ func (f *RunUpgradeSuiteFlags) ToOptions(args []string) (*RunUpgradeSuiteOptions
 		return nil, fmt.Errorf("--to-image must be specified to run an upgrade test")
 	}
 
+	hops, err := upgradeoptions.NewUpgradeHops(f.ToImage, f.HopOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	suite, err := f.TestSuiteSelectionFlags.SelectSuite(
 		f.AvailableSuites,
 		args)
@@ -89,6 +97,7 @@ func (f *RunUpgradeSuiteFlags) ToOptions(args []string) (*RunUpgradeSuiteOptions
 		ToImage:               f.ToImage,
 		FromRepository:        f.FromRepository,
 		TestOptions:           f.TestOptions,
+		Hops:                  hops,
 		CloseFn:               closeFn,
 		IOStreams:             f.IOStreams,
 	}
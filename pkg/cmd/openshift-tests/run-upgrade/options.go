@@ -34,6 +34,7 @@ type RunUpgradeSuiteOptions struct {
 	// CloudProviderJSON string
 
 	TestOptions []string
+	Hops        []upgradeoptions.UpgradeHop
 
 	CloseFn iooptions.CloseFunc
 
@@ -57,6 +58,7 @@ func (o *RunUpgradeSuiteOptions) TestCommandEnvironment() []string {
 		Suite:       o.Suite.Name,
 		ToImage:     o.ToImage,
 		TestOptions: o.TestOptions,
+		Hops:        o.Hops,
 	}
 	args = append(args, fmt.Sprintf("TEST_UPGRADE_OPTIONS=%s", upgradeOptions.ToEnv()))
 
@@ -1,19 +1,20 @@
 package defaultmonitortests
 
 import (
-	"fmt"
-
 	"github.com/openshift/origin/pkg/monitortestframework"
 	"github.com/openshift/origin/pkg/monitortests/authentication/legacyauthenticationmonitortests"
 	nodefaultserviceaccountoperatortests "github.com/openshift/origin/pkg/monitortests/authentication/nodefaultserviceaccountoperatortests"
 	"github.com/openshift/origin/pkg/monitortests/authentication/requiredsccmonitortests"
 	admupgradestatus "github.com/openshift/origin/pkg/monitortests/cli/adm_upgrade/status"
 	azuremetrics "github.com/openshift/origin/pkg/monitortests/cloud/azure/metrics"
+	"github.com/openshift/origin/pkg/monitortests/cloud/cloudthrottling"
 	"github.com/openshift/origin/pkg/monitortests/clusterversionoperator/clusterversionchecker"
 	"github.com/openshift/origin/pkg/monitortests/clusterversionoperator/legacycvomonitortests"
 	"github.com/openshift/origin/pkg/monitortests/clusterversionoperator/operatorstateanalyzer"
 	"github.com/openshift/origin/pkg/monitortests/clusterversionoperator/terminationmessagepolicy"
+	"github.com/openshift/origin/pkg/monitortests/clusterversionoperator/upgradehops"
 	"github.com/openshift/origin/pkg/monitortests/etcd/etcdloganalyzer"
+	"github.com/openshift/origin/pkg/monitortests/etcd/etcdstoragegrowth"
 	"github.com/openshift/origin/pkg/monitortests/etcd/legacyetcdmonitortests"
 	"github.com/openshift/origin/pkg/monitortests/imageregistry/disruptionimageregistry"
 	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/apiservergracefulrestart"
@@ -28,26 +29,34 @@ import (
 	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/legacykubeapiservermonitortests"
 	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/staticpodinstall"
 	"github.com/openshift/origin/pkg/monitortests/kubelet/containerfailures"
+	"github.com/openshift/origin/pkg/monitortests/machines/machineconfigrollout"
 	"github.com/openshift/origin/pkg/monitortests/machines/watchmachines"
 	"github.com/openshift/origin/pkg/monitortests/monitoring/disruptionmetricsapi"
 	"github.com/openshift/origin/pkg/monitortests/monitoring/statefulsetsrecreation"
 	"github.com/openshift/origin/pkg/monitortests/network/disruptioningress"
 	"github.com/openshift/origin/pkg/monitortests/network/disruptionpodnetwork"
 	"github.com/openshift/origin/pkg/monitortests/network/disruptionserviceloadbalancer"
+	"github.com/openshift/origin/pkg/monitortests/network/ingresshealth"
 	"github.com/openshift/origin/pkg/monitortests/network/legacynetworkmonitortests"
 	"github.com/openshift/origin/pkg/monitortests/network/onpremhaproxy"
 	"github.com/openshift/origin/pkg/monitortests/network/onpremkeepalived"
 	"github.com/openshift/origin/pkg/monitortests/node/kubeletlogcollector"
 	"github.com/openshift/origin/pkg/monitortests/node/legacynodemonitortests"
+	"github.com/openshift/origin/pkg/monitortests/node/nodehealth"
 	"github.com/openshift/origin/pkg/monitortests/node/nodestateanalyzer"
+	"github.com/openshift/origin/pkg/monitortests/node/poddisplacement"
+	"github.com/openshift/origin/pkg/monitortests/node/podstartuplatency"
 	"github.com/openshift/origin/pkg/monitortests/node/watchnodes"
 	"github.com/openshift/origin/pkg/monitortests/node/watchpods"
+	"github.com/openshift/origin/pkg/monitortests/olm/operatorlifecycle"
 	"github.com/openshift/origin/pkg/monitortests/storage/legacystoragemonitortests"
+	"github.com/openshift/origin/pkg/monitortests/storage/volumelifecycle"
 	"github.com/openshift/origin/pkg/monitortests/testframework/additionaleventscollector"
 	"github.com/openshift/origin/pkg/monitortests/testframework/alertanalyzer"
 	"github.com/openshift/origin/pkg/monitortests/testframework/clusterinfoserializer"
 	"github.com/openshift/origin/pkg/monitortests/testframework/clusterinstancetypes"
 	"github.com/openshift/origin/pkg/monitortests/testframework/cpumetriccollector"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptioncauseattribution"
 	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionexternalawscloudservicemonitoring"
 	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionexternalazurecloudservicemonitoring"
 	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionexternalgcpcloudservicemonitoring"
@@ -92,17 +101,9 @@ func ListAllMonitorTests() []string {
 
 func NewMonitorTestsFor(info monitortestframework.MonitorTestInitializationInfo) (monitortestframework.MonitorTestRegistry, error) {
 	// get tests and apply any filtering defined in info
-	var startingRegistry monitortestframework.MonitorTestRegistry
-
-	switch info.ClusterStabilityDuringTest {
-	case monitortestframework.Stable:
-		startingRegistry = newDefaultMonitorTests(info)
-	case monitortestframework.Disruptive:
-		startingRegistry = newDisruptiveMonitorTests(info)
-	case monitortestframework.SpotCheck:
-		startingRegistry = newSpotCheckMonitorTests(info)
-	default:
-		panic(fmt.Sprintf("unknown cluster stability level: %q", info.ClusterStabilityDuringTest))
+	startingRegistry, err := newMonitorTests(info).GetRegistryForClusterStability(info.ClusterStabilityDuringTest)
+	if err != nil {
+		return nil, err
 	}
 
 	switch {
@@ -118,201 +119,149 @@ func NewMonitorTestsFor(info monitortestframework.MonitorTestInitializationInfo)
 	return startingRegistry, nil
 }
 
-// newDefaultMonitorTests is the Stable cluster stability level. All monitor tests produce hard
-// pass/fail results. This is the full set of monitor tests used for standard conformance/upgrade+conformance jobs.
-func newDefaultMonitorTests(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTestRegistry {
+var (
+	stable     = monitortestframework.Stable
+	disruptive = monitortestframework.Disruptive
+	spotCheck  = monitortestframework.SpotCheck
+
+	// stableOnly monitor tests are too sensitive to provide value when the cluster is intentionally disrupted.
+	stableOnly = monitortestframework.NewMonitorTestMetadata(monitortestframework.Sensitive).HardFailIn(stable)
+	// sensitiveFlakeWhenUnstable monitor tests still gather useful information in Disruptive and SpotCheck jobs,
+	// but their failures are converted to flakes so they are visible without failing the job.
+	sensitiveFlakeWhenUnstable = monitortestframework.NewMonitorTestMetadata(monitortestframework.Sensitive).
+					HardFailIn(stable).FlakeIn(disruptive, spotCheck)
+	// sensitiveFlakeWhenDisruptive monitor tests are like sensitiveFlakeWhenUnstable, but stay out of the curated
+	// SpotCheck subset.  New monitor tests start here until they are deliberately added to SpotCheck.
+	sensitiveFlakeWhenDisruptive = monitortestframework.NewMonitorTestMetadata(monitortestframework.Sensitive).
+					HardFailIn(stable).FlakeIn(disruptive)
+	// criticalInvariant monitor tests check things that must hold even while the cluster is being disrupted.
+	criticalInvariant = monitortestframework.NewMonitorTestMetadata(monitortestframework.Critical).HardFailIn(stable, disruptive)
+)
+
+// informational is used for collectors, analyzers and serializers that feed timelines and artifacts.
+func informational(stabilities ...monitortestframework.ClusterStabilityDuringTest) monitortestframework.MonitorTestMetadata {
+	return monitortestframework.NewMonitorTestMetadata(monitortestframework.Informational).HardFailIn(stabilities...)
+}
+
+// newMonitorTests registers every monitor test once, along with the cluster stability modes it runs in and
+// whether its junits are hard failures or flakes in each of those modes.
+//   - Stable is the full set of monitor tests used for standard conformance/upgrade+conformance jobs.  All monitor
+//     tests produce hard pass/fail results.
+//   - Disruptive jobs intentionally break things, so some more sensitive monitor tests still run to gather info, but
+//     have their junit results converted to flakes.  Other monitor tests are omitted if they do not provide value
+//     in disruptive testing.
+//   - SpotCheck jobs are minimal, less-sensitive runs intended for quick cluster health verification.  They run a
+//     curated subset of monitor tests, and the more sensitive ones have their junit results converted to flakes.
+func newMonitorTests(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTestRegistry {
 	monitorTestRegistry := monitortestframework.NewMonitorTestRegistry()
 
 	// Authentication
-	monitorTestRegistry.AddMonitorTestOrDie("legacy-authentication-invariants", "apiserver-auth", legacyauthenticationmonitortests.NewLegacyTests())
-	monitorTestRegistry.AddMonitorTestOrDie("no-default-service-account-operator-checker", "oauth-apiserver", nodefaultserviceaccountoperatortests.NewAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("legacy-authentication-invariants", "apiserver-auth", criticalInvariant, legacyauthenticationmonitortests.NewLegacyTests())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("no-default-service-account-operator-checker", "oauth-apiserver", stableOnly, nodefaultserviceaccountoperatortests.NewAnalyzer())
 
 	// Cluster Version Operator
-	monitorTestRegistry.AddMonitorTestOrDie("operator-state-analyzer", "Cluster Version Operator", operatorstateanalyzer.NewAnalyzer())
-	monitorTestRegistry.AddMonitorTestOrDie("required-scc-annotation-checker", "Cluster Version Operator", requiredsccmonitortests.NewAnalyzer())
-	monitorTestRegistry.AddMonitorTestOrDie("cluster-version-checker", "Cluster Version Operator", clusterversionchecker.NewClusterVersionChecker())
-	monitorTestRegistry.AddMonitorTestOrDie("legacy-cvo-invariants", "Cluster Version Operator", legacycvomonitortests.NewLegacyTests())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("operator-state-analyzer", "Cluster Version Operator", informational(stable, disruptive, spotCheck), operatorstateanalyzer.NewAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("required-scc-annotation-checker", "Cluster Version Operator", stableOnly, requiredsccmonitortests.NewAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("cluster-version-checker", "Cluster Version Operator", stableOnly, clusterversionchecker.NewClusterVersionChecker())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("legacy-cvo-invariants", "Cluster Version Operator", stableOnly, legacycvomonitortests.NewLegacyTests())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("upgrade-hops", "Cluster Version Operator", informational(stable), upgradehops.NewUpgradeHops())
 
 	// etcd
-	monitorTestRegistry.AddMonitorTestOrDie("etcd-log-analyzer", "etcd", etcdloganalyzer.NewEtcdLogAnalyzer(monitortestframework.HardFail))
-	monitorTestRegistry.AddMonitorTestOrDie("legacy-etcd-invariants", "etcd", legacyetcdmonitortests.NewLegacyTests())
-	monitorTestRegistry.AddMonitorTestOrDie("etcd-disk-metrics-intervals", "etcd", etcddiskmetricsintervals.NewEtcdDiskMetricsCollector())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("etcd-log-analyzer", "etcd", sensitiveFlakeWhenUnstable, etcdloganalyzer.NewEtcdLogAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("legacy-etcd-invariants", "etcd", criticalInvariant, legacyetcdmonitortests.NewLegacyTests())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("etcd-disk-metrics-intervals", "etcd", informational(stable, disruptive), etcddiskmetricsintervals.NewEtcdDiskMetricsCollector())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("etcd-storage-growth", "etcd", sensitiveFlakeWhenDisruptive, etcdstoragegrowth.NewEtcdStorageGrowthCollector())
 
 	// kube-apiserver
-	monitorTestRegistry.AddMonitorTestOrDie("audit-log-analyzer", "kube-apiserver", auditloganalyzer.NewAuditLogAnalyzer(info))
-	monitorTestRegistry.AddMonitorTestOrDie("legacy-kube-apiserver-invariants", "kube-apiserver", legacykubeapiservermonitortests.NewLegacyTests())
-	monitorTestRegistry.AddMonitorTestOrDie("graceful-shutdown-analyzer", "kube-apiserver", apiservergracefulrestart.NewGracefulShutdownAnalyzer())
-	monitorTestRegistry.AddMonitorTestOrDie("crd-version-checker", "kube-apiserver", crdversionchecker.NewCRDVersionChecker())
-	monitorTestRegistry.AddMonitorTestOrDie("apiserver-disruption-invariant", "kube-apiserver", disruptionnewapiserver.NewDisruptionInvariant())
-	monitorTestRegistry.AddMonitorTestOrDie("apiserver-external-availability", "kube-apiserver", disruptionexternalapiserver.NewExternalDisruptionInvariant(info))
-	monitorTestRegistry.AddMonitorTestOrDie("apiserver-incluster-availability", "kube-apiserver", disruptioninclusterapiserver.NewInvariantInClusterDisruption(info))
-	monitorTestRegistry.AddMonitorTestOrDie(apiunreachablefromclientmetrics.MonitorName, "kube-apiserver", apiunreachablefromclientmetrics.NewMonitorTest())
-	monitorTestRegistry.AddMonitorTestOrDie(faultyloadbalancer.MonitorName, "kube-apiserver", faultyloadbalancer.NewMonitorTest())
-	monitorTestRegistry.AddMonitorTestOrDie(staticpodinstall.MonitorName, "kube-apiserver", staticpodinstall.NewStaticPodInstallMonitorTest())
-	monitorTestRegistry.AddMonitorTestOrDie("generation-analyzer", "kube-apiserver", generationanalyzer.NewGenerationAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("audit-log-analyzer", "kube-apiserver", stableOnly, auditloganalyzer.NewAuditLogAnalyzer(info))
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("legacy-kube-apiserver-invariants", "kube-apiserver", criticalInvariant, legacykubeapiservermonitortests.NewLegacyTests())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("graceful-shutdown-analyzer", "kube-apiserver", criticalInvariant, apiservergracefulrestart.NewGracefulShutdownAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("crd-version-checker", "kube-apiserver", criticalInvariant, crdversionchecker.NewCRDVersionChecker())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("apiserver-disruption-invariant", "kube-apiserver", stableOnly, disruptionnewapiserver.NewDisruptionInvariant())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("apiserver-external-availability", "kube-apiserver", stableOnly, disruptionexternalapiserver.NewExternalDisruptionInvariant(info))
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("apiserver-incluster-availability", "kube-apiserver", stableOnly, disruptioninclusterapiserver.NewInvariantInClusterDisruption(info))
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie(apiunreachablefromclientmetrics.MonitorName, "kube-apiserver", stableOnly, apiunreachablefromclientmetrics.NewMonitorTest())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie(faultyloadbalancer.MonitorName, "kube-apiserver", stableOnly, faultyloadbalancer.NewMonitorTest())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie(staticpodinstall.MonitorName, "kube-apiserver", stableOnly, staticpodinstall.NewStaticPodInstallMonitorTest())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("generation-analyzer", "kube-apiserver", criticalInvariant, generationanalyzer.NewGenerationAnalyzer())
 
 	// Networking
-	monitorTestRegistry.AddMonitorTestOrDie("legacy-networking-invariants", "Networking / cluster-network-operator", legacynetworkmonitortests.NewLegacyTests())
-	monitorTestRegistry.AddMonitorTestOrDie("pod-network-avalibility", "Network / ovn-kubernetes", disruptionpodnetwork.NewPodNetworkAvalibilityInvariant(info))
-	monitorTestRegistry.AddMonitorTestOrDie("service-type-load-balancer-availability", "Networking / router", disruptionserviceloadbalancer.NewAvailabilityInvariant())
-	monitorTestRegistry.AddMonitorTestOrDie("ingress-availability", "Networking / router", disruptioningress.NewAvailabilityInvariant())
-	monitorTestRegistry.AddMonitorTestOrDie("on-prem-keepalived", "Networking / On-Prem Loadbalancer", onpremkeepalived.InitialAndFinalOperatorLogScraper())
-	monitorTestRegistry.AddMonitorTestOrDie("on-prem-haproxy", "Networking / On-Prem Host Networking", onpremhaproxy.InitialAndFinalOperatorLogScraper())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("legacy-networking-invariants", "Networking / cluster-network-operator", stableOnly, legacynetworkmonitortests.NewLegacyTests())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("pod-network-avalibility", "Network / ovn-kubernetes", stableOnly, disruptionpodnetwork.NewPodNetworkAvalibilityInvariant(info))
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("service-type-load-balancer-availability", "Networking / router", stableOnly, disruptionserviceloadbalancer.NewAvailabilityInvariant())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("ingress-availability", "Networking / router", stableOnly, disruptioningress.NewAvailabilityInvariant())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("ingress-health", "Networking / router", sensitiveFlakeWhenDisruptive, ingresshealth.NewIngressHealthWatcher())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("on-prem-keepalived", "Networking / On-Prem Loadbalancer", stableOnly, onpremkeepalived.InitialAndFinalOperatorLogScraper())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("on-prem-haproxy", "Networking / On-Prem Host Networking", stableOnly, onpremhaproxy.InitialAndFinalOperatorLogScraper())
 
 	// Node / Kubelet
-	monitorTestRegistry.AddMonitorTestOrDie("kubelet-log-collector", "Node / Kubelet", kubeletlogcollector.NewKubeletLogCollector())
-	monitorTestRegistry.AddMonitorTestOrDie("legacy-node-invariants", "Node / Kubelet", legacynodemonitortests.NewLegacyTests())
-	monitorTestRegistry.AddMonitorTestOrDie("node-state-analyzer", "Node / Kubelet", nodestateanalyzer.NewAnalyzer())
-	monitorTestRegistry.AddMonitorTestOrDie("cpu-metric-collector", "Node / Kubelet", cpumetriccollector.NewCPUMetricCollector())
-	monitorTestRegistry.AddMonitorTestOrDie("pod-lifecycle", "Node / Kubelet", watchpods.NewPodWatcher())
-	monitorTestRegistry.AddMonitorTestOrDie("node-lifecycle", "Node / Kubelet", watchnodes.NewNodeWatcher())
-	monitorTestRegistry.AddMonitorTestOrDie(containerfailures.MonitorName, "Node / Kubelet", containerfailures.NewContainerFailuresTests())
-	monitorTestRegistry.AddMonitorTestOrDie("termination-message-policy", "Cluster Version Operator", terminationmessagepolicy.NewAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("kubelet-log-collector", "Node / Kubelet", informational(stable, disruptive), kubeletlogcollector.NewKubeletLogCollector())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("legacy-node-invariants", "Node / Kubelet", criticalInvariant, legacynodemonitortests.NewLegacyTests())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("node-state-analyzer", "Node / Kubelet", informational(stable, disruptive, spotCheck), nodestateanalyzer.NewAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("cpu-metric-collector", "Node / Kubelet", informational(stable, disruptive), cpumetriccollector.NewCPUMetricCollector())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("node-pressure-metric-collector", "Node / Kubelet", informational(stable, disruptive), cpumetriccollector.NewNodePressureMetricCollector())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("pod-lifecycle", "Node / Kubelet", informational(stable, disruptive, spotCheck), watchpods.NewPodWatcher())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("node-lifecycle", "Node / Kubelet", informational(stable, spotCheck), watchnodes.NewNodeWatcher())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("pod-displacement-analyzer", "Node / Kubelet", sensitiveFlakeWhenUnstable, poddisplacement.NewPodDisplacementAnalyzer())
+	// pod_startup_baseline.json has no data yet, the analyzer only summarizes latencies until it is filled from CI runs
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("pod-startup-latency-analyzer", "Node / Kubelet", sensitiveFlakeWhenDisruptive, podstartuplatency.NewPodStartupLatencyAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("node-health-analyzer", "Node / Kubelet", sensitiveFlakeWhenDisruptive, nodehealth.NewNodeHealthAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie(containerfailures.MonitorName, "Node / Kubelet", stableOnly, containerfailures.NewContainerFailuresTests())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("termination-message-policy", "Cluster Version Operator", stableOnly, terminationmessagepolicy.NewAnalyzer())
 
 	// Machines
-	monitorTestRegistry.AddMonitorTestOrDie("machine-lifecycle", "Cluster-Lifecycle / machine-api", watchmachines.NewMachineWatcher())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("machine-lifecycle", "Cluster-Lifecycle / machine-api", informational(stable, disruptive), watchmachines.NewMachineWatcher())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("machine-config-rollout", "Machine Config Operator", sensitiveFlakeWhenDisruptive, machineconfigrollout.NewMachineConfigRolloutWatcher())
 
 	// Image Registry
-	monitorTestRegistry.AddMonitorTestOrDie("image-registry-availability", "Image Registry", disruptionimageregistry.NewAvailabilityInvariant())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("image-registry-availability", "Image Registry", stableOnly, disruptionimageregistry.NewAvailabilityInvariant())
 
 	// Storage
-	monitorTestRegistry.AddMonitorTestOrDie("legacy-storage-invariants", "Storage", legacystoragemonitortests.NewLegacyTests())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("legacy-storage-invariants", "Storage", criticalInvariant, legacystoragemonitortests.NewLegacyTests())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("volume-lifecycle", "Storage", sensitiveFlakeWhenDisruptive, volumelifecycle.NewVolumeLifecycleWatcher())
+
+	// OLM
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("operator-lifecycle", "OLM", sensitiveFlakeWhenDisruptive, operatorlifecycle.NewOperatorLifecycleWatcher())
 
 	// Monitoring
-	monitorTestRegistry.AddMonitorTestOrDie("monitoring-statefulsets-recreation", "Monitoring", statefulsetsrecreation.NewStatefulsetsChecker())
-	monitorTestRegistry.AddMonitorTestOrDie("metrics-api-availability", "Monitoring", disruptionmetricsapi.NewAvailabilityInvariant())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("monitoring-statefulsets-recreation", "Monitoring", stableOnly, statefulsetsrecreation.NewStatefulsetsChecker())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("metrics-api-availability", "Monitoring", stableOnly, disruptionmetricsapi.NewAvailabilityInvariant())
 
 	// Test Framework — alerts, serializers, collectors, analyzers
-	monitorTestRegistry.AddMonitorTestOrDie(legacytestframeworkmonitortests.AlertsMonitorName, "Test Framework", legacytestframeworkmonitortests.NewLegacyAlertsMonitorTests(info, monitortestframework.HardFail))
-	monitorTestRegistry.AddMonitorTestOrDie("alert-summary-serializer", "Test Framework", alertanalyzer.NewAlertSummarySerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("metrics-endpoints-down", "Test Framework", metricsendpointdown.NewMetricsEndpointDown())
-	monitorTestRegistry.AddMonitorTestOrDie("interval-duration-sum", "Test Framework", intervaldurationsum.NewIntervalDurationSum())
-	monitorTestRegistry.AddMonitorTestOrDie("external-service-availability", "Test Framework", disruptionexternalservicemonitoring.NewAvailabilityInvariant())
-	monitorTestRegistry.AddMonitorTestOrDie("external-gcp-cloud-service-availability", "Test Framework", disruptionexternalgcpcloudservicemonitoring.NewCloudAvailabilityInvariant())
-	monitorTestRegistry.AddMonitorTestOrDie("external-aws-cloud-service-availability", "Test Framework", disruptionexternalawscloudservicemonitoring.NewCloudAvailabilityInvariant())
-	monitorTestRegistry.AddMonitorTestOrDie("external-azure-cloud-service-availability", "Test Framework", disruptionexternalazurecloudservicemonitoring.NewCloudAvailabilityInvariant())
-	monitorTestRegistry.AddMonitorTestOrDie("pathological-event-analyzer", "Test Framework", pathologicaleventanalyzer.NewAnalyzer())
-	monitorTestRegistry.AddMonitorTestOrDie(legacytestframeworkmonitortests.PathologicalMonitorName, "Test Framework", legacytestframeworkmonitortests.NewLegacyPathologicalMonitorTests(info))
-	monitorTestRegistry.AddMonitorTestOrDie("disruption-summary-serializer", "Test Framework", disruptionserializer.NewDisruptionSummarySerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("timeline-serializer", "Test Framework", timelineserializer.NewTimelineSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("interval-serializer", "Test Framework", intervalserializer.NewIntervalSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("tracked-resources-serializer", "Test Framework", trackedresourcesserializer.NewTrackedResourcesSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("cluster-info-serializer", "Test Framework", clusterinfoserializer.NewClusterInfoSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("cluster-instance-types", "Test Framework", clusterinstancetypes.NewClusterInstanceTypes(info))
-	monitorTestRegistry.AddMonitorTestOrDie("additional-events-collector", "Test Framework", additionaleventscollector.NewIntervalSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("known-image-checker", "Test Framework", knownimagechecker.NewEnsureValidImages())
-	monitorTestRegistry.AddMonitorTestOrDie("e2e-test-analyzer", "Test Framework", e2etestanalyzer.NewAnalyzer())
-	monitorTestRegistry.AddMonitorTestOrDie("event-collector", "Test Framework", watchevents.NewEventWatcher())
-	monitorTestRegistry.AddMonitorTestOrDie("clusteroperator-collector", "Test Framework", watchclusteroperators.NewOperatorWatcher())
-	monitorTestRegistry.AddMonitorTestOrDie("initial-and-final-operator-log-scraper", "Test Framework", operatorloganalyzer.InitialAndFinalOperatorLogScraper())
-	monitorTestRegistry.AddMonitorTestOrDie("lease-checker", "Test Framework", operatorloganalyzer.OperatorLeaseCheck())
-	monitorTestRegistry.AddMonitorTestOrDie("watch-namespaces", "Test Framework", watchnamespaces.NewNamespaceWatcher())
-	monitorTestRegistry.AddMonitorTestOrDie("high-cpu-test-analyzer", "Test Framework", highcputestanalyzer.NewHighCPUTestAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie(legacytestframeworkmonitortests.AlertsMonitorName, "Test Framework", sensitiveFlakeWhenUnstable, legacytestframeworkmonitortests.NewLegacyAlertsMonitorTests(info))
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("alert-summary-serializer", "Test Framework", informational(stable, spotCheck), alertanalyzer.NewAlertSummarySerializer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("metrics-endpoints-down", "Test Framework", stableOnly, metricsendpointdown.NewMetricsEndpointDown())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("interval-duration-sum", "Test Framework", informational(stable), intervaldurationsum.NewIntervalDurationSum())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("external-service-availability", "Test Framework", stableOnly, disruptionexternalservicemonitoring.NewAvailabilityInvariant())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("external-gcp-cloud-service-availability", "Test Framework", stableOnly, disruptionexternalgcpcloudservicemonitoring.NewCloudAvailabilityInvariant())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("external-aws-cloud-service-availability", "Test Framework", stableOnly, disruptionexternalawscloudservicemonitoring.NewCloudAvailabilityInvariant())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("external-azure-cloud-service-availability", "Test Framework", stableOnly, disruptionexternalazurecloudservicemonitoring.NewCloudAvailabilityInvariant())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("pathological-event-analyzer", "Test Framework", informational(stable), pathologicaleventanalyzer.NewAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie(legacytestframeworkmonitortests.PathologicalMonitorName, "Test Framework", stableOnly, legacytestframeworkmonitortests.NewLegacyPathologicalMonitorTests(info))
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("disruption-summary-serializer", "Test Framework", informational(stable, spotCheck), disruptionserializer.NewDisruptionSummarySerializer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("disruption-cause-attribution", "Test Framework", informational(stable), disruptioncauseattribution.NewDisruptionCauseAttribution())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("timeline-serializer", "Test Framework", informational(stable, disruptive, spotCheck), timelineserializer.NewTimelineSerializer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("interval-serializer", "Test Framework", informational(stable, disruptive, spotCheck), intervalserializer.NewIntervalSerializer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("tracked-resources-serializer", "Test Framework", informational(stable, disruptive, spotCheck), trackedresourcesserializer.NewTrackedResourcesSerializer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("cluster-info-serializer", "Test Framework", informational(stable, disruptive, spotCheck), clusterinfoserializer.NewClusterInfoSerializer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("cluster-instance-types", "Test Framework", informational(stable), clusterinstancetypes.NewClusterInstanceTypes(info))
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("additional-events-collector", "Test Framework", informational(stable, disruptive), additionaleventscollector.NewIntervalSerializer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("known-image-checker", "Test Framework", criticalInvariant, knownimagechecker.NewEnsureValidImages())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("e2e-test-analyzer", "Test Framework", informational(stable, disruptive, spotCheck), e2etestanalyzer.NewAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("event-collector", "Test Framework", informational(stable, disruptive, spotCheck), watchevents.NewEventWatcher())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("clusteroperator-collector", "Test Framework", informational(stable, disruptive, spotCheck), watchclusteroperators.NewOperatorWatcher())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("initial-and-final-operator-log-scraper", "Test Framework", informational(stable, disruptive), operatorloganalyzer.InitialAndFinalOperatorLogScraper())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("lease-checker", "Test Framework", monitortestframework.NewMonitorTestMetadata(monitortestframework.Sensitive).HardFailIn(stable, disruptive), operatorloganalyzer.OperatorLeaseCheck())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("watch-namespaces", "Test Framework", informational(stable, disruptive, spotCheck), watchnamespaces.NewNamespaceWatcher())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("high-cpu-test-analyzer", "Test Framework", informational(stable, disruptive), highcputestanalyzer.NewHighCPUTestAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("node-pressure-test-analyzer", "Test Framework", informational(stable, disruptive), highcputestanalyzer.NewNodePressureTestAnalyzer())
 
 	// Cloud
-	monitorTestRegistry.AddMonitorTestOrDie("azure-metrics-collector", "Test Framework", azuremetrics.NewAzureMetricsCollector())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("cloud-api-throttling", "Cloud Compute / Cloud Controller Manager", monitortestframework.NewMonitorTestMetadata(monitortestframework.Sensitive).FlakeIn(stable, disruptive), cloudthrottling.NewCloudThrottlingDetector())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("azure-metrics-collector", "Test Framework", informational(stable, disruptive), azuremetrics.NewAzureMetricsCollector())
 
 	// CLI
-	monitorTestRegistry.AddMonitorTestOrDie("oc-adm-upgrade-status", "oc / update", admupgradestatus.NewOcAdmUpgradeStatusChecker())
-
-	return monitorTestRegistry
-}
-
-// newDisruptiveMonitorTests is the Disruptive cluster stability level. Disruptive jobs intentionally
-// break things, so some more sensitive monitor tests still run to gather info, but have their junit
-// results converted to flakes — visible / tracked in CI but unable to fail the job.
-// Other monitortests are omitted if they do not provide value in disruptive testing.
-func newDisruptiveMonitorTests(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTestRegistry {
-	monitorTestRegistry := monitortestframework.NewMonitorTestRegistry()
-
-	// Authentication
-	monitorTestRegistry.AddMonitorTestOrDie("legacy-authentication-invariants", "apiserver-auth", legacyauthenticationmonitortests.NewLegacyTests())
-
-	// Cluster Version Operator
-	monitorTestRegistry.AddMonitorTestOrDie("operator-state-analyzer", "Cluster Version Operator", operatorstateanalyzer.NewAnalyzer())
-
-	// etcd — etcd-log-analyzer flaked due to intentional disruption
-	monitorTestRegistry.AddMonitorTestOrDie("etcd-log-analyzer", "etcd", etcdloganalyzer.NewEtcdLogAnalyzer(monitortestframework.AsFlake))
-	monitorTestRegistry.AddMonitorTestOrDie("legacy-etcd-invariants", "etcd", legacyetcdmonitortests.NewLegacyTests())
-	monitorTestRegistry.AddMonitorTestOrDie("etcd-disk-metrics-intervals", "etcd", etcddiskmetricsintervals.NewEtcdDiskMetricsCollector())
-
-	// kube-apiserver
-	monitorTestRegistry.AddMonitorTestOrDie("legacy-kube-apiserver-invariants", "kube-apiserver", legacykubeapiservermonitortests.NewLegacyTests())
-	monitorTestRegistry.AddMonitorTestOrDie("graceful-shutdown-analyzer", "kube-apiserver", apiservergracefulrestart.NewGracefulShutdownAnalyzer())
-	monitorTestRegistry.AddMonitorTestOrDie("crd-version-checker", "kube-apiserver", crdversionchecker.NewCRDVersionChecker())
-	monitorTestRegistry.AddMonitorTestOrDie("generation-analyzer", "kube-apiserver", generationanalyzer.NewGenerationAnalyzer())
-
-	// Node / Kubelet
-	monitorTestRegistry.AddMonitorTestOrDie("kubelet-log-collector", "Node / Kubelet", kubeletlogcollector.NewKubeletLogCollector())
-	monitorTestRegistry.AddMonitorTestOrDie("legacy-node-invariants", "Node / Kubelet", legacynodemonitortests.NewLegacyTests())
-	monitorTestRegistry.AddMonitorTestOrDie("node-state-analyzer", "Node / Kubelet", nodestateanalyzer.NewAnalyzer())
-	monitorTestRegistry.AddMonitorTestOrDie("cpu-metric-collector", "Node / Kubelet", cpumetriccollector.NewCPUMetricCollector())
-	monitorTestRegistry.AddMonitorTestOrDie("pod-lifecycle", "Node / Kubelet", watchpods.NewPodWatcher())
-
-	// Machines
-	monitorTestRegistry.AddMonitorTestOrDie("machine-lifecycle", "Cluster-Lifecycle / machine-api", watchmachines.NewMachineWatcher())
-
-	// Storage
-	monitorTestRegistry.AddMonitorTestOrDie("legacy-storage-invariants", "Storage", legacystoragemonitortests.NewLegacyTests())
-
-	// Test Framework — alerts flaked due to intentional disruption
-	monitorTestRegistry.AddMonitorTestOrDie(legacytestframeworkmonitortests.AlertsMonitorName, "Test Framework", legacytestframeworkmonitortests.NewLegacyAlertsMonitorTests(info, monitortestframework.AsFlake))
-	monitorTestRegistry.AddMonitorTestOrDie("timeline-serializer", "Test Framework", timelineserializer.NewTimelineSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("interval-serializer", "Test Framework", intervalserializer.NewIntervalSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("tracked-resources-serializer", "Test Framework", trackedresourcesserializer.NewTrackedResourcesSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("cluster-info-serializer", "Test Framework", clusterinfoserializer.NewClusterInfoSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("additional-events-collector", "Test Framework", additionaleventscollector.NewIntervalSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("known-image-checker", "Test Framework", knownimagechecker.NewEnsureValidImages())
-	monitorTestRegistry.AddMonitorTestOrDie("e2e-test-analyzer", "Test Framework", e2etestanalyzer.NewAnalyzer())
-	monitorTestRegistry.AddMonitorTestOrDie("event-collector", "Test Framework", watchevents.NewEventWatcher())
-	monitorTestRegistry.AddMonitorTestOrDie("clusteroperator-collector", "Test Framework", watchclusteroperators.NewOperatorWatcher())
-	monitorTestRegistry.AddMonitorTestOrDie("initial-and-final-operator-log-scraper", "Test Framework", operatorloganalyzer.InitialAndFinalOperatorLogScraper())
-	monitorTestRegistry.AddMonitorTestOrDie("lease-checker", "Test Framework", operatorloganalyzer.OperatorLeaseCheck())
-	monitorTestRegistry.AddMonitorTestOrDie("watch-namespaces", "Test Framework", watchnamespaces.NewNamespaceWatcher())
-	monitorTestRegistry.AddMonitorTestOrDie("high-cpu-test-analyzer", "Test Framework", highcputestanalyzer.NewHighCPUTestAnalyzer())
-
-	// Cloud
-	monitorTestRegistry.AddMonitorTestOrDie("azure-metrics-collector", "Test Framework", azuremetrics.NewAzureMetricsCollector())
-
-	return monitorTestRegistry
-}
-
-// newSpotCheckMonitorTests is the SpotCheck cluster stability level. SpotCheck jobs are minimal,
-// less-sensitive runs intended for quick cluster health verification. They run a curated subset
-// of monitor tests. The more sensitive ones (etcd-log-analyzer, alerts) have their junit results
-// converted to flakes so failures are visible but cannot cause job failures.
-func newSpotCheckMonitorTests(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTestRegistry {
-	monitorTestRegistry := monitortestframework.NewMonitorTestRegistry()
-
-	// Cluster Version Operator
-	monitorTestRegistry.AddMonitorTestOrDie("operator-state-analyzer", "Cluster Version Operator", operatorstateanalyzer.NewAnalyzer())
-
-	// etcd — etcd-log-analyzer flaked for spot-check sensitivity
-	monitorTestRegistry.AddMonitorTestOrDie("etcd-log-analyzer", "etcd", etcdloganalyzer.NewEtcdLogAnalyzer(monitortestframework.AsFlake))
-
-	// Node / Kubelet
-	monitorTestRegistry.AddMonitorTestOrDie("node-lifecycle", "Node / Kubelet", watchnodes.NewNodeWatcher())
-	monitorTestRegistry.AddMonitorTestOrDie("pod-lifecycle", "Node / Kubelet", watchpods.NewPodWatcher())
-	monitorTestRegistry.AddMonitorTestOrDie("node-state-analyzer", "Node / Kubelet", nodestateanalyzer.NewAnalyzer())
-
-	// Cluster operator and event collection — feeds timelines
-	monitorTestRegistry.AddMonitorTestOrDie("clusteroperator-collector", "Test Framework", watchclusteroperators.NewOperatorWatcher())
-	monitorTestRegistry.AddMonitorTestOrDie("event-collector", "Test Framework", watchevents.NewEventWatcher())
-	monitorTestRegistry.AddMonitorTestOrDie("watch-namespaces", "Test Framework", watchnamespaces.NewNamespaceWatcher())
-
-	// Serializers — needed to produce artifacts
-	monitorTestRegistry.AddMonitorTestOrDie("timeline-serializer", "Test Framework", timelineserializer.NewTimelineSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("interval-serializer", "Test Framework", intervalserializer.NewIntervalSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("tracked-resources-serializer", "Test Framework", trackedresourcesserializer.NewTrackedResourcesSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("cluster-info-serializer", "Test Framework", clusterinfoserializer.NewClusterInfoSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("disruption-summary-serializer", "Test Framework", disruptionserializer.NewDisruptionSummarySerializer())
-
-	// Alerts — flaked for spot-check sensitivity
-	monitorTestRegistry.AddMonitorTestOrDie("alert-summary-serializer", "Test Framework", alertanalyzer.NewAlertSummarySerializer())
-	monitorTestRegistry.AddMonitorTestOrDie(legacytestframeworkmonitortests.AlertsMonitorName, "Test Framework", legacytestframeworkmonitortests.NewLegacyAlertsMonitorTests(info, monitortestframework.AsFlake))
-
-	// e2e test analyzer — records which tests ran
-	monitorTestRegistry.AddMonitorTestOrDie("e2e-test-analyzer", "Test Framework", e2etestanalyzer.NewAnalyzer())
+	monitorTestRegistry.AddMonitorTestWithMetadataOrDie("oc-adm-upgrade-status", "oc / update", stableOnly, admupgradestatus.NewOcAdmUpgradeStatusChecker())
 
 	return monitorTestRegistry
 }
@@ -5,10 +5,13 @@ import (
 	"container/list"
 	"context"
 	"crypto/tls"
+	"encoding/csv"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/http/httptrace"
+	"os"
 	"regexp"
 	"sync"
 	"time"
@@ -81,6 +84,16 @@ type BackendSampler struct {
 	consumptionFinished chan struct{}
 
 	samplerHooks []SamplerHook
+
+	// phaseTimingLock protects the sample file and brownout detector, which the consumer feeds in sample order.
+	phaseTimingLock sync.Mutex
+	// keepPhaseTimings is set for samplers whose owner calls WritePhaseTimings, only they get a sample file.
+	keepPhaseTimings bool
+	// phaseTimingFile is a temporary file holding the sample rows until WritePhaseTimings moves them to storage.
+	phaseTimingFile    *os.File
+	phaseTimingWriter  *csv.Writer
+	phaseTimingErr     error
+	latencyDegradation *latencyDegradationDetector
 }
 
 type routeCoordinates struct {
@@ -237,6 +250,13 @@ func (b *BackendSampler) WithTimeout(timeout time.Duration) *BackendSampler {
 	return b
 }
 
+// WithPhaseTimingsFile keeps the phase timings of every sample in a temporary file until WritePhaseTimings moves them
+// to storage.  Only set it when WritePhaseTimings is going to be called, otherwise the file is left behind.
+func (b *BackendSampler) WithPhaseTimingsFile() *BackendSampler {
+	b.keepPhaseTimings = true
+	return b
+}
+
 // bodyMatches checks the body content and returns an error if it doesn't match the expected.
 func (b *BackendSampler) bodyMatches(body []byte) error {
 	switch {
@@ -308,10 +328,11 @@ func (b *BackendSampler) GetHTTPClient() (*http.Client, error) {
 		switch b.GetConnectionType() {
 		case monitorapi.NewConnectionType:
 			httpTransport = &http.Transport{
-				Dial: (&net.Dialer{
+				// DialContext rather than Dial so the DNS and connect phases show up in the request trace.
+				DialContext: (&net.Dialer{
 					Timeout:   timeoutForPartOfRequest,
 					KeepAlive: -1, // this looks unnecessary to me, but it was set in other code.
-				}).Dial,
+				}).DialContext,
 				TLSClientConfig:       b.getTLSConfig(),
 				DisableKeepAlives:     true, // this prevents connections from being reused
 				TLSHandshakeTimeout:   timeoutForPartOfRequest,
@@ -323,9 +344,9 @@ func (b *BackendSampler) GetHTTPClient() (*http.Client, error) {
 
 		case monitorapi.ReusedConnectionType:
 			httpTransport = &http.Transport{
-				Dial: (&net.Dialer{
+				DialContext: (&net.Dialer{
 					Timeout: timeoutForPartOfRequest,
-				}).Dial,
+				}).DialContext,
 				TLSClientConfig:       b.getTLSConfig(),
 				TLSHandshakeTimeout:   timeoutForPartOfRequest,
 				IdleConnTimeout:       timeoutForPartOfRequest,
@@ -364,23 +385,30 @@ func (b *BackendSampler) GetHTTPClient() (*http.Client, error) {
 
 // CheckConnnection returns the audit request UID and an error if there was one.
 func (b *BackendSampler) CheckConnection(ctx context.Context) (string, error) {
+	uid, _, err := b.checkConnection(ctx)
+	return uid, err
+}
+
+// checkConnection is CheckConnection that also returns how long each phase of the request took.
+func (b *BackendSampler) checkConnection(ctx context.Context) (string, PhaseTimings, error) {
 	httpClient, err := b.GetHTTPClient()
 	if err != nil {
-		return "", err
+		return "", PhaseTimings{}, err
 	}
 
 	url, err := b.GetURL()
 	if err != nil {
-		return "", err
+		return "", PhaseTimings{}, err
 	}
 
 	// this is longer than the http client timeout to avoid tripping, but is here to be sure we finish eventually
 	backstopContextTimeout := b.getTimeout() * 3 / 2 // (1.5)
 	requestContext, requestCancel := context.WithTimeout(ctx, backstopContextTimeout)
 	defer requestCancel()
-	req, err := http.NewRequestWithContext(requestContext, http.MethodGet, url, nil)
+	trace := newPhaseTimingTrace(time.Now())
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(requestContext, trace.clientTrace()), http.MethodGet, url, nil)
 	if err != nil {
-		return "", err
+		return "", PhaseTimings{}, err
 	}
 
 	uid := uuid.New().String()
@@ -389,7 +417,7 @@ func (b *BackendSampler) CheckConnection(ctx context.Context) (string, error) {
 	resp, getErr := httpClient.Do(req)
 	if requestContext.Err() == context.Canceled {
 		// this isn't an error, we were simply cancelled
-		return uid, nil
+		return uid, trace.finish(time.Now()), nil
 	}
 
 	var body []byte
@@ -400,6 +428,7 @@ func (b *BackendSampler) CheckConnection(ctx context.Context) (string, error) {
 			framework.Logf("error closing body: %v: %v", b.GetLocator(), closeErr)
 		}
 	}
+	timings := trace.finish(time.Now())
 
 	// we don't have an error, but the response code was an error, then we have to set an artificial error for the logic below to work.
 	switch {
@@ -417,7 +446,7 @@ func (b *BackendSampler) CheckConnection(ctx context.Context) (string, error) {
 		}
 	}
 
-	return uid, sampleErr
+	return uid, timings, sampleErr
 }
 
 // RunEndpointMonitoring sets up a client for the given BackendSampler, starts checking the endpoint, and recording
@@ -543,9 +572,10 @@ func (b *disruptionSampler) produceSamples(ctx context.Context, interval time.Du
 		// was actually 30s before.
 		currDisruptionSample := b.newSample(ctx)
 		go func() {
-			uid, sampleErr := b.backendSampler.CheckConnection(ctx)
+			uid, timings, sampleErr := b.backendSampler.checkConnection(ctx)
 			currDisruptionSample.setSampleError(sampleErr)
 			currDisruptionSample.setRequestAuditID(uid)
+			currDisruptionSample.setPhaseTimings(timings)
 			if sampleErr != nil {
 				// We'd like to include these UUIDs in the backend-disruption.json file but this is
 				// not possible without some work as we're basing everything off intervals today. There is
@@ -558,6 +588,7 @@ func (b *disruptionSampler) produceSamples(ctx context.Context, interval time.Du
 					"backend":       b.backendSampler.GetDisruptionBackendName(),
 					"type":          b.backendSampler.connectionType,
 					"auditID":       uid,
+					"timings":       timings.String(),
 				}).Errorf("disruption sample failed: %v", sampleErr)
 			}
 			close(currDisruptionSample.finished)
@@ -587,6 +618,10 @@ func (b *disruptionSampler) consumeSamples(ctx context.Context, consumerDoneCh c
 			monitorRecorder.EndInterval(previousIntervalID, previousSampleTime.Add(interval))
 		}
 	}()
+	// brownouts still in progress are closed with the last sample.
+	defer func() {
+		monitorRecorder.AddIntervals(b.backendSampler.finishLatencyDegradation()...)
+	}()
 
 	for {
 		select {
@@ -614,6 +649,11 @@ func (b *disruptionSampler) consumeSamples(ctx context.Context, consumerDoneCh c
 
 		previouslyAvailable := previousError == nil
 		currentError := currSample.getSampleError()
+		b.backendSampler.recordPhaseTimings(phaseTimingSample{
+			startTime: currSample.startTime,
+			failed:    currentError != nil,
+			timings:   currSample.getPhaseTimings(),
+		})
 		currentlyAvailable := currentError == nil
 		currSampleTime := currSample.startTime
 
@@ -727,6 +767,7 @@ type disruptionSample struct {
 	startTime      time.Time
 	sampleErr      error
 	requestAuditID string
+	phaseTimings   PhaseTimings
 
 	finished chan struct{}
 }
@@ -761,3 +802,15 @@ func (s *disruptionSample) getRequestAuditID() string {
 	defer s.lock.Unlock()
 	return s.requestAuditID
 }
+
+func (s *disruptionSample) setPhaseTimings(phaseTimings PhaseTimings) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.phaseTimings = phaseTimings
+}
+
+func (s *disruptionSample) getPhaseTimings() PhaseTimings {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.phaseTimings
+}
@@ -0,0 +1,319 @@
+package backenddisruption
+
+import (
+	"crypto/tls"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"net/http/httptrace"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const (
+	// latencyDegradationThreshold and latencyDegradationWindow describe a brownout: the P95 of successful samples
+	// in a window stays over the threshold for at least another window.
+	latencyDegradationThreshold = 1 * time.Second
+	latencyDegradationWindow    = 30 * time.Second
+)
+
+// PhaseTimings are how long each phase of a sample request took.  Phases that did not happen, like DNS and connect
+// on a reused connection, are zero.  TimeToFirstByte and Total are measured from the start of the request.
+type PhaseTimings struct {
+	DNS             time.Duration
+	Connect         time.Duration
+	TLSHandshake    time.Duration
+	TimeToFirstByte time.Duration
+	Total           time.Duration
+}
+
+func (t PhaseTimings) String() string {
+	parts := []string{
+		fmt.Sprintf("dns=%s", t.DNS.Round(time.Millisecond)),
+		fmt.Sprintf("connect=%s", t.Connect.Round(time.Millisecond)),
+		fmt.Sprintf("tls=%s", t.TLSHandshake.Round(time.Millisecond)),
+		fmt.Sprintf("ttfb=%s", t.TimeToFirstByte.Round(time.Millisecond)),
+		fmt.Sprintf("total=%s", t.Total.Round(time.Millisecond)),
+	}
+	return strings.Join(parts, " ")
+}
+
+// phaseTimingSample is the timing of a single sample as it is passed to the sample file and latency intervals.
+type phaseTimingSample struct {
+	startTime time.Time
+	failed    bool
+	timings   PhaseTimings
+}
+
+// phaseTimingTrace collects PhaseTimings from the httptrace callbacks of one request.  The callbacks can be called
+// from transport goroutines, so everything is behind the lock.
+type phaseTimingTrace struct {
+	lock sync.Mutex
+
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	timings      PhaseTimings
+}
+
+func newPhaseTimingTrace(start time.Time) *phaseTimingTrace {
+	return &phaseTimingTrace{start: start}
+}
+
+func (t *phaseTimingTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			t.timings.DNS = time.Since(t.dnsStart)
+		},
+		ConnectStart: func(network, addr string) {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			// with multiple addresses the dialer races connections, the first attempt is when we started waiting.
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(network, addr string, err error) {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			if err == nil {
+				t.timings.Connect = time.Since(t.connectStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			t.timings.TLSHandshake = time.Since(t.tlsStart)
+		},
+		GotFirstResponseByte: func() {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			t.timings.TimeToFirstByte = time.Since(t.start)
+		},
+	}
+}
+
+// finish records the total duration of the request and returns the timings.
+func (t *phaseTimingTrace) finish(end time.Time) PhaseTimings {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.timings.Total = end.Sub(t.start)
+	return t.timings
+}
+
+// recordPhaseTimings is called by the consumer in sample order.  Samples are fed to the brownout detector and, when
+// the sampler keeps its phase timings, appended to a temporary sample file, so nothing grows with the length of the
+// run.
+func (b *BackendSampler) recordPhaseTimings(sample phaseTimingSample) {
+	b.phaseTimingLock.Lock()
+	defer b.phaseTimingLock.Unlock()
+
+	if b.latencyDegradation == nil {
+		b.latencyDegradation = newLatencyDegradationDetector(b.GetLocator(), b.GetConnectionType(), latencyDegradationThreshold, latencyDegradationWindow)
+	}
+	b.latencyDegradation.add(sample)
+
+	if !b.keepPhaseTimings || b.phaseTimingErr != nil {
+		return
+	}
+	if b.phaseTimingFile == nil {
+		b.phaseTimingFile, b.phaseTimingErr = os.CreateTemp("", "disruption-phase-timings-*.csv")
+		if b.phaseTimingErr != nil {
+			return
+		}
+		b.phaseTimingWriter = csv.NewWriter(b.phaseTimingFile)
+		b.phaseTimingErr = b.phaseTimingWriter.Write([]string{"start_ms", "failed", "dns_ms", "connect_ms", "tls_ms", "ttfb_ms", "total_ms"})
+		if b.phaseTimingErr != nil {
+			return
+		}
+	}
+	b.phaseTimingErr = b.phaseTimingWriter.Write([]string{
+		strconv.FormatInt(sample.startTime.UnixMilli(), 10),
+		strconv.FormatBool(sample.failed),
+		strconv.FormatInt(sample.timings.DNS.Milliseconds(), 10),
+		strconv.FormatInt(sample.timings.Connect.Milliseconds(), 10),
+		strconv.FormatInt(sample.timings.TLSHandshake.Milliseconds(), 10),
+		strconv.FormatInt(sample.timings.TimeToFirstByte.Milliseconds(), 10),
+		strconv.FormatInt(sample.timings.Total.Milliseconds(), 10),
+	})
+}
+
+// finishLatencyDegradation returns the brownouts found in the samples recorded so far, closing one still in progress.
+func (b *BackendSampler) finishLatencyDegradation() monitorapi.Intervals {
+	b.phaseTimingLock.Lock()
+	defer b.phaseTimingLock.Unlock()
+	if b.latencyDegradation == nil {
+		return nil
+	}
+	return b.latencyDegradation.finish()
+}
+
+// WritePhaseTimings moves the phase timings of every sample recorded so far into a CSV file in storageDir, one row per
+// sample with the start time in unix milliseconds and the phases in milliseconds.  Nothing is written if there were
+// no samples or the sampler was not created WithPhaseTimingsFile.
+func (b *BackendSampler) WritePhaseTimings(storageDir, timeSuffix string) error {
+	b.phaseTimingLock.Lock()
+	defer b.phaseTimingLock.Unlock()
+
+	if b.phaseTimingFile == nil {
+		return b.phaseTimingErr
+	}
+	tempFile, tempWriter := b.phaseTimingFile, b.phaseTimingWriter
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+	b.phaseTimingFile, b.phaseTimingWriter = nil, nil
+	if b.phaseTimingErr != nil {
+		return b.phaseTimingErr
+	}
+	tempWriter.Flush()
+	if err := tempWriter.Error(); err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("disruption-phase-timings_%s_%s%s.csv", b.GetDisruptionBackendName(), b.GetConnectionType(), timeSuffix)
+	f, err := os.Create(filepath.Join(storageDir, filename))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := tempFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, tempFile); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// latencyDegradationDetector finds the brownouts in a sequence of samples ordered by start time.  Every successful
+// sample is an evaluation: it breaches when the P95 total latency of the successful samples in the window ending at
+// it is over the threshold.  Failed samples are left out because they are already disruption, and evaluations only
+// start once we have sampled for a full window.  A brownout is reported when breaching evaluations follow each other
+// for at least the window, it starts at the first breaching evaluation and ends with the last.  Only the samples of
+// the current window are kept.
+type latencyDegradationDetector struct {
+	locator        monitorapi.Locator
+	connectionType monitorapi.BackendConnectionType
+	threshold      time.Duration
+	window         time.Duration
+
+	firstSample time.Time
+	// windowSamples are the successful samples in the window ending at the latest one.
+	windowSamples []phaseTimingSample
+
+	breachFrom, lastBreach, breachTo time.Time
+	worst                            time.Duration
+
+	intervals monitorapi.Intervals
+}
+
+func newLatencyDegradationDetector(locator monitorapi.Locator, connectionType monitorapi.BackendConnectionType, threshold, window time.Duration) *latencyDegradationDetector {
+	return &latencyDegradationDetector{
+		locator:        locator,
+		connectionType: connectionType,
+		threshold:      threshold,
+		window:         window,
+		intervals:      monitorapi.Intervals{},
+	}
+}
+
+func (d *latencyDegradationDetector) add(sample phaseTimingSample) {
+	if sample.failed {
+		return
+	}
+	if d.firstSample.IsZero() {
+		d.firstSample = sample.startTime
+	}
+	d.windowSamples = append(d.windowSamples, sample)
+	windowStart := 0
+	for d.windowSamples[windowStart].startTime.Before(sample.startTime.Add(-d.window)) {
+		windowStart++
+	}
+	d.windowSamples = append(d.windowSamples[:0], d.windowSamples[windowStart:]...)
+	if sample.startTime.Sub(d.firstSample) < d.window {
+		return
+	}
+
+	p95 := percentileTotal(d.windowSamples, 0.95)
+	if p95 <= d.threshold {
+		d.endBreach()
+		return
+	}
+	if d.breachFrom.IsZero() {
+		d.breachFrom = sample.startTime
+	}
+	d.lastBreach = sample.startTime
+	d.breachTo = sample.startTime.Add(sample.timings.Total)
+	if p95 > d.worst {
+		d.worst = p95
+	}
+}
+
+// endBreach reports the current run of breaching evaluations if it lasted for the window.
+func (d *latencyDegradationDetector) endBreach() {
+	if d.breachFrom.IsZero() {
+		return
+	}
+	if d.lastBreach.Sub(d.breachFrom) >= d.window {
+		d.intervals = append(d.intervals, monitorapi.NewInterval(monitorapi.SourceDisruptionLatency, monitorapi.Warning).
+			Locator(d.locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionLatencyDegradedEventReason).
+				HumanMessagef("P95 latency for %s connections was over %s for %s, peaking at %s",
+					d.connectionType, d.threshold, d.breachTo.Sub(d.breachFrom).Round(time.Second), d.worst.Round(time.Millisecond))).
+			Display().
+			Build(d.breachFrom, d.breachTo))
+	}
+	d.breachFrom, d.lastBreach, d.breachTo, d.worst = time.Time{}, time.Time{}, time.Time{}, 0
+}
+
+func (d *latencyDegradationDetector) finish() monitorapi.Intervals {
+	d.endBreach()
+	ret := d.intervals
+	d.intervals = monitorapi.Intervals{}
+	return ret
+}
+
+// latencyDegradationIntervals runs the samples through a latencyDegradationDetector.
+func latencyDegradationIntervals(locator monitorapi.Locator, connectionType monitorapi.BackendConnectionType, samples []phaseTimingSample, threshold, window time.Duration) monitorapi.Intervals {
+	detector := newLatencyDegradationDetector(locator, connectionType, threshold, window)
+	for _, sample := range samples {
+		detector.add(sample)
+	}
+	return detector.finish()
+}
+
+// percentileTotal is the nearest-rank percentile of the total latency of the samples.
+func percentileTotal(samples []phaseTimingSample, percentile float64) time.Duration {
+	totals := make([]time.Duration, 0, len(samples))
+	for _, sample := range samples {
+		totals = append(totals, sample.timings.Total)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i] < totals[j] })
+	rank := int(math.Ceil(percentile*float64(len(totals)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return totals[rank]
+}
@@ -0,0 +1,157 @@
+package backenddisruption
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestBackendSampler_checkConnectionPhaseTimings(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(200)
+		w.Write([]byte("200"))
+	}))
+	defer testServer.Close()
+
+	backend := NewSimpleBackendFromOpenshiftTests(testServer.URL, "phases", "/", monitorapi.NewConnectionType)
+	_, timings, err := backend.checkConnection(context.Background())
+	require.NoError(t, err)
+
+	assert.Greater(t, timings.Connect, time.Duration(0), "new connections dial")
+	assert.Greater(t, timings.TLSHandshake, time.Duration(0), "new connections handshake")
+	assert.GreaterOrEqual(t, timings.TimeToFirstByte, 50*time.Millisecond, "the server waits before answering")
+	assert.GreaterOrEqual(t, timings.Total, timings.TimeToFirstByte)
+}
+
+func samplesWithTotals(start time.Time, totals ...time.Duration) []phaseTimingSample {
+	ret := []phaseTimingSample{}
+	for i, total := range totals {
+		ret = append(ret, phaseTimingSample{
+			startTime: start.Add(time.Duration(i) * time.Second),
+			timings:   PhaseTimings{Total: total},
+		})
+	}
+	return ret
+}
+
+func repeatDuration(d time.Duration, count int) []time.Duration {
+	ret := []time.Duration{}
+	for i := 0; i < count; i++ {
+		ret = append(ret, d)
+	}
+	return ret
+}
+
+func Test_latencyDegradationIntervals(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	locator := monitorapi.NewLocator().LocateDisruptionCheck("kube-api-new-connections", OpenshiftTestsSource, monitorapi.NewConnectionType)
+	fast, slow := 50*time.Millisecond, 2*time.Second
+
+	tests := []struct {
+		name     string
+		samples  []phaseTimingSample
+		expected []string
+		// from is the start of the first brownout, the first evaluation whose window is over the threshold
+		from time.Time
+	}{
+		{
+			name:    "fast",
+			samples: samplesWithTotals(start, repeatDuration(fast, 120)...),
+		},
+		{
+			name:    "one slow sample is not a brownout",
+			samples: samplesWithTotals(start, append(append(repeatDuration(fast, 60), slow), repeatDuration(fast, 60)...)...),
+		},
+		{
+			name:    "a short burst of slow samples is not a brownout",
+			samples: samplesWithTotals(start, append(append(repeatDuration(fast, 60), slow, slow), repeatDuration(fast, 60)...)...),
+		},
+		{
+			name:     "sustained slow samples",
+			samples:  samplesWithTotals(start, append(append(repeatDuration(fast, 60), repeatDuration(slow, 40)...), repeatDuration(fast, 60)...)...),
+			expected: []string{"P95 latency for new connections was over 1s"},
+			// the second slow sample puts the P95 of the 31 samples in the window over the threshold
+			from: start.Add(61 * time.Second),
+		},
+		{
+			name: "failed samples are disruption, not latency",
+			samples: func() []phaseTimingSample {
+				samples := samplesWithTotals(start, append(append(repeatDuration(fast, 60), repeatDuration(slow, 40)...), repeatDuration(fast, 60)...)...)
+				for i := 60; i < 100; i++ {
+					samples[i].failed = true
+				}
+				return samples
+			}(),
+		},
+		{
+			name:     "two brownouts",
+			samples:  samplesWithTotals(start, append(append(append(append(repeatDuration(fast, 60), repeatDuration(slow, 40)...), repeatDuration(fast, 60)...), repeatDuration(slow, 40)...), repeatDuration(fast, 60)...)...),
+			expected: []string{"P95 latency for new connections was over 1s", "P95 latency for new connections was over 1s"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := latencyDegradationIntervals(locator, monitorapi.NewConnectionType, tt.samples, latencyDegradationThreshold, latencyDegradationWindow)
+			require.Len(t, actual, len(tt.expected))
+			if !tt.from.IsZero() {
+				assert.Equal(t, tt.from, actual[0].From)
+			}
+			for i := range actual {
+				assert.Contains(t, actual[i].Message.HumanMessage, tt.expected[i])
+				assert.Equal(t, monitorapi.SourceDisruptionLatency, actual[i].Source)
+				assert.Equal(t, monitorapi.DisruptionLatencyDegradedEventReason, actual[i].Message.Reason)
+				assert.Equal(t, monitorapi.Warning, actual[i].Level)
+				assert.GreaterOrEqual(t, actual[i].To.Sub(actual[i].From), latencyDegradationWindow)
+			}
+		})
+	}
+}
+
+func TestBackendSampler_WritePhaseTimings(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	storageDir := t.TempDir()
+	sample := phaseTimingSample{startTime: start, timings: PhaseTimings{Total: 120 * time.Millisecond}}
+
+	unkept := NewSimpleBackendFromOpenshiftTests("https://example.com", "unkept", "/", monitorapi.ReusedConnectionType)
+	unkept.recordPhaseTimings(sample)
+	assert.Nil(t, unkept.phaseTimingFile, "no sample file without WithPhaseTimingsFile")
+	require.NoError(t, unkept.WritePhaseTimings(storageDir, "_suffix"))
+
+	backend := NewSimpleBackendFromOpenshiftTests("https://example.com", "phases", "/", monitorapi.ReusedConnectionType).WithPhaseTimingsFile()
+	require.NoError(t, backend.WritePhaseTimings(storageDir, "_suffix"))
+	entries, err := os.ReadDir(storageDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "no samples, no file")
+
+	backend.recordPhaseTimings(phaseTimingSample{
+		startTime: start,
+		timings: PhaseTimings{
+			DNS:             5 * time.Millisecond,
+			Connect:         10 * time.Millisecond,
+			TLSHandshake:    20 * time.Millisecond,
+			TimeToFirstByte: 100 * time.Millisecond,
+			Total:           120 * time.Millisecond,
+		},
+	})
+	backend.recordPhaseTimings(phaseTimingSample{startTime: start.Add(time.Second), failed: true, timings: PhaseTimings{Total: 15 * time.Second}})
+	require.NoError(t, backend.WritePhaseTimings(storageDir, "_suffix"))
+
+	content, err := os.ReadFile(filepath.Join(storageDir, "disruption-phase-timings_phases_reused_suffix.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"start_ms,failed,dns_ms,connect_ms,tls_ms,ttfb_ms,total_ms",
+		"1714557600000,false,5,10,20,100,120",
+		"1714557601000,true,0,0,0,0,15000",
+	}, strings.Split(strings.TrimSpace(string(content)), "\n"))
+}
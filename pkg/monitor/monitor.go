@@ -168,6 +168,10 @@ func (m *Monitor) Stop(ctx context.Context) (ResultState, error) {
 	return resultState, nil
 }
 
+func (m *Monitor) MonitorTestPhaseTimings() []monitortestframework.MonitorTestPhaseTiming {
+	return m.monitorTestRegistry.PhaseTimings()
+}
+
 func (m *Monitor) SerializeResults(ctx context.Context, junitSuiteName, timeSuffix string) (*junitapi.JUnitTestSuite, error) {
 	fmt.Fprintf(os.Stderr, "Serializing results.\n")
 	m.lock.Lock()
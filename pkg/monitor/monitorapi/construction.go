@@ -529,6 +529,16 @@ func (b *LocatorBuilder) PodFromPod(pod *corev1.Pod) Locator {
 	return b.Build()
 }
 
+// PodDisplacement locates the pod that replaced a deleted pod, along with the workload that owns both.
+func (b *LocatorBuilder) PodDisplacement(namespace, ownerKind, ownerName, podName, nodeName string) Locator {
+	b.PodFromNames(namespace, podName, "")
+	if len(nodeName) > 0 {
+		b.annotations[LocatorNodeKey] = nodeName
+	}
+	b.annotations[LocatorOwnerKey] = fmt.Sprintf("%s/%s", ownerKind, ownerName)
+	return b.Build()
+}
+
 func (b *LocatorBuilder) E2ETest(testName string) Locator {
 	b.targetType = LocatorTypeE2ETest
 	b.annotations[LocatorE2ETestKey] = testName
@@ -547,6 +557,94 @@ func (b *LocatorBuilder) TestBucket(bucketName string) Locator {
 	return b.Build()
 }
 
+// EtcdStorage locates what etcd stored during a test bucket, the whole database when resource is empty.
+func (b *LocatorBuilder) EtcdStorage(bucketName, resource string) Locator {
+	b.targetType = LocatorTypeEtcdStorage
+	if len(bucketName) > 0 {
+		b.annotations[LocatorTestBucketKey] = bucketName
+	}
+	if len(resource) > 0 {
+		b.annotations[LocatorResourceKey] = resource
+	}
+	return b.Build()
+}
+
+// CloudAPI locates the calls a cluster component made to the API of a cloud provider.
+func (b *LocatorBuilder) CloudAPI(provider, component string) Locator {
+	b.targetType = LocatorTypeCloudAPI
+	b.annotations[LocatorCloudProviderKey] = provider
+	b.annotations[LocatorCloudComponentKey] = component
+	return b.Build()
+}
+
+// StabilityGate locates the wait for the cluster to become stable before tests start, or the wait for one of its
+// criteria when criterion is set.
+func (b *LocatorBuilder) StabilityGate(criterion string) Locator {
+	b.targetType = LocatorTypeStabilityGate
+	if len(criterion) > 0 {
+		b.annotations[LocatorStabilityCriterionKey] = criterion
+	}
+	return b.Build()
+}
+
+func (b *LocatorBuilder) MachineConfigPool(name string) Locator {
+	b.targetType = LocatorTypeMachineConfigPool
+	b.annotations[LocatorMachineConfigPoolKey] = name
+	return b.Build()
+}
+
+func (b *LocatorBuilder) PersistentVolumeClaim(namespace, name string) Locator {
+	b.targetType = LocatorTypePersistentVolumeClaim
+	b.annotations[LocatorNamespaceKey] = namespace
+	b.annotations[LocatorPersistentVolumeClaimKey] = name
+	return b.Build()
+}
+
+// VolumeAttachment locates the attachment of a persistent volume to a node. Inline volumes have no persistent volume.
+func (b *LocatorBuilder) VolumeAttachment(name, persistentVolume, nodeName string) Locator {
+	b.targetType = LocatorTypeVolumeAttachment
+	b.annotations[LocatorVolumeAttachmentKey] = name
+	if len(persistentVolume) > 0 {
+		b.annotations[LocatorPersistentVolumeKey] = persistentVolume
+	}
+	return b.withNode(nodeName).Build()
+}
+
+func (b *LocatorBuilder) IngressController(name string) Locator {
+	b.targetType = LocatorTypeIngressController
+	b.annotations[LocatorIngressControllerKey] = name
+	return b.Build()
+}
+
+func (b *LocatorBuilder) Route(namespace, name string) Locator {
+	return b.
+		withTargetType(LocatorTypeRoute).
+		withNamespace(namespace).
+		withRoute(name).
+		Build()
+}
+
+func (b *LocatorBuilder) ClusterServiceVersion(namespace, name string) Locator {
+	b.targetType = LocatorTypeClusterServiceVersion
+	b.annotations[LocatorNamespaceKey] = namespace
+	b.annotations[LocatorClusterServiceVersionKey] = name
+	return b.Build()
+}
+
+func (b *LocatorBuilder) InstallPlan(namespace, name string) Locator {
+	b.targetType = LocatorTypeInstallPlan
+	b.annotations[LocatorNamespaceKey] = namespace
+	b.annotations[LocatorInstallPlanKey] = name
+	return b.Build()
+}
+
+func (b *LocatorBuilder) CatalogSource(namespace, name string) Locator {
+	b.targetType = LocatorTypeCatalogSource
+	b.annotations[LocatorNamespaceKey] = namespace
+	b.annotations[LocatorCatalogSourceKey] = name
+	return b.Build()
+}
+
 func (b *LocatorBuilder) Build() Locator {
 	ret := Locator{
 		Type: b.targetType,
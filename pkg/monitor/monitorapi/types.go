@@ -48,6 +48,10 @@ const (
 	// time a resource has been recreated.  The internal cache doesn't remove an entry on delete.
 	// This is useful during post-processing for determining if we have a hot resource.
 	ObservedRecreationCountAnnotation = "monitor.openshift.io/observed-recreation-count"
+
+	// UpgradeHopEventAnnotation is set by the upgrade test on the events it records for each hop of an upgrade.  The
+	// event watcher copies it onto the interval message as AnnotationUpgradeHop.
+	UpgradeHopEventAnnotation = "monitor.openshift.io/upgrade-hop"
 )
 
 type IntervalLevel int
@@ -119,6 +123,24 @@ const (
 	LocatorTypeKubeletSyncLoopPLEG  LocatorType = "KubeletSyncLoopPLEG"
 	LocatorTypeStaticPodInstall     LocatorType = "StaticPodInstall"
 	LocatorTypeTestBucket           LocatorType = "TestBucket"
+
+	LocatorTypeStabilityGate LocatorType = "StabilityGate"
+
+	LocatorTypeMachineConfigPool LocatorType = "MachineConfigPool"
+
+	LocatorTypePersistentVolumeClaim LocatorType = "PersistentVolumeClaim"
+	LocatorTypeVolumeAttachment      LocatorType = "VolumeAttachment"
+
+	LocatorTypeIngressController LocatorType = "IngressController"
+	LocatorTypeRoute             LocatorType = "Route"
+
+	LocatorTypeClusterServiceVersion LocatorType = "ClusterServiceVersion"
+	LocatorTypeInstallPlan           LocatorType = "InstallPlan"
+	LocatorTypeCatalogSource         LocatorType = "CatalogSource"
+
+	LocatorTypeEtcdStorage LocatorType = "EtcdStorage"
+
+	LocatorTypeCloudAPI LocatorType = "CloudAPI"
 )
 
 type LocatorKey string
@@ -166,6 +188,29 @@ const (
 	LocatorTypeKubeletSyncLoopPLEGType  LocatorKey = "plegType"
 	LocatorStaticPodInstallType         LocatorKey = "podType"
 	LocatorTestBucketKey                LocatorKey = "test-bucket"
+	LocatorOwnerKey                     LocatorKey = "owner"
+
+	LocatorStabilityCriterionKey LocatorKey = "stability-criterion"
+
+	LocatorMachineConfigPoolKey LocatorKey = "machineconfigpool"
+
+	LocatorPersistentVolumeClaimKey LocatorKey = "persistentvolumeclaim"
+	LocatorPersistentVolumeKey      LocatorKey = "persistentvolume"
+	LocatorVolumeAttachmentKey      LocatorKey = "volumeattachment"
+
+	LocatorIngressControllerKey LocatorKey = "ingresscontroller"
+
+	LocatorClusterServiceVersionKey LocatorKey = "clusterserviceversion"
+	LocatorInstallPlanKey           LocatorKey = "installplan"
+	LocatorCatalogSourceKey         LocatorKey = "catalogsource"
+
+	// LocatorResourceKey is the resource stored in etcd, as labeled by apiserver_storage_objects.
+	LocatorResourceKey LocatorKey = "resource"
+
+	// LocatorCloudProviderKey is the cloud whose API was called, LocatorCloudComponentKey is the cluster component
+	// calling it.
+	LocatorCloudProviderKey  LocatorKey = "provider"
+	LocatorCloudComponentKey LocatorKey = "component"
 )
 
 type Locator struct {
@@ -183,6 +228,8 @@ const (
 	DisruptionBeganEventReason              IntervalReason = "DisruptionBegan"
 	DisruptionEndedEventReason              IntervalReason = "DisruptionEnded"
 	DisruptionSamplerOutageBeganEventReason IntervalReason = "DisruptionSamplerOutageBegan"
+	DisruptionLatencyDegradedEventReason    IntervalReason = "DisruptionLatencyDegraded"
+	DisruptionCauseAttributedReason         IntervalReason = "DisruptionCauseAttributed"
 	GracefulAPIServerShutdown               IntervalReason = "GracefulAPIServerShutdown"
 	IncompleteAPIServerShutdown             IntervalReason = "IncompleteAPIServerShutdown"
 
@@ -223,13 +270,90 @@ const (
 	NodeUnreachable                 IntervalReason = "Unreachable"
 	// Kubelet tries to get lease five times and then gives up
 	NodeFailedLeaseBackoff IntervalReason = "FailedToUpdateLeaseInBackoff"
-	NodeDiskPressure       IntervalReason = "NodeDiskPressure"
-	NodeNoDiskPressure     IntervalReason = "NodeNoDiskPressure"
-	NodeDeleted            IntervalReason = "Deleted"
+	// The pod lifecycle event generator of the kubelet was not seen active for longer than its threshold
+	NodePLEGUnhealthyReason IntervalReason = "PLEGUnhealthy"
+	// The signals a node had over a span of time, and the health score they add up to
+	NodeHealthDegradedReason IntervalReason = "NodeHealthDegraded"
+	NodeDiskPressure         IntervalReason = "NodeDiskPressure"
+	NodeNoDiskPressure       IntervalReason = "NodeNoDiskPressure"
+	NodeDeleted              IntervalReason = "Deleted"
+
+	// Node and container resource pressure observed in metrics, each one an interval spanning the samples beyond the
+	// threshold of the collector.
+	HighMemoryWorkingSetReason IntervalReason = "HighMemoryWorkingSet"
+	LowMemoryAvailableReason   IntervalReason = "LowMemoryAvailable"
+	HighDiskUsageReason        IntervalReason = "HighDiskUsage"
+	HighInodeUsageReason       IntervalReason = "HighInodeUsage"
+	ContainerOOMKilledReason   IntervalReason = "ContainerOOMKilled"
+
+	// The points in time at which a claim or a VolumeAttachment changed, and the attach, detach, mount and unmount
+	// intervals constructed from them.
+	PersistentVolumeClaimPhaseChangedReason IntervalReason = "PersistentVolumeClaimPhaseChanged"
+	PersistentVolumeClaimPendingReason      IntervalReason = "PersistentVolumeClaimPending"
+	VolumeAttachRequestedReason             IntervalReason = "VolumeAttachRequested"
+	VolumeAttachedReason                    IntervalReason = "VolumeAttached"
+	VolumeAttachErrorReason                 IntervalReason = "VolumeAttachError"
+	VolumeDetachRequestedReason             IntervalReason = "VolumeDetachRequested"
+	VolumeDetachedReason                    IntervalReason = "VolumeDetached"
+	VolumeDetachErrorReason                 IntervalReason = "VolumeDetachError"
+	VolumeAttachingReason                   IntervalReason = "VolumeAttaching"
+	VolumeDetachingReason                   IntervalReason = "VolumeDetaching"
+	VolumeMountingReason                    IntervalReason = "VolumeMounting"
+	VolumeUnmountingReason                  IntervalReason = "VolumeUnmounting"
+
+	// IngressController conditions, router deployment rollouts, haproxy reloads and route admission as observed by the
+	// ingress health watcher, and the periods constructed from them.
+	IngressControllerConditionChangedReason IntervalReason = "IngressControllerConditionChanged"
+	IngressControllerDegradedReason         IntervalReason = "IngressControllerDegraded"
+	IngressControllerProgressingReason      IntervalReason = "IngressControllerProgressing"
+	RouterRolloutStartedReason              IntervalReason = "RouterRolloutStarted"
+	RouterRolloutCompletedReason            IntervalReason = "RouterRolloutCompleted"
+	RouterRolloutReason                     IntervalReason = "RouterRollout"
+	RouterReloadedReason                    IntervalReason = "RouterReloaded"
+	RouteCreatedReason                      IntervalReason = "RouteCreated"
+	RouteAdmittedReason                     IntervalReason = "RouteAdmitted"
+	RouteRejectedReason                     IntervalReason = "RouteRejected"
+	RouteDeletedReason                      IntervalReason = "RouteDeleted"
+	RouteAdmissionReason                    IntervalReason = "RouteAdmission"
+
+	// ClusterServiceVersion and InstallPlan phases and catalog source connection states as observed by the operator
+	// lifecycle watcher, and the periods constructed from them.
+	ClusterServiceVersionCreatedReason      IntervalReason = "ClusterServiceVersionCreated"
+	ClusterServiceVersionPhaseChangedReason IntervalReason = "ClusterServiceVersionPhaseChanged"
+	ClusterServiceVersionPhaseReason        IntervalReason = "ClusterServiceVersionPhase"
+	InstallPlanPhaseChangedReason           IntervalReason = "InstallPlanPhaseChanged"
+	InstallPlanApprovedReason               IntervalReason = "InstallPlanApproved"
+	InstallPlanPhaseReason                  IntervalReason = "InstallPlanPhase"
+	CatalogSourceConnectionChangedReason    IntervalReason = "CatalogSourceConnectionChanged"
+	CatalogSourceDisconnectedReason         IntervalReason = "CatalogSourceDisconnected"
+
+	// The change of the etcd database size and of the number of stored objects over a test bucket.
+	EtcdDatabaseGrowthReason    IntervalReason = "EtcdDatabaseGrowth"
+	EtcdObjectCountGrowthReason IntervalReason = "EtcdObjectCountGrowth"
+
+	CloudAPIThrottledReason        IntervalReason = "CloudAPIThrottled"
+	CloudAPIThrottlingPeriodReason IntervalReason = "CloudAPIThrottlingPeriod"
 
 	MachineConfigChangeReason  IntervalReason = "MachineConfigChange"
 	MachineConfigReachedReason IntervalReason = "MachineConfigReached"
 
+	// The machine-config-daemon drives a node through cordon, drain, reboot and config apply, recording its progress
+	// in node annotations. These are the points in time at which those annotations or the node spec changed, and
+	// MachineConfigNodePhase is constructed from them.
+	MachineConfigDaemonStateChangedReason IntervalReason = "MachineConfigDaemonStateChanged"
+	NodeCordonedReason                    IntervalReason = "NodeCordoned"
+	NodeUncordonedReason                  IntervalReason = "NodeUncordoned"
+	NodeDrainRequestedReason              IntervalReason = "NodeDrainRequested"
+	NodeDrainCompletedReason              IntervalReason = "NodeDrainCompleted"
+	NodeDrainFailedReason                 IntervalReason = "NodeDrainFailed"
+	NodeRebootedReason                    IntervalReason = "NodeRebooted"
+	MachineConfigNodePhaseReason          IntervalReason = "MachineConfigNodePhase"
+
+	MachineConfigPoolConditionChangedReason    IntervalReason = "MachineConfigPoolConditionChanged"
+	MachineConfigPoolUpdatingReason            IntervalReason = "MachineConfigPoolUpdating"
+	MachineConfigPoolDegradedReason            IntervalReason = "MachineConfigPoolDegraded"
+	MachineConfigPoolMachineCountChangedReason IntervalReason = "MachineConfigPoolMachineCountChanged"
+
 	MachineCreated      IntervalReason = "MachineCreated"
 	MachineDeletedInAPI IntervalReason = "MachineDeletedInAPI"
 	MachinePhaseChanged IntervalReason = "MachinePhaseChange"
@@ -261,6 +385,9 @@ const (
 	UpgradeFailedReason   IntervalReason = "UpgradeFailed"
 	UpgradeCompleteReason IntervalReason = "UpgradeComplete"
 
+	StabilityGateWaitingReason    IntervalReason = "StabilityGateWaiting"
+	StabilityCriterionUnmetReason IntervalReason = "StabilityCriterionUnmet"
+
 	NodeInstallerReason IntervalReason = "NodeInstaller"
 
 	// client metrics show error connecting to the kube-apiserver
@@ -272,12 +399,18 @@ const (
 
 	ReasonBadOperatorApply  IntervalReason = "BadOperatorApply"
 	ReasonKubeAPIServer500s IntervalReason = "KubeAPIServer500s"
+	// ReasonAPFPriorityLevelSaturated is a period where an API Priority and Fairness priority level rejected or
+	// queued requests for a long time.
+	ReasonAPFPriorityLevelSaturated IntervalReason = "APFPriorityLevelSaturated"
 
 	ReasonHighGeneration    IntervalReason = "HighGeneration"
 	ReasonInvalidGeneration IntervalReason = "GenerationViolation"
 
 	ReasonEtcdBootstrap     IntervalReason = "EtcdBootstrap"
 	ReasonProcessDumpedCore IntervalReason = "ProcessDumpedCore"
+
+	// PodDisplaced is used when a deleted pod was replaced by a newly created pod of the same owner.
+	PodDisplaced IntervalReason = "PodDisplaced"
 )
 
 type AnnotationKey string
@@ -313,7 +446,34 @@ const (
 	AnnotationPercentage       AnnotationKey = "percentage"
 	AnnotationPriority         AnnotationKey = "priority"
 	AnnotationPreviousPriority AnnotationKey = "prev-priority"
+	AnnotationPriorityLevel    AnnotationKey = "priority-level"
 	AnnotationVIP              AnnotationKey = "vip"
+
+	AnnotationPreviousPod  AnnotationKey = "previous-pod"
+	AnnotationPreviousNode AnnotationKey = "previous-node"
+	AnnotationChain        AnnotationKey = "chain"
+	AnnotationChainLength  AnnotationKey = "chain-length"
+
+	AnnotationLikelyCauses AnnotationKey = "likely-causes"
+
+	AnnotationUpgradeHop AnnotationKey = "hop"
+
+	AnnotationMachineConfigPool AnnotationKey = "pool"
+	AnnotationPreviousStatus    AnnotationKey = "previousStatus"
+	AnnotationOperatorVersion   AnnotationKey = "operator-version"
+
+	// AnnotationCSIDriver is the CSI driver provisioning, attaching or mounting the volume, so that the storage
+	// intervals can be filtered by driver.
+	AnnotationCSIDriver             AnnotationKey = "driver"
+	AnnotationPersistentVolume      AnnotationKey = "volume"
+	AnnotationPersistentVolumeClaim AnnotationKey = "claim"
+
+	// AnnotationRouter is the router that admitted or rejected a route, one route can be exposed by several.
+	AnnotationRouter AnnotationKey = "router"
+
+	// AnnotationReplaces is the ClusterServiceVersion a newly created one replaces, following it gives the upgrade
+	// chain of an operator.
+	AnnotationReplaces AnnotationKey = "replaces"
 )
 
 // ConstructionOwner was originally meant to signify that an interval was derived from other intervals.
@@ -333,6 +493,18 @@ const (
 	ConstructionOwnerLeaseChecker     = "lease-checker"
 	ConstructionOwnerOnPremHaproxy    = "on-prem-haproxy-constructor"
 	ConstructionOwnerOnPremKeepalived = "on-prem-keepalived-constructor"
+
+	ConstructionOwnerDisruptionCauseAttribution = "disruption-cause-attribution"
+
+	ConstructionOwnerMachineConfigRollout = "machine-config-rollout-constructor"
+
+	ConstructionOwnerVolumeLifecycle = "volume-lifecycle-constructor"
+	ConstructionOwnerIngressHealth   = "ingress-health-constructor"
+
+	ConstructionOwnerOperatorLifecycle = "operator-lifecycle-constructor"
+	ConstructionOwnerEtcdStorageGrowth = "etcd-storage-growth-constructor"
+	ConstructionOwnerCloudThrottling   = "cloud-throttling-constructor"
+	ConstructionOwnerNodeHealth        = "node-health-constructor"
 )
 
 type Message struct {
@@ -354,6 +526,7 @@ const (
 	SourceAlert                     IntervalSource = "Alert"
 	SourceAPIServerShutdown         IntervalSource = "APIServerShutdown"
 	SourceDisruption                IntervalSource = "Disruption"
+	SourceDisruptionLatency         IntervalSource = "DisruptionLatency"
 	SourceE2ETest                   IntervalSource = "E2ETest"
 	SourceKubeEvent                 IntervalSource = "KubeEvent"
 	SourceNetworkManagerLog         IntervalSource = "NetworkMangerLog"
@@ -389,11 +562,29 @@ const (
 
 	SourceGenerationMonitor IntervalSource = "GenerationMonitor"
 
+	SourceDisruptionCauseAttribution IntervalSource = "DisruptionCauseAttribution"
+
+	SourceStabilityGate IntervalSource = "StabilityGate"
+
+	SourceMachineConfigRollout IntervalSource = "MachineConfigRollout"
+
+	SourceMemoryMonitor IntervalSource = "MemoryMonitor"
+	SourceDiskMonitor   IntervalSource = "DiskMonitor"
+
+	SourceStorageMonitor IntervalSource = "StorageMonitor"
+	SourceIngressMonitor IntervalSource = "IngressMonitor"
+	SourceOLMMonitor     IntervalSource = "OLMMonitor"
+
 	SourceStaticPodInstallMonitor  IntervalSource = "StaticPodInstallMonitor"
 	SourceCPUMonitor               IntervalSource = "CPUMonitor"
 	SourceEtcdDiskCommitDuration   IntervalSource = "EtcdDiskCommitDuration"
 	SourceEtcdDiskWalFsyncDuration IntervalSource = "EtcdDiskWalFsyncDuration"
+	SourceEtcdStorageGrowth        IntervalSource = "EtcdStorageGrowth"
+	SourceCloudThrottling          IntervalSource = "CloudThrottling"
+	SourceNodeHealth               IntervalSource = "NodeHealth"
 	SourceTestBucket               IntervalSource = "TestBucket"
+	SourcePodDisplacement          IntervalSource = "PodDisplacement"
+	SourceAPFMonitor               IntervalSource = "APFMonitor"
 	KubeletPanic                   IntervalReason = "KubeletPanic"
 	CrioPanic                      IntervalReason = "CrioPanic"
 )
@@ -0,0 +1,158 @@
+package otlpexport
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// CollectorEndpointConfigured returns true when the standard OTLP environment variables point at a collector.
+// The grpc client reads the same variables, including OTEL_EXPORTER_OTLP_INSECURE and headers.
+func CollectorEndpointConfigured() bool {
+	return len(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")) > 0 || len(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")) > 0
+}
+
+// WriteRun writes the run as otel-traces<timeSuffix>.json to storageDir, and uploads it to a collector if one is
+// configured.  A failed upload does not prevent the file from being written.
+func WriteRun(ctx context.Context, storageDir, timeSuffix string, run Run) error {
+	resourceSpans := RunToResourceSpans(run)
+	errs := []error{}
+
+	filename := filepath.Join(storageDir, fmt.Sprintf("otel-traces%s.json", timeSuffix))
+	if err := WriteTracesFile(filename, resourceSpans); err != nil {
+		errs = append(errs, fmt.Errorf("unable to write %s: %w", filename, err))
+	}
+
+	if CollectorEndpointConfigured() {
+		logrus.Infof("Uploading %d spans to the OTLP collector", len(resourceSpans.ScopeSpans[0].Spans))
+		if err := Upload(ctx, otlptracegrpc.NewClient(), resourceSpans); err != nil {
+			errs = append(errs, fmt.Errorf("unable to upload traces: %w", err))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// Upload sends the spans using the client.  Tests provide a client pointed at a local receiver.
+func Upload(ctx context.Context, client otlptrace.Client, resourceSpans *tracepb.ResourceSpans) error {
+	if err := client.Start(ctx); err != nil {
+		return err
+	}
+	uploadErr := client.UploadTraces(ctx, []*tracepb.ResourceSpans{resourceSpans})
+	if err := client.Stop(ctx); err != nil && uploadErr == nil {
+		return err
+	}
+	return uploadErr
+}
+
+// WriteTracesFile writes the spans in the OTLP JSON file format, which the collector's otlpjsonfile receiver and
+// Jaeger's file import both read.
+func WriteTracesFile(filename string, resourceSpans *tracepb.ResourceSpans) error {
+	data, err := TracesToJSON(resourceSpans)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// TracesToJSON encodes the spans as OTLP JSON.  OTLP JSON differs from the canonical protobuf JSON mapping
+// because trace and span IDs are hex rather than base64, and enums are numbers.
+func TracesToJSON(resourceSpans *tracepb.ResourceSpans) ([]byte, error) {
+	canonical, err := protojson.MarshalOptions{UseEnumNumbers: true}.Marshal(&tracepb.TracesData{
+		ResourceSpans: []*tracepb.ResourceSpans{resourceSpans},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(canonical, &decoded); err != nil {
+		return nil, err
+	}
+	if err := hexEncodeIDs(decoded); err != nil {
+		return nil, err
+	}
+	return json.Marshal(decoded)
+}
+
+// TracesFromJSON decodes OTLP JSON written by TracesToJSON.
+func TracesFromJSON(data []byte) (*tracepb.TracesData, error) {
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	if err := base64EncodeIDs(decoded); err != nil {
+		return nil, err
+	}
+	canonical, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, err
+	}
+	ret := &tracepb.TracesData{}
+	if err := protojson.Unmarshal(canonical, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+var idFields = []string{"traceId", "spanId", "parentSpanId"}
+
+func hexEncodeIDs(value interface{}) error {
+	return rewriteIDs(value, func(id string) (string, error) {
+		raw, err := base64.StdEncoding.DecodeString(id)
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(raw), nil
+	})
+}
+
+func base64EncodeIDs(value interface{}) error {
+	return rewriteIDs(value, func(id string) (string, error) {
+		raw, err := hex.DecodeString(id)
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(raw), nil
+	})
+}
+
+func rewriteIDs(value interface{}, rewrite func(string) (string, error)) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, field := range idFields {
+			id, ok := v[field].(string)
+			if !ok {
+				continue
+			}
+			rewritten, err := rewrite(id)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: %w", field, id, err)
+			}
+			v[field] = rewritten
+		}
+		for _, child := range v {
+			if err := rewriteIDs(child, rewrite); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if err := rewriteIDs(child, rewrite); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
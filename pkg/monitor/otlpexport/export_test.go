@@ -0,0 +1,156 @@
+package otlpexport
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	collectortracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+func testRun() Run {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return Run{
+		SuiteName: "openshift/conformance/parallel",
+		Start:     start,
+		End:       start.Add(time.Hour),
+		TestAttempts: []TestAttempt{
+			{Name: "[sig-foo] passes", Attempt: 1, Start: start.Add(time.Minute), End: start.Add(2 * time.Minute), Result: "passed"},
+			{Name: "[sig-foo] retried", Attempt: 1, Start: start.Add(time.Minute), End: start.Add(3 * time.Minute), Result: "failed"},
+			{Name: "[sig-foo] retried", Attempt: 2, Start: start.Add(50 * time.Minute), End: start.Add(51 * time.Minute), Result: "passed"},
+		},
+		MonitorTestPhases: []monitortestframework.MonitorTestPhaseTiming{
+			{MonitorTest: "pod-lifecycle", Phase: "setup", Start: start, End: start.Add(time.Second)},
+			{MonitorTest: "pod-lifecycle", Phase: "collection", Start: start.Add(time.Hour), End: start.Add(time.Hour + time.Second)},
+		},
+		Intervals: monitorapi.Intervals{
+			monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+				Locator(monitorapi.NewLocator().NodeFromName("node-1")).
+				Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).HumanMessage("unreachable")).
+				Build(start.Add(10*time.Minute), start.Add(11*time.Minute)),
+			monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Info).
+				Locator(monitorapi.NewLocator().NodeFromName("node-1")).
+				Message(monitorapi.NewMessage().Reason(monitorapi.NodeUpdateReason).WithAnnotation(monitorapi.AnnotationPhase, "Reboot")).
+				Build(start.Add(20*time.Minute), start.Add(22*time.Minute)),
+			monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Info).
+				Locator(monitorapi.NewLocator().NodeFromName("node-1")).
+				Message(monitorapi.NewMessage().Reason(monitorapi.NodeUpdateReason).WithAnnotation(monitorapi.AnnotationPhase, "Drain")).
+				Build(start.Add(18*time.Minute), start.Add(20*time.Minute)),
+		},
+	}
+}
+
+func TestRunToResourceSpans(t *testing.T) {
+	resourceSpans := RunToResourceSpans(testRun())
+	spans := resourceSpans.ScopeSpans[0].Spans
+
+	// suite, tests group, three attempts, monitor test group, two phases, cluster events group, two traced intervals
+	if len(spans) != 11 {
+		t.Fatalf("expected 11 spans, got %d", len(spans))
+	}
+
+	root := spans[0]
+	if len(root.ParentSpanId) != 0 {
+		t.Errorf("expected the suite to be the root span")
+	}
+	spanIDs := map[string]bool{}
+	for _, span := range spans {
+		if string(span.TraceId) != string(root.TraceId) {
+			t.Errorf("span %q is in a different trace", span.Name)
+		}
+		if spanIDs[string(span.SpanId)] {
+			t.Errorf("span %q reuses a span ID", span.Name)
+		}
+		spanIDs[string(span.SpanId)] = true
+	}
+
+	failed := 0
+	for _, span := range spans {
+		if span.Status.Code == tracepb.Status_STATUS_CODE_ERROR {
+			failed++
+		}
+	}
+	// the failed first attempt and the disruption
+	if failed != 2 {
+		t.Errorf("expected 2 failed spans, got %d", failed)
+	}
+
+	again := RunToResourceSpans(testRun())
+	if !proto.Equal(resourceSpans, again) {
+		t.Errorf("expected the same run to produce the same trace")
+	}
+}
+
+func TestTracesFileRoundTrip(t *testing.T) {
+	resourceSpans := RunToResourceSpans(testRun())
+	filename := filepath.Join(t.TempDir(), "otel-traces.json")
+	if err := WriteTracesFile(filename, resourceSpans); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	traces, err := TracesFromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(traces.ResourceSpans[0], resourceSpans) {
+		t.Errorf("round trip mismatch")
+	}
+}
+
+type fakeReceiver struct {
+	collectortracepb.UnimplementedTraceServiceServer
+
+	lock     sync.Mutex
+	received []*tracepb.ResourceSpans
+}
+
+func (r *fakeReceiver) Export(ctx context.Context, request *collectortracepb.ExportTraceServiceRequest) (*collectortracepb.ExportTraceServiceResponse, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.received = append(r.received, request.ResourceSpans...)
+	return &collectortracepb.ExportTraceServiceResponse{}, nil
+}
+
+func TestUpload(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver := &fakeReceiver{}
+	server := grpc.NewServer()
+	collectortracepb.RegisterTraceServiceServer(server, receiver)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resourceSpans := RunToResourceSpans(testRun())
+	client := otlptracegrpc.NewClient(otlptracegrpc.WithEndpoint(listener.Addr().String()), otlptracegrpc.WithInsecure())
+	if err := Upload(ctx, client, resourceSpans); err != nil {
+		t.Fatal(err)
+	}
+
+	receiver.lock.Lock()
+	defer receiver.lock.Unlock()
+	if len(receiver.received) != 1 {
+		t.Fatalf("expected 1 resource span, got %d", len(receiver.received))
+	}
+	if !proto.Equal(receiver.received[0], resourceSpans) {
+		t.Errorf("received spans do not match the uploaded spans")
+	}
+}
@@ -0,0 +1,174 @@
+package otlpexport
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"time"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+const (
+	serviceName         = "openshift-tests"
+	instrumentationName = "github.com/openshift/origin/pkg/monitor/otlpexport"
+)
+
+// TestAttempt is a single execution of a test.  Retries of the same test are separate attempts.
+type TestAttempt struct {
+	Name string
+	// Attempt starts at 1 for the first execution.
+	Attempt int
+	Start   time.Time
+	End     time.Time
+	// Result is one of passed, failed, flaked, skipped, or timedout.
+	Result string
+}
+
+// Run is everything about a suite run that becomes a span.
+type Run struct {
+	SuiteName         string
+	Start             time.Time
+	End               time.Time
+	TestAttempts      []TestAttempt
+	MonitorTestPhases []monitortestframework.MonitorTestPhaseTiming
+	Intervals         monitorapi.Intervals
+}
+
+// IsTracedInterval selects the cluster events that are worth a span: disruption, operators progressing, and node reboots.
+// Everything else stays in the interval files; traces are for correlating tests with the big events.
+func IsTracedInterval(interval monitorapi.Interval) bool {
+	switch interval.Source {
+	case monitorapi.SourceDisruption:
+		return interval.Level > monitorapi.Info
+	case monitorapi.SourceOperatorState:
+		return interval.Message.Annotations[monitorapi.AnnotationCondition] == "Progressing"
+	case monitorapi.SourceNodeState:
+		return interval.Message.Annotations[monitorapi.AnnotationPhase] == "Reboot"
+	}
+	return false
+}
+
+// spanBuilder derives every ID from the suite name and start time so the same run always produces the same trace.
+type spanBuilder struct {
+	traceID []byte
+	spans   []*tracepb.Span
+}
+
+func newSpanBuilder(run Run) *spanBuilder {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s", run.SuiteName, run.Start.UTC().Format(time.RFC3339Nano))))
+	return &spanBuilder{traceID: sum[:16]}
+}
+
+func (b *spanBuilder) spanID(key string) []byte {
+	sum := sha256.Sum256(append(append([]byte{}, b.traceID...), key...))
+	return sum[:8]
+}
+
+func (b *spanBuilder) add(key string, parent []byte, name string, start, end time.Time, failed bool, attributes ...*commonpb.KeyValue) []byte {
+	if end.Before(start) {
+		end = start
+	}
+	span := &tracepb.Span{
+		TraceId:           b.traceID,
+		SpanId:            b.spanID(key),
+		ParentSpanId:      parent,
+		Name:              name,
+		Kind:              tracepb.Span_SPAN_KIND_INTERNAL,
+		StartTimeUnixNano: uint64(start.UnixNano()),
+		EndTimeUnixNano:   uint64(end.UnixNano()),
+		Attributes:        attributes,
+		Status:            &tracepb.Status{Code: tracepb.Status_STATUS_CODE_OK},
+	}
+	if failed {
+		span.Status.Code = tracepb.Status_STATUS_CODE_ERROR
+	}
+	b.spans = append(b.spans, span)
+	return span.SpanId
+}
+
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+func intAttribute(key string, value int64) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: value}}}
+}
+
+// RunToResourceSpans converts a run into a single trace.  The suite is the root span with three children grouping the
+// test attempts, the monitor test phases, and the cluster events.
+func RunToResourceSpans(run Run) *tracepb.ResourceSpans {
+	b := newSpanBuilder(run)
+	root := b.add("suite", nil, fmt.Sprintf("suite %s", run.SuiteName), run.Start, run.End, false,
+		stringAttribute("openshift.suite.name", run.SuiteName))
+
+	testsParent := b.add("tests", root, "tests", run.Start, run.End, false)
+	for _, attempt := range run.TestAttempts {
+		b.add(fmt.Sprintf("test/%s/%d", attempt.Name, attempt.Attempt), testsParent, attempt.Name, attempt.Start, attempt.End,
+			attempt.Result == "failed" || attempt.Result == "timedout",
+			stringAttribute("openshift.test.name", attempt.Name),
+			intAttribute("openshift.test.attempt", int64(attempt.Attempt)),
+			stringAttribute("openshift.test.result", attempt.Result),
+		)
+	}
+
+	if len(run.MonitorTestPhases) > 0 {
+		phasesStart, phasesEnd := run.MonitorTestPhases[0].Start, run.MonitorTestPhases[0].End
+		for _, phase := range run.MonitorTestPhases {
+			if phase.Start.Before(phasesStart) {
+				phasesStart = phase.Start
+			}
+			if phase.End.After(phasesEnd) {
+				phasesEnd = phase.End
+			}
+		}
+		phasesParent := b.add("monitortests", root, "monitor tests", phasesStart, phasesEnd, false)
+		for _, phase := range run.MonitorTestPhases {
+			b.add(fmt.Sprintf("monitortest/%s/%s", phase.MonitorTest, phase.Phase), phasesParent,
+				fmt.Sprintf("%s %s", phase.MonitorTest, phase.Phase), phase.Start, phase.End, false,
+				stringAttribute("openshift.monitortest.name", phase.MonitorTest),
+				stringAttribute("openshift.monitortest.phase", phase.Phase),
+			)
+		}
+	}
+
+	intervals := run.Intervals.Filter(IsTracedInterval)
+	sort.Stable(intervals)
+	if len(intervals) > 0 {
+		eventsParent := b.add("intervals", root, "cluster events", run.Start, run.End, false)
+		for i, interval := range intervals {
+			end := interval.To
+			if end.IsZero() {
+				end = run.End
+			}
+			b.add(fmt.Sprintf("interval/%d", i), eventsParent,
+				fmt.Sprintf("%s %s", interval.Source, interval.Message.Reason), interval.From, end,
+				interval.Level == monitorapi.Error,
+				stringAttribute("openshift.interval.source", string(interval.Source)),
+				stringAttribute("openshift.interval.level", interval.Level.String()),
+				stringAttribute("openshift.interval.locator", interval.Locator.OldLocator()),
+				stringAttribute("openshift.interval.message", interval.Message.OldMessage()),
+			)
+		}
+	}
+
+	return &tracepb.ResourceSpans{
+		Resource: &resourcepb.Resource{
+			Attributes: []*commonpb.KeyValue{
+				stringAttribute("service.name", serviceName),
+				stringAttribute("openshift.suite.name", run.SuiteName),
+			},
+		},
+		ScopeSpans: []*tracepb.ScopeSpans{
+			{
+				Scope: &commonpb.InstrumentationScope{Name: instrumentationName},
+				Spans: b.spans,
+			},
+		},
+	}
+}
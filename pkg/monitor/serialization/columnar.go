@@ -0,0 +1,339 @@
+package monitorserialization
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// The columnar encoding stores a block of intervals column by column instead of interval by interval.
+// Every string (sources, locator types, locator keys and values, reasons, causes, annotation keys and values,
+// and human messages) is stored once in a dictionary and referenced by index.  Timestamps are stored with
+// millisecond precision: From as a delta from the previous From, and To as an offset from its own From.
+// All integers are varints, signed ones are zigzag encoded.  The layout is:
+//
+//	version
+//	string count, then (length, bytes) for every string
+//	interval count
+//	from column: zigzag delta in ms from the previous interval's From
+//	to column: 0 for a zero To, otherwise 1 + zigzag(To - From in ms)
+//	level column
+//	source column: string index
+//	display column: 0 or 1
+//	locator type column: string index
+//	locator key count column, then (key index, value index) for every locator key
+//	reason column, cause column, human message column: string indexes
+//	annotation count column, then (key index, value index) for every annotation
+//
+// The viewer page decodes the same layout in the browser, so changes must be made in both places.
+const columnarBlockVersion = 1
+
+// IntervalsToColumnar encodes the intervals, sorted by time, as a single columnar block.
+func IntervalsToColumnar(intervals monitorapi.Intervals) ([]byte, error) {
+	sorted := make(monitorapi.Intervals, len(intervals))
+	copy(sorted, intervals)
+	sort.Stable(sorted)
+
+	buf := &bytes.Buffer{}
+	if err := writeColumnarBlock(buf, sorted); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// IntervalsFromColumnar decodes a single columnar block.
+func IntervalsFromColumnar(data []byte) (monitorapi.Intervals, error) {
+	return readColumnarBlock(bufio.NewReader(bytes.NewReader(data)))
+}
+
+type stringDictionary struct {
+	indexes map[string]uint64
+	values  []string
+}
+
+func newStringDictionary() *stringDictionary {
+	return &stringDictionary{indexes: map[string]uint64{}}
+}
+
+func (d *stringDictionary) index(value string) uint64 {
+	if i, ok := d.indexes[value]; ok {
+		return i
+	}
+	i := uint64(len(d.values))
+	d.indexes[value] = i
+	d.values = append(d.values, value)
+	return i
+}
+
+type columnarWriter struct {
+	buf     []byte
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (w *columnarWriter) uvarint(v uint64) {
+	n := binary.PutUvarint(w.scratch[:], v)
+	w.buf = append(w.buf, w.scratch[:n]...)
+}
+
+func (w *columnarWriter) varint(v int64) {
+	w.uvarint(zigzag(v))
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixMilli()
+}
+
+func fromMillis(ms int64) time.Time {
+	return time.UnixMilli(ms).UTC()
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func sortedLocatorKeys(keys map[monitorapi.LocatorKey]string) []monitorapi.LocatorKey {
+	ret := make([]monitorapi.LocatorKey, 0, len(keys))
+	for k := range keys {
+		ret = append(ret, k)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i] < ret[j] })
+	return ret
+}
+
+func sortedAnnotationKeys(annotations map[monitorapi.AnnotationKey]string) []monitorapi.AnnotationKey {
+	ret := make([]monitorapi.AnnotationKey, 0, len(annotations))
+	for k := range annotations {
+		ret = append(ret, k)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i] < ret[j] })
+	return ret
+}
+
+func writeColumnarBlock(out io.Writer, intervals monitorapi.Intervals) error {
+	dictionary := newStringDictionary()
+	columns := &columnarWriter{}
+
+	prevFrom := int64(0)
+	for _, interval := range intervals {
+		from := toMillis(interval.From)
+		columns.varint(from - prevFrom)
+		prevFrom = from
+	}
+	for _, interval := range intervals {
+		if interval.To.IsZero() {
+			columns.uvarint(0)
+			continue
+		}
+		// shift by one to leave room for the zero marker.
+		columns.uvarint(zigzag(toMillis(interval.To)-toMillis(interval.From)) + 1)
+	}
+	for _, interval := range intervals {
+		columns.uvarint(uint64(interval.Level))
+	}
+	for _, interval := range intervals {
+		columns.uvarint(dictionary.index(string(interval.Source)))
+	}
+	for _, interval := range intervals {
+		if interval.Display {
+			columns.uvarint(1)
+		} else {
+			columns.uvarint(0)
+		}
+	}
+	for _, interval := range intervals {
+		columns.uvarint(dictionary.index(string(interval.Locator.Type)))
+	}
+	for _, interval := range intervals {
+		columns.uvarint(uint64(len(interval.Locator.Keys)))
+	}
+	for _, interval := range intervals {
+		for _, k := range sortedLocatorKeys(interval.Locator.Keys) {
+			columns.uvarint(dictionary.index(string(k)))
+			columns.uvarint(dictionary.index(interval.Locator.Keys[k]))
+		}
+	}
+	for _, interval := range intervals {
+		columns.uvarint(dictionary.index(string(interval.Message.Reason)))
+	}
+	for _, interval := range intervals {
+		columns.uvarint(dictionary.index(interval.Message.Cause))
+	}
+	for _, interval := range intervals {
+		columns.uvarint(dictionary.index(interval.Message.HumanMessage))
+	}
+	for _, interval := range intervals {
+		columns.uvarint(uint64(len(interval.Message.Annotations)))
+	}
+	for _, interval := range intervals {
+		for _, k := range sortedAnnotationKeys(interval.Message.Annotations) {
+			columns.uvarint(dictionary.index(string(k)))
+			columns.uvarint(dictionary.index(interval.Message.Annotations[k]))
+		}
+	}
+
+	header := &columnarWriter{}
+	header.uvarint(columnarBlockVersion)
+	header.uvarint(uint64(len(dictionary.values)))
+	for _, value := range dictionary.values {
+		header.uvarint(uint64(len(value)))
+		header.buf = append(header.buf, value...)
+	}
+	header.uvarint(uint64(len(intervals)))
+
+	if _, err := out.Write(header.buf); err != nil {
+		return err
+	}
+	_, err := out.Write(columns.buf)
+	return err
+}
+
+// columnarReader reads a block and remembers the first error so the column loops stay readable.
+type columnarReader struct {
+	in  *bufio.Reader
+	err error
+}
+
+func (r *columnarReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(r.in)
+	if err != nil {
+		r.err = err
+	}
+	return v
+}
+
+func (r *columnarReader) varint() int64 {
+	return unzigzag(r.uvarint())
+}
+
+func (r *columnarReader) string(dictionary []string) string {
+	i := r.uvarint()
+	if r.err != nil {
+		return ""
+	}
+	if i >= uint64(len(dictionary)) {
+		r.err = fmt.Errorf("string index %d out of range %d", i, len(dictionary))
+		return ""
+	}
+	return dictionary[i]
+}
+
+// maxColumnarCount guards against allocating absurd amounts of memory for corrupt input.
+const maxColumnarCount = 1 << 28
+
+func readColumnarBlock(in *bufio.Reader) (monitorapi.Intervals, error) {
+	r := &columnarReader{in: in}
+
+	version := r.uvarint()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if version != columnarBlockVersion {
+		return nil, fmt.Errorf("unsupported columnar block version %d", version)
+	}
+
+	stringCount := r.uvarint()
+	if stringCount > maxColumnarCount {
+		return nil, fmt.Errorf("too many strings: %d", stringCount)
+	}
+	dictionary := make([]string, 0, stringCount)
+	for i := uint64(0); i < stringCount && r.err == nil; i++ {
+		length := r.uvarint()
+		if length > maxColumnarCount {
+			return nil, fmt.Errorf("string too long: %d", length)
+		}
+		value := make([]byte, length)
+		if r.err == nil {
+			if _, err := io.ReadFull(in, value); err != nil {
+				r.err = err
+			}
+		}
+		dictionary = append(dictionary, string(value))
+	}
+
+	count := r.uvarint()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if count > maxColumnarCount {
+		return nil, fmt.Errorf("too many intervals: %d", count)
+	}
+	intervals := make(monitorapi.Intervals, count)
+
+	prevFrom := int64(0)
+	for i := range intervals {
+		prevFrom += r.varint()
+		intervals[i].From = fromMillis(prevFrom)
+	}
+	for i := range intervals {
+		v := r.uvarint()
+		if v == 0 {
+			continue
+		}
+		intervals[i].To = fromMillis(toMillis(intervals[i].From) + unzigzag(v-1))
+	}
+	for i := range intervals {
+		intervals[i].Level = monitorapi.IntervalLevel(r.uvarint())
+	}
+	for i := range intervals {
+		intervals[i].Source = monitorapi.IntervalSource(r.string(dictionary))
+	}
+	for i := range intervals {
+		intervals[i].Display = r.uvarint() == 1
+	}
+	for i := range intervals {
+		intervals[i].Locator.Type = monitorapi.LocatorType(r.string(dictionary))
+	}
+	locatorKeyCounts := make([]uint64, count)
+	for i := range intervals {
+		locatorKeyCounts[i] = r.uvarint()
+	}
+	for i := range intervals {
+		intervals[i].Locator.Keys = map[monitorapi.LocatorKey]string{}
+		for j := uint64(0); j < locatorKeyCounts[i] && r.err == nil; j++ {
+			k := r.string(dictionary)
+			intervals[i].Locator.Keys[monitorapi.LocatorKey(k)] = r.string(dictionary)
+		}
+	}
+	for i := range intervals {
+		intervals[i].Message.Reason = monitorapi.IntervalReason(r.string(dictionary))
+	}
+	for i := range intervals {
+		intervals[i].Message.Cause = r.string(dictionary)
+	}
+	for i := range intervals {
+		intervals[i].Message.HumanMessage = r.string(dictionary)
+	}
+	annotationCounts := make([]uint64, count)
+	for i := range intervals {
+		annotationCounts[i] = r.uvarint()
+	}
+	for i := range intervals {
+		intervals[i].Message.Annotations = map[monitorapi.AnnotationKey]string{}
+		for j := uint64(0); j < annotationCounts[i] && r.err == nil; j++ {
+			k := r.string(dictionary)
+			intervals[i].Message.Annotations[monitorapi.AnnotationKey(k)] = r.string(dictionary)
+		}
+	}
+
+	if r.err != nil {
+		if errors.Is(r.err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, r.err
+	}
+	return intervals, nil
+}
@@ -0,0 +1,153 @@
+package monitorserialization
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// A columnar file is the columnarFileMagic header followed by a gzip stream of columnar blocks.  Blocks are
+// written every columnarFileBlockSize intervals so that neither writing nor reading needs the whole run in memory.
+const (
+	columnarFileMagic     = "OINTCOL\x01"
+	columnarFileBlockSize = 16 * 1024
+)
+
+// IsColumnar returns true if the data starts with the columnar file header.
+func IsColumnar(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(columnarFileMagic))
+}
+
+// ColumnarIntervalWriter streams intervals into a columnar file.  Close must be called to flush the last block.
+type ColumnarIntervalWriter struct {
+	out     io.Writer
+	gzip    *gzip.Writer
+	pending monitorapi.Intervals
+}
+
+func NewColumnarIntervalWriter(out io.Writer) (*ColumnarIntervalWriter, error) {
+	if _, err := io.WriteString(out, columnarFileMagic); err != nil {
+		return nil, err
+	}
+	return &ColumnarIntervalWriter{
+		out:  out,
+		gzip: gzip.NewWriter(out),
+	}, nil
+}
+
+func (w *ColumnarIntervalWriter) Write(intervals ...monitorapi.Interval) error {
+	w.pending = append(w.pending, intervals...)
+	for len(w.pending) >= columnarFileBlockSize {
+		if err := writeColumnarBlock(w.gzip, w.pending[:columnarFileBlockSize]); err != nil {
+			return err
+		}
+		w.pending = w.pending[columnarFileBlockSize:]
+	}
+	return nil
+}
+
+func (w *ColumnarIntervalWriter) Close() error {
+	if len(w.pending) > 0 {
+		if err := writeColumnarBlock(w.gzip, w.pending); err != nil {
+			return err
+		}
+		w.pending = nil
+	}
+	return w.gzip.Close()
+}
+
+// ColumnarIntervalReader streams blocks of intervals out of a columnar file.
+type ColumnarIntervalReader struct {
+	gzip *gzip.Reader
+	in   *bufio.Reader
+}
+
+func NewColumnarIntervalReader(in io.Reader) (*ColumnarIntervalReader, error) {
+	magic := make([]byte, len(columnarFileMagic))
+	if _, err := io.ReadFull(in, magic); err != nil {
+		return nil, fmt.Errorf("unable to read columnar header: %w", err)
+	}
+	if !IsColumnar(magic) {
+		return nil, fmt.Errorf("not a columnar interval file")
+	}
+	gzipReader, err := gzip.NewReader(in)
+	if err != nil {
+		return nil, err
+	}
+	return &ColumnarIntervalReader{
+		gzip: gzipReader,
+		in:   bufio.NewReader(gzipReader),
+	}, nil
+}
+
+// Next returns the next block of intervals, or io.EOF when there are no more blocks.
+func (r *ColumnarIntervalReader) Next() (monitorapi.Intervals, error) {
+	if _, err := r.in.Peek(1); errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	return readColumnarBlock(r.in)
+}
+
+// ReadAll reads the remaining blocks into a single list.
+func (r *ColumnarIntervalReader) ReadAll() (monitorapi.Intervals, error) {
+	ret := monitorapi.Intervals{}
+	for {
+		block, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return ret, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, block...)
+	}
+}
+
+func (r *ColumnarIntervalReader) Close() error {
+	return r.gzip.Close()
+}
+
+// IntervalsToColumnarFile writes the intervals, sorted by time, as a columnar file.
+func IntervalsToColumnarFile(filename string, intervals monitorapi.Intervals) error {
+	sorted := make(monitorapi.Intervals, len(intervals))
+	copy(sorted, intervals)
+	sort.Stable(sorted)
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	out := bufio.NewWriter(f)
+	w, err := NewColumnarIntervalWriter(out)
+	if err != nil {
+		return err
+	}
+	if err := w.Write(sorted...); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func intervalsFromColumnarReader(in io.Reader) (monitorapi.Intervals, error) {
+	r, err := NewColumnarIntervalReader(in)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return r.ReadAll()
+}
@@ -0,0 +1,128 @@
+package monitorserialization
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// existing interval testdata used by the monitor tests.
+func intervalTestdataFiles(t *testing.T) []string {
+	files := []string{}
+	for _, pattern := range []string{
+		"../../monitortests/*/*/*/*/*.json",
+		"../../monitortests/testframework/timelineserializer/*.json",
+	} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		t.Fatal("no interval testdata found")
+	}
+	return files
+}
+
+func TestColumnarFileRoundTripTestdata(t *testing.T) {
+	for _, filename := range intervalTestdataFiles(t) {
+		t.Run(filename, func(t *testing.T) {
+			expected, err := EventsFromFile(filename)
+			if err != nil {
+				t.Skipf("not an intervals file: %v", err)
+			}
+
+			columnarFile := filepath.Join(t.TempDir(), "intervals.columnar")
+			if err := IntervalsToColumnarFile(columnarFile, expected); err != nil {
+				t.Fatal(err)
+			}
+			actual, err := EventsFromFile(columnarFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectedJSON, err := IntervalsToJSON(expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actualJSON, err := IntervalsToJSON(actual)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(expectedJSON, actualJSON) {
+				t.Errorf("round trip mismatch\nexpected:\n%s\nactual:\n%s", expectedJSON, actualJSON)
+			}
+		})
+	}
+}
+
+func TestColumnarFileStreaming(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	count := columnarFileBlockSize*2 + 17
+
+	buf := &bytes.Buffer{}
+	w, err := NewColumnarIntervalWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < count; i++ {
+		interval := monitorapi.NewInterval(monitorapi.SourceAlert, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("node-1")).
+			Message(monitorapi.NewMessage().HumanMessage("tick")).
+			Build(start.Add(time.Duration(i)*time.Second), start.Add(time.Duration(i+1)*time.Second))
+		if err := w.Write(interval); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !IsColumnar(buf.Bytes()) {
+		t.Fatal("expected columnar header")
+	}
+
+	r, err := NewColumnarIntervalReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks, total := 0, 0
+	for {
+		block, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, interval := range block {
+			if expected := start.Add(time.Duration(total+i) * time.Second); !interval.From.Equal(expected) {
+				t.Fatalf("interval %d: expected from %v, got %v", total+i, expected, interval.From)
+			}
+		}
+		blocks++
+		total += len(block)
+	}
+	if blocks != 3 || total != count {
+		t.Errorf("expected 3 blocks with %d intervals, got %d blocks with %d intervals", count, blocks, total)
+	}
+}
+
+func TestEventsFromFileJSON(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "intervals.json")
+	if err := os.WriteFile(filename, []byte(`{"items":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	intervals, err := EventsFromFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intervals) != 0 {
+		t.Errorf("expected no intervals, got %d", len(intervals))
+	}
+}
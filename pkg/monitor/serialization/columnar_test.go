@@ -0,0 +1,77 @@
+package monitorserialization
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestColumnarRoundTrip(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	intervals := monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourcePodMonitor, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().PodFromNames("openshift-etcd", "etcd-0", "node-0")).
+			Message(monitorapi.NewMessage().Reason(monitorapi.PodReasonReady).
+				WithAnnotation(monitorapi.AnnotationContainerExitCode, "1").
+				HumanMessage("pod became ready")).
+			Display().
+			Build(start.Add(2*time.Second), start.Add(90*time.Second)),
+		monitorapi.NewInterval(monitorapi.SourceAlert, monitorapi.Error).
+			Locator(monitorapi.NewLocator().NodeFromName("node-1")).
+			Message(monitorapi.NewMessage().HumanMessage("still going")).
+			Build(start.Add(time.Second), time.Time{}),
+		monitorapi.NewInterval(monitorapi.SourceAlert, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("node-1")).
+			Message(monitorapi.NewMessage().HumanMessage("instant")).
+			Build(start.Add(1500*time.Millisecond), start.Add(1500*time.Millisecond)),
+	}
+
+	data, err := IntervalsToColumnar(intervals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := IntervalsFromColumnar(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := make(monitorapi.Intervals, len(intervals))
+	copy(expected, intervals)
+	sort.Stable(expected)
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d intervals, got %d", len(expected), len(actual))
+	}
+	for i := range expected {
+		if !actual[i].From.Equal(expected[i].From) || !actual[i].To.Equal(expected[i].To) {
+			t.Errorf("interval %d: expected %v - %v, got %v - %v", i, expected[i].From, expected[i].To, actual[i].From, actual[i].To)
+		}
+		actual[i].From, actual[i].To = expected[i].From, expected[i].To
+		if expected[i].Message.Annotations == nil {
+			expected[i].Message.Annotations = map[monitorapi.AnnotationKey]string{}
+		}
+		if expected[i].Locator.Keys == nil {
+			expected[i].Locator.Keys = map[monitorapi.LocatorKey]string{}
+		}
+		if !reflect.DeepEqual(actual[i], expected[i]) {
+			t.Errorf("interval %d: expected %#v, got %#v", i, expected[i], actual[i])
+		}
+	}
+}
+
+func TestColumnarTruncated(t *testing.T) {
+	data, err := IntervalsToColumnar(monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceAlert, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("node-1")).
+			Message(monitorapi.NewMessage().HumanMessage("truncated")).
+			Build(time.Now(), time.Now()),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := IntervalsFromColumnar(data[:len(data)-1]); err == nil {
+		t.Fatal("expected an error for truncated input")
+	}
+}
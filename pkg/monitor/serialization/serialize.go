@@ -1,10 +1,12 @@
 package monitorserialization
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"sort"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
@@ -42,8 +44,21 @@ func EventsToFile(filename string, events monitorapi.Intervals) error {
 	return ioutil.WriteFile(filename, json, 0644)
 }
 
+// EventsFromFile reads intervals written either as JSON or as a columnar file.
 func EventsFromFile(filename string) (monitorapi.Intervals, error) {
-	data, err := ioutil.ReadFile(filename)
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	in := bufio.NewReader(f)
+	header, err := in.Peek(len(columnarFileMagic))
+	if err == nil && IsColumnar(header) {
+		return intervalsFromColumnarReader(in)
+	}
+
+	data, err := ioutil.ReadAll(in)
 	if err != nil {
 		return nil, err
 	}
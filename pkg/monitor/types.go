@@ -3,6 +3,7 @@ package monitor
 import (
 	"context"
 
+	"github.com/openshift/origin/pkg/monitortestframework"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 )
 
@@ -10,6 +11,8 @@ type Interface interface {
 	Start(ctx context.Context) error
 	Stop(ctx context.Context) (ResultState, error)
 	SerializeResults(ctx context.Context, junitSuiteName, timeSuffix string) (*junitapi.JUnitTestSuite, error)
+	// MonitorTestPhaseTimings returns when every phase of every monitor test ran.
+	MonitorTestPhaseTimings() []monitortestframework.MonitorTestPhaseTiming
 }
 
 type ResultState string
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -20,28 +22,49 @@ import (
 
 type monitorTestRegistry struct {
 	monitorTests map[string]*monitorTesttItem
+
+	// clusterStability is the cluster stability mode the junit policies of the registry are resolved for.  It is
+	// Stable until the registry is narrowed to a single cluster stability mode.
+	clusterStability ClusterStabilityDuringTest
+
+	phaseTimingsLock sync.Mutex
+	phaseTimings     []MonitorTestPhaseTiming
 }
 
 type monitorTesttItem struct {
 	name          string
 	jiraComponent string
+	metadata      MonitorTestMetadata
+
+	// flakeJunits is the junit policy resolved for the cluster stability mode of the registry.
+	flakeJunits FlakeJunits
 
 	monitorTest MonitorTest
 }
 
 func NewMonitorTestRegistry() MonitorTestRegistry {
 	return &monitorTestRegistry{
-		monitorTests: map[string]*monitorTesttItem{},
+		monitorTests:     map[string]*monitorTesttItem{},
+		clusterStability: Stable,
 	}
 }
 
 func (r *monitorTestRegistry) AddMonitorTest(name, jiraComponent string, monitorTest MonitorTest) error {
+	return r.AddMonitorTestWithMetadata(name, jiraComponent, DefaultMonitorTestMetadata(), monitorTest)
+}
+
+func (r *monitorTestRegistry) AddMonitorTestWithMetadata(name, jiraComponent string, metadata MonitorTestMetadata, monitorTest MonitorTest) error {
 	if _, ok := r.monitorTests[name]; ok {
 		return fmt.Errorf("%q is already registered", name)
 	}
+	if err := metadata.Validate(); err != nil {
+		return fmt.Errorf("%q has invalid metadata: %w", name, err)
+	}
 	r.monitorTests[name] = &monitorTesttItem{
 		name:          name,
 		jiraComponent: jiraComponent,
+		metadata:      metadata,
+		flakeJunits:   metadata.JUnitPolicy[r.clusterStability],
 		monitorTest:   monitorTest,
 	}
 
@@ -55,8 +78,39 @@ func (r *monitorTestRegistry) AddMonitorTestOrDie(name, jiraComponent string, mo
 	}
 }
 
+func (r *monitorTestRegistry) AddMonitorTestWithMetadataOrDie(name, jiraComponent string, metadata MonitorTestMetadata, monitorTest MonitorTest) {
+	err := r.AddMonitorTestWithMetadata(name, jiraComponent, metadata, monitorTest)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (r *monitorTestRegistry) GetRegistryForClusterStability(clusterStability ClusterStabilityDuringTest) (MonitorTestRegistry, error) {
+	if !isKnownClusterStability(clusterStability) {
+		return nil, fmt.Errorf("unknown cluster stability level: %q", clusterStability)
+	}
+	ret := NewMonitorTestRegistry().(*monitorTestRegistry)
+	ret.clusterStability = clusterStability
+
+	for name, monitorTestItem := range r.monitorTests {
+		if !monitorTestItem.metadata.RunsIn(clusterStability) {
+			continue
+		}
+		ret.monitorTests[name] = &monitorTesttItem{
+			name:          monitorTestItem.name,
+			jiraComponent: monitorTestItem.jiraComponent,
+			metadata:      monitorTestItem.metadata,
+			flakeJunits:   monitorTestItem.metadata.JUnitPolicy[clusterStability],
+			monitorTest:   monitorTestItem.monitorTest,
+		}
+	}
+
+	return ret, nil
+}
+
 func (r *monitorTestRegistry) GetRegistryFor(names ...string) (MonitorTestRegistry, error) {
 	ret := NewMonitorTestRegistry().(*monitorTestRegistry)
+	ret.clusterStability = r.clusterStability
 
 	missingNames := []string{}
 	for _, name := range names {
@@ -78,6 +132,50 @@ func (r *monitorTestRegistry) ListMonitorTests() sets.String {
 	return sets.StringKeySet(r.monitorTests)
 }
 
+func (r *monitorTestRegistry) ListMonitorTestPolicies() []MonitorTestPolicy {
+	ret := []MonitorTestPolicy{}
+	for _, monitorTest := range r.monitorTests {
+		runsIn := []ClusterStabilityDuringTest{}
+		for _, stability := range allClusterStabilities {
+			if monitorTest.metadata.RunsIn(stability) {
+				runsIn = append(runsIn, stability)
+			}
+		}
+		ret = append(ret, MonitorTestPolicy{
+			MonitorTest:                monitorTest.name,
+			JiraComponent:              monitorTest.jiraComponent,
+			Criticality:                monitorTest.metadata.Criticality,
+			ClusterStabilityDuringTest: r.clusterStability,
+			FlakeJunits:                bool(monitorTest.flakeJunits),
+			RunsIn:                     runsIn,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].MonitorTest < ret[j].MonitorTest
+	})
+	return ret
+}
+
+func (r *monitorTestRegistry) recordPhaseTiming(monitorTest, phase string, start, end time.Time) {
+	r.phaseTimingsLock.Lock()
+	defer r.phaseTimingsLock.Unlock()
+	r.phaseTimings = append(r.phaseTimings, MonitorTestPhaseTiming{
+		MonitorTest: monitorTest,
+		Phase:       phase,
+		Start:       start,
+		End:         end,
+	})
+}
+
+func (r *monitorTestRegistry) PhaseTimings() []MonitorTestPhaseTiming {
+	r.phaseTimingsLock.Lock()
+	defer r.phaseTimingsLock.Unlock()
+	ret := make([]MonitorTestPhaseTiming, len(r.phaseTimings))
+	copy(ret, r.phaseTimings)
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].Start.Before(ret[j].Start) })
+	return ret
+}
+
 func (r *monitorTestRegistry) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) ([]*junitapi.JUnitTestCase, error) {
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
@@ -91,6 +189,7 @@ func (r *monitorTestRegistry) PrepareCollection(ctx context.Context, adminRESTCo
 		err := prepareCollectionWithPanicProtection(ctx, invariant.monitorTest, adminRESTConfig, recorder)
 		end := time.Now()
 		duration := end.Sub(start)
+		r.recordPhaseTiming(invariant.name, "preparation", start, end)
 		if err != nil {
 			var nsErr *NotSupportedError
 			if errors.As(err, &nsErr) {
@@ -144,6 +243,7 @@ func (r *monitorTestRegistry) StartCollection(ctx context.Context, adminRESTConf
 			err := startCollectionWithPanicProtection(ctx, invariant.monitorTest, adminRESTConfig, recorder)
 			end := time.Now()
 			duration := end.Sub(start)
+			r.recordPhaseTiming(invariant.name, "setup", start, end)
 			if err != nil {
 				var nsErr *NotSupportedError
 				if errors.As(err, &nsErr) {
@@ -212,6 +312,9 @@ func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string
 			start := time.Now()
 			logrus.Infof("  Starting CollectData for %s", testName)
 			localIntervals, localJunits, err := collectDataWithPanicProtection(ctx, monitorTest.monitorTest, storageDir, beginning, end)
+			if monitorTest.flakeJunits {
+				localJunits = JUnitsToFlakes(localJunits)
+			}
 
 			// make sure we have the annotation
 			for i := range localJunits {
@@ -224,6 +327,7 @@ func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string
 			junitCh <- localJunits
 			end := time.Now()
 			duration := end.Sub(start)
+			r.recordPhaseTiming(monitorTest.name, "collection", start, end)
 			if err != nil {
 				var nsErr *NotSupportedError
 				if errors.As(err, &nsErr) {
@@ -302,6 +406,7 @@ func (r *monitorTestRegistry) ConstructComputedIntervals(ctx context.Context, st
 		intervals = append(intervals, localIntervals...)
 		end := time.Now()
 		duration := end.Sub(start)
+		r.recordPhaseTiming(monitorTest.name, "interval construction", start, end)
 		if err != nil {
 			var nsErr *NotSupportedError
 			if errors.As(err, &nsErr) {
@@ -349,6 +454,9 @@ func (r *monitorTestRegistry) EvaluateTestsFromConstructedIntervals(ctx context.
 
 		start := time.Now()
 		localJunits, err := evaluateTestsFromConstructedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, finalIntervals)
+		if monitorTest.flakeJunits {
+			localJunits = JUnitsToFlakes(localJunits)
+		}
 
 		// make sure we have the annotation
 		for i := range localJunits {
@@ -360,6 +468,7 @@ func (r *monitorTestRegistry) EvaluateTestsFromConstructedIntervals(ctx context.
 		junits = append(junits, localJunits...)
 		end := time.Now()
 		duration := end.Sub(start)
+		r.recordPhaseTiming(monitorTest.name, "test evaluation", start, end)
 		if err != nil {
 			var nsErr *NotSupportedError
 			if errors.As(err, &nsErr) {
@@ -401,6 +510,12 @@ func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storage
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
 
+	policyFilename := filepath.Join(storageDir, fmt.Sprintf("monitor-test-policy%s.json", timeSuffix))
+	if err := writePolicyTable(policyFilename, r.ListMonitorTestPolicies()); err != nil {
+		logrus.WithError(err).Error("failed to write monitor test policy table")
+		errs = append(errs, err)
+	}
+
 	for _, monitorTest := range r.monitorTests {
 		monitorAnnotation := fmt.Sprintf("[Monitor:%s]", monitorTest.name)
 		testName := fmt.Sprintf("%s[Jira:%q] monitor test %v writing to storage", monitorAnnotation, monitorTest.jiraComponent, monitorTest.name)
@@ -418,6 +533,7 @@ func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storage
 		err := writeContentToStorageWithPanicProtection(ctx, monitorTest.monitorTest, storageDir, timeSuffix, finalIntervals, finalResourceState)
 		end := time.Now()
 		duration := end.Sub(start)
+		r.recordPhaseTiming(monitorTest.name, "writing to storage", start, end)
 		if err != nil {
 			var nsErr *NotSupportedError
 			if errors.As(err, &nsErr) {
@@ -469,6 +585,7 @@ func (r *monitorTestRegistry) Cleanup(ctx context.Context) ([]*junitapi.JUnitTes
 		err := cleanupWithPanicProtection(ctx, monitorTest.monitorTest)
 		end := time.Now()
 		duration := end.Sub(start)
+		r.recordPhaseTiming(monitorTest.name, "cleanup", start, end)
 		if err != nil {
 			var nsErr *NotSupportedError
 			if errors.As(err, &nsErr) {
@@ -507,9 +624,12 @@ func (r *monitorTestRegistry) Cleanup(ctx context.Context) ([]*junitapi.JUnitTes
 	return junits, utilerrors.NewAggregate(errs)
 }
 
+// AddRegistryOrDie adds every monitor test of the registry, keeping the junit policy it was resolved to there so
+// that merging a narrowed registry does not turn its flakes into failures.
 func (r *monitorTestRegistry) AddRegistryOrDie(registry MonitorTestRegistry) {
 	for _, v := range registry.getMonitorTests() {
-		r.AddMonitorTestOrDie(v.name, v.jiraComponent, v.monitorTest)
+		r.AddMonitorTestWithMetadataOrDie(v.name, v.jiraComponent, v.metadata, v.monitorTest)
+		r.monitorTests[v.name].flakeJunits = v.flakeJunits
 	}
 }
 
@@ -0,0 +1,135 @@
+package monitortestframework
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MonitorTestCriticality describes how important the signal from a monitor test is.  It bounds which junit policies
+// are allowed for the monitor test in each cluster stability mode.
+type MonitorTestCriticality string
+
+var (
+	// Critical monitor tests check invariants that must hold even when the suite intentionally disrupts the cluster.
+	// Their failures are never converted to flakes.
+	Critical MonitorTestCriticality = "Critical"
+	// Sensitive monitor tests are valuable on a stable cluster, but are easily tripped by intentional disruption.
+	// They are commonly reported as flakes in Disruptive and SpotCheck modes.
+	Sensitive MonitorTestCriticality = "Sensitive"
+	// Informational monitor tests collect, compute, or serialize data.  Their junits mostly report framework
+	// problems like failing to write artifacts.
+	Informational MonitorTestCriticality = "Informational"
+)
+
+// MonitorTestMetadata is the registry level description of a monitor test: how critical it is, which cluster
+// stability modes it runs in, and whether its junit failures are hard failures or flakes in each of those modes.
+// The registry applies the policy uniformly so individual monitor tests do not need to know about it.
+type MonitorTestMetadata struct {
+	Criticality MonitorTestCriticality
+
+	// JUnitPolicy holds an entry for every cluster stability mode the monitor test runs in.
+	// Modes without an entry do not run the monitor test at all.
+	JUnitPolicy map[ClusterStabilityDuringTest]FlakeJunits
+}
+
+// allClusterStabilities is every ClusterStabilityDuringTest that can be requested for a run.
+var allClusterStabilities = []ClusterStabilityDuringTest{Stable, Disruptive, SpotCheck}
+
+// NewMonitorTestMetadata returns metadata for a monitor test that does not run in any cluster stability mode yet.
+// Use HardFailIn and FlakeIn to add modes.
+func NewMonitorTestMetadata(criticality MonitorTestCriticality) MonitorTestMetadata {
+	return MonitorTestMetadata{
+		Criticality: criticality,
+		JUnitPolicy: map[ClusterStabilityDuringTest]FlakeJunits{},
+	}
+}
+
+// DefaultMonitorTestMetadata is used for monitor tests added without metadata.  They run in every cluster stability
+// mode and report their junits as-is, which matches how a registry behaved before metadata existed.
+func DefaultMonitorTestMetadata() MonitorTestMetadata {
+	return NewMonitorTestMetadata(Sensitive).HardFailIn(allClusterStabilities...)
+}
+
+// HardFailIn returns a copy of the metadata that runs in the provided modes and reports failures as failures.
+func (m MonitorTestMetadata) HardFailIn(stabilities ...ClusterStabilityDuringTest) MonitorTestMetadata {
+	return m.withPolicy(HardFail, stabilities...)
+}
+
+// FlakeIn returns a copy of the metadata that runs in the provided modes and converts failures to flakes.
+func (m MonitorTestMetadata) FlakeIn(stabilities ...ClusterStabilityDuringTest) MonitorTestMetadata {
+	return m.withPolicy(AsFlake, stabilities...)
+}
+
+func (m MonitorTestMetadata) withPolicy(policy FlakeJunits, stabilities ...ClusterStabilityDuringTest) MonitorTestMetadata {
+	ret := MonitorTestMetadata{
+		Criticality: m.Criticality,
+		JUnitPolicy: map[ClusterStabilityDuringTest]FlakeJunits{},
+	}
+	for k, v := range m.JUnitPolicy {
+		ret.JUnitPolicy[k] = v
+	}
+	for _, stability := range stabilities {
+		ret.JUnitPolicy[stability] = policy
+	}
+	return ret
+}
+
+// RunsIn returns true if the monitor test runs in the provided cluster stability mode.
+func (m MonitorTestMetadata) RunsIn(stability ClusterStabilityDuringTest) bool {
+	_, ok := m.JUnitPolicy[stability]
+	return ok
+}
+
+// Validate checks that the metadata is internally consistent.
+func (m MonitorTestMetadata) Validate() error {
+	switch m.Criticality {
+	case Critical, Sensitive, Informational:
+	default:
+		return fmt.Errorf("unknown criticality %q", m.Criticality)
+	}
+	if len(m.JUnitPolicy) == 0 {
+		return fmt.Errorf("must run in at least one cluster stability mode")
+	}
+	for stability, policy := range m.JUnitPolicy {
+		if !isKnownClusterStability(stability) {
+			return fmt.Errorf("unknown cluster stability %q", stability)
+		}
+		if m.Criticality == Critical && policy == AsFlake {
+			return fmt.Errorf("%s monitor tests may not flake in %s", m.Criticality, stability)
+		}
+	}
+	return nil
+}
+
+func isKnownClusterStability(stability ClusterStabilityDuringTest) bool {
+	for _, curr := range allClusterStabilities {
+		if curr == stability {
+			return true
+		}
+	}
+	return false
+}
+
+// MonitorTestPolicy is one row of the effective policy table for a run.
+type MonitorTestPolicy struct {
+	MonitorTest                string                       `json:"monitorTest"`
+	JiraComponent              string                       `json:"jiraComponent"`
+	Criticality                MonitorTestCriticality       `json:"criticality"`
+	ClusterStabilityDuringTest ClusterStabilityDuringTest   `json:"clusterStabilityDuringTest,omitempty"`
+	FlakeJunits                bool                         `json:"flakeJunits"`
+	RunsIn                     []ClusterStabilityDuringTest `json:"runsIn"`
+}
+
+// writePolicyTable writes the effective policies for a run so it is clear which monitor tests could fail the job.
+func writePolicyTable(filename string, policies []MonitorTestPolicy) error {
+	jsonContent, err := json.MarshalIndent(policies, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, jsonContent, 0644)
+}
@@ -0,0 +1,201 @@
+package monitortestframework
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type failingMonitorTest struct{}
+
+func (failingMonitorTest) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (failingMonitorTest) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (failingMonitorTest) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (failingMonitorTest) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (failingMonitorTest) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return []*junitapi.JUnitTestCase{
+		{
+			Name:          "[Monitor:failing] always fails",
+			FailureOutput: &junitapi.FailureOutput{Output: "broke"},
+		},
+	}, nil
+}
+
+func (failingMonitorTest) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (failingMonitorTest) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+func TestMonitorTestMetadataValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata MonitorTestMetadata
+		wantErr  bool
+	}{
+		{
+			name:     "sensitive flaking when unstable",
+			metadata: NewMonitorTestMetadata(Sensitive).HardFailIn(Stable).FlakeIn(Disruptive, SpotCheck),
+		},
+		{
+			name:     "critical hard failing everywhere",
+			metadata: NewMonitorTestMetadata(Critical).HardFailIn(Stable, Disruptive, SpotCheck),
+		},
+		{
+			name:     "critical may not flake",
+			metadata: NewMonitorTestMetadata(Critical).HardFailIn(Stable).FlakeIn(Disruptive),
+			wantErr:  true,
+		},
+		{
+			name:     "must run somewhere",
+			metadata: NewMonitorTestMetadata(Informational),
+			wantErr:  true,
+		},
+		{
+			name:     "unknown criticality",
+			metadata: NewMonitorTestMetadata("Bogus").HardFailIn(Stable),
+			wantErr:  true,
+		},
+		{
+			name:     "unknown stability",
+			metadata: NewMonitorTestMetadata(Sensitive).HardFailIn("Upgrade"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.metadata.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMonitorTestMetadataIsCopied(t *testing.T) {
+	base := NewMonitorTestMetadata(Sensitive).HardFailIn(Stable)
+	_ = base.FlakeIn(Disruptive)
+	if base.RunsIn(Disruptive) {
+		t.Fatal("FlakeIn mutated the original metadata")
+	}
+}
+
+func TestGetRegistryForClusterStability(t *testing.T) {
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestWithMetadataOrDie("failing", "Test Framework",
+		NewMonitorTestMetadata(Sensitive).HardFailIn(Stable).FlakeIn(Disruptive), failingMonitorTest{})
+	registry.AddMonitorTestWithMetadataOrDie("stable-only", "Test Framework",
+		NewMonitorTestMetadata(Sensitive).HardFailIn(Stable), failingMonitorTest{})
+
+	tests := []struct {
+		name              string
+		clusterStability  ClusterStabilityDuringTest
+		expectedTests     []string
+		expectedFailures  int
+		expectedPassNames int
+	}{
+		{
+			name:             "stable runs everything as hard failures",
+			clusterStability: Stable,
+			expectedTests:    []string{"failing", "stable-only"},
+			expectedFailures: 2,
+		},
+		{
+			name:              "disruptive runs only the flaking test",
+			clusterStability:  Disruptive,
+			expectedTests:     []string{"failing"},
+			expectedFailures:  1,
+			expectedPassNames: 1,
+		},
+		{
+			name:             "spotcheck runs nothing",
+			clusterStability: SpotCheck,
+			expectedTests:    []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stabilityRegistry, err := registry.GetRegistryForClusterStability(tt.clusterStability)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := stabilityRegistry.ListMonitorTests().List(); len(actual) != len(tt.expectedTests) {
+				t.Fatalf("expected %v, got %v", tt.expectedTests, actual)
+			}
+
+			junits, err := stabilityRegistry.EvaluateTestsFromConstructedIntervals(context.TODO(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			failures, passes := 0, 0
+			for _, junit := range junits {
+				if junit.Name != "[Monitor:failing] always fails" {
+					continue
+				}
+				if junit.FailureOutput != nil {
+					failures++
+				} else {
+					passes++
+				}
+			}
+			if tt.expectedFailures > 0 && failures == 0 {
+				t.Errorf("expected failures, got none")
+			}
+			if passes != tt.expectedPassNames {
+				t.Errorf("expected %d passes to flake the failure, got %d", tt.expectedPassNames, passes)
+			}
+
+			for _, policy := range stabilityRegistry.ListMonitorTestPolicies() {
+				if policy.ClusterStabilityDuringTest != tt.clusterStability {
+					t.Errorf("expected %v, got %v", tt.clusterStability, policy.ClusterStabilityDuringTest)
+				}
+			}
+		})
+	}
+
+	if _, err := registry.GetRegistryForClusterStability("Upgrade"); err == nil {
+		t.Error("expected error for unknown cluster stability")
+	}
+}
+
+func TestAddRegistryKeepsPolicy(t *testing.T) {
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestWithMetadataOrDie("failing", "Test Framework",
+		NewMonitorTestMetadata(Sensitive).HardFailIn(Stable).FlakeIn(Disruptive), failingMonitorTest{})
+	disruptiveRegistry, err := registry.GetRegistryForClusterStability(Disruptive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged := NewMonitorTestRegistry()
+	merged.AddRegistryOrDie(disruptiveRegistry)
+	for _, policy := range merged.ListMonitorTestPolicies() {
+		if !policy.FlakeJunits {
+			t.Errorf("expected %s to keep flaking after the merge", policy.MonitorTest)
+		}
+		if policy.ClusterStabilityDuringTest != Stable {
+			t.Errorf("expected an unnarrowed registry to default to %v, got %q", Stable, policy.ClusterStabilityDuringTest)
+		}
+	}
+}
@@ -182,9 +182,26 @@ type MonitorTestRegistry interface {
 
 	AddMonitorTestOrDie(name, jiraComponent string, monitorTest MonitorTest)
 
+	// AddMonitorTestWithMetadata adds a monitor test along with its criticality and the junit policy for every
+	// cluster stability mode it runs in.  The registry converts junit failures to flakes based on that policy.
+	AddMonitorTestWithMetadata(name, jiraComponent string, metadata MonitorTestMetadata, monitorTest MonitorTest) error
+
+	AddMonitorTestWithMetadataOrDie(name, jiraComponent string, metadata MonitorTestMetadata, monitorTest MonitorTest)
+
+	// GetRegistryForClusterStability returns a registry with only the monitor tests that run in the provided
+	// cluster stability mode, with the junit policy for that mode applied.
+	GetRegistryForClusterStability(clusterStability ClusterStabilityDuringTest) (MonitorTestRegistry, error)
+
 	GetRegistryFor(names ...string) (MonitorTestRegistry, error)
 	ListMonitorTests() sets.String
 
+	// ListMonitorTestPolicies returns the effective policy of every monitor test in the registry.
+	// It is also written to the storage directory during WriteContentToStorage.
+	ListMonitorTestPolicies() []MonitorTestPolicy
+
+	// PhaseTimings returns when every phase of every monitor test ran, ordered by start time.
+	PhaseTimings() []MonitorTestPhaseTiming
+
 	// PrepareCollection is responsible for setting up all resources required for collection of data on the cluster
 	// and returning when preparation is complete.
 	// An error will not stop execution, but will cause a junit failure that will cause the job run to fail.
@@ -227,3 +244,13 @@ type MonitorTestRegistry interface {
 
 	getMonitorTests() map[string]*monitorTesttItem
 }
+
+// MonitorTestPhaseTiming records when a single phase of a monitor test ran.  Phases are named after the junit
+// the registry creates for them: preparation, setup, collection, interval construction, test evaluation,
+// writing to storage, and cleanup.
+type MonitorTestPhaseTiming struct {
+	MonitorTest string
+	Phase       string
+	Start       time.Time
+	End         time.Time
+}
@@ -90,48 +90,15 @@ func (a *alertBuilder) withAllowance(allowanceCalculator AlertTestAllowanceCalcu
 	return a
 }
 
-func (a *alertBuilder) pending() *alertBuilder {
-	a.alertState = AlertPending
-	return a
-}
-
 // inNamespace limits the alert test to a specific namespace.
 func (a *alertBuilder) inNamespace(namespace string) *alertBuilder {
 	a.alertNamespace = namespace
 	return a
 }
 
-func (a *alertBuilder) firing() *alertBuilder {
-	a.alertState = AlertInfo
-	return a
-}
-
-func (a *alertBuilder) warning() *alertBuilder {
-	a.alertState = AlertWarning
-	return a
-}
-
-func (a *alertBuilder) critical() *alertBuilder {
-	a.alertState = AlertCritical
-	return a
-}
-
-func (a *alertBuilder) neverFail() *alertBuilder {
-	a.allowanceCalculator = neverFail(a.allowanceCalculator)
-	return a
-}
-
-// alwaysFlake will flake the test if the alert enters the given state for any amount of time,
-// regardless of historical data.
-func (a *alertBuilder) alwaysFlake() *alertBuilder {
-	a.allowanceCalculator = alwaysFlake()
-	return a
-}
-
-// alwaysFail will fail the test if the alert enters the given state for any amount of time,
-// regardless of historical data.
-func (a *alertBuilder) alwaysFail() *alertBuilder {
-	a.allowanceCalculator = failOnAny()
+// inState sets the state the alert test limits the alert from reaching.
+func (a *alertBuilder) inState(state AlertState) *alertBuilder {
+	a.alertState = state
 	return a
 }
 
@@ -355,12 +322,6 @@ func (a *basicAlertTest) InvariantCheck(allEventIntervals monitorapi.Intervals,
 	state, message := a.failOrFlake(firingIntervals, pendingIntervals)
 
 	switch a.alertName {
-	case "KubeAPIErrorBudgetBurn":
-		// Currently this is a known issue that happens less often on HA but more frequently on single node
-		// TODO: Remove this flake when OCPBUGS-42083 is resolved
-		if state == fail && a.jobType.Topology == "single" {
-			state = flake
-		}
 	case "KubePodNotReady":
 		if state == fail && (kubePodNotReadyDueToImagePullBackoff(resourcesMap["events"], firingIntervals) || kubePodNotReadyDueToErrParsingSignature(resourcesMap["events"], firingIntervals)) {
 			// Since this is due to imagePullBackoff, change the state to flake instead of fail
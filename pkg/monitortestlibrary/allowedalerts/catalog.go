@@ -0,0 +1,471 @@
+package allowedalerts
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// alertCatalogData holds the expectation for every alert that has its own test. Exceptions to the expectations
+// live next to them in the same file, each with an owner, a bug and usually an expiry, so they can be reviewed and
+// cleaned up like any other data.
+//
+//go:embed alert_catalog.json
+var alertCatalogData []byte
+
+// AlertCatalogVersion is the version of the alert catalog format this binary understands.
+const AlertCatalogVersion = 1
+
+// expiryLayout is the format of the expiry dates in the alert catalog. An exception expires at the start of the day.
+const expiryLayout = "2006-01-02"
+
+// AllowanceKind selects how long an alert may be at or above its state before its test flakes or fails.
+type AllowanceKind string
+
+const (
+	// AllowanceHistorical flakes and fails beyond the P95 and P99 of the historical data for the job type
+	AllowanceHistorical AllowanceKind = "historical"
+	// AllowanceNeverFail flakes beyond the P95 of the historical data, but never fails
+	AllowanceNeverFail AllowanceKind = "neverFail"
+	// AllowanceAlwaysFlake flakes if the alert reaches its state at all
+	AllowanceAlwaysFlake AllowanceKind = "alwaysFlake"
+	// AllowanceAlwaysFail fails if the alert reaches its state at all
+	AllowanceAlwaysFail AllowanceKind = "alwaysFail"
+	// AllowanceEtcdRevisionChange allows more time when etcd rolled out revisions while the tests ran
+	AllowanceEtcdRevisionChange AllowanceKind = "etcdRevisionChange"
+)
+
+var knownAllowanceKinds = map[AllowanceKind]bool{
+	AllowanceHistorical:         true,
+	AllowanceNeverFail:          true,
+	AllowanceAlwaysFlake:        true,
+	AllowanceAlwaysFail:         true,
+	AllowanceEtcdRevisionChange: true,
+}
+
+// AlertCatalog is the versioned list of alert expectations.
+type AlertCatalog struct {
+	Version int                `json:"version"`
+	Alerts  []AlertExpectation `json:"alerts"`
+}
+
+// AlertExpectation is how long an alert may be at or above a state, on every job type unless overridden.
+type AlertExpectation struct {
+	AlertName string     `json:"alertName"`
+	State     AlertState `json:"state"`
+
+	// Component owns the alert and its exceptions, and is the bugzilla component in the test name. It is not set
+	// for expectations per namespace, which take the component of every namespace.
+	Component string `json:"component,omitempty"`
+	// Namespace limits the expectation to the alert in one namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// PerNamespace creates a test for every namespace we know the component of, and one for all the others.
+	PerNamespace bool `json:"perNamespace,omitempty"`
+
+	// Allowance defaults to AllowanceHistorical. An allowance that never fails a state other than pending is an
+	// exception, and needs an owner and an expiry.
+	Allowance AllowanceKind `json:"allowance,omitempty"`
+	// Owner is the component responsible for removing the exception.
+	Owner  string `json:"owner,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	Bug    string `json:"bug,omitempty"`
+	// Expires is the day an allowance other than the historical one stops applying, as YYYY-MM-DD.
+	Expires string `json:"expires,omitempty"`
+
+	// Overrides are tried in order and the first one that matches the job type replaces the allowance.
+	Overrides []AlertOverride `json:"overrides,omitempty"`
+}
+
+// AlertOverride is an exception to an expectation for the job types it matches. Empty fields match every job type.
+// Every override needs an owner, a bug and an expiry.
+type AlertOverride struct {
+	Release      string `json:"release,omitempty"`
+	FromRelease  string `json:"fromRelease,omitempty"`
+	Platform     string `json:"platform,omitempty"`
+	Architecture string `json:"architecture,omitempty"`
+	Network      string `json:"network,omitempty"`
+	Topology     string `json:"topology,omitempty"`
+
+	Allowance AllowanceKind `json:"allowance"`
+	// Owner is the component responsible for removing the exception.
+	Owner   string `json:"owner,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Bug     string `json:"bug,omitempty"`
+	Expires string `json:"expires,omitempty"`
+}
+
+var (
+	readAlertCatalog sync.Once
+	alertCatalog     *AlertCatalog
+)
+
+// GetAlertCatalog returns the alert catalog built into this binary.
+func GetAlertCatalog() *AlertCatalog {
+	readAlertCatalog.Do(
+		func() {
+			var err error
+			alertCatalog, err = ParseAlertCatalog(alertCatalogData)
+			if err != nil {
+				panic(err)
+			}
+		})
+
+	return alertCatalog
+}
+
+// ParseAlertCatalog reads an alert catalog, refusing versions this binary does not understand.
+func ParseAlertCatalog(data []byte) (*AlertCatalog, error) {
+	catalog := &AlertCatalog{}
+	if err := json.Unmarshal(data, catalog); err != nil {
+		return nil, fmt.Errorf("error reading alert catalog: %w", err)
+	}
+	if catalog.Version != AlertCatalogVersion {
+		return nil, fmt.Errorf("alert catalog has version %d, expected %d", catalog.Version, AlertCatalogVersion)
+	}
+	return catalog, nil
+}
+
+// matches returns true if every field the override sets is the same in the job type.
+func (o *AlertOverride) matches(jobType *platformidentification.JobType) bool {
+	if jobType == nil {
+		return false
+	}
+	return o.covers(&AlertOverride{
+		Release:      jobType.Release,
+		FromRelease:  jobType.FromRelease,
+		Platform:     jobType.Platform,
+		Architecture: jobType.Architecture,
+		Network:      jobType.Network,
+		Topology:     jobType.Topology,
+	})
+}
+
+// covers returns true if the override matches every job type the other one matches.
+func (o *AlertOverride) covers(other *AlertOverride) bool {
+	for _, field := range []struct{ want, have string }{
+		{o.Release, other.Release},
+		{o.FromRelease, other.FromRelease},
+		{o.Platform, other.Platform},
+		{o.Architecture, other.Architecture},
+		{o.Network, other.Network},
+		{o.Topology, other.Topology},
+	} {
+		if len(field.want) > 0 && field.want != field.have {
+			return false
+		}
+	}
+	return true
+}
+
+// describe names the job types the override matches.
+func (o *AlertOverride) describe() string {
+	var parts []string
+	for _, field := range []struct{ name, value string }{
+		{"release", o.Release},
+		{"fromRelease", o.FromRelease},
+		{"platform", o.Platform},
+		{"architecture", o.Architecture},
+		{"network", o.Network},
+		{"topology", o.Topology},
+	} {
+		if len(field.value) > 0 {
+			parts = append(parts, fmt.Sprintf("%s=%s", field.name, field.value))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// isException returns true if the allowance of the expectation never fails the alert. Pending alerts never fail
+// whatever the alert, so that is the rule for them rather than an exception.
+func (e *AlertExpectation) isException() bool {
+	if e.State == AlertPending {
+		return false
+	}
+	return e.Allowance == AllowanceNeverFail || e.Allowance == AllowanceAlwaysFlake
+}
+
+func expired(expires string, now time.Time) bool {
+	if len(expires) == 0 {
+		return false
+	}
+	day, err := time.Parse(expiryLayout, expires)
+	if err != nil {
+		// an expiry that cannot be read is reported by Validate, and is treated as expired so it does not linger
+		return true
+	}
+	return !now.Before(day)
+}
+
+// allowanceFor returns the allowance that applies to the job type, skipping exceptions that expired.
+func (e *AlertExpectation) allowanceFor(jobType *platformidentification.JobType, now time.Time) AllowanceKind {
+	for i := range e.Overrides {
+		override := &e.Overrides[i]
+		if override.matches(jobType) && !expired(override.Expires, now) {
+			return override.Allowance
+		}
+	}
+	if len(e.Allowance) == 0 || expired(e.Expires, now) {
+		return AllowanceHistorical
+	}
+	return e.Allowance
+}
+
+func allowanceCalculator(kind AllowanceKind, etcdAllowance AlertTestAllowanceCalculator) AlertTestAllowanceCalculator {
+	switch kind {
+	case AllowanceNeverFail:
+		return neverFail(DefaultAllowances)
+	case AllowanceAlwaysFlake:
+		return alwaysFlake()
+	case AllowanceAlwaysFail:
+		return failOnAny()
+	case AllowanceEtcdRevisionChange:
+		if etcdAllowance == nil {
+			return DefaultAllowances
+		}
+		return etcdAllowance
+	default:
+		return DefaultAllowances
+	}
+}
+
+// alertTests creates the tests for every expectation, with the allowances that apply to the job type now.
+func (c *AlertCatalog) alertTests(jobType *platformidentification.JobType, etcdAllowance AlertTestAllowanceCalculator, now time.Time) []AlertTest {
+	ret := []AlertTest{}
+	for i := range c.Alerts {
+		expectation := &c.Alerts[i]
+		builder := newAlertTest(expectation.Component, expectation.AlertName, jobType)
+		if expectation.PerNamespace {
+			builder = newAlertTestPerNamespace(expectation.AlertName, jobType)
+		}
+		ret = append(ret, builder.
+			inState(expectation.State).
+			inNamespace(expectation.Namespace).
+			withAllowance(allowanceCalculator(expectation.allowanceFor(jobType, now), etcdAllowance)).
+			toTests()...)
+	}
+	return ret
+}
+
+// exception is an override, or an expectation that never fails the alert, and stops applying on a given day.
+type exception struct {
+	expectation *AlertExpectation
+	override    *AlertOverride
+}
+
+func (e exception) expires() string {
+	if e.override != nil {
+		return e.override.Expires
+	}
+	return e.expectation.Expires
+}
+
+func (e exception) bug() string {
+	if e.override != nil {
+		return e.override.Bug
+	}
+	return e.expectation.Bug
+}
+
+func (e exception) owner() string {
+	if e.override != nil {
+		return e.override.Owner
+	}
+	return e.expectation.Owner
+}
+
+func (e exception) testName() string {
+	owner := e.owner()
+	if len(owner) == 0 {
+		owner = "Unknown"
+	}
+	name := fmt.Sprintf("[%s][invariant] alert/%s exception for %s", owner, e.expectation.AlertName, e.expectation.State)
+	if len(e.expectation.Namespace) > 0 {
+		name += fmt.Sprintf(" in ns/%s", e.expectation.Namespace)
+	}
+	if e.override != nil {
+		name += fmt.Sprintf(" on %s", e.override.describe())
+	}
+	return name + " should not be expired"
+}
+
+func (e exception) expiredMessage() string {
+	message := fmt.Sprintf("the exception expired on %s and no longer applies, remove it from the alert catalog or extend it", e.expires())
+	if len(e.bug()) > 0 {
+		message += fmt.Sprintf(", see %s", e.bug())
+	}
+	return message
+}
+
+// exceptions returns every exception. Overrides are only included when they match the job type, unless the job type
+// is nil.
+func (c *AlertCatalog) exceptions(jobType *platformidentification.JobType) []exception {
+	var ret []exception
+	for i := range c.Alerts {
+		expectation := &c.Alerts[i]
+		for j := range expectation.Overrides {
+			override := &expectation.Overrides[j]
+			if jobType == nil || override.matches(jobType) {
+				ret = append(ret, exception{expectation: expectation, override: override})
+			}
+		}
+		if expectation.isException() {
+			ret = append(ret, exception{expectation: expectation})
+		}
+	}
+	return ret
+}
+
+// ExpiryJUnits returns a failing junit for every exception that applies to the job type and expired. The exception
+// no longer applies by then, so the alert test may fail as well, but this junit points at the exception to remove and
+// who owns it.
+func (c *AlertCatalog) ExpiryJUnits(jobType *platformidentification.JobType, now time.Time) []*junitapi.JUnitTestCase {
+	ret := []*junitapi.JUnitTestCase{}
+	if jobType == nil {
+		return ret
+	}
+	for _, exception := range c.exceptions(jobType) {
+		if !expired(exception.expires(), now) {
+			continue
+		}
+		message := exception.expiredMessage()
+		ret = append(ret, &junitapi.JUnitTestCase{
+			Name: exception.testName(),
+			FailureOutput: &junitapi.FailureOutput{
+				Output: message,
+			},
+			SystemOut: message,
+		})
+	}
+	return ret
+}
+
+// Expired describes every exception that expired, whatever job types it applies to.
+func (c *AlertCatalog) Expired(now time.Time) []string {
+	var ret []string
+	for _, exception := range c.exceptions(nil) {
+		if expired(exception.expires(), now) {
+			ret = append(ret, fmt.Sprintf("%s: %s", exception.testName(), exception.expiredMessage()))
+		}
+	}
+	return ret
+}
+
+// Validate returns every problem with the catalog: malformed entries, entries that repeat an earlier one, and
+// entries or overrides that can never apply because an earlier one already covers them.
+func (c *AlertCatalog) Validate() []error {
+	var errs []error
+	type expectationKey struct {
+		alertName string
+		state     AlertState
+		namespace string
+	}
+	seen := map[expectationKey]int{}
+	perNamespace := map[expectationKey]int{}
+
+	for i := range c.Alerts {
+		expectation := &c.Alerts[i]
+		where := fmt.Sprintf("alerts[%d] (%s at %s)", i, expectation.AlertName, expectation.State)
+
+		if len(expectation.AlertName) == 0 {
+			errs = append(errs, fmt.Errorf("%s: alertName is required", where))
+		}
+		switch expectation.State {
+		case AlertPending, AlertInfo, AlertWarning, AlertCritical:
+		default:
+			errs = append(errs, fmt.Errorf("%s: unknown state %q", where, expectation.State))
+		}
+		switch {
+		case expectation.PerNamespace && len(expectation.Namespace) > 0:
+			errs = append(errs, fmt.Errorf("%s: perNamespace cannot be limited to a namespace", where))
+		case expectation.PerNamespace && len(expectation.Component) > 0:
+			errs = append(errs, fmt.Errorf("%s: perNamespace takes the component of every namespace, so component must not be set", where))
+		case !expectation.PerNamespace && len(expectation.Component) == 0:
+			errs = append(errs, fmt.Errorf("%s: component is required", where))
+		}
+		if len(expectation.Allowance) > 0 && !knownAllowanceKinds[expectation.Allowance] {
+			errs = append(errs, fmt.Errorf("%s: unknown allowance %q", where, expectation.Allowance))
+		}
+		errs = append(errs, validateExpiry(where, expectation.Expires)...)
+		if expectation.isException() {
+			errs = append(errs, validateException(where, expectation.Owner, expectation.Expires)...)
+		}
+
+		key := expectationKey{alertName: expectation.AlertName, state: expectation.State, namespace: expectation.Namespace}
+		if first, ok := seen[key]; ok {
+			errs = append(errs, fmt.Errorf("%s: duplicates alerts[%d]", where, first))
+		} else {
+			seen[key] = i
+		}
+		allNamespaces := expectationKey{alertName: expectation.AlertName, state: expectation.State}
+		if expectation.PerNamespace {
+			if first, ok := perNamespace[allNamespaces]; ok {
+				errs = append(errs, fmt.Errorf("%s: duplicates alerts[%d]", where, first))
+			} else {
+				perNamespace[allNamespaces] = i
+			}
+		}
+
+		for j := range expectation.Overrides {
+			override := &expectation.Overrides[j]
+			overrideWhere := fmt.Sprintf("%s overrides[%d]", where, j)
+			if len(override.describe()) == 0 {
+				errs = append(errs, fmt.Errorf("%s: matches every job type, change the allowance of the alert instead", overrideWhere))
+			}
+			if !knownAllowanceKinds[override.Allowance] {
+				errs = append(errs, fmt.Errorf("%s: unknown allowance %q", overrideWhere, override.Allowance))
+			}
+			if len(override.Bug) == 0 {
+				errs = append(errs, fmt.Errorf("%s: bug is required for an exception", overrideWhere))
+			}
+			errs = append(errs, validateExpiry(overrideWhere, override.Expires)...)
+			errs = append(errs, validateException(overrideWhere, override.Owner, override.Expires)...)
+			for k := 0; k < j; k++ {
+				if expectation.Overrides[k].covers(override) {
+					errs = append(errs, fmt.Errorf("%s: is shadowed by overrides[%d], which matches every job type it does", overrideWhere, k))
+					break
+				}
+			}
+		}
+	}
+
+	// an expectation per namespace already covers the alert in a single namespace and in all of them
+	for i := range c.Alerts {
+		expectation := &c.Alerts[i]
+		if expectation.PerNamespace {
+			continue
+		}
+		if first, ok := perNamespace[expectationKey{alertName: expectation.AlertName, state: expectation.State}]; ok {
+			errs = append(errs, fmt.Errorf("alerts[%d] (%s at %s): is shadowed by alerts[%d], which has a test for every namespace",
+				i, expectation.AlertName, expectation.State, first))
+		}
+	}
+
+	return errs
+}
+
+func validateException(where, owner, expires string) []error {
+	var errs []error
+	if len(owner) == 0 {
+		errs = append(errs, fmt.Errorf("%s: owner is required for an exception", where))
+	}
+	if len(expires) == 0 {
+		errs = append(errs, fmt.Errorf("%s: expires is required for an exception", where))
+	}
+	return errs
+}
+
+func validateExpiry(where, expires string) []error {
+	if len(expires) == 0 {
+		return nil
+	}
+	if _, err := time.Parse(expiryLayout, expires); err != nil {
+		return []error{fmt.Errorf("%s: expires must be a date like %s: %v", where, expiryLayout, err)}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package allowedalerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+func TestAlertCatalogIsValid(t *testing.T) {
+	catalog, err := ParseAlertCatalog(alertCatalogData)
+	require.NoError(t, err)
+	assert.Empty(t, catalog.Validate())
+}
+
+func TestParseAlertCatalogVersion(t *testing.T) {
+	_, err := ParseAlertCatalog([]byte(`{"version": 2, "alerts": []}`))
+	assert.Error(t, err)
+}
+
+func TestAlertExpectationAllowanceFor(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	expectation := AlertExpectation{
+		AlertName: "KubeAPIErrorBudgetBurn",
+		State:     AlertInfo,
+		Component: "bz-kube-apiserver",
+		Overrides: []AlertOverride{
+			{Topology: "single", Platform: "aws", Allowance: AllowanceAlwaysFail, Expires: "2024-06-01"},
+			{Topology: "single", Allowance: AllowanceNeverFail},
+			{Platform: "metal", Allowance: AllowanceAlwaysFlake, Expires: "2024-07-01"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		jobType  *platformidentification.JobType
+		expected AllowanceKind
+	}{
+		{
+			name:     "no override matches",
+			jobType:  &platformidentification.JobType{Platform: "gcp", Topology: "ha"},
+			expected: AllowanceHistorical,
+		},
+		{
+			name:     "first matching override wins",
+			jobType:  &platformidentification.JobType{Platform: "gcp", Topology: "single"},
+			expected: AllowanceNeverFail,
+		},
+		{
+			name:     "expired override is skipped",
+			jobType:  &platformidentification.JobType{Platform: "aws", Topology: "single"},
+			expected: AllowanceNeverFail,
+		},
+		{
+			name:     "override that has not expired applies",
+			jobType:  &platformidentification.JobType{Platform: "metal", Topology: "ha"},
+			expected: AllowanceAlwaysFlake,
+		},
+		{
+			name:     "unknown job type",
+			expected: AllowanceHistorical,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, expectation.allowanceFor(tt.jobType, now))
+		})
+	}
+
+	expired := AlertExpectation{Allowance: AllowanceNeverFail, Expires: "2024-05-31"}
+	assert.Equal(t, AllowanceHistorical, expired.allowanceFor(&platformidentification.JobType{}, now))
+}
+
+func TestAlertCatalogExpiryJUnits(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	catalog := &AlertCatalog{
+		Version: AlertCatalogVersion,
+		Alerts: []AlertExpectation{
+			{
+				AlertName: "MCDDrainError",
+				State:     AlertInfo,
+				Component: "bz-machine config operator",
+				Allowance: AllowanceNeverFail,
+				Owner:     "bz-machine config operator",
+				Bug:       "https://issues.redhat.com/browse/OCPBUGS-1",
+				Expires:   "2024-07-01",
+				Overrides: []AlertOverride{
+					{Platform: "vsphere", Allowance: AllowanceAlwaysFlake, Owner: "bz-storage", Bug: "https://issues.redhat.com/browse/OCPBUGS-2", Expires: "2024-05-01"},
+					{Platform: "aws", Allowance: AllowanceAlwaysFlake, Owner: "bz-machine config operator", Bug: "https://issues.redhat.com/browse/OCPBUGS-3", Expires: "2024-05-01"},
+				},
+			},
+		},
+	}
+
+	// only the expired exceptions that apply to the job type are reported
+	junits := catalog.ExpiryJUnits(&platformidentification.JobType{Platform: "vsphere"}, now)
+	require.Len(t, junits, 1)
+	assert.Equal(t, "[bz-storage][invariant] alert/MCDDrainError exception for info on platform=vsphere should not be expired", junits[0].Name)
+	require.NotNil(t, junits[0].FailureOutput)
+	assert.Contains(t, junits[0].FailureOutput.Output, "OCPBUGS-2")
+
+	assert.Empty(t, catalog.ExpiryJUnits(&platformidentification.JobType{Platform: "gcp"}, now))
+	assert.Len(t, catalog.Expired(now), 2)
+	assert.Len(t, catalog.Expired(now.AddDate(0, 1, 0)), 3)
+}
+
+func TestAlertCatalogValidate(t *testing.T) {
+	catalog := &AlertCatalog{
+		Version: AlertCatalogVersion,
+		Alerts: []AlertExpectation{
+			{AlertName: "KubePodNotReady", State: AlertPending, PerNamespace: true},
+			{AlertName: "KubePodNotReady", State: AlertPending, Component: "bz-etcd", Namespace: "openshift-etcd"},
+			{
+				AlertName: "etcdNoLeader",
+				State:     AlertInfo,
+				Component: "bz-etcd",
+				Overrides: []AlertOverride{
+					{Topology: "single", Allowance: AllowanceNeverFail, Bug: "https://issues.redhat.com/browse/OCPBUGS-1"},
+					{Topology: "single", Platform: "aws", Allowance: AllowanceAlwaysFail, Bug: "https://issues.redhat.com/browse/OCPBUGS-2"},
+					{Allowance: AllowanceNeverFail, Bug: "https://issues.redhat.com/browse/OCPBUGS-3"},
+					{Platform: "gcp", Allowance: "sometimes", Expires: "next week"},
+				},
+			},
+			{AlertName: "etcdNoLeader", State: AlertInfo, Component: "bz-etcd"},
+			{AlertName: "TargetDown", State: "firing"},
+			{AlertName: "KubeMemoryOvercommit", State: AlertInfo, Component: "bz-single-node", Allowance: AllowanceNeverFail},
+			{AlertName: "KubeMemoryOvercommit", State: AlertPending, Component: "bz-single-node", Allowance: AllowanceNeverFail},
+		},
+	}
+
+	var messages []string
+	for _, err := range catalog.Validate() {
+		messages = append(messages, err.Error())
+	}
+	assert.ElementsMatch(t, []string{
+		"alerts[2] (etcdNoLeader at info) overrides[0]: owner is required for an exception",
+		"alerts[2] (etcdNoLeader at info) overrides[0]: expires is required for an exception",
+		"alerts[2] (etcdNoLeader at info) overrides[1]: owner is required for an exception",
+		"alerts[2] (etcdNoLeader at info) overrides[1]: expires is required for an exception",
+		"alerts[2] (etcdNoLeader at info) overrides[2]: owner is required for an exception",
+		"alerts[2] (etcdNoLeader at info) overrides[2]: expires is required for an exception",
+		"alerts[2] (etcdNoLeader at info) overrides[3]: owner is required for an exception",
+		"alerts[2] (etcdNoLeader at info) overrides[1]: is shadowed by overrides[0], which matches every job type it does",
+		"alerts[2] (etcdNoLeader at info) overrides[2]: matches every job type, change the allowance of the alert instead",
+		"alerts[2] (etcdNoLeader at info) overrides[3]: unknown allowance \"sometimes\"",
+		"alerts[2] (etcdNoLeader at info) overrides[3]: bug is required for an exception",
+		"alerts[2] (etcdNoLeader at info) overrides[3]: expires must be a date like 2006-01-02: parsing time \"next week\" as \"2006-01-02\": cannot parse \"next week\" as \"2006\"",
+		"alerts[2] (etcdNoLeader at info) overrides[3]: is shadowed by overrides[2], which matches every job type it does",
+		"alerts[3] (etcdNoLeader at info): duplicates alerts[2]",
+		"alerts[4] (TargetDown at firing): unknown state \"firing\"",
+		"alerts[4] (TargetDown at firing): component is required",
+		"alerts[5] (KubeMemoryOvercommit at info): owner is required for an exception",
+		"alerts[5] (KubeMemoryOvercommit at info): expires is required for an exception",
+		"alerts[1] (KubePodNotReady at pending): is shadowed by alerts[0], which has a test for every namespace",
+	}, messages)
+}
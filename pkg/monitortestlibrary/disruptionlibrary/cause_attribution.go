@@ -0,0 +1,320 @@
+package disruptionlibrary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// CauseKind is a kind of cluster event that is known to cause disruption.
+type CauseKind string
+
+const (
+	CauseIncompleteAPIServerShutdown CauseKind = "IncompleteAPIServerShutdown"
+	CauseAPIServerShutdown           CauseKind = "APIServerShutdown"
+	CauseNodeReboot                  CauseKind = "NodeReboot"
+	CauseNodeNotReady                CauseKind = "NodeNotReady"
+	CauseLoadBalancerUnreachable     CauseKind = "LoadBalancerUnreachable"
+	CauseHaproxyBackendDown          CauseKind = "HaproxyBackendDown"
+	CauseKeepalivedVIPMove           CauseKind = "KeepalivedVIPMove"
+	CauseEtcdLeaderChange            CauseKind = "EtcdLeaderChange"
+	CauseUnknown                     CauseKind = "Unknown"
+)
+
+// causeLookback is how long before a disruption began we still consider an event that already ended to be a
+// likely cause.  Clients notice a dead endpoint some time after it happened, connections in flight time out, and
+// load balancers take a few health checks to take a backend out.
+const causeLookback = 60 * time.Second
+
+// causeWeight ranks the kinds of cause against each other when they are equally close to a disruption.  An
+// apiserver that did not finish shutting down gracefully is very likely to drop requests, an etcd leader change
+// usually only slows them down.
+var causeWeight = map[CauseKind]float64{
+	CauseIncompleteAPIServerShutdown: 1.0,
+	CauseAPIServerShutdown:           0.9,
+	CauseNodeReboot:                  0.9,
+	CauseNodeNotReady:                0.8,
+	CauseLoadBalancerUnreachable:     0.8,
+	CauseHaproxyBackendDown:          0.7,
+	CauseKeepalivedVIPMove:           0.7,
+	CauseEtcdLeaderChange:            0.5,
+}
+
+// LikelyCause is a candidate cause of a disruption with how likely we think it is, higher is more likely.
+type LikelyCause struct {
+	Kind CauseKind
+	// Subject is what the cause happened to, usually a node.
+	Subject  string
+	Score    float64
+	Interval monitorapi.Interval
+}
+
+func (c LikelyCause) String() string {
+	return fmt.Sprintf("%s %s (%.2f)", c.Kind, c.Subject, c.Score)
+}
+
+// causeCandidate is an interval that could explain a disruption.
+type causeCandidate struct {
+	kind     CauseKind
+	subject  string
+	interval monitorapi.Interval
+}
+
+// classifyCause decides whether an interval is a candidate cause.  It recognizes both the raw intervals, which is all
+// we have while computed intervals are constructed, and the intervals other monitor tests compute from them, which
+// we have by the time tests are evaluated.
+func classifyCause(interval monitorapi.Interval) (CauseKind, bool) {
+	reason := interval.Message.Reason
+	_, hasNode := interval.Locator.Keys[monitorapi.LocatorNodeKey]
+
+	switch {
+	case interval.Source == monitorapi.APIServerGracefulShutdown && reason == monitorapi.IncompleteAPIServerShutdown:
+		return CauseIncompleteAPIServerShutdown, true
+	case interval.Source == monitorapi.APIServerGracefulShutdown:
+		return CauseAPIServerShutdown, true
+	// openshift-apiserver still is using the old event name TerminationStart
+	case reason == "ShutdownInitiated", reason == "TerminationStart", reason == "TerminationGracefulTerminationFinished":
+		return CauseAPIServerShutdown, true
+
+	case interval.Source == monitorapi.SourceNodeState && interval.Message.Annotations[monitorapi.AnnotationPhase] == "Reboot":
+		return CauseNodeReboot, true
+	case hasNode && reason == "Reboot":
+		return CauseNodeReboot, true
+	case (interval.Source == monitorapi.SourceNodeState || interval.Source == monitorapi.SourceNodeMonitor) && reason == monitorapi.NodeNotReadyReason:
+		return CauseNodeNotReady, true
+	case interval.Source == monitorapi.SourceUnexpectedReady, interval.Source == monitorapi.SourceUnreachable:
+		return CauseNodeNotReady, true
+
+	case interval.Source == monitorapi.SourceAPIUnreachableFromClient:
+		return CauseLoadBalancerUnreachable, true
+	case interval.Source == monitorapi.SourceHaproxyMonitor:
+		return CauseHaproxyBackendDown, true
+	case interval.Source == monitorapi.SourceKeepalivedMonitor && (reason == monitorapi.OnPremLBTookVIP || reason == monitorapi.OnPremLBLostVIP):
+		return CauseKeepalivedVIPMove, true
+
+	// the computed leadership intervals start with whoever led when the run began, so only the raw changes count.
+	case interval.Source == monitorapi.SourceEtcdLeadership && (reason == "LeaderElected" || reason == "LeaderLost" || reason == "LeaderMissing"):
+		return CauseEtcdLeaderChange, true
+	}
+
+	return "", false
+}
+
+func causeSubject(interval monitorapi.Interval) string {
+	if node, ok := interval.Locator.Keys[monitorapi.LocatorNodeKey]; ok && len(node) > 0 {
+		return fmt.Sprintf("node/%s", node)
+	}
+	return interval.Locator.OldLocator()
+}
+
+func findCauseCandidates(intervals monitorapi.Intervals) []causeCandidate {
+	ret := []causeCandidate{}
+	for _, interval := range intervals {
+		kind, ok := classifyCause(interval)
+		if !ok {
+			continue
+		}
+		ret = append(ret, causeCandidate{kind: kind, subject: causeSubject(interval), interval: interval})
+	}
+	return ret
+}
+
+// CauseIndex holds the candidate causes found in intervals ordered by start, so many disruptions can be attributed
+// while only looking at the candidates close to each of them.
+type CauseIndex struct {
+	candidates []causeCandidate
+	// reach is the latest end of the candidates up to and including the same index.  Intervals that never ended
+	// reach forever.
+	reach []time.Time
+}
+
+// NewCauseIndex finds the candidate causes in intervals once.
+func NewCauseIndex(intervals monitorapi.Intervals) *CauseIndex {
+	candidates := findCauseCandidates(intervals)
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].interval.From.Before(candidates[j].interval.From) })
+
+	forever := time.Unix(1<<62, 0)
+	reach := make([]time.Time, len(candidates))
+	for i, candidate := range candidates {
+		to := candidate.interval.To
+		if to.IsZero() {
+			to = forever
+		}
+		if i > 0 && reach[i-1].After(to) {
+			to = reach[i-1]
+		}
+		reach[i] = to
+	}
+	return &CauseIndex{candidates: candidates, reach: reach}
+}
+
+// near returns the candidates that started before the disruption ended, without those that all ended more than the
+// lookback before it began.
+func (c *CauseIndex) near(disruption monitorapi.Interval) []causeCandidate {
+	disruptionTo := disruption.To
+	if disruptionTo.IsZero() {
+		disruptionTo = disruption.From
+	}
+	lookbackStart := disruption.From.Add(-causeLookback)
+	first := sort.Search(len(c.reach), func(i int) bool { return !c.reach[i].Before(lookbackStart) })
+	last := sort.Search(len(c.candidates), func(i int) bool { return c.candidates[i].interval.From.After(disruptionTo) })
+	if first >= last {
+		return nil
+	}
+	return c.candidates[first:last]
+}
+
+// LikelyCauses ranks the candidate causes that could have caused the disruption, most likely first.
+func (c *CauseIndex) LikelyCauses(disruption monitorapi.Interval) []LikelyCause {
+	return likelyCauses(disruption, c.near(disruption))
+}
+
+// temporalScore is how well the timing of a candidate explains a disruption: 1 when they overlap, decaying from 0.75
+// to 0.25 over the lookback when the candidate ended shortly before the disruption, and 0 otherwise.
+func temporalScore(disruption, candidate monitorapi.Interval) float64 {
+	disruptionTo := disruption.To
+	if disruptionTo.IsZero() {
+		disruptionTo = disruption.From
+	}
+	if candidate.From.After(disruptionTo) {
+		return 0
+	}
+	// an interval that never ended is still going on
+	if candidate.To.IsZero() || !candidate.To.Before(disruption.From) {
+		return 1
+	}
+
+	gap := disruption.From.Sub(candidate.To)
+	if gap > causeLookback {
+		return 0
+	}
+	return 0.75 - 0.5*float64(gap)/float64(causeLookback)
+}
+
+func likelyCauses(disruption monitorapi.Interval, candidates []causeCandidate) []LikelyCause {
+	// the same thing happening to the same subject is one cause, however many intervals it produced.
+	bestByKey := map[string]LikelyCause{}
+	for _, candidate := range candidates {
+		temporal := temporalScore(disruption, candidate.interval)
+		if temporal == 0 {
+			continue
+		}
+		cause := LikelyCause{
+			Kind:     candidate.kind,
+			Subject:  candidate.subject,
+			Score:    temporal * causeWeight[candidate.kind],
+			Interval: candidate.interval,
+		}
+		key := fmt.Sprintf("%s %s", cause.Kind, cause.Subject)
+		if existing, ok := bestByKey[key]; !ok || cause.Score > existing.Score {
+			bestByKey[key] = cause
+		}
+	}
+
+	ret := []LikelyCause{}
+	for _, cause := range bestByKey {
+		ret = append(ret, cause)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Score != ret[j].Score {
+			return ret[i].Score > ret[j].Score
+		}
+		if ret[i].Kind != ret[j].Kind {
+			return ret[i].Kind < ret[j].Kind
+		}
+		return ret[i].Subject < ret[j].Subject
+	})
+	return ret
+}
+
+// IsDisruptionBegan selects the intervals that mark a backend being unreachable.
+func IsDisruptionBegan(interval monitorapi.Interval) bool {
+	return interval.Source == monitorapi.SourceDisruption && interval.Message.Reason == monitorapi.DisruptionBeganEventReason
+}
+
+// AttributeDisruptionCauses returns an interval for every disruption in intervals, covering the same time and
+// locator, annotated with the ranked likely causes.  A disruption with no candidate cause is attributed to Unknown.
+func AttributeDisruptionCauses(intervals monitorapi.Intervals) monitorapi.Intervals {
+	index := NewCauseIndex(intervals)
+
+	ret := monitorapi.Intervals{}
+	for _, disruption := range intervals {
+		if !IsDisruptionBegan(disruption) {
+			continue
+		}
+
+		causes := index.LikelyCauses(disruption)
+		ranked := []string{}
+		for _, cause := range causes {
+			ranked = append(ranked, cause.String())
+		}
+		humanMessage := "no likely cause found"
+		if len(causes) == 0 {
+			ranked = append(ranked, string(CauseUnknown))
+		} else {
+			humanMessage = fmt.Sprintf("likely caused by %s %s", causes[0].Kind, causes[0].Subject)
+		}
+
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceDisruptionCauseAttribution, monitorapi.Info).
+				Locator(disruption.Locator).
+				Message(monitorapi.NewMessage().
+					Reason(monitorapi.DisruptionCauseAttributedReason).
+					Constructed(monitorapi.ConstructionOwnerDisruptionCauseAttribution).
+					WithAnnotation(monitorapi.AnnotationLikelyCauses, strings.Join(ranked, ", ")).
+					HumanMessage(humanMessage),
+				).
+				Build(disruption.From, disruption.To),
+		)
+	}
+	return ret
+}
+
+// CauseBreakdown summarizes what most likely caused each of the disruptions, using the candidate causes found in
+// intervals.  Disruption is attributed to the top ranked cause only, so the durations add up to the total.
+func CauseBreakdown(disruptions, intervals monitorapi.Intervals) string {
+	if len(disruptions) == 0 {
+		return ""
+	}
+	index := NewCauseIndex(intervals)
+
+	type kindTotal struct {
+		kind     CauseKind
+		count    int
+		duration time.Duration
+	}
+	totals := map[CauseKind]*kindTotal{}
+	for _, disruption := range disruptions {
+		kind := CauseUnknown
+		if causes := index.LikelyCauses(disruption); len(causes) > 0 {
+			kind = causes[0].Kind
+		}
+		if _, ok := totals[kind]; !ok {
+			totals[kind] = &kindTotal{kind: kind}
+		}
+		totals[kind].count++
+		if !disruption.To.IsZero() {
+			totals[kind].duration += disruption.To.Sub(disruption.From)
+		}
+	}
+
+	sorted := []*kindTotal{}
+	for _, total := range totals {
+		sorted = append(sorted, total)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].duration != sorted[j].duration {
+			return sorted[i].duration > sorted[j].duration
+		}
+		return sorted[i].kind < sorted[j].kind
+	})
+
+	lines := []string{"likely causes of disruption:"}
+	for _, total := range sorted {
+		lines = append(lines, fmt.Sprintf("  %s: %d intervals, %s", total.kind, total.count, total.duration.Round(time.Second)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,140 @@
+package disruptionlibrary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func disruptionInterval(from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+		Locator(monitorapi.NewLocator().LocateDisruptionCheck("kube-api-new-connections", "openshift-tests", monitorapi.NewConnectionType)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).HumanMessage("stopped responding")).
+		Build(from, to)
+}
+
+func shutdownInterval(node string, reason monitorapi.IntervalReason, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.APIServerGracefulShutdown, monitorapi.Info).
+		Locator(monitorapi.NewLocator().LocateServer("kube-apiserver", node, "openshift-kube-apiserver", "kube-apiserver-"+node)).
+		Message(monitorapi.NewMessage().Reason(reason).HumanMessage("shutting down")).
+		Build(from, to)
+}
+
+func leaderChangeInterval(node string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceEtcdLeadership, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName(node)).
+		Message(monitorapi.NewMessage().Reason("LeaderElected").HumanMessage("elected leader")).
+		Build(from, to)
+}
+
+func TestLikelyCauses(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	disruption := disruptionInterval(start, start.Add(10*time.Second))
+
+	tests := []struct {
+		name      string
+		intervals monitorapi.Intervals
+		expected  []string
+	}{
+		{
+			name: "overlapping shutdown outranks preceding leader change",
+			intervals: monitorapi.Intervals{
+				leaderChangeInterval("master-1", start.Add(-20*time.Second), start.Add(-19*time.Second)),
+				shutdownInterval("master-0", "GracefulAPIServerShutdown", start.Add(-5*time.Second), start.Add(5*time.Second)),
+			},
+			expected: []string{"APIServerShutdown node/master-0 (0.90)", "EtcdLeaderChange node/master-1 (0.30)"},
+		},
+		{
+			name: "incomplete shutdown outranks graceful shutdown",
+			intervals: monitorapi.Intervals{
+				shutdownInterval("master-0", "GracefulAPIServerShutdown", start, start.Add(5*time.Second)),
+				shutdownInterval("master-1", monitorapi.IncompleteAPIServerShutdown, start, start.Add(5*time.Second)),
+			},
+			expected: []string{"IncompleteAPIServerShutdown node/master-1 (1.00)", "APIServerShutdown node/master-0 (0.90)"},
+		},
+		{
+			name: "repeated causes on the same subject are one cause",
+			intervals: monitorapi.Intervals{
+				leaderChangeInterval("master-1", start.Add(-30*time.Second), start.Add(-30*time.Second)),
+				leaderChangeInterval("master-1", start.Add(2*time.Second), start.Add(3*time.Second)),
+			},
+			expected: []string{"EtcdLeaderChange node/master-1 (0.50)"},
+		},
+		{
+			name: "causes before the lookback and after the disruption are ignored",
+			intervals: monitorapi.Intervals{
+				leaderChangeInterval("master-1", start.Add(-2*time.Minute), start.Add(-90*time.Second)),
+				shutdownInterval("master-0", "GracefulAPIServerShutdown", start.Add(time.Minute), start.Add(2*time.Minute)),
+			},
+		},
+		{
+			name: "long running causes that started well before are found",
+			intervals: monitorapi.Intervals{
+				shutdownInterval("master-0", "GracefulAPIServerShutdown", start.Add(-time.Hour), start.Add(time.Hour)),
+				leaderChangeInterval("master-1", start.Add(-10*time.Minute), start.Add(-10*time.Minute)),
+				leaderChangeInterval("master-2", start.Add(-5*time.Minute), time.Time{}),
+			},
+			expected: []string{"APIServerShutdown node/master-0 (0.90)", "EtcdLeaderChange node/master-2 (0.50)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := []string{}
+			for _, cause := range NewCauseIndex(tt.intervals).LikelyCauses(disruption) {
+				actual = append(actual, cause.String())
+			}
+			if len(tt.expected) == 0 {
+				assert.Empty(t, actual)
+				return
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestAttributeDisruptionCauses(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	explained := disruptionInterval(start, start.Add(10*time.Second))
+	unexplained := disruptionInterval(start.Add(time.Hour), start.Add(time.Hour+3*time.Second))
+	intervals := monitorapi.Intervals{
+		explained,
+		unexplained,
+		shutdownInterval("master-0", monitorapi.IncompleteAPIServerShutdown, start.Add(-5*time.Second), start.Add(5*time.Second)),
+	}
+
+	actual := AttributeDisruptionCauses(intervals)
+	require.Len(t, actual, 2)
+
+	assert.Equal(t, monitorapi.SourceDisruptionCauseAttribution, actual[0].Source)
+	assert.Equal(t, monitorapi.Info, actual[0].Level)
+	assert.Equal(t, explained.Locator, actual[0].Locator)
+	assert.Equal(t, explained.From, actual[0].From)
+	assert.Equal(t, explained.To, actual[0].To)
+	assert.Equal(t, "IncompleteAPIServerShutdown node/master-0 (1.00)", actual[0].Message.Annotations[monitorapi.AnnotationLikelyCauses])
+	assert.Equal(t, "likely caused by IncompleteAPIServerShutdown node/master-0", actual[0].Message.HumanMessage)
+
+	assert.Equal(t, "Unknown", actual[1].Message.Annotations[monitorapi.AnnotationLikelyCauses])
+	assert.Equal(t, "no likely cause found", actual[1].Message.HumanMessage)
+}
+
+func TestCauseBreakdown(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	disruptions := monitorapi.Intervals{
+		disruptionInterval(start, start.Add(10*time.Second)),
+		disruptionInterval(start.Add(20*time.Second), start.Add(25*time.Second)),
+		disruptionInterval(start.Add(time.Hour), start.Add(time.Hour+3*time.Second)),
+	}
+	intervals := append(monitorapi.Intervals{
+		shutdownInterval("master-0", "GracefulAPIServerShutdown", start.Add(-5*time.Second), start.Add(30*time.Second)),
+	}, disruptions...)
+
+	assert.Equal(t, "likely causes of disruption:\n"+
+		"  APIServerShutdown: 2 intervals, 15s\n"+
+		"  Unknown: 1 intervals, 3s",
+		CauseBreakdown(disruptions, intervals))
+	assert.Empty(t, CauseBreakdown(nil, intervals))
+}
@@ -39,8 +39,8 @@ func NewAvailabilityInvariant(
 	return &Availability{
 		newConnectionTestName:             newConnectionTestName,
 		reusedConnectionTestName:          reusedConnectionTestName,
-		newConnectionDisruptionSampler:    newConnectionDisruptionSampler,
-		reusedConnectionDisruptionSampler: reusedConnectionDisruptionSampler,
+		newConnectionDisruptionSampler:    newConnectionDisruptionSampler.WithPhaseTimingsFile(),
+		reusedConnectionDisruptionSampler: reusedConnectionDisruptionSampler.WithPhaseTimingsFile(),
 	}
 }
 
@@ -100,12 +100,25 @@ func (w *Availability) CollectData(ctx context.Context) (monitorapi.Intervals, [
 	return nil, nil, utilerrors.NewAggregate([]error{newRecoverErr, reusedRecoverErr})
 }
 
+// WriteContentToStorage writes the phase timings of every sample from both samplers to storageDir.
+func (w *Availability) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string) error {
+	if w == nil {
+		return fmt.Errorf("unable to write content because instance is nil")
+	}
+
+	return utilerrors.NewAggregate([]error{
+		w.newConnectionDisruptionSampler.WritePhaseTimings(storageDir, timeSuffix),
+		w.reusedConnectionDisruptionSampler.WritePhaseTimings(storageDir, timeSuffix),
+	})
+}
+
 func createDisruptionJunit(
 	testName string,
 	allowedDisruption *time.Duration,
 	disruptionDetails string,
 	locator monitorapi.Locator,
 	disruptedIntervals monitorapi.Intervals,
+	causeBreakdown string,
 	jobType *platformidentification.JobType) *junitapi.JUnitTestCase {
 
 	// Not sure what these are, but this will help find them, and we don't get any value from testing these:
@@ -179,15 +192,17 @@ func createDisruptionJunit(
 
 	if roundedDisruptionDuration <= finalAllowedDisruption {
 		return &junitapi.JUnitTestCase{
-			Name: testName,
+			Name:      testName,
+			SystemOut: causeBreakdown,
 		}
 	}
 
 	reason := fmt.Sprintf("%v was unreachable during disruption: %v", locator.OldLocator(), disruptionDetails)
 	describe := disruptedIntervals.Strings()
-	failureMessage := fmt.Sprintf("%s for at least %s (maxAllowed=%s):\n%s\n\n%s", reason,
+	failureMessage := fmt.Sprintf("%s for at least %s (maxAllowed=%s):\n%s\n\n%s\n\n%s", reason,
 		roundedDisruptionDuration, finalAllowedDisruption,
 		strings.Join(allowedDetails, "\n"),
+		causeBreakdown,
 		strings.Join(describe, "\n"))
 
 	return &junitapi.JUnitTestCase{
@@ -204,14 +219,16 @@ func (w *Availability) junitForNewConnections(ctx context.Context, finalInterval
 	if err != nil {
 		return nil, fmt.Errorf("unable to get new allowed disruption: %w", err)
 	}
+	disruptedIntervals := finalIntervals.Filter(
+		monitorapi.And(
+			monitorapi.IsEventForLocator(w.newConnectionDisruptionSampler.GetLocator()),
+			monitorapi.IsErrorEvent,
+		),
+	)
 	return createDisruptionJunit(
 			w.newConnectionTestName, newConnectionAllowed, newConnectionDisruptionDetails, w.newConnectionDisruptionSampler.GetLocator(),
-			finalIntervals.Filter(
-				monitorapi.And(
-					monitorapi.IsEventForLocator(w.newConnectionDisruptionSampler.GetLocator()),
-					monitorapi.IsErrorEvent,
-				),
-			),
+			disruptedIntervals,
+			CauseBreakdown(disruptedIntervals, finalIntervals),
 			jobType,
 		),
 		nil
@@ -222,14 +239,16 @@ func (w *Availability) junitForReusedConnections(ctx context.Context, finalInter
 	if err != nil {
 		return nil, fmt.Errorf("unable to get reused allowed disruption: %w", err)
 	}
+	disruptedIntervals := finalIntervals.Filter(
+		monitorapi.And(
+			monitorapi.IsEventForLocator(w.reusedConnectionDisruptionSampler.GetLocator()),
+			monitorapi.IsErrorEvent,
+		),
+	)
 	return createDisruptionJunit(
 			w.reusedConnectionTestName, reusedConnectionAllowed, reusedConnectionDisruptionDetails, w.reusedConnectionDisruptionSampler.GetLocator(),
-			finalIntervals.Filter(
-				monitorapi.And(
-					monitorapi.IsEventForLocator(w.reusedConnectionDisruptionSampler.GetLocator()),
-					monitorapi.IsErrorEvent,
-				),
-			),
+			disruptedIntervals,
+			CauseBreakdown(disruptedIntervals, finalIntervals),
 			jobType,
 		),
 		nil
@@ -0,0 +1,44 @@
+// Package junittest holds the assertions the unit tests of monitor tests make on the junits they evaluate.
+package junittest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// ExpectJUnits checks that count junits were returned and all are named name. When expectFailure is set the first
+// must fail with output starting with it, when expectPass is set the last must pass. A flake is a failing junit
+// followed by a passing one.
+func ExpectJUnits(t *testing.T, junits []*junitapi.JUnitTestCase, name string, count int, expectFailure string, expectPass bool) {
+	t.Helper()
+	if len(junits) != count {
+		t.Fatalf("expected %d %q junits, got %d", count, name, len(junits))
+	}
+	if count == 0 {
+		return
+	}
+	for _, junit := range junits {
+		if junit.Name != name {
+			t.Errorf("expected %q, got %q", name, junit.Name)
+		}
+	}
+	if len(expectFailure) > 0 && (junits[0].FailureOutput == nil || !strings.HasPrefix(junits[0].FailureOutput.Output, expectFailure)) {
+		t.Errorf("expected failure to start with %q, got %v", expectFailure, junits[0].FailureOutput)
+	}
+	if last := junits[len(junits)-1]; expectPass && last.FailureOutput != nil {
+		t.Errorf("unexpected failure: %s", last.FailureOutput.Output)
+	}
+}
+
+// ExpectSingleJUnit checks that no junit was returned unless expectJUnit is set, and otherwise a single junit named
+// name that fails with output starting with expectFailure, or passes when expectFailure is empty.
+func ExpectSingleJUnit(t *testing.T, junits []*junitapi.JUnitTestCase, name string, expectJUnit bool, expectFailure string) {
+	t.Helper()
+	count := 0
+	if expectJUnit {
+		count = 1
+	}
+	ExpectJUnits(t, junits, name, count, expectFailure, len(expectFailure) == 0)
+}
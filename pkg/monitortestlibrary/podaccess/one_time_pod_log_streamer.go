@@ -20,6 +20,7 @@ type OneTimePodStreamer struct {
 	namespace     string
 	podName       string
 	containerName string
+	sinceTime     *metav1.Time
 
 	logHandlers []LogHandler
 }
@@ -34,6 +35,12 @@ func NewOneTimePodStreamer(kubeClient kubernetes.Interface, namespace, podName,
 	}
 }
 
+// WithSinceTime makes the streamer only read the lines logged after sinceTime, instead of the whole log.
+func (s *OneTimePodStreamer) WithSinceTime(sinceTime time.Time) *OneTimePodStreamer {
+	s.sinceTime = &metav1.Time{Time: sinceTime}
+	return s
+}
+
 func (s *OneTimePodStreamer) ReadLog(ctx context.Context) error {
 	currPod, err := s.kubeClient.CoreV1().Pods(s.namespace).Get(ctx, s.podName, metav1.GetOptions{})
 	if err != nil {
@@ -52,6 +59,7 @@ func (s *OneTimePodStreamer) streamLogsReader(ctx context.Context, currPod *core
 		Container:  s.containerName,
 		Follow:     false,
 		Timestamps: true,
+		SinceTime:  s.sinceTime,
 	}).Stream(ctx)
 	if err != nil {
 		return err
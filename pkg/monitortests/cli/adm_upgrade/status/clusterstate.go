@@ -0,0 +1,306 @@
+package admupgradestatus
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// A snapshot is timestamped before oc runs, and oc is retried for up to two minutes on apiserver unavailability,
+	// so the state it reports may be from any time shortly after the timestamp. The monitors watching the cluster
+	// see changes with a small delay of their own. We only compare against state that held over the whole window.
+	toleranceBefore = time.Minute
+	toleranceAfter  = 3 * time.Minute
+
+	masterPool = "master"
+)
+
+var (
+	operatorsUpdatedPattern = regexp.MustCompile(`\((\d+) operators? updated`)
+	poolCompletionPattern   = regexp.MustCompile(`\(\d+/(\d+)\)`)
+	poolDrainingPattern     = regexp.MustCompile(`(\d+) Draining`)
+)
+
+// change is a value a resource started to report at a point in time.
+type change struct {
+	at    time.Time
+	value string
+}
+
+// history holds the changes of a single value of a single resource, ordered by time.
+type history []change
+
+// valueAt returns the value in effect at t, false when nothing was observed before t.
+func (h history) valueAt(t time.Time) (string, bool) {
+	i := sort.Search(len(h), func(i int) bool { return h[i].at.After(t) })
+	if i == 0 {
+		return "", false
+	}
+	return h[i-1].value, true
+}
+
+// stableValue returns the value in effect over the whole from-to window, false when it was unknown at from or
+// changed within the window. Resources are observed again with the same value, which is not a change.
+func (h history) stableValue(from, to time.Time) (string, bool) {
+	value, ok := h.valueAt(from)
+	if !ok {
+		return "", false
+	}
+	for _, c := range h {
+		if c.at.After(from) && !c.at.After(to) && c.value != value {
+			return "", false
+		}
+	}
+	return value, true
+}
+
+// clusterState is the state of the cluster as recorded by the other monitor tests.
+type clusterState struct {
+	cvProgressing    history
+	coProgressing    map[string]history
+	coVersions       map[string]history
+	poolMachineCount map[string]history
+	// poolDrains holds the Cordon and Drain phases of the nodes in each pool
+	poolDrains map[string]monitorapi.Intervals
+}
+
+func newClusterState(finalIntervals monitorapi.Intervals) *clusterState {
+	state := &clusterState{
+		coProgressing:    map[string]history{},
+		coVersions:       map[string]history{},
+		poolMachineCount: map[string]history{},
+		poolDrains:       map[string]monitorapi.Intervals{},
+	}
+
+	for _, interval := range finalIntervals {
+		annotations := interval.Message.Annotations
+		switch {
+		case interval.Source == monitorapi.SourceClusterOperatorMonitor && interval.Locator.Type == monitorapi.LocatorTypeClusterVersion:
+			if annotations[monitorapi.AnnotationCondition] == string(configv1.OperatorProgressing) {
+				state.cvProgressing = append(state.cvProgressing, change{at: interval.From, value: annotations[monitorapi.AnnotationStatus]})
+			}
+
+		case interval.Source == monitorapi.SourceClusterOperatorMonitor && interval.Locator.Type == monitorapi.LocatorTypeClusterOperator:
+			name := interval.Locator.Keys[monitorapi.LocatorClusterOperatorKey]
+			if annotations[monitorapi.AnnotationCondition] == string(configv1.OperatorProgressing) {
+				state.coProgressing[name] = append(state.coProgressing[name], change{at: interval.From, value: annotations[monitorapi.AnnotationStatus]})
+			}
+			if version, ok := annotations[monitorapi.AnnotationOperatorVersion]; ok {
+				state.coVersions[name] = append(state.coVersions[name], change{at: interval.From, value: version})
+			}
+
+		case interval.Message.Reason == monitorapi.MachineConfigPoolMachineCountChangedReason:
+			pool := interval.Locator.Keys[monitorapi.LocatorMachineConfigPoolKey]
+			state.poolMachineCount[pool] = append(state.poolMachineCount[pool], change{at: interval.From, value: annotations[monitorapi.AnnotationCount]})
+
+		case interval.Message.Reason == monitorapi.MachineConfigNodePhaseReason:
+			// the node rollout phases recorded by the machine-config-rollout monitor test
+			if phase := annotations[monitorapi.AnnotationPhase]; phase == "Cordon" || phase == "Drain" {
+				pool := annotations[monitorapi.AnnotationMachineConfigPool]
+				state.poolDrains[pool] = append(state.poolDrains[pool], interval)
+			}
+		}
+	}
+
+	histories := []history{state.cvProgressing}
+	for _, byName := range []map[string]history{state.coProgressing, state.coVersions, state.poolMachineCount} {
+		for _, h := range byName {
+			histories = append(histories, h)
+		}
+	}
+	for _, h := range histories {
+		sort.SliceStable(h, func(i, j int) bool { return h[i].at.Before(h[j].at) })
+	}
+
+	return state
+}
+
+// drainingBounds returns how many nodes of the pool were draining for the whole from-to window, and how many were
+// draining at some point of it. A node with both phases in the window is only counted once.
+func (s *clusterState) drainingBounds(pool string, from, to time.Time) (int, int) {
+	covering, overlapping := map[string]bool{}, map[string]bool{}
+	for _, phase := range s.poolDrains[pool] {
+		node := phase.Locator.Keys[monitorapi.LocatorNodeKey]
+		if phase.From.After(to) || phase.To.Before(from) {
+			continue
+		}
+		overlapping[node] = true
+		if !phase.From.After(from) && !phase.To.Before(to) {
+			covering[node] = true
+		}
+	}
+	return len(covering), len(overlapping)
+}
+
+// crossCheck returns a junit that fails when check reports a contradiction for any snapshot. The test is skipped
+// when check could not compare any snapshot with the cluster state.
+func (w *monitor) crossCheck(name string, check func(observed outputModel, fail func(string)) bool) *junitapi.JUnitTestCase {
+	crossCheck := &junitapi.JUnitTestCase{
+		Name: name,
+		SkipMessage: &junitapi.SkipMessage{
+			Message: "Test skipped because no oc adm upgrade status output could be compared with the observed cluster state",
+		},
+	}
+
+	failureOutputBuilder := strings.Builder{}
+
+	for _, observed := range w.ocAdmUpgradeStatusOutputModels {
+		if observed.output == nil {
+			// Failing to parse the output is handled in expectedLayout, so we can skip here
+			continue
+		}
+
+		wroteOnce := false
+		fail := func(message string) {
+			if !wroteOnce {
+				wroteOnce = true
+				failureOutputBuilder.WriteString(fmt.Sprintf("\n===== %s\n", observed.when.Format(time.RFC3339)))
+				failureOutputBuilder.WriteString(observed.output.rawOutput)
+				failureOutputBuilder.WriteString("\n\n")
+			}
+			failureOutputBuilder.WriteString(fmt.Sprintf("=> %s\n", message))
+		}
+
+		if check(observed, fail) {
+			crossCheck.SkipMessage = nil
+		}
+	}
+
+	if failureOutputBuilder.Len() > 0 {
+		crossCheck.SkipMessage = nil
+		crossCheck.FailureOutput = &junitapi.FailureOutput{
+			Output: failureOutputBuilder.String(),
+		}
+	}
+
+	return crossCheck
+}
+
+func (w *monitor) clusterVersionAgreement(state *clusterState) *junitapi.JUnitTestCase {
+	return w.crossCheck("[sig-cli][OCPFeatureGate:UpgradeStatus] oc adm upgrade status agrees with the observed ClusterVersion state",
+		func(observed outputModel, fail func(string)) bool {
+			progressing, ok := state.cvProgressing.stableValue(observed.when.Add(-toleranceBefore), observed.when.Add(toleranceAfter))
+			if !ok {
+				return false
+			}
+
+			output := observed.output
+			if !output.updating && progressing == string(configv1.ConditionTrue) {
+				fail("Cluster is reported not updating but ClusterVersion was Progressing=True")
+			}
+			if output.updating && output.controlPlane != nil && !output.controlPlane.Updated && progressing == string(configv1.ConditionFalse) {
+				fail("Control plane is reported updating but ClusterVersion was Progressing=False")
+			}
+			return true
+		})
+}
+
+func (w *monitor) clusterOperatorsAgreement(state *clusterState) *junitapi.JUnitTestCase {
+	return w.crossCheck("[sig-cli][OCPFeatureGate:UpgradeStatus] oc adm upgrade status agrees with the observed ClusterOperator state",
+		func(observed outputModel, fail func(string)) bool {
+			cp := observed.output.controlPlane
+			if cp == nil || cp.Summary == nil {
+				return false
+			}
+			from, to := observed.when.Add(-toleranceBefore), observed.when.Add(toleranceAfter)
+			compared := false
+
+			if updating, ok := cp.Summary["Updating"]; ok {
+				for _, operator := range strings.Split(updating, ",") {
+					operator = strings.TrimSpace(operator)
+					progressing, ok := state.coProgressing[operator].stableValue(from, to)
+					if !ok {
+						continue
+					}
+					compared = true
+					if progressing == string(configv1.ConditionFalse) {
+						fail(fmt.Sprintf("Operator %s is reported updating but it was Progressing=False", operator))
+					}
+				}
+			}
+
+			targetVersion := strings.Fields(cp.Summary["Target Version"])
+			match := operatorsUpdatedPattern.FindStringSubmatch(cp.Summary["Completion"])
+			if len(targetVersion) == 0 || match == nil || len(state.coVersions) == 0 {
+				return compared
+			}
+			reported, _ := strconv.Atoi(match[1])
+			var updated []string
+			for operator, versions := range state.coVersions {
+				// operators keep the version they reached, the earliest point of the window is enough
+				if version, ok := versions.valueAt(from); ok && version == targetVersion[0] {
+					updated = append(updated, operator)
+				}
+			}
+			if reported < len(updated) {
+				sort.Strings(updated)
+				fail(fmt.Sprintf("Completion reports %d operators updated but %d operators were observed at %s: %s",
+					reported, len(updated), targetVersion[0], strings.Join(updated, ", ")))
+			}
+			return true
+		})
+}
+
+func (w *monitor) machineConfigPoolsAgreement(state *clusterState) *junitapi.JUnitTestCase {
+	return w.crossCheck("[sig-cli][OCPFeatureGate:UpgradeStatus] oc adm upgrade status agrees with the observed MachineConfigPool state",
+		func(observed outputModel, fail func(string)) bool {
+			output := observed.output
+			if !output.updating {
+				return false
+			}
+			from, to := observed.when.Add(-toleranceBefore), observed.when.Add(toleranceAfter)
+			compared := false
+
+			if cp := output.controlPlane; cp != nil && cp.Nodes != nil {
+				if count, ok := state.poolMachineCount[masterPool].stableValue(from, to); ok {
+					compared = true
+					if count != strconv.Itoa(len(cp.Nodes)) {
+						fail(fmt.Sprintf("Control plane nodes table has %d nodes but the %s pool had %s machines", len(cp.Nodes), masterPool, count))
+					}
+				}
+			}
+
+			if output.workers == nil {
+				return compared
+			}
+			for _, line := range output.workers.Pools {
+				pool := strings.Fields(line)[0]
+				count, ok := state.poolMachineCount[pool].stableValue(from, to)
+				if !ok {
+					continue
+				}
+				compared = true
+
+				total := "0"
+				if match := poolCompletionPattern.FindStringSubmatch(line); match != nil {
+					total = match[1]
+				} else if !emptyPoolLinePattern.MatchString(line) {
+					// malformed lines are handled in workers
+					continue
+				}
+				if total != count {
+					fail(fmt.Sprintf("Worker pool %s is reported with %s nodes but it had %s machines", pool, total, count))
+				}
+
+				match := poolDrainingPattern.FindStringSubmatch(line)
+				if match == nil {
+					continue
+				}
+				draining, _ := strconv.Atoi(match[1])
+				atLeast, atMost := state.drainingBounds(pool, from, to)
+				if draining < atLeast || draining > atMost {
+					fail(fmt.Sprintf("Worker pool %s is reported with %d nodes draining but between %d and %d nodes were observed draining", pool, draining, atLeast, atMost))
+				}
+			}
+			return compared
+		})
+}
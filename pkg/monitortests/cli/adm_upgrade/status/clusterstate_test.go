@@ -0,0 +1,255 @@
+package admupgradestatus
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	initialVersion = "4.20.0-0.ci-2025-08-13-114210-test-ci-op-njttt0ww-initial"
+	targetVersion  = "4.20.0-0.ci-2025-08-13-121604-test-ci-op-njttt0ww-latest"
+)
+
+func cvProgressing(at time.Time, status configv1.ConditionStatus) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceClusterOperatorMonitor, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().ClusterVersion(&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}})).
+		Message(monitorapi.NewMessage().WithAnnotations(map[monitorapi.AnnotationKey]string{
+			monitorapi.AnnotationCondition: string(configv1.OperatorProgressing),
+			monitorapi.AnnotationStatus:    string(status),
+		}).HumanMessage("changed")).
+		Build(at, at)
+}
+
+func coProgressing(operator string, at time.Time, status configv1.ConditionStatus) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceClusterOperatorMonitor, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().ClusterOperator(operator)).
+		Message(monitorapi.NewMessage().WithAnnotations(map[monitorapi.AnnotationKey]string{
+			monitorapi.AnnotationCondition: string(configv1.OperatorProgressing),
+			monitorapi.AnnotationStatus:    string(status),
+		}).HumanMessage("changed")).
+		Build(at, at)
+}
+
+func coVersion(operator string, at time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceClusterOperatorMonitor, monitorapi.Info).
+		Locator(monitorapi.NewLocator().ClusterOperator(operator)).
+		Message(monitorapi.NewMessage().
+			WithAnnotation(monitorapi.AnnotationOperatorVersion, targetVersion).
+			HumanMessagef("versions: operator %s -> %s", initialVersion, targetVersion)).
+		Build(at, at)
+}
+
+func poolMachineCount(pool string, at time.Time, count int) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceMachineConfigRollout, monitorapi.Info).
+		Locator(monitorapi.NewLocator().MachineConfigPool(pool)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.MachineConfigPoolMachineCountChangedReason).
+			WithAnnotation(monitorapi.AnnotationCount, strconv.Itoa(count)).
+			HumanMessagef("pool has %d machines", count)).
+		Build(at, at)
+}
+
+func nodeDraining(node, pool string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceMachineConfigRollout, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName(node)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.MachineConfigNodePhaseReason).
+			WithAnnotation(monitorapi.AnnotationPhase, "Drain").
+			WithAnnotation(monitorapi.AnnotationMachineConfigPool, pool).
+			HumanMessage("draining node")).
+		Build(from, to)
+}
+
+func TestHistory_StableValue(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 8, 13, 12, 0, 0, 0, time.UTC)
+	h := history{
+		{at: start, value: "False"},
+		{at: start.Add(3 * time.Minute), value: "False"},
+		{at: start.Add(10 * time.Minute), value: "True"},
+	}
+
+	testCases := []struct {
+		name     string
+		from, to time.Duration
+		expected string
+		ok       bool
+	}{
+		{name: "before anything was observed", from: -time.Minute, to: time.Minute},
+		{name: "stable over the window", from: time.Minute, to: 5 * time.Minute, expected: "False", ok: true},
+		{name: "observed again with the same value", from: 2 * time.Minute, to: 4 * time.Minute, expected: "False", ok: true},
+		{name: "changed within the window", from: 8 * time.Minute, to: 12 * time.Minute},
+		{name: "stable after the change", from: 10 * time.Minute, to: 20 * time.Minute, expected: "True", ok: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			value, ok := h.stableValue(start.Add(tc.from), start.Add(tc.to))
+			if value != tc.expected || ok != tc.ok {
+				t.Errorf("expected (%q, %t), got (%q, %t)", tc.expected, tc.ok, value, ok)
+			}
+		})
+	}
+}
+
+func TestMonitor_ClusterStateAgreement(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 8, 13, 12, 0, 0, 0, time.UTC)
+	when := start.Add(30 * time.Minute)
+
+	const (
+		cvName   = "[sig-cli][OCPFeatureGate:UpgradeStatus] oc adm upgrade status agrees with the observed ClusterVersion state"
+		coName   = "[sig-cli][OCPFeatureGate:UpgradeStatus] oc adm upgrade status agrees with the observed ClusterOperator state"
+		poolName = "[sig-cli][OCPFeatureGate:UpgradeStatus] oc adm upgrade status agrees with the observed MachineConfigPool state"
+	)
+	skipped := &junitapi.SkipMessage{
+		Message: "Test skipped because no oc adm upgrade status output could be compared with the observed cluster state",
+	}
+	failed := &junitapi.FailureOutput{Output: "observed contradicting cluster state"}
+
+	agreeing := monitorapi.Intervals{
+		cvProgressing(start, configv1.ConditionTrue),
+		coProgressing("kube-apiserver", start, configv1.ConditionTrue),
+		coVersion("etcd", start.Add(5*time.Minute)),
+		coVersion("kube-storage-version-migrator", start.Add(10*time.Minute)),
+		poolMachineCount("master", start, 3),
+		poolMachineCount("worker", start, 3),
+	}
+
+	testCases := []struct {
+		name      string
+		snapshots []snapshot
+		intervals monitorapi.Intervals
+		expected  []*junitapi.JUnitTestCase
+	}{
+		{
+			name:      "no cluster state -> tests skipped",
+			snapshots: []snapshot{{when: when, out: workersExampleOutput}},
+			expected: []*junitapi.JUnitTestCase{
+				{Name: cvName, SkipMessage: skipped},
+				{Name: coName, SkipMessage: skipped},
+				{Name: poolName, SkipMessage: skipped},
+			},
+		},
+		{
+			name:      "output agrees with cluster state",
+			snapshots: []snapshot{{when: when, out: workersExampleOutput}},
+			intervals: agreeing,
+			expected: []*junitapi.JUnitTestCase{
+				{Name: cvName},
+				{Name: coName},
+				{Name: poolName},
+			},
+		},
+		{
+			name:      "state changing around the snapshot is not compared",
+			snapshots: []snapshot{{when: when, out: "The cluster is not updating."}},
+			intervals: monitorapi.Intervals{
+				cvProgressing(start, configv1.ConditionTrue),
+				cvProgressing(when.Add(time.Minute), configv1.ConditionFalse),
+			},
+			expected: []*junitapi.JUnitTestCase{
+				{Name: cvName, SkipMessage: skipped},
+				{Name: coName, SkipMessage: skipped},
+				{Name: poolName, SkipMessage: skipped},
+			},
+		},
+		{
+			name:      "not updating while ClusterVersion is progressing",
+			snapshots: []snapshot{{when: when, out: "The cluster is not updating."}},
+			intervals: monitorapi.Intervals{
+				cvProgressing(start, configv1.ConditionTrue),
+			},
+			expected: []*junitapi.JUnitTestCase{
+				{Name: cvName, FailureOutput: failed},
+				{Name: coName, SkipMessage: skipped},
+				{Name: poolName, SkipMessage: skipped},
+			},
+		},
+		{
+			name:      "updating operator is not progressing",
+			snapshots: []snapshot{{when: when, out: workersExampleOutput}},
+			intervals: append(agreeing, coProgressing("kube-apiserver", start.Add(time.Minute), configv1.ConditionFalse)),
+			expected: []*junitapi.JUnitTestCase{
+				{Name: cvName},
+				{Name: coName, FailureOutput: failed},
+				{Name: poolName},
+			},
+		},
+		{
+			name:      "fewer operators reported updated than observed at target version",
+			snapshots: []snapshot{{when: when, out: workersExampleOutput}},
+			intervals: append(agreeing, coVersion("kube-apiserver", start.Add(15*time.Minute))),
+			expected: []*junitapi.JUnitTestCase{
+				{Name: cvName},
+				{Name: coName, FailureOutput: failed},
+				{Name: poolName},
+			},
+		},
+		{
+			name:      "pool total differs from machine count",
+			snapshots: []snapshot{{when: when, out: workersExampleOutput}},
+			intervals: append(agreeing, poolMachineCount("worker", start.Add(time.Minute), 4)),
+			expected: []*junitapi.JUnitTestCase{
+				{Name: cvName},
+				{Name: coName},
+				{Name: poolName, FailureOutput: failed},
+			},
+		},
+		{
+			name:      "node draining over the whole window is not reported",
+			snapshots: []snapshot{{when: when, out: workersExampleOutput}},
+			intervals: append(agreeing, nodeDraining("ip-10-0-0-72.us-west-1.compute.internal", "worker", when.Add(-10*time.Minute), when.Add(10*time.Minute))),
+			expected: []*junitapi.JUnitTestCase{
+				{Name: cvName},
+				{Name: coName},
+				{Name: poolName, FailureOutput: failed},
+			},
+		},
+		{
+			name:      "node briefly draining within the window may be missed",
+			snapshots: []snapshot{{when: when, out: workersExampleOutput}},
+			intervals: append(agreeing, nodeDraining("ip-10-0-0-72.us-west-1.compute.internal", "worker", when.Add(time.Minute), when.Add(2*time.Minute))),
+			expected: []*junitapi.JUnitTestCase{
+				{Name: cvName},
+				{Name: coName},
+				{Name: poolName},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewOcAdmUpgradeStatusChecker().(*monitor)
+			m.ocAdmUpgradeStatus = append(m.ocAdmUpgradeStatus, tc.snapshots...)
+
+			ignoreOutput := cmpopts.IgnoreFields(junitapi.FailureOutput{}, "Output")
+
+			// Process snapshots into models for the cross checks to work with
+			_ = m.expectedLayout()
+
+			state := newClusterState(tc.intervals)
+			result := []*junitapi.JUnitTestCase{
+				m.clusterVersionAgreement(state),
+				m.clusterOperatorsAgreement(state),
+				m.machineConfigPoolsAgreement(state),
+			}
+			if diff := cmp.Diff(tc.expected, result, ignoreOutput); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -212,7 +212,13 @@ func (w *monitor) EvaluateTestsFromConstructedIntervals(ctx context.Context, fin
 	if w.notSupportedReason != nil {
 		return nil, w.notSupportedReason
 	}
-	return nil, nil
+
+	state := newClusterState(finalIntervals)
+	return []*junitapi.JUnitTestCase{
+		w.clusterVersionAgreement(state),
+		w.clusterOperatorsAgreement(state),
+		w.machineConfigPoolsAgreement(state),
+	}, nil
 }
 
 func (w *monitor) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
@@ -0,0 +1,123 @@
+package cloudthrottling
+
+import (
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// component is a part of the cluster that calls the cloud API.
+type component struct {
+	name string
+	// namespaces are where the pods of the component run, the logs of their containers are scanned for throttling.
+	// Only the containers calling the cloud are read, not sidecars like kube-rbac-proxy.
+	namespaces []string
+	containers []string
+	// eventKeys are the locator keys of the objects the component reports events about, eventTypes are the locator
+	// types of those that have their own. Kubelet events carry a node key whatever they are about.
+	eventKeys  []monitorapi.LocatorKey
+	eventTypes []monitorapi.LocatorType
+}
+
+// otherComponent is charged with the throttling that can't be attributed to a known component, like events about
+// pods in e2e namespaces.
+const otherComponent = "other"
+
+var components = []component{
+	{
+		name:       "cloud-controller-manager",
+		namespaces: []string{"openshift-cloud-controller-manager", "openshift-cloud-controller-manager-operator"},
+		containers: []string{"cloud-controller-manager", "cloud-node-manager", "cluster-cloud-controller-manager", "config-sync-controllers"},
+		// the service controller provisions load balancers, the node controllers label and remove nodes
+		eventKeys:  []monitorapi.LocatorKey{"service"},
+		eventTypes: []monitorapi.LocatorType{monitorapi.LocatorTypeNode},
+	},
+	{
+		name:       "machine-api",
+		namespaces: []string{"openshift-machine-api"},
+		containers: []string{"machine-controller", "machineset-controller", "nodelink-controller", "machine-healthcheck-controller"},
+		eventKeys:  []monitorapi.LocatorKey{"machine"},
+	},
+	{
+		name:       "storage",
+		namespaces: []string{"openshift-cluster-csi-drivers", "openshift-cluster-storage-operator"},
+		containers: []string{"csi-driver", "csi-provisioner", "csi-attacher", "csi-resizer", "csi-snapshotter"},
+		eventKeys:  []monitorapi.LocatorKey{monitorapi.LocatorPersistentVolumeClaimKey, monitorapi.LocatorPersistentVolumeKey, monitorapi.LocatorVolumeAttachmentKey},
+	},
+	{
+		name:       "ingress",
+		namespaces: []string{"openshift-ingress-operator"},
+		containers: []string{"ingress-operator"},
+		eventKeys:  []monitorapi.LocatorKey{"dnsrecord"},
+	},
+	{
+		name:       "image-registry",
+		namespaces: []string{"openshift-image-registry"},
+		containers: []string{"cluster-image-registry-operator", "registry"},
+	},
+	{
+		name:       "cloud-credential",
+		namespaces: []string{"openshift-cloud-credential-operator"},
+		containers: []string{"cloud-credential-operator"},
+	},
+	{
+		name:       "cloud-network-config",
+		namespaces: []string{"openshift-cloud-network-config-controller"},
+		containers: []string{"controller"},
+	},
+}
+
+// componentForNamespace returns the component whose pods run in namespace.
+func componentForNamespace(namespace string) string {
+	for _, c := range components {
+		for _, ns := range c.namespaces {
+			if ns == namespace {
+				return c.name
+			}
+		}
+	}
+	return otherComponent
+}
+
+// componentForEvent returns the component that reported the event, by the object it is about first since the
+// objects of e2e namespaces are managed by cluster components too.
+func componentForEvent(locator monitorapi.Locator) string {
+	for _, c := range components {
+		for _, key := range c.eventKeys {
+			if locator.HasKey(key) {
+				return c.name
+			}
+		}
+		for _, locatorType := range c.eventTypes {
+			if locator.Type == locatorType {
+				return c.name
+			}
+		}
+	}
+	return componentForNamespace(locator.Keys[monitorapi.LocatorNamespaceKey])
+}
+
+// isComponentContainer returns true if container is one of the containers calling the cloud of the component whose
+// pods run in namespace.
+func isComponentContainer(namespace, container string) bool {
+	for _, c := range components {
+		for _, ns := range c.namespaces {
+			if ns != namespace {
+				continue
+			}
+			for _, curr := range c.containers {
+				if curr == container {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// componentNamespaces returns the namespaces of all the components.
+func componentNamespaces() []string {
+	var ret []string
+	for _, c := range components {
+		ret = append(ret, c.namespaces...)
+	}
+	return ret
+}
@@ -0,0 +1,114 @@
+package cloudthrottling
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/podaccess"
+)
+
+// periodGap is how far apart throttled calls can be and still be charted as a single throttling period.
+const periodGap = 2 * time.Minute
+
+// throttledCallMessage describes a call that was throttled. caller is the pod or the event the throttling was found
+// in.
+func throttledCallMessage(caller, message string) *monitorapi.MessageBuilder {
+	return monitorapi.NewMessage().Reason(monitorapi.CloudAPIThrottledReason).
+		WithAnnotation("caller", caller).
+		HumanMessage(message)
+}
+
+func throttledCall(provider configv1.PlatformType, component string, message *monitorapi.MessageBuilder, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceCloudThrottling, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().CloudAPI(string(provider), component)).
+		Message(message).
+		Build(from, to)
+}
+
+// throttlingLogHandler records the calls throttled by the cloud of platform that the pods of the components logged
+// after afterTime.
+type throttlingLogHandler struct {
+	recorder  monitorapi.RecorderWriter
+	platform  configv1.PlatformType
+	afterTime time.Time
+}
+
+func (h throttlingLogHandler) HandleLogLine(logLine podaccess.LogLineContent) {
+	if logLine.Instant.Before(h.afterTime) || !isThrottled(h.platform, logLine.Line) {
+		return
+	}
+	component := componentForNamespace(logLine.Locator.Keys[monitorapi.LocatorNamespaceKey])
+	h.recorder.AddIntervals(throttledCall(h.platform, component, throttledCallMessage(logLine.Locator.OldLocator(), logLine.Line),
+		logLine.Instant, logLine.Instant.Add(time.Second)))
+}
+
+// eventThrottlingIntervals returns a throttled call for every event reporting one by the cloud of platform. Cloud
+// controllers report the failures of their calls as events on the objects they were reconciling.
+func eventThrottlingIntervals(platform configv1.PlatformType, startingIntervals monitorapi.Intervals) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, event := range startingIntervals {
+		if event.Source != monitorapi.SourceKubeEvent {
+			continue
+		}
+		if !isThrottled(platform, event.Message.HumanMessage) {
+			continue
+		}
+		message := throttledCallMessage(event.Locator.OldLocator(), event.Message.HumanMessage).
+			Constructed(monitorapi.ConstructionOwnerCloudThrottling)
+		ret = append(ret, throttledCall(platform, componentForEvent(event.Locator), message, event.From, event.To))
+	}
+	return ret
+}
+
+// throttlingPeriods charts the throttled calls of every component by every provider, merging those less than
+// periodGap apart.
+func throttlingPeriods(throttledCalls monitorapi.Intervals) monitorapi.Intervals {
+	byLocator := map[string]monitorapi.Intervals{}
+	var locators []string
+	for _, call := range throttledCalls {
+		locator := call.Locator.OldLocator()
+		if _, ok := byLocator[locator]; !ok {
+			locators = append(locators, locator)
+		}
+		byLocator[locator] = append(byLocator[locator], call)
+	}
+	sort.Strings(locators)
+
+	ret := monitorapi.Intervals{}
+	for _, locator := range locators {
+		calls := byLocator[locator]
+		sort.SliceStable(calls, func(i, j int) bool {
+			return calls[i].From.Before(calls[j].From)
+		})
+		provider, component := calls[0].Locator.Keys[monitorapi.LocatorCloudProviderKey], calls[0].Locator.Keys[monitorapi.LocatorCloudComponentKey]
+
+		periodStart, periodEnd, count := calls[0].From, calls[0].To, 0
+		for _, call := range calls {
+			if call.From.After(periodEnd.Add(periodGap)) {
+				ret = append(ret, throttlingPeriod(calls[0].Locator, provider, component, count, periodStart, periodEnd))
+				periodStart, count = call.From, 0
+			}
+			if call.To.After(periodEnd) {
+				periodEnd = call.To
+			}
+			count++
+		}
+		ret = append(ret, throttlingPeriod(calls[0].Locator, provider, component, count, periodStart, periodEnd))
+	}
+	return ret
+}
+
+func throttlingPeriod(locator monitorapi.Locator, provider, component string, count int, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceCloudThrottling, monitorapi.Warning).
+		Locator(locator).
+		Message(monitorapi.NewMessage().Reason(monitorapi.CloudAPIThrottlingPeriodReason).
+			Constructed(monitorapi.ConstructionOwnerCloudThrottling).
+			WithAnnotation(monitorapi.AnnotationCount, fmt.Sprintf("%d", count)).
+			HumanMessagef("%s throttled %d calls from %s", provider, count, component)).
+		Display().
+		Build(from, to)
+}
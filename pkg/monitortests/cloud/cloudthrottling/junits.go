@@ -0,0 +1,57 @@
+package cloudthrottling
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func throttlingTestName(component string) string {
+	return fmt.Sprintf("[sig-arch] cloud API calls from %s should not be throttled", component)
+}
+
+// throttlingJUnits fails for every component that had calls throttled. The quota of the account the cluster runs in
+// is shared with every other cluster there, so the monitor test is registered to report the failures as flakes.
+func throttlingJUnits(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	byComponent := map[string]monitorapi.Intervals{}
+	for _, call := range finalIntervals {
+		if call.Source != monitorapi.SourceCloudThrottling || call.Message.Reason != monitorapi.CloudAPIThrottledReason {
+			continue
+		}
+		component := call.Locator.Keys[monitorapi.LocatorCloudComponentKey]
+		byComponent[component] = append(byComponent[component], call)
+	}
+
+	names := make([]string, 0, len(components)+1)
+	for _, c := range components {
+		names = append(names, c.name)
+	}
+	names = append(names, otherComponent)
+
+	ret := []*junitapi.JUnitTestCase{}
+	for _, name := range names {
+		testName := throttlingTestName(name)
+		calls := byComponent[name]
+		if len(calls) == 0 {
+			ret = append(ret, &junitapi.JUnitTestCase{Name: testName})
+			continue
+		}
+
+		var lines []string
+		for _, call := range calls {
+			lines = append(lines, call.String())
+		}
+		output := fmt.Sprintf("%s had %d cloud API calls throttled by %s:\n%s", name, len(calls),
+			calls[0].Locator.Keys[monitorapi.LocatorCloudProviderKey], strings.Join(lines, "\n"))
+		ret = append(ret, &junitapi.JUnitTestCase{
+			Name: testName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: output,
+			},
+			SystemOut: output,
+		})
+	}
+	return ret
+}
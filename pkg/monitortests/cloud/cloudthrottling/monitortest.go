@@ -0,0 +1,119 @@
+package cloudthrottling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/podaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// cloudThrottlingDetector finds the cloud API calls that were throttled in the logs of the components calling the
+// cloud and in the events they report.
+type cloudThrottlingDetector struct {
+	kubeClient kubernetes.Interface
+	platform   configv1.PlatformType
+}
+
+func NewCloudThrottlingDetector() monitortestframework.MonitorTest {
+	return &cloudThrottlingDetector{}
+}
+
+func (w *cloudThrottlingDetector) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *cloudThrottlingDetector) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	var err error
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	configClient, err := configclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	infrastructure, err := configClient.ConfigV1().Infrastructures().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to determine the platform: %w", err)
+	}
+	if infrastructure.Status.PlatformStatus != nil {
+		w.platform = infrastructure.Status.PlatformStatus.Type
+	}
+	return nil
+}
+
+func (w *cloudThrottlingDetector) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	// platforms without a cloud API have nothing to throttle
+	if len(throttlingPatterns[w.platform]) == 0 {
+		return nil, nil, nil
+	}
+
+	localRecorder := monitor.NewRecorder()
+	logHandler := throttlingLogHandler{recorder: localRecorder, platform: w.platform, afterTime: beginning}
+	if err := scanComponentPods(ctx, w.kubeClient, logHandler, beginning); err != nil {
+		return nil, nil, fmt.Errorf("unable to scan cloud component logs: %w", err)
+	}
+
+	return localRecorder.Intervals(time.Time{}, time.Time{}), nil, nil
+}
+
+// scanComponentPods reads what the containers calling the cloud in the pods of the components logged since beginning.
+func scanComponentPods(ctx context.Context, kubeClient kubernetes.Interface, logHandler podaccess.LogHandler, beginning time.Time) error {
+	errs := []error{}
+	for _, namespace := range componentNamespaces() {
+		pods, err := kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("couldn't list pods in %s: %w", namespace, err))
+			continue
+		}
+		for _, pod := range pods.Items {
+			if pod.Status.Phase == corev1.PodPending || pod.Status.Phase == corev1.PodUnknown {
+				continue
+			}
+			for _, container := range pod.Spec.Containers {
+				if !isComponentContainer(pod.Namespace, container.Name) {
+					continue
+				}
+				streamer := podaccess.NewOneTimePodStreamer(kubeClient, pod.Namespace, pod.Name, container.Name, logHandler).WithSinceTime(beginning)
+				if err := streamer.ReadLog(ctx); err != nil && !apierrors.IsNotFound(err) {
+					errs = append(errs, fmt.Errorf("error reading log for pods/%s -n %s -c %s: %w", pod.Name, pod.Namespace, container.Name, err))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *cloudThrottlingDetector) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	eventCalls := eventThrottlingIntervals(w.platform, startingIntervals)
+	throttledCalls := startingIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceCloudThrottling && eventInterval.Message.Reason == monitorapi.CloudAPIThrottledReason
+	})
+	return append(eventCalls, throttlingPeriods(append(throttledCalls, eventCalls...))...), nil
+}
+
+func (*cloudThrottlingDetector) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return throttlingJUnits(finalIntervals), nil
+}
+
+func (*cloudThrottlingDetector) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*cloudThrottlingDetector) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,53 @@
+package cloudthrottling
+
+import (
+	"regexp"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// throttlingPatterns match the errors the cloud SDK of each platform returns when the provider rate limits or runs out
+// of quota for API calls. They are specific to each SDK, a bare 429 is far more likely to come from the
+// kube-apiserver priority and fairness than from a cloud. Only the patterns of the platform the cluster runs on are
+// applied, an error worded like another provider's is not a throttled cloud call.
+var throttlingPatterns = map[configv1.PlatformType][]*regexp.Regexp{
+	configv1.AWSPlatformType: {
+		regexp.MustCompile(`RequestLimitExceeded`),
+		regexp.MustCompile(`Throttling: Rate exceeded`),
+		regexp.MustCompile(`ThrottlingException`),
+		regexp.MustCompile(`TooManyRequestsException`),
+		regexp.MustCompile(`SlowDown: Please reduce your request rate`),
+	},
+	configv1.AzurePlatformType: {
+		regexp.MustCompile(`(SubscriptionRequestsThrottled|ResourceGroupRequestsThrottled|TenantRequestsThrottled)`),
+		regexp.MustCompile(`(?i)azure( -)? cloud provider (rate limited|throttled)`),
+		regexp.MustCompile(`Retriable: true, RetryAfter: .*StatusCode=429`),
+	},
+	configv1.GCPPlatformType: {
+		regexp.MustCompile(`googleapi: Error 403: Quota exceeded`),
+		regexp.MustCompile(`googleapi: Error 429`),
+		regexp.MustCompile(`googleapi: .*(rateLimitExceeded|userRateLimitExceeded)`),
+	},
+	configv1.VSpherePlatformType: {
+		regexp.MustCompile(`(?i)ServerFaultCode: .*(too many|limit exceeded)`),
+		regexp.MustCompile(`(?i)vcenter.*429 Too Many Requests`),
+	},
+	configv1.OpenStackPlatformType: {
+		regexp.MustCompile(`Expected HTTP response code .* but got 429 instead`),
+		regexp.MustCompile(`Too many requests have been sent in a given amount of time`),
+		regexp.MustCompile(`Quota exceeded for (resources|instances|cores|ram)`),
+	},
+	configv1.IBMCloudPlatformType: {
+		regexp.MustCompile(`\b(too_many_requests|rate_limit_exceeded)\b`),
+	},
+}
+
+// isThrottled returns true if message reports a call throttled by the cloud of platform.
+func isThrottled(platform configv1.PlatformType, message string) bool {
+	for _, pattern := range throttlingPatterns[platform] {
+		if pattern.MatchString(message) {
+			return true
+		}
+	}
+	return false
+}
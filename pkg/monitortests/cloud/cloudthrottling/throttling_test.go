@@ -0,0 +1,231 @@
+package cloudthrottling
+
+import (
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junittest"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func TestIsThrottled(t *testing.T) {
+	tests := []struct {
+		platform        configv1.PlatformType
+		message         string
+		expectThrottled bool
+	}{
+		{
+			platform:        configv1.AWSPlatformType,
+			message:         `Error syncing load balancer: failed to ensure load balancer: RequestLimitExceeded: Request limit exceeded.`,
+			expectThrottled: true,
+		},
+		{
+			platform:        configv1.AWSPlatformType,
+			message:         `failed to describe instances: Throttling: Rate exceeded status code: 400`,
+			expectThrottled: true,
+		},
+		{
+			platform:        configv1.AzurePlatformType,
+			message:         `Retriable: true, RetryAfter: 17s, HTTPStatusCode: 0, RawError: Retriable: true, RetryAfter: 0s, HTTPStatusCode: 429, RawError: azure.BearerAuthorizer#WithAuthorization: StatusCode=429`,
+			expectThrottled: true,
+		},
+		{
+			platform:        configv1.AzurePlatformType,
+			message:         `Code="SubscriptionRequestsThrottled" Message="Number of 'read' requests for subscription exceeded the limit of '12000' for time interval '01:00:00'."`,
+			expectThrottled: true,
+		},
+		{
+			platform:        configv1.GCPPlatformType,
+			message:         `googleapi: Error 403: Quota exceeded for quota metric 'Queries' and limit 'Queries per minute'`,
+			expectThrottled: true,
+		},
+		{
+			platform:        configv1.GCPPlatformType,
+			message:         `googleapi: Error 403: Rate Limit Exceeded, rateLimitExceeded`,
+			expectThrottled: true,
+		},
+		{
+			platform:        configv1.VSpherePlatformType,
+			message:         `ServerFaultCode: Too many outstanding operations`,
+			expectThrottled: true,
+		},
+		{
+			platform:        configv1.OpenStackPlatformType,
+			message:         `Expected HTTP response code [200] when accessing [GET https://compute.example.com/v2.1/servers/detail], but got 429 instead`,
+			expectThrottled: true,
+		},
+		{
+			platform:        configv1.OpenStackPlatformType,
+			message:         `Quota exceeded for resources: ['ram']`,
+			expectThrottled: true,
+		},
+		{
+			platform:        configv1.IBMCloudPlatformType,
+			message:         `failed to list instances: Error code: too_many_requests, message: Too many requests`,
+			expectThrottled: true,
+		},
+		{
+			// kube-apiserver priority and fairness
+			platform: configv1.AWSPlatformType,
+			message:  `the server has received too many requests and has asked us to try again later (get pods) 429 Too Many Requests`,
+		},
+		{
+			platform: configv1.AWSPlatformType,
+			message:  `Updated load balancer with new hosts`,
+		},
+		{
+			// worded like OpenStack, but not from the cloud the cluster runs on
+			platform: configv1.AWSPlatformType,
+			message:  `Quota exceeded for resources: ['ram']`,
+		},
+		{
+			platform: configv1.BareMetalPlatformType,
+			message:  `RequestLimitExceeded: Request limit exceeded.`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.platform)+" "+tt.message, func(t *testing.T) {
+			if throttled := isThrottled(tt.platform, tt.message); throttled != tt.expectThrottled {
+				t.Errorf("expected %v, got %v", tt.expectThrottled, throttled)
+			}
+		})
+	}
+}
+
+func TestEventThrottlingIntervals(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	event := func(involvedObject corev1.ObjectReference, source corev1.EventSource, message string) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().KubeEvent(&corev1.Event{InvolvedObject: involvedObject, Source: source, Message: message})).
+			Message(monitorapi.NewMessage().HumanMessage(message)).
+			Build(from, from.Add(time.Minute))
+	}
+
+	tests := []struct {
+		name            string
+		platform        configv1.PlatformType
+		event           monitorapi.Interval
+		expectComponent string
+	}{
+		{
+			name:            "load balancer in an e2e namespace",
+			platform:        configv1.AWSPlatformType,
+			event:           event(corev1.ObjectReference{Kind: "Service", Namespace: "e2e-test-lb", Name: "lb"}, corev1.EventSource{Component: "service-controller"}, "Error syncing load balancer: RequestLimitExceeded"),
+			expectComponent: "cloud-controller-manager",
+		},
+		{
+			name:            "node",
+			platform:        configv1.GCPPlatformType,
+			event:           event(corev1.ObjectReference{Kind: "Node", Name: "worker-a"}, corev1.EventSource{Component: "cloud-node-lifecycle-controller"}, "googleapi: Error 429: Too Many Requests"),
+			expectComponent: "cloud-controller-manager",
+		},
+		{
+			name:            "claim",
+			platform:        configv1.AWSPlatformType,
+			event:           event(corev1.ObjectReference{Kind: "PersistentVolumeClaim", Namespace: "e2e-test-volume", Name: "data"}, corev1.EventSource{Component: "ebs.csi.aws.com"}, "failed to provision volume: RequestLimitExceeded"),
+			expectComponent: "storage",
+		},
+		{
+			name:            "mount reported by the kubelet",
+			platform:        configv1.AWSPlatformType,
+			event:           event(corev1.ObjectReference{Kind: "Pod", Namespace: "e2e-test-volume", Name: "writer"}, corev1.EventSource{Component: "kubelet", Host: "worker-a"}, "MountVolume.SetUp failed: RequestLimitExceeded"),
+			expectComponent: otherComponent,
+		},
+		{
+			name:            "operator",
+			platform:        configv1.AWSPlatformType,
+			event:           event(corev1.ObjectReference{Kind: "Deployment", Namespace: "openshift-image-registry", Name: "cluster-image-registry-operator"}, corev1.EventSource{}, "SlowDown: Please reduce your request rate"),
+			expectComponent: "image-registry",
+		},
+		{
+			name:     "throttled by another cloud",
+			platform: configv1.AzurePlatformType,
+			event:    event(corev1.ObjectReference{Kind: "Service", Namespace: "e2e-test-lb", Name: "lb"}, corev1.EventSource{Component: "service-controller"}, "Error syncing load balancer: RequestLimitExceeded"),
+		},
+		{
+			name:     "not throttled",
+			platform: configv1.AWSPlatformType,
+			event:    event(corev1.ObjectReference{Kind: "Service", Namespace: "e2e-test-lb", Name: "lb"}, corev1.EventSource{Component: "service-controller"}, "Ensured load balancer"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := eventThrottlingIntervals(tt.platform, monitorapi.Intervals{tt.event})
+			if len(tt.expectComponent) == 0 {
+				if len(calls) != 0 {
+					t.Errorf("expected no throttled calls, got %v", calls)
+				}
+				return
+			}
+			if len(calls) != 1 {
+				t.Fatalf("expected a throttled call, got %v", calls)
+			}
+			if component := calls[0].Locator.Keys[monitorapi.LocatorCloudComponentKey]; component != tt.expectComponent {
+				t.Errorf("expected %q, got %q", tt.expectComponent, component)
+			}
+		})
+	}
+}
+
+func TestThrottlingPeriods(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	call := func(provider configv1.PlatformType, component string, at time.Duration) monitorapi.Interval {
+		return throttledCall(provider, component, throttledCallMessage("ns/openshift-machine-api pod/machine-api-controllers", "RequestLimitExceeded"),
+			from.Add(at), from.Add(at+time.Second))
+	}
+
+	periods := throttlingPeriods(monitorapi.Intervals{
+		call(configv1.AWSPlatformType, "machine-api", 5*time.Minute),
+		call(configv1.AWSPlatformType, "machine-api", 0),
+		call(configv1.AWSPlatformType, "machine-api", time.Minute),
+		call(configv1.AWSPlatformType, "storage", time.Minute),
+	})
+	expected := []struct {
+		component string
+		count     string
+		from, to  time.Duration
+	}{
+		{component: "machine-api", count: "2", from: 0, to: time.Minute + time.Second},
+		{component: "machine-api", count: "1", from: 5 * time.Minute, to: 5*time.Minute + time.Second},
+		{component: "storage", count: "1", from: time.Minute, to: time.Minute + time.Second},
+	}
+	if len(periods) != len(expected) {
+		t.Fatalf("expected %d periods, got %v", len(expected), periods)
+	}
+	for i, period := range periods {
+		if period.Locator.Keys[monitorapi.LocatorCloudComponentKey] != expected[i].component ||
+			period.Message.Annotations[monitorapi.AnnotationCount] != expected[i].count ||
+			!period.From.Equal(from.Add(expected[i].from)) || !period.To.Equal(from.Add(expected[i].to)) {
+			t.Errorf("period %d: expected %+v, got %s", i, expected[i], period.String())
+		}
+	}
+}
+
+func TestThrottlingJUnits(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	message := throttledCallMessage("ns/openshift-machine-api pod/machine-api-controllers", "RequestLimitExceeded")
+	finalIntervals := monitorapi.Intervals{
+		throttledCall(configv1.AWSPlatformType, "machine-api", message, from, from.Add(time.Second)),
+		throttledCall(configv1.AWSPlatformType, "machine-api", message, from.Add(time.Minute), from.Add(time.Minute+time.Second)),
+	}
+	finalIntervals = append(finalIntervals, throttlingPeriods(finalIntervals)...)
+
+	byName := map[string][]*junitapi.JUnitTestCase{}
+	for _, junit := range throttlingJUnits(finalIntervals) {
+		byName[junit.Name] = append(byName[junit.Name], junit)
+	}
+	if len(byName) != len(components)+1 {
+		t.Fatalf("expected a junit for every component and the other calls, got %d", len(byName))
+	}
+	for name, junits := range byName {
+		if name == throttlingTestName("machine-api") {
+			junittest.ExpectJUnits(t, junits, name, 1, "machine-api had 2 cloud API calls throttled by AWS:", false)
+			continue
+		}
+		junittest.ExpectJUnits(t, junits, name, 1, "", true)
+	}
+}
@@ -0,0 +1,167 @@
+package upgradehops
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var imageRegex = regexp.MustCompile(`\bimage/(\S+)`)
+
+// upgradeHops summarizes every hop of the upgrade from the upgrade events recorded by the upgrade test, which carry
+// the hop they belong to.  With a single target image there is one hop.
+type upgradeHops struct {
+	// end is the end of the run, when hops that never ended are cut.
+	end time.Time
+}
+
+func NewUpgradeHops() monitortestframework.MonitorTest {
+	return &upgradeHops{}
+}
+
+func (*upgradeHops) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (*upgradeHops) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (*upgradeHops) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (w *upgradeHops) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	w.end = end
+	return nil, nil
+}
+
+func (*upgradeHops) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (w *upgradeHops) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	hops := upgradeHopWindows(finalIntervals, w.end)
+	if len(hops) == 0 {
+		return nil
+	}
+	disruptions := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceDisruption && interval.Message.Reason == monitorapi.DisruptionBeganEventReason
+	})
+
+	rows := []map[string]string{}
+	for _, hop := range hops {
+		rows = append(rows, map[string]string{
+			"Hop":                 hop.hop,
+			"Image":               hop.image,
+			"From":                hop.from.UTC().Format(time.RFC3339),
+			"DurationSeconds":     fmt.Sprintf("%d", int(hop.to.Sub(hop.from).Seconds())),
+			"Failed":              strconv.FormatBool(hop.failed),
+			"DisruptionSeconds":   fmt.Sprintf("%d", int(disruptionDuring(hop, disruptions).Seconds())),
+			"DisruptionIntervals": fmt.Sprintf("%d", len(disruptions.Filter(hop.overlaps))),
+		})
+	}
+
+	dataFile := dataloader.DataFile{
+		TableName: "upgrade_hops",
+		Schema: map[string]dataloader.DataType{
+			"Hop":                 dataloader.DataTypeInteger,
+			"Image":               dataloader.DataTypeString,
+			"From":                dataloader.DataTypeTimestamp,
+			"DurationSeconds":     dataloader.DataTypeInteger,
+			"Failed":              dataloader.DataTypeString,
+			"DisruptionSeconds":   dataloader.DataTypeInteger,
+			"DisruptionIntervals": dataloader.DataTypeInteger,
+		},
+		Rows: rows,
+	}
+	fileName := filepath.Join(storageDir, fmt.Sprintf("upgrade-hops%s-%s", timeSuffix, dataloader.AutoDataLoaderSuffix))
+	return dataloader.WriteDataFile(fileName, dataFile)
+}
+
+func (*upgradeHops) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+// upgradeHopWindow is when one hop of the upgrade was active.
+type upgradeHopWindow struct {
+	hop      string
+	image    string
+	from, to time.Time
+	failed   bool
+}
+
+func (h upgradeHopWindow) overlaps(interval monitorapi.Interval) bool {
+	return interval.From.Before(h.to) && !interval.To.Before(h.from)
+}
+
+// upgradeHopWindows finds every hop from the UpgradeStarted event that starts it to the UpgradeComplete or
+// UpgradeFailed event that ends it.  A rollback belongs to the hop it aborts.  A hop that never ended lasts until
+// end.  Events recorded without the hop annotation are numbered in order.
+func upgradeHopWindows(intervals monitorapi.Intervals, end time.Time) []upgradeHopWindow {
+	ret := []upgradeHopWindow{}
+
+	var current *upgradeHopWindow
+	hops := 0
+	finish := func(to time.Time, failed bool) {
+		if current == nil {
+			return
+		}
+		current.to = to
+		current.failed = failed
+		ret = append(ret, *current)
+		current = nil
+	}
+
+	for _, interval := range intervals {
+		if interval.Source != monitorapi.SourceKubeEvent || interval.Locator.Keys[monitorapi.LocatorClusterVersionKey] != "cluster" {
+			continue
+		}
+
+		switch interval.Message.Reason {
+		case monitorapi.UpgradeStartedReason:
+			finish(interval.From, false)
+			hops++
+			current = &upgradeHopWindow{hop: strconv.Itoa(hops), from: interval.From}
+			if hop, ok := interval.Message.Annotations[monitorapi.AnnotationUpgradeHop]; ok {
+				current.hop = hop
+			}
+			if m := imageRegex.FindStringSubmatch(interval.Message.HumanMessage); m != nil {
+				current.image = m[1]
+			}
+		case monitorapi.UpgradeCompleteReason:
+			finish(interval.From, false)
+		case monitorapi.UpgradeFailedReason:
+			finish(interval.From, true)
+		}
+	}
+	finish(end, false)
+
+	return ret
+}
+
+// disruptionDuring is how much of the disruption falls inside the hop.
+func disruptionDuring(hop upgradeHopWindow, disruptions monitorapi.Intervals) time.Duration {
+	var total time.Duration
+	for _, disruption := range disruptions.Filter(hop.overlaps) {
+		from, to := disruption.From, disruption.To
+		if from.Before(hop.from) {
+			from = hop.from
+		}
+		if to.After(hop.to) {
+			to = hop.to
+		}
+		total += to.Sub(from)
+	}
+	return total
+}
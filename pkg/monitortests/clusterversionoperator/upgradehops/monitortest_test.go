@@ -0,0 +1,116 @@
+package upgradehops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func upgradeEvent(reason monitorapi.IntervalReason, note string, hop string, at time.Time) monitorapi.Interval {
+	annotations := map[monitorapi.AnnotationKey]string{}
+	if len(hop) > 0 {
+		annotations[monitorapi.AnnotationUpgradeHop] = hop
+	}
+	return monitorapi.Interval{
+		Condition: monitorapi.Condition{
+			Locator: monitorapi.Locator{
+				Type: monitorapi.LocatorTypeKubeEvent,
+				Keys: map[monitorapi.LocatorKey]string{
+					monitorapi.LocatorClusterVersionKey: "cluster",
+				},
+			},
+			Message: monitorapi.Message{
+				Reason:       reason,
+				HumanMessage: note,
+				Annotations:  annotations,
+			},
+		},
+		Source: monitorapi.SourceKubeEvent,
+		From:   at,
+		To:     at,
+	}
+}
+
+func Test_upgradeHopWindows(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(5 * time.Hour)
+
+	type hop struct {
+		hop    string
+		image  string
+		from   time.Time
+		to     time.Time
+		failed bool
+	}
+	tests := []struct {
+		name      string
+		intervals monitorapi.Intervals
+		expected  []hop
+	}{
+		{
+			name: "no upgrade",
+		},
+		{
+			name: "single upgrade without hop annotations",
+			intervals: monitorapi.Intervals{
+				upgradeEvent(monitorapi.UpgradeStartedReason, "version/ image/registry/a", "", start),
+				upgradeEvent(monitorapi.UpgradeVersionReason, "version/4.15.1 image/4.15.1", "", start.Add(50*time.Minute)),
+				upgradeEvent(monitorapi.UpgradeCompleteReason, "version/4.15.1 image/registry/a", "", start.Add(time.Hour)),
+			},
+			expected: []hop{
+				{hop: "1", image: "registry/a", from: start, to: start.Add(time.Hour)},
+			},
+		},
+		{
+			name: "two hops",
+			intervals: monitorapi.Intervals{
+				upgradeEvent(monitorapi.UpgradeStartedReason, "version/ image/registry/a", "1", start),
+				upgradeEvent(monitorapi.UpgradeCompleteReason, "version/4.15.1 image/registry/a", "1", start.Add(time.Hour)),
+				upgradeEvent(monitorapi.UpgradeStartedReason, "version/ image/registry/b", "2", start.Add(2*time.Hour)),
+				upgradeEvent(monitorapi.UpgradeFailedReason, "failed to upgrade nodes: timed out", "2", start.Add(3*time.Hour)),
+			},
+			expected: []hop{
+				{hop: "1", image: "registry/a", from: start, to: start.Add(time.Hour)},
+				{hop: "2", image: "registry/b", from: start.Add(2 * time.Hour), to: start.Add(3 * time.Hour), failed: true},
+			},
+		},
+		{
+			name: "unfinished hop lasts until the end",
+			intervals: monitorapi.Intervals{
+				upgradeEvent(monitorapi.UpgradeStartedReason, "version/ image/registry/a", "1", start),
+				upgradeEvent(monitorapi.UpgradeRollbackReason, "version/4.14.1 image/4.14.1", "1", start.Add(30*time.Minute)),
+			},
+			expected: []hop{
+				{hop: "1", image: "registry/a", from: start, to: end},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := upgradeHopWindows(tt.intervals, end)
+			require.Len(t, actual, len(tt.expected))
+			for i, expected := range tt.expected {
+				assert.Equal(t, expected.hop, actual[i].hop)
+				assert.Equal(t, expected.image, actual[i].image)
+				assert.Equal(t, expected.from, actual[i].from)
+				assert.Equal(t, expected.to, actual[i].to)
+				assert.Equal(t, expected.failed, actual[i].failed)
+			}
+		})
+	}
+}
+
+func Test_disruptionDuring(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	hop := upgradeHopWindow{from: start, to: start.Add(time.Hour)}
+	disruptions := monitorapi.Intervals{
+		{From: start.Add(-10 * time.Second), To: start.Add(5 * time.Second)},
+		{From: start.Add(time.Minute), To: start.Add(time.Minute + 3*time.Second)},
+		{From: start.Add(2 * time.Hour), To: start.Add(2*time.Hour + time.Second)},
+	}
+	assert.Equal(t, 8*time.Second, disruptionDuring(hop, disruptions))
+}
@@ -36,13 +36,11 @@ type etcdLogAnalyzer struct {
 	finishedCollecting chan struct{}
 	dualReplica        bool // true if running on DualReplica topology where etcd runs externally
 	etcdRecorder       *etcdRecorder
-	flakeJunits        monitortestframework.FlakeJunits
 }
 
-func NewEtcdLogAnalyzer(flakeJunits monitortestframework.FlakeJunits) monitortestframework.MonitorTest {
+func NewEtcdLogAnalyzer() monitortestframework.MonitorTest {
 	return &etcdLogAnalyzer{
 		finishedCollecting: make(chan struct{}),
-		flakeJunits:        flakeJunits,
 	}
 }
 
@@ -227,11 +225,7 @@ func (w *etcdLogAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Cont
 		return junitTest.Skip(), nil
 	}
 
-	junits := junitTest.Result()
-	if w.flakeJunits {
-		junits = monitortestframework.JUnitsToFlakes(junits)
-	}
-	return junits, nil
+	return junitTest.Result(), nil
 }
 
 func (w *etcdLogAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
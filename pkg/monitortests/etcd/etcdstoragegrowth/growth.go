@@ -0,0 +1,164 @@
+package etcdstoragegrowth
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	prometheustypes "github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// GrowthThresholds decide which growth of a resource over a test bucket is flagged.
+type GrowthThresholds struct {
+	// ObjectsPerMinute is the net growth rate beyond which a resource is flagged.
+	ObjectsPerMinute float64
+	// MinimumObjects is the net growth below which a resource is not flagged whatever its rate, in a short bucket a
+	// few objects look like a fast growth.
+	MinimumObjects float64
+}
+
+// DefaultGrowthThresholds leave room for the objects of e2e namespaces that are still being deleted when a bucket
+// ends. They are not derived from measured runs, which is why a growth only fails when it continues into the next
+// bucket.
+func DefaultGrowthThresholds() GrowthThresholds {
+	return GrowthThresholds{
+		ObjectsPerMinute: 10,
+		MinimumObjects:   500,
+	}
+}
+
+// ignoredResources are expected to grow over the run. Events are only removed by their TTL, hours after they are
+// created.
+var ignoredResources = sets.New("events", "events.events.k8s.io")
+
+type sample struct {
+	timestamp time.Time
+	value     float64
+}
+
+// storageSamples holds the etcd database size and the stored object counts sampled over the run.
+type storageSamples struct {
+	dbSize  []sample
+	dbInUse []sample
+	// objects is keyed by resource
+	objects map[string][]sample
+}
+
+// samplesFromMatrix returns the samples of every series of the matrix, keyed by the value of label.
+func samplesFromMatrix(promVal prometheustypes.Value, label prometheustypes.LabelName) map[string][]sample {
+	ret := map[string][]sample{}
+	if promVal == nil || promVal.Type() != prometheustypes.ValMatrix {
+		return ret
+	}
+	for _, promSampleStream := range promVal.(prometheustypes.Matrix) {
+		key := string(promSampleStream.Metric[label])
+		for _, currValue := range promSampleStream.Values {
+			ret[key] = append(ret[key], sample{timestamp: currValue.Timestamp.Time(), value: float64(currValue.Value)})
+		}
+	}
+	return ret
+}
+
+// window is the span of a test bucket, or of the whole run when there were no buckets.
+type window struct {
+	bucket   string
+	from, to time.Time
+}
+
+// testBucketWindows returns the span of every test bucket. Buckets still running are cut at end.
+func testBucketWindows(startingIntervals monitorapi.Intervals, beginning, end time.Time) []window {
+	buckets := startingIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceTestBucket
+	})
+	if len(buckets) == 0 {
+		return []window{{from: beginning, to: end}}
+	}
+	ret := make([]window, 0, len(buckets))
+	for _, bucket := range buckets {
+		to := bucket.To
+		if to.IsZero() || to.After(end) {
+			to = end
+		}
+		ret = append(ret, window{bucket: bucket.Locator.Keys[monitorapi.LocatorTestBucketKey], from: bucket.From, to: to})
+	}
+	return ret
+}
+
+// change returns the value of the samples at the start and at the end of the window. The start is the last sample
+// taken before the window, or its first sample when there is none. ok is false when nothing was sampled for the
+// window.
+func change(samples []sample, w window) (from, to float64, ok bool) {
+	var haveFrom, haveTo bool
+	for _, s := range samples {
+		if s.timestamp.After(w.to) {
+			break
+		}
+		if !s.timestamp.After(w.from) || !haveFrom {
+			from, haveFrom = s.value, true
+		}
+		to, haveTo = s.value, true
+	}
+	return from, to, haveFrom && haveTo
+}
+
+const mebibyte = 1024 * 1024
+
+// growthIntervals constructs an interval for every window with the change of the etcd database size, and one for
+// every resource that grew by at least thresholds.MinimumObjects. Resources growing faster than
+// thresholds.ObjectsPerMinute are charted as warnings.
+func growthIntervals(samples storageSamples, windows []window, thresholds GrowthThresholds) monitorapi.Intervals {
+	resources := make([]string, 0, len(samples.objects))
+	for resource := range samples.objects {
+		if !ignoredResources.Has(resource) {
+			resources = append(resources, resource)
+		}
+	}
+	sort.Strings(resources)
+
+	ret := monitorapi.Intervals{}
+	for _, w := range windows {
+		if fromSize, toSize, ok := change(samples.dbSize, w); ok {
+			humanMessage := fmt.Sprintf("etcd database size changed from %.0fMiB to %.0fMiB", fromSize/mebibyte, toSize/mebibyte)
+			if fromInUse, toInUse, ok := change(samples.dbInUse, w); ok {
+				humanMessage = fmt.Sprintf("%s, in use from %.0fMiB to %.0fMiB", humanMessage, fromInUse/mebibyte, toInUse/mebibyte)
+			}
+			ret = append(ret,
+				monitorapi.NewInterval(monitorapi.SourceEtcdStorageGrowth, monitorapi.Info).
+					Locator(monitorapi.NewLocator().EtcdStorage(w.bucket, "")).
+					Message(monitorapi.NewMessage().Reason(monitorapi.EtcdDatabaseGrowthReason).
+						Constructed(monitorapi.ConstructionOwnerEtcdStorageGrowth).
+						HumanMessage(humanMessage)).
+					Display().
+					Build(w.from, w.to))
+		}
+
+		minutes := w.to.Sub(w.from).Minutes()
+		for _, resource := range resources {
+			fromCount, toCount, ok := change(samples.objects[resource], w)
+			growth := toCount - fromCount
+			if !ok || growth < thresholds.MinimumObjects || minutes <= 0 {
+				continue
+			}
+			rate := growth / minutes
+			level := monitorapi.Info
+			if rate > thresholds.ObjectsPerMinute {
+				level = monitorapi.Warning
+			}
+			ret = append(ret,
+				monitorapi.NewInterval(monitorapi.SourceEtcdStorageGrowth, level).
+					Locator(monitorapi.NewLocator().EtcdStorage(w.bucket, resource)).
+					Message(monitorapi.NewMessage().Reason(monitorapi.EtcdObjectCountGrowthReason).
+						Constructed(monitorapi.ConstructionOwnerEtcdStorageGrowth).
+						WithAnnotation(monitorapi.AnnotationCount, fmt.Sprintf("%.0f", growth)).
+						WithAnnotation("rate", fmt.Sprintf("%.1f", rate)).
+						WithAnnotation("threshold", fmt.Sprintf("%.1f", thresholds.ObjectsPerMinute)).
+						HumanMessagef("%s grew from %.0f to %.0f objects, %.1f per minute", resource, fromCount, toCount, rate)).
+					Display().
+					Build(w.from, w.to))
+		}
+	}
+	return ret
+}
@@ -0,0 +1,228 @@
+package etcdstoragegrowth
+
+import (
+	"testing"
+	"time"
+
+	prometheustypes "github.com/prometheus/common/model"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junittest"
+)
+
+// linearSamples returns a sample a minute from from until to, starting at start and growing by perMinute.
+func linearSamples(from, to time.Time, start, perMinute float64) []sample {
+	var ret []sample
+	for t, value := from, start; !t.After(to); t, value = t.Add(time.Minute), value+perMinute {
+		ret = append(ret, sample{timestamp: t, value: value})
+	}
+	return ret
+}
+
+func testBucket(name string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceTestBucket, monitorapi.Info).
+		Locator(monitorapi.NewLocator().TestBucket(name)).
+		Message(monitorapi.NewMessage().HumanMessage("Executing test bucket: "+name)).
+		Build(from, to)
+}
+
+func TestSamplesFromMatrix(t *testing.T) {
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	matrix := prometheustypes.Matrix{
+		{
+			Metric: prometheustypes.Metric{"resource": "secrets"},
+			Values: []prometheustypes.SamplePair{{Timestamp: prometheustypes.TimeFromUnixNano(at.UnixNano()), Value: 1200}},
+		},
+		{
+			Metric: prometheustypes.Metric{"resource": "configmaps"},
+			Values: []prometheustypes.SamplePair{{Timestamp: prometheustypes.TimeFromUnixNano(at.UnixNano()), Value: 800}},
+		},
+	}
+	samples := samplesFromMatrix(matrix, "resource")
+	if len(samples) != 2 || samples["secrets"][0].value != 1200 || !samples["configmaps"][0].timestamp.Equal(at) {
+		t.Errorf("unexpected samples %v", samples)
+	}
+	if samples := samplesFromMatrix(prometheustypes.Vector{}, "resource"); len(samples) != 0 {
+		t.Errorf("expected no samples from a vector, got %v", samples)
+	}
+}
+
+func TestTestBucketWindows(t *testing.T) {
+	beginning := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := beginning.Add(time.Hour)
+
+	windows := testBucketWindows(nil, beginning, end)
+	if len(windows) != 1 || windows[0].bucket != "" || !windows[0].from.Equal(beginning) || !windows[0].to.Equal(end) {
+		t.Errorf("expected the whole run without buckets, got %v", windows)
+	}
+
+	windows = testBucketWindows(monitorapi.Intervals{
+		testBucket("parallel", beginning.Add(5*time.Minute), beginning.Add(30*time.Minute)),
+		testBucket("serial", beginning.Add(30*time.Minute), time.Time{}),
+	}, beginning, end)
+	if len(windows) != 2 || windows[0].bucket != "parallel" || windows[1].bucket != "serial" || !windows[1].to.Equal(end) {
+		t.Errorf("expected the parallel bucket and the still running serial bucket cut at the end, got %v", windows)
+	}
+}
+
+func TestChange(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	samples := linearSamples(from, from.Add(10*time.Minute), 100, 10)
+
+	tests := []struct {
+		name            string
+		window          window
+		expectFrom      float64
+		expectTo        float64
+		expectNoSamples bool
+	}{
+		{
+			name:       "window within the samples",
+			window:     window{from: from.Add(2 * time.Minute), to: from.Add(5 * time.Minute)},
+			expectFrom: 120,
+			expectTo:   150,
+		},
+		{
+			name:       "window between samples",
+			window:     window{from: from.Add(90 * time.Second), to: from.Add(270 * time.Second)},
+			expectFrom: 110,
+			expectTo:   140,
+		},
+		{
+			name:       "window starting before the samples",
+			window:     window{from: from.Add(-5 * time.Minute), to: from.Add(3 * time.Minute)},
+			expectFrom: 100,
+			expectTo:   130,
+		},
+		{
+			name:            "window before the samples",
+			window:          window{from: from.Add(-5 * time.Minute), to: from.Add(-time.Minute)},
+			expectNoSamples: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fromValue, toValue, ok := change(samples, tt.window)
+			if ok == tt.expectNoSamples {
+				t.Fatalf("expected samples %v, got %v", !tt.expectNoSamples, ok)
+			}
+			if ok && (fromValue != tt.expectFrom || toValue != tt.expectTo) {
+				t.Errorf("expected %.0f to %.0f, got %.0f to %.0f", tt.expectFrom, tt.expectTo, fromValue, toValue)
+			}
+		})
+	}
+}
+
+func TestGrowthIntervalsAndJUnits(t *testing.T) {
+	beginning := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := beginning.Add(time.Hour)
+	buckets := monitorapi.Intervals{
+		testBucket("parallel", beginning, beginning.Add(30*time.Minute)),
+		testBucket("serial", beginning.Add(30*time.Minute), end),
+	}
+	windows := testBucketWindows(buckets, beginning, end)
+	// cleanedUp grows by perMinute over the parallel bucket and shrinks back over the serial one
+	cleanedUp := func(start, perMinute float64) []sample {
+		return append(linearSamples(beginning, beginning.Add(30*time.Minute), start, perMinute),
+			linearSamples(beginning.Add(31*time.Minute), end, start+29*perMinute, -perMinute)...)
+	}
+
+	tests := []struct {
+		name          string
+		samples       storageSamples
+		expectReasons []monitorapi.IntervalReason
+		expectJUnit   bool
+		expectFailure string
+	}{
+		{
+			name: "nothing sampled",
+		},
+		{
+			name: "stable counts",
+			samples: storageSamples{
+				dbSize: linearSamples(beginning, end, 512*mebibyte, 0),
+				objects: map[string][]sample{
+					"secrets": linearSamples(beginning, end, 1000, 0),
+				},
+			},
+			expectReasons: []monitorapi.IntervalReason{monitorapi.EtcdDatabaseGrowthReason, monitorapi.EtcdDatabaseGrowthReason},
+			expectJUnit:   true,
+		},
+		{
+			name: "events grow",
+			samples: storageSamples{
+				dbSize: linearSamples(beginning, end, 512*mebibyte, mebibyte),
+				objects: map[string][]sample{
+					"events": linearSamples(beginning, end, 1000, 100),
+				},
+			},
+			expectReasons: []monitorapi.IntervalReason{monitorapi.EtcdDatabaseGrowthReason, monitorapi.EtcdDatabaseGrowthReason},
+			expectJUnit:   true,
+		},
+		{
+			name: "growth below the minimum",
+			samples: storageSamples{
+				dbSize: linearSamples(beginning, end, 512*mebibyte, mebibyte),
+				objects: map[string][]sample{
+					"configmaps": linearSamples(beginning, end, 1000, 15),
+				},
+			},
+			// 450 configmaps over each 30 minute bucket
+			expectReasons: []monitorapi.IntervalReason{monitorapi.EtcdDatabaseGrowthReason, monitorapi.EtcdDatabaseGrowthReason},
+			expectJUnit:   true,
+		},
+		{
+			name: "fast growth cleaned up in the next bucket",
+			samples: storageSamples{
+				dbSize: linearSamples(beginning, end, 512*mebibyte, mebibyte),
+				objects: map[string][]sample{
+					"secrets": cleanedUp(1000, 30),
+				},
+			},
+			expectReasons: []monitorapi.IntervalReason{monitorapi.EtcdDatabaseGrowthReason, monitorapi.EtcdObjectCountGrowthReason, monitorapi.EtcdDatabaseGrowthReason},
+			expectJUnit:   true,
+		},
+		{
+			name: "fast growth in the last bucket",
+			samples: storageSamples{
+				dbSize: linearSamples(beginning, end, 512*mebibyte, mebibyte),
+				objects: map[string][]sample{
+					"secrets": append(linearSamples(beginning, beginning.Add(30*time.Minute), 1000, 0),
+						linearSamples(beginning.Add(31*time.Minute), end, 1000, 30)...),
+				},
+			},
+			expectReasons: []monitorapi.IntervalReason{monitorapi.EtcdDatabaseGrowthReason, monitorapi.EtcdDatabaseGrowthReason, monitorapi.EtcdObjectCountGrowthReason},
+			expectJUnit:   true,
+		},
+		{
+			name: "growth across buckets",
+			samples: storageSamples{
+				dbSize: linearSamples(beginning, end, 512*mebibyte, mebibyte),
+				objects: map[string][]sample{
+					"configmaps": linearSamples(beginning, end, 1000, 20),
+				},
+			},
+			expectReasons: []monitorapi.IntervalReason{monitorapi.EtcdDatabaseGrowthReason, monitorapi.EtcdObjectCountGrowthReason, monitorapi.EtcdDatabaseGrowthReason, monitorapi.EtcdObjectCountGrowthReason},
+			expectJUnit:   true,
+			expectFailure: "configmaps grew by 600 objects during parallel, 20.0 per minute is more than 10.0, and by 600 more during serial",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var constructed monitorapi.Intervals
+			if len(tt.samples.dbSize) > 0 {
+				constructed = growthIntervals(tt.samples, windows, DefaultGrowthThresholds())
+			}
+			if len(constructed) != len(tt.expectReasons) {
+				t.Fatalf("expected %d intervals, got %d: %v", len(tt.expectReasons), len(constructed), constructed)
+			}
+			for i, reason := range tt.expectReasons {
+				if constructed[i].Message.Reason != reason {
+					t.Errorf("interval %d: expected %s, got %s", i, reason, constructed[i].String())
+				}
+			}
+
+			junittest.ExpectSingleJUnit(t, objectGrowthJUnits(append(buckets, constructed...)), objectGrowthTestName, tt.expectJUnit, tt.expectFailure)
+		})
+	}
+}
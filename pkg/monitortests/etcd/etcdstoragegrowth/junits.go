@@ -0,0 +1,88 @@
+package etcdstoragegrowth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const objectGrowthTestName = "[sig-api-machinery] etcd object counts should not grow faster than tests clean up after themselves"
+
+// objectGrowthJUnits fails for every resource that grew faster than its threshold over a test bucket and grew by at
+// least the minimum again over the bucket that followed, which is how objects that tests never delete show up. The
+// objects of e2e namespaces that are still being deleted when a bucket ends are gone during the next one, so a fast
+// growth in the last bucket, or in a run without buckets, is only charted. No junit is returned when nothing was
+// sampled.
+func objectGrowthJUnits(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	growths := finalIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceEtcdStorageGrowth
+	})
+	if len(growths) == 0 {
+		return nil
+	}
+
+	// the growth intervals of a resource span the bucket they were measured over
+	var bucketStarts []time.Time
+	for _, bucket := range finalIntervals {
+		if bucket.Source == monitorapi.SourceTestBucket {
+			bucketStarts = append(bucketStarts, bucket.From)
+		}
+	}
+	sort.Slice(bucketStarts, func(i, j int) bool {
+		return bucketStarts[i].Before(bucketStarts[j])
+	})
+	nextBucketStart := func(from time.Time) (time.Time, bool) {
+		for _, start := range bucketStarts {
+			if start.After(from) {
+				return start, true
+			}
+		}
+		return time.Time{}, false
+	}
+	type resourceGrowth struct {
+		resource string
+		from     time.Time
+	}
+	objectGrowths := map[resourceGrowth]monitorapi.Interval{}
+	for _, growth := range growths {
+		if growth.Message.Reason == monitorapi.EtcdObjectCountGrowthReason {
+			objectGrowths[resourceGrowth{resource: growth.Locator.Keys[monitorapi.LocatorResourceKey], from: growth.From}] = growth
+		}
+	}
+
+	var failures []string
+	for _, growth := range growths {
+		if growth.Message.Reason != monitorapi.EtcdObjectCountGrowthReason || growth.Level != monitorapi.Warning {
+			continue
+		}
+		resource := growth.Locator.Keys[monitorapi.LocatorResourceKey]
+		next, ok := nextBucketStart(growth.From)
+		if !ok {
+			continue
+		}
+		nextGrowth, ok := objectGrowths[resourceGrowth{resource: resource, from: next}]
+		if !ok {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("%s grew by %s objects during %s, %s per minute is more than %s, and by %s more during %s: %s",
+			resource, growth.Message.Annotations[monitorapi.AnnotationCount], growth.Locator.Keys[monitorapi.LocatorTestBucketKey],
+			growth.Message.Annotations["rate"], growth.Message.Annotations["threshold"],
+			nextGrowth.Message.Annotations[monitorapi.AnnotationCount], nextGrowth.Locator.Keys[monitorapi.LocatorTestBucketKey], growth.String()))
+	}
+	if len(failures) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: objectGrowthTestName}}
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: objectGrowthTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: strings.Join(failures, "\n"),
+			},
+			SystemOut: strings.Join(failures, "\n"),
+		},
+	}
+}
@@ -0,0 +1,200 @@
+package etcdstoragegrowth
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	"github.com/openshift/library-go/test/library/metrics"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	prometheustypes "github.com/prometheus/common/model"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheus"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// every etcd member reports the size of its own copy of the database, they only differ until compaction catches up
+	dbSizeQuery  = `max(etcd_mvcc_db_total_size_in_bytes{job=~".*etcd.*"})`
+	dbInUseQuery = `max(etcd_mvcc_db_total_size_in_use_in_bytes{job=~".*etcd.*"})`
+	// every apiserver reports the count of the objects it stores
+	objectCountQuery = `max by (resource) (apiserver_storage_objects)`
+)
+
+// etcdStorageGrowthCollector samples the etcd database size and the stored object counts over the run, and charts how
+// they changed over every test bucket.
+type etcdStorageGrowthCollector struct {
+	adminRESTConfig *rest.Config
+	thresholds      GrowthThresholds
+	samples         storageSamples
+}
+
+func NewEtcdStorageGrowthCollector() monitortestframework.MonitorTest {
+	return NewEtcdStorageGrowthCollectorWithThresholds(DefaultGrowthThresholds())
+}
+
+func NewEtcdStorageGrowthCollectorWithThresholds(thresholds GrowthThresholds) monitortestframework.MonitorTest {
+	return &etcdStorageGrowthCollector{thresholds: thresholds}
+}
+
+func (w *etcdStorageGrowthCollector) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *etcdStorageGrowthCollector) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
+	return nil
+}
+
+func (w *etcdStorageGrowthCollector) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	const testName = "[Monitor:etcd-storage-growth][Jira:\"etcd\"] monitor test etcd-storage-growth collection"
+	logger := logrus.WithField("MonitorTest", "EtcdStorageGrowthCollector")
+
+	if err := w.collectSamples(ctx, beginning); err != nil {
+		// Thanos may briefly lose its sidecars after disruptive operations, like for the other metric collectors
+		logger.WithError(err).Warn("failed during collection; recording as flake")
+		return nil, []*junitapi.JUnitTestCase{
+			{
+				Name: testName,
+				FailureOutput: &junitapi.FailureOutput{
+					Output: fmt.Sprintf("failed during collection\n%v", err),
+				},
+			},
+			{Name: testName},
+		}, nil
+	}
+
+	logger.Infof("collected %d database size samples and the object counts of %d resources", len(w.samples.dbSize), len(w.samples.objects))
+	return nil, nil, nil
+}
+
+func (w *etcdStorageGrowthCollector) collectSamples(ctx context.Context, startTime time.Time) error {
+	logger := logrus.WithField("func", "collectSamples")
+	kubeClient, err := kubernetes.NewForConfig(w.adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	routeClient, err := routeclient.NewForConfig(w.adminRESTConfig)
+	if err != nil {
+		return err
+	}
+
+	_, err = kubeClient.CoreV1().Namespaces().Get(ctx, "openshift-monitoring", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	prometheusClient, err := metrics.NewPrometheusClient(ctx, kubeClient, routeClient)
+	if err != nil {
+		return fmt.Errorf("failed to create Prometheus client: %w", err)
+	}
+	if _, err := prometheus.EnsureThanosQueriersConnectedToPromSidecars(ctx, prometheusClient); err != nil {
+		return fmt.Errorf("failed to check Thanos querier connection to Prometheus sidecars: %w", err)
+	}
+
+	// counts change slowly, a sample a minute is enough to attribute them to test buckets
+	timeRange := prometheusv1.Range{
+		Start: startTime,
+		End:   time.Now(),
+		Step:  time.Minute,
+	}
+	queries := []struct {
+		name, query string
+		label       prometheustypes.LabelName
+		into        func(map[string][]sample)
+	}{
+		{name: "DatabaseSize", query: dbSizeQuery, into: func(s map[string][]sample) { w.samples.dbSize = s[""] }},
+		{name: "DatabaseInUse", query: dbInUseQuery, into: func(s map[string][]sample) { w.samples.dbInUse = s[""] }},
+		{name: "ObjectCount", query: objectCountQuery, label: "resource", into: func(s map[string][]sample) { w.samples.objects = s }},
+	}
+	for _, query := range queries {
+		promVal, warnings, err := prometheusClient.QueryRange(ctx, query.query, timeRange)
+		if err != nil {
+			return fmt.Errorf("failed to query %s: %w", query.name, err)
+		}
+		for _, warning := range warnings {
+			logger.Warnf("%s metric query warning: %s", query.name, warning)
+		}
+		query.into(samplesFromMatrix(promVal, query.label))
+	}
+	return nil
+}
+
+func (w *etcdStorageGrowthCollector) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	if len(w.samples.dbSize) == 0 && len(w.samples.objects) == 0 {
+		return nil, nil
+	}
+	return growthIntervals(w.samples, testBucketWindows(startingIntervals, beginning, end), w.thresholds), nil
+}
+
+func (w *etcdStorageGrowthCollector) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return objectGrowthJUnits(finalIntervals), nil
+}
+
+func (w *etcdStorageGrowthCollector) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	logger := logrus.WithField("func", "WriteContentToStorage")
+
+	series := map[string][]sample{
+		"DatabaseSize":  w.samples.dbSize,
+		"DatabaseInUse": w.samples.dbInUse,
+	}
+	for resource, samples := range w.samples.objects {
+		series[resource] = samples
+	}
+	names := make([]string, 0, len(series))
+	for name := range series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := []map[string]string{}
+	for _, name := range names {
+		for _, s := range series[name] {
+			rows = append(rows, map[string]string{
+				"Timestamp": s.timestamp.Format(time.RFC3339),
+				"Series":    name,
+				"Value":     fmt.Sprintf("%.0f", s.value),
+			})
+		}
+	}
+	if len(rows) == 0 {
+		logger.Info("No etcd storage samples to export")
+		return nil
+	}
+
+	dataFile := dataloader.DataFile{
+		TableName: "etcd_storage_timeline",
+		Schema: map[string]dataloader.DataType{
+			"Timestamp": dataloader.DataTypeTimestamp,
+			"Series":    dataloader.DataTypeString,
+			"Value":     dataloader.DataTypeFloat64,
+		},
+		Rows: rows,
+	}
+
+	fileName := filepath.Join(storageDir, fmt.Sprintf("etcd-storage-timeline%s-%s", timeSuffix, dataloader.AutoDataLoaderSuffix))
+	if err := dataloader.WriteDataFile(fileName, dataFile); err != nil {
+		logger.WithError(err).Warnf("Failed to write etcd storage timeline autodl file: %s", fileName)
+		return err
+	}
+
+	logger.Infof("Wrote %d etcd storage samples to autodl file: %s", len(rows), fileName)
+	return nil
+}
+
+func (*etcdStorageGrowthCollector) Cleanup(ctx context.Context) error {
+	return nil
+}
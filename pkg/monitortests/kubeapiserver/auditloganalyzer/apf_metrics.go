@@ -0,0 +1,253 @@
+package auditloganalyzer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	"github.com/openshift/library-go/test/library/metrics"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	prometheustypes "github.com/prometheus/common/model"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheus"
+)
+
+const (
+	// the flow schema and priority level a request was classified into are only reported by the apiserver metrics,
+	// audit events don't carry them.
+	apfDispatchedQuery = `sum by (flow_schema, priority_level) (increase(apiserver_flowcontrol_dispatched_requests_total{apiserver="kube-apiserver"}[%s]))`
+	apfRejectedQuery   = `sum by (flow_schema, priority_level) (increase(apiserver_flowcontrol_rejected_requests_total{apiserver="kube-apiserver"}[%s]))`
+	apfWaitSumQuery    = `sum by (flow_schema, priority_level) (increase(apiserver_flowcontrol_request_wait_duration_seconds_sum{apiserver="kube-apiserver",execute="true"}[%s]))`
+	apfWaitCountQuery  = `sum by (flow_schema, priority_level) (increase(apiserver_flowcontrol_request_wait_duration_seconds_count{apiserver="kube-apiserver",execute="true"}[%s]))`
+
+	apfRejectionRateQuery = `sum by (priority_level) (rate(apiserver_flowcontrol_rejected_requests_total{apiserver="kube-apiserver"}[1m])) > 0`
+	apfQueueWaitQuery     = `histogram_quantile(0.99, sum by (priority_level, le) (rate(apiserver_flowcontrol_request_wait_duration_seconds_bucket{apiserver="kube-apiserver",execute="true"}[1m]))) > 1`
+
+	// apfSaturationStep is the resolution of the saturation queries, samples further apart start a new interval.
+	apfSaturationStep = 30 * time.Second
+)
+
+type apfClassification struct {
+	flowSchema    string
+	priorityLevel string
+}
+
+type apfClassificationStats struct {
+	dispatched float64
+	rejected   float64
+	waitSum    float64
+	waitCount  float64
+}
+
+// apfMetrics summarizes API Priority and Fairness from the kube-apiserver metrics: how many requests every flow schema
+// and priority level dispatched and rejected, and the periods a priority level rejected requests or made them queue.
+type apfMetrics struct {
+	classifications map[apfClassification]*apfClassificationStats
+	intervals       monitorapi.Intervals
+}
+
+// collectAPFMetrics returns nil when the cluster has no monitoring stack to query.
+func collectAPFMetrics(ctx context.Context, adminRESTConfig *rest.Config, beginning, end time.Time) (*apfMetrics, error) {
+	logger := logrus.WithField("func", "collectAPFMetrics")
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return nil, err
+	}
+	routeClient, err := routeclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = kubeClient.CoreV1().Namespaces().Get(ctx, "openshift-monitoring", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	prometheusClient, err := metrics.NewPrometheusClient(ctx, kubeClient, routeClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Prometheus client: %w", err)
+	}
+	if _, err := prometheus.EnsureThanosQueriersConnectedToPromSidecars(ctx, prometheusClient); err != nil {
+		return nil, fmt.Errorf("failed to check Thanos querier connection to Prometheus sidecars: %w", err)
+	}
+
+	ret := &apfMetrics{classifications: map[apfClassification]*apfClassificationStats{}}
+	window := prometheustypes.Duration(end.Sub(beginning)).String()
+	vectors := []struct {
+		name, query string
+		into        func(*apfClassificationStats, float64)
+	}{
+		{name: "Dispatched", query: apfDispatchedQuery, into: func(s *apfClassificationStats, v float64) { s.dispatched = v }},
+		{name: "Rejected", query: apfRejectedQuery, into: func(s *apfClassificationStats, v float64) { s.rejected = v }},
+		{name: "WaitSum", query: apfWaitSumQuery, into: func(s *apfClassificationStats, v float64) { s.waitSum = v }},
+		{name: "WaitCount", query: apfWaitCountQuery, into: func(s *apfClassificationStats, v float64) { s.waitCount = v }},
+	}
+	for _, vector := range vectors {
+		promVal, warnings, err := prometheusClient.Query(ctx, fmt.Sprintf(vector.query, window), end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s: %w", vector.name, err)
+		}
+		for _, warning := range warnings {
+			logger.Warnf("%s metric query warning: %s", vector.name, warning)
+		}
+		ret.addClassificationValues(promVal, vector.into)
+	}
+
+	timeRange := prometheusv1.Range{
+		Start: beginning,
+		End:   end,
+		Step:  apfSaturationStep,
+	}
+	matrices := map[string]prometheustypes.Value{}
+	for name, query := range map[string]string{"RejectionRate": apfRejectionRateQuery, "QueueWait": apfQueueWaitQuery} {
+		promVal, warnings, err := prometheusClient.QueryRange(ctx, query, timeRange)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s: %w", name, err)
+		}
+		for _, warning := range warnings {
+			logger.Warnf("%s metric query warning: %s", name, warning)
+		}
+		matrices[name] = promVal
+	}
+	ret.intervals = apfSaturationIntervals(matrices["RejectionRate"], matrices["QueueWait"])
+
+	return ret, nil
+}
+
+func (m *apfMetrics) addClassificationValues(promVal prometheustypes.Value, into func(*apfClassificationStats, float64)) {
+	if promVal == nil || promVal.Type() != prometheustypes.ValVector {
+		return
+	}
+	for _, promSample := range promVal.(prometheustypes.Vector) {
+		key := apfClassification{
+			flowSchema:    string(promSample.Metric["flow_schema"]),
+			priorityLevel: string(promSample.Metric["priority_level"]),
+		}
+		if _, ok := m.classifications[key]; !ok {
+			m.classifications[key] = &apfClassificationStats{}
+		}
+		into(m.classifications[key], float64(promSample.Value))
+	}
+}
+
+type apfSaturatedSample struct {
+	rejectionsPerSecond float64
+	queueWait           time.Duration
+}
+
+// apfSaturationIntervals returns an interval for every period a priority level rejected requests or queued one in a
+// hundred of them for more than a second.  Samples at most apfSaturationStep apart are reported as one interval.
+func apfSaturationIntervals(rejectionRates, queueWaits prometheustypes.Value) monitorapi.Intervals {
+	priorityLevelToSamples := map[string]map[time.Time]*apfSaturatedSample{}
+	addSamples := func(promVal prometheustypes.Value, into func(*apfSaturatedSample, float64)) {
+		if promVal == nil || promVal.Type() != prometheustypes.ValMatrix {
+			return
+		}
+		for _, promSampleStream := range promVal.(prometheustypes.Matrix) {
+			priorityLevel := string(promSampleStream.Metric["priority_level"])
+			if _, ok := priorityLevelToSamples[priorityLevel]; !ok {
+				priorityLevelToSamples[priorityLevel] = map[time.Time]*apfSaturatedSample{}
+			}
+			for _, currValue := range promSampleStream.Values {
+				timestamp := currValue.Timestamp.Time().UTC()
+				if _, ok := priorityLevelToSamples[priorityLevel][timestamp]; !ok {
+					priorityLevelToSamples[priorityLevel][timestamp] = &apfSaturatedSample{}
+				}
+				into(priorityLevelToSamples[priorityLevel][timestamp], float64(currValue.Value))
+			}
+		}
+	}
+	addSamples(rejectionRates, func(s *apfSaturatedSample, v float64) { s.rejectionsPerSecond = v })
+	addSamples(queueWaits, func(s *apfSaturatedSample, v float64) { s.queueWait = time.Duration(v * float64(time.Second)) })
+
+	ret := monitorapi.Intervals{}
+	for priorityLevel, samples := range priorityLevelToSamples {
+		timestamps := make([]time.Time, 0, len(samples))
+		for timestamp := range samples {
+			timestamps = append(timestamps, timestamp)
+		}
+		sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j]) })
+
+		start := 0
+		for i := range timestamps {
+			if i+1 < len(timestamps) && timestamps[i+1].Sub(timestamps[i]) <= apfSaturationStep {
+				continue
+			}
+
+			maxRejectionRate, maxQueueWait := 0.0, time.Duration(0)
+			for _, timestamp := range timestamps[start : i+1] {
+				maxRejectionRate = math.Max(maxRejectionRate, samples[timestamp].rejectionsPerSecond)
+				if samples[timestamp].queueWait > maxQueueWait {
+					maxQueueWait = samples[timestamp].queueWait
+				}
+			}
+			level := monitorapi.Warning
+			if maxRejectionRate > 0 {
+				level = monitorapi.Error
+			}
+			ret = append(ret,
+				monitorapi.NewInterval(monitorapi.SourceAPFMonitor, level).
+					Locator(monitorapi.NewLocator().KubeAPIServerWithLB("any")).
+					Message(monitorapi.NewMessage().
+						Reason(monitorapi.ReasonAPFPriorityLevelSaturated).
+						WithAnnotation(monitorapi.AnnotationPriorityLevel, priorityLevel).
+						HumanMessagef("priority level %s rejected up to %.2f requests per second and queued one in a hundred for up to %v",
+							priorityLevel, maxRejectionRate, maxQueueWait.Round(time.Millisecond)),
+					).
+					Display().
+					Build(timestamps[start], timestamps[i].Add(apfSaturationStep)))
+			start = i + 1
+		}
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+func (m *apfMetrics) WriteSummary(artifactDir, name, timeSuffix string) error {
+	rows := make([]map[string]string, 0, len(m.classifications))
+	for classification, curr := range m.classifications {
+		averageQueueWait := time.Duration(0)
+		if curr.waitCount > 0 {
+			averageQueueWait = time.Duration(curr.waitSum / curr.waitCount * float64(time.Second))
+		}
+		rows = append(rows, map[string]string{
+			"FlowSchema":                   classification.flowSchema,
+			"PriorityLevel":                classification.priorityLevel,
+			"DispatchedRequests":           strconv.FormatInt(int64(math.Round(curr.dispatched)), 10),
+			"RejectedRequests":             strconv.FormatInt(int64(math.Round(curr.rejected)), 10),
+			"AverageQueueWaitMilliseconds": strconv.FormatInt(averageQueueWait.Milliseconds(), 10),
+		})
+	}
+
+	dataFile := dataloader.DataFile{
+		TableName: "apf_summary",
+		Schema: map[string]dataloader.DataType{
+			"FlowSchema":                   dataloader.DataTypeString,
+			"PriorityLevel":                dataloader.DataTypeString,
+			"DispatchedRequests":           dataloader.DataTypeInteger,
+			"RejectedRequests":             dataloader.DataTypeInteger,
+			"AverageQueueWaitMilliseconds": dataloader.DataTypeInteger,
+		},
+		Rows: rows,
+	}
+	fileName := filepath.Join(artifactDir, fmt.Sprintf("%s-summary%s-%s", name, timeSuffix, dataloader.AutoDataLoaderSuffix))
+	if err := dataloader.WriteDataFile(fileName, dataFile); err != nil {
+		logrus.WithError(err).Warnf("unable to write data file: %s", fileName)
+	}
+
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
 )
 
@@ -481,7 +482,7 @@ func cleanupUser(user string) (string, string) {
 	return user, ""
 }
 
-func writeAuditLogDL(artifactDir, timeSuffix string, auditLogSummary *AuditLogSummary) {
+func writeAuditLogDL(artifactDir, apiserver, timeSuffix string, auditLogSummary *AuditLogSummary) {
 	rows := make([]map[string]string, 0)
 	for _, uv := range auditLogSummary.perUserRequestCount {
 		if !isMonitoredUser(uv.user) {
@@ -491,7 +492,7 @@ func writeAuditLogDL(artifactDir, timeSuffix string, auditLogSummary *AuditLogSu
 			for vk, vv := range rv.perVerbRequestCount {
 				for sk, sv := range vv.perHTTPStatusRequestCount {
 					user, unmodifiedUser := cleanupUser(uv.user)
-					data := map[string]string{"User": user, "Resource": rk.Resource, "Verb": vk, "HttpStatus": strconv.FormatInt(int64(sk), 10), "RequestCount": strconv.FormatInt(int64(sv), 10)}
+					data := map[string]string{"APIServer": apiserver, "User": user, "Resource": rk.Resource, "Verb": vk, "HttpStatus": strconv.FormatInt(int64(sk), 10), "RequestCount": strconv.FormatInt(int64(sv), 10)}
 					if len(unmodifiedUser) > 0 {
 						data["UserUnmodified"] = unmodifiedUser
 					}
@@ -504,7 +505,7 @@ func writeAuditLogDL(artifactDir, timeSuffix string, auditLogSummary *AuditLogSu
 
 	dataFile := dataloader.DataFile{
 		TableName: "audit_resource_requests_per_user",
-		Schema:    map[string]dataloader.DataType{"User": dataloader.DataTypeString, "Resource": dataloader.DataTypeString, "Verb": dataloader.DataTypeString, "HttpStatus": dataloader.DataTypeInteger, "RequestCount": dataloader.DataTypeInteger},
+		Schema:    map[string]dataloader.DataType{"APIServer": dataloader.DataTypeString, "User": dataloader.DataTypeString, "Resource": dataloader.DataTypeString, "Verb": dataloader.DataTypeString, "HttpStatus": dataloader.DataTypeInteger, "RequestCount": dataloader.DataTypeInteger},
 		Rows:      rows,
 	}
 	fileName := filepath.Join(artifactDir, fmt.Sprintf("audit-resource-requests-per-user%s-%s", timeSuffix, dataloader.AutoDataLoaderSuffix))
@@ -515,6 +516,28 @@ func writeAuditLogDL(artifactDir, timeSuffix string, auditLogSummary *AuditLogSu
 }
 
 func WriteAuditLogSummary(artifactDir, timeSuffix string, auditLogSummary *AuditLogSummary) error {
+	return writeAPIServerAuditLogSummary(artifactDir, KubeAPIServer, timeSuffix, auditLogSummary)
+}
+
+// WriteAuditLogSummaries writes the summary of every apiserver.  The kube-apiserver summary keeps the file names
+// used before the aggregated apiservers were summarized, the others include the apiserver name.
+func WriteAuditLogSummaries(artifactDir, timeSuffix string, s *summarizer) error {
+	errs := []error{}
+	for _, apiserver := range s.APIServers() {
+		if err := writeAPIServerAuditLogSummary(artifactDir, apiserver, timeSuffix, s.GetAuditLogSummaryForAPIServer(apiserver)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func writeAPIServerAuditLogSummary(artifactDir, apiserver, timeSuffix string, auditLogSummary *AuditLogSummary) error {
+	dataFileSuffix := timeSuffix
+	if apiserver != KubeAPIServer {
+		dataFileSuffix = fmt.Sprintf("-%s%s", apiserver, timeSuffix)
+		timeSuffix = fmt.Sprintf("%s_%s", apiserver, timeSuffix)
+	}
+
 	serializable := NewSerializedAuditLogSummary(*auditLogSummary)
 	writeSummary(artifactDir, fmt.Sprintf("audit-log-summary_%s.json", timeSuffix), serializable)
 
@@ -540,7 +563,7 @@ func WriteAuditLogSummary(artifactDir, timeSuffix string, auditLogSummary *Audit
 	}
 	writeSummary(artifactDir, fmt.Sprintf("just-resources-audit-log-summary_%s.json", timeSuffix), justResources)
 
-	writeAuditLogDL(artifactDir, timeSuffix, auditLogSummary)
+	writeAuditLogDL(artifactDir, apiserver, dataFileSuffix, auditLogSummary)
 
 	return nil
 }
@@ -0,0 +1,149 @@
+package auditloganalyzer
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// auditLogFile is an audit log found in a local directory.
+type auditLogFile struct {
+	path      string
+	nodeName  string
+	apiserver string
+}
+
+// GetKubeAuditLogSummaryFromDirectory feeds the kube-apiserver audit logs found under dir to the handlers.  It reads
+// the same files GetKubeAuditLogSummary streams from the masters, after they have been gathered into a must-gather
+// or CI artifact directory.
+func GetKubeAuditLogSummaryFromDirectory(ctx context.Context, dir string, beginning, end *time.Time, auditLogHandlers []AuditEventHandler) error {
+	return GetAuditLogSummaryFromDirectory(ctx, dir, []string{KubeAPIServer}, beginning, end, auditLogHandlers)
+}
+
+// GetAuditLogSummaryFromDirectory is GetKubeAuditLogSummaryFromDirectory for a list of apiservers.  It is an error
+// for none of them to have audit logs in dir.
+func GetAuditLogSummaryFromDirectory(ctx context.Context, dir string, apiservers []string, beginning, end *time.Time, auditLogHandlers []AuditEventHandler) error {
+	auditLogFiles := []auditLogFile{}
+	for _, apiserver := range apiservers {
+		apiserverAuditLogFiles, err := findAuditLogFiles(dir, apiserver)
+		if err != nil {
+			return err
+		}
+		auditLogFiles = append(auditLogFiles, apiserverAuditLogFiles...)
+	}
+	if len(auditLogFiles) == 0 {
+		return fmt.Errorf("no %s audit logs found in %s", strings.Join(apiservers, ", "), dir)
+	}
+
+	var microBeginning, microEnd *metav1.MicroTime
+	if beginning != nil {
+		micro := metav1.NewMicroTime(*beginning)
+		microBeginning = &micro
+	}
+	if end != nil {
+		micro := metav1.NewMicroTime(*end)
+		microEnd = &micro
+	}
+
+	wg := sync.WaitGroup{}
+	errCh := make(chan error, len(auditLogFiles))
+	for _, file := range auditLogFiles {
+		wg.Add(1)
+		go func(ctx context.Context, file auditLogFile) {
+			defer wg.Done()
+			if ctx.Err() != nil {
+				errCh <- ctx.Err()
+				return
+			}
+			if err := handleAuditLogFile(file, microBeginning, microEnd, auditLogHandlers); err != nil {
+				errCh <- err
+			}
+		}(ctx, file)
+	}
+	wg.Wait()
+	close(errCh)
+
+	errs := []error{}
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func handleAuditLogFile(file auditLogFile, beginning, end *metav1.MicroTime, auditLogHandlers []AuditEventHandler) error {
+	f, err := os.Open(file.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var auditStream io.Reader = f
+	if strings.HasSuffix(file.path, ".gz") {
+		gzipReader, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("unable to read %s: %w", file.path, err)
+		}
+		defer gzipReader.Close()
+		auditStream = gzipReader
+	}
+
+	handleAuditLogStream(auditStream, file.path, file.nodeName, file.apiserver, beginning, end, auditLogHandlers)
+	return nil
+}
+
+// findAuditLogFiles finds the audit logs for an apiserver in the layouts we archive them in:
+//
+//	must-gather:  audit_logs/<apiserver>/<node>-audit-<rotation>.log.gz
+//	node logs:    <node>/<apiserver>/audit-<rotation>.log
+//
+// Rotated and compressed files are included.  The node name comes from the file name prefix when there is one,
+// and from the directory above the apiserver directory otherwise.
+func findAuditLogFiles(dir, apiserver string) ([]auditLogFile, error) {
+	ret := []auditLogFile{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if filepath.Base(filepath.Dir(path)) != apiserver {
+			return nil
+		}
+
+		filename := d.Name()
+		trimmed := strings.TrimSuffix(filename, ".gz")
+		if !strings.HasSuffix(trimmed, ".log") {
+			return nil
+		}
+		auditIndex := strings.Index(trimmed, "audit")
+		if auditIndex < 0 {
+			return nil
+		}
+
+		nodeName := strings.TrimSuffix(trimmed[:auditIndex], "-")
+		if len(nodeName) == 0 {
+			nodeName = filepath.Base(filepath.Dir(filepath.Dir(path)))
+		}
+		ret = append(ret, auditLogFile{path: path, nodeName: nodeName, apiserver: apiserver})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(ret, func(i, j int) bool { return ret[i].path < ret[j].path })
+	return ret, nil
+}
@@ -0,0 +1,175 @@
+package auditloganalyzer
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+type recordingHandler struct {
+	lock            sync.Mutex
+	nodesToIDs      map[string][]types.UID
+	apiserversToIDs map[string][]types.UID
+}
+
+func newRecordingHandler() *recordingHandler {
+	return &recordingHandler{nodesToIDs: map[string][]types.UID{}, apiserversToIDs: map[string][]types.UID{}}
+}
+
+func (r *recordingHandler) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName, apiserver string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.nodesToIDs[nodeName] = append(r.nodesToIDs[nodeName], auditEvent.AuditID)
+	r.apiserversToIDs[apiserver] = append(r.apiserversToIDs[apiserver], auditEvent.AuditID)
+}
+
+func writeAuditLog(t *testing.T, path string, compress bool, auditIDs ...string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var out interface{ Write([]byte) (int, error) } = f
+	if compress {
+		gzipWriter := gzip.NewWriter(f)
+		defer gzipWriter.Close()
+		out = gzipWriter
+	}
+	for _, auditID := range auditIDs {
+		line, err := json.Marshal(auditv1.Event{AuditID: types.UID(auditID)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := out.Write(append(line, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGetKubeAuditLogSummaryFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	// must-gather layout, rotated and compressed
+	writeAuditLog(t, filepath.Join(dir, "must-gather", "audit_logs", "kube-apiserver", "master-0-audit.log.gz"), true, "a")
+	writeAuditLog(t, filepath.Join(dir, "must-gather", "audit_logs", "kube-apiserver", "master-0-audit-2024-01-01T00-00-00.000.log.gz"), true, "b", "c")
+	// node log layout
+	writeAuditLog(t, filepath.Join(dir, "nodes", "master-1", "kube-apiserver", "audit.log"), false, "d")
+	// other apiservers and other files are ignored
+	writeAuditLog(t, filepath.Join(dir, "must-gather", "audit_logs", "oauth-apiserver", "master-0-audit.log.gz"), true, "e")
+	writeAuditLog(t, filepath.Join(dir, "nodes", "master-1", "kube-apiserver", "termination.log"), false, "f")
+
+	handler := newRecordingHandler()
+	if err := GetKubeAuditLogSummaryFromDirectory(context.Background(), dir, nil, nil, []AuditEventHandler{handler}); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := len(handler.nodesToIDs["master-0"]); actual != 3 {
+		t.Errorf("expected 3 events from master-0, got %d: %v", actual, handler.nodesToIDs)
+	}
+	if actual := len(handler.nodesToIDs["master-1"]); actual != 1 {
+		t.Errorf("expected 1 event from master-1, got %d: %v", actual, handler.nodesToIDs)
+	}
+	if len(handler.nodesToIDs) != 2 {
+		t.Errorf("expected events from 2 nodes, got %v", handler.nodesToIDs)
+	}
+}
+
+func TestGetAuditLogSummaryFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeAuditLog(t, filepath.Join(dir, "audit_logs", "kube-apiserver", "master-0-audit.log.gz"), true, "a", "b")
+	writeAuditLog(t, filepath.Join(dir, "audit_logs", "openshift-apiserver", "master-0-audit.log.gz"), true, "c")
+	writeAuditLog(t, filepath.Join(dir, "audit_logs", "oauth-apiserver", "master-0-audit.log.gz"), true, "d")
+
+	handler := newRecordingHandler()
+	if err := GetAuditLogSummaryFromDirectory(context.Background(), dir, AuditedAPIServers, nil, nil, []AuditEventHandler{handler}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]types.UID{
+		KubeAPIServer:      {"a", "b"},
+		OpenShiftAPIServer: {"c"},
+		OAuthAPIServer:     {"d"},
+	}
+	if !reflect.DeepEqual(expected, handler.apiserversToIDs) {
+		t.Errorf("expected events tagged %v, got %v", expected, handler.apiserversToIDs)
+	}
+}
+
+func TestSummarizerPerAPIServer(t *testing.T) {
+	s := NewAuditLogSummarizer()
+	for _, apiserver := range []string{KubeAPIServer, KubeAPIServer, OpenShiftAPIServer} {
+		s.HandleAuditLogEvent(&auditv1.Event{
+			Stage: auditv1.StageResponseComplete,
+			Verb:  "get",
+		}, nil, nil, "master-0", apiserver)
+	}
+
+	if !reflect.DeepEqual([]string{KubeAPIServer, OpenShiftAPIServer}, s.APIServers()) {
+		t.Errorf("unexpected apiservers %v", s.APIServers())
+	}
+	if actual := s.GetAuditLogSummary().requestCounts.requestFinishedCount; actual != 2 {
+		t.Errorf("expected 2 kube-apiserver requests, got %d", actual)
+	}
+	if actual := s.GetAuditLogSummaryForAPIServer(OpenShiftAPIServer).requestCounts.requestFinishedCount; actual != 1 {
+		t.Errorf("expected 1 openshift-apiserver request, got %d", actual)
+	}
+	if s.GetAuditLogSummaryForAPIServer(OAuthAPIServer) != nil {
+		t.Errorf("expected no oauth-apiserver summary")
+	}
+}
+
+func TestGetKubeAuditLogSummaryFromEmptyDirectory(t *testing.T) {
+	handler := newRecordingHandler()
+	if err := GetKubeAuditLogSummaryFromDirectory(context.Background(), t.TempDir(), nil, nil, []AuditEventHandler{handler}); err == nil {
+		t.Fatal("expected an error when there are no audit logs")
+	}
+}
+
+func TestFindInfrastructure(t *testing.T) {
+	dir := t.TempDir()
+	configDir := filepath.Join(dir, "quay-io-openshift-must-gather", "cluster-scoped-resources", "config.openshift.io")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	list := `apiVersion: config.openshift.io/v1
+kind: InfrastructureList
+items:
+- apiVersion: config.openshift.io/v1
+  kind: Infrastructure
+  metadata:
+    name: cluster
+  spec:
+    platformSpec:
+      type: AWS
+  status:
+    controlPlaneTopology: HighlyAvailable
+`
+	if err := os.WriteFile(filepath.Join(configDir, "infrastructures.yaml"), []byte(list), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	infrastructure, err := findInfrastructure(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if infrastructure == nil || infrastructure.Spec.PlatformSpec.Type != "AWS" {
+		t.Errorf("expected the AWS infrastructure, got %v", infrastructure)
+	}
+
+	infrastructure, err = findInfrastructure(t.TempDir())
+	if err != nil || infrastructure != nil {
+		t.Errorf("expected no infrastructure and no error, got %v, %v", infrastructure, err)
+	}
+}
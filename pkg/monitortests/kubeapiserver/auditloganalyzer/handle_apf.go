@@ -0,0 +1,218 @@
+package auditloganalyzer
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+const (
+	// apfQueueWaitAnnotation is the time a request waited in its priority level queue before being dispatched.  The
+	// apiserver only writes latency annotations for requests that took more than 500ms in total, so requests that
+	// queued briefly and finished quickly never carry it.  The queue waits summarized from audit logs therefore only
+	// describe slow requests; the wait of every request is in the apiserver_flowcontrol_request_wait_duration_seconds
+	// metric summarized by apfMetrics.
+	apfQueueWaitAnnotation = "apiserver.latency.k8s.io/apf-queue-wait"
+
+	// apfPlatformRejectionLimit is how many requests of a single platform service account can be rejected before the
+	// test fails instead of flaking.  client-go retries 429s, so a few rejections while the cluster is busy are survivable.
+	apfPlatformRejectionLimit = 20
+)
+
+type apfThrottledRequest struct {
+	auditID    string
+	requestURI string
+	received   time.Time
+}
+
+// apfUserStats counts the rejections and the annotated queue waits of one user.  The audit events don't say which
+// flow schema and priority level a request was classified into, apfMetrics reports those.
+type apfUserStats struct {
+	rejections         int
+	slowQueuedRequests int
+	totalQueueWait     time.Duration
+	maxQueueWait       time.Duration
+
+	first10Rejections []apfThrottledRequest
+}
+
+// apfTracking summarizes API Priority and Fairness rejections and queue waits by user.
+// Only kube-apiserver is tracked: requests for aggregated resources are classified and throttled there first.
+type apfTracking struct {
+	lock sync.Mutex
+
+	usersToStats map[string]*apfUserStats
+}
+
+func CheckForAPFThrottling() *apfTracking {
+	return &apfTracking{
+		usersToStats: map[string]*apfUserStats{},
+	}
+}
+
+func (a *apfTracking) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName, apiserver string) {
+	if apiserver != KubeAPIServer {
+		return
+	}
+	if beginning != nil && auditEvent.RequestReceivedTimestamp.Before(beginning) || end != nil && end.Before(&auditEvent.RequestReceivedTimestamp) {
+		return
+	}
+	if auditEvent.Stage != auditv1.StageResponseComplete {
+		return
+	}
+
+	var queueWait time.Duration
+	if value, ok := auditEvent.Annotations[apfQueueWaitAnnotation]; ok {
+		// malformed values are treated as no wait
+		queueWait, _ = time.ParseDuration(value)
+	}
+	rejected := isAPFRejection(auditEvent)
+	if !rejected && queueWait == 0 {
+		return
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	username := auditEvent.User.Username
+	if _, ok := a.usersToStats[username]; !ok {
+		a.usersToStats[username] = &apfUserStats{}
+	}
+	stats := a.usersToStats[username]
+	if queueWait > 0 {
+		stats.slowQueuedRequests++
+		stats.totalQueueWait += queueWait
+		if queueWait > stats.maxQueueWait {
+			stats.maxQueueWait = queueWait
+		}
+	}
+	if !rejected {
+		return
+	}
+	stats.rejections++
+	if len(stats.first10Rejections) < 10 {
+		stats.first10Rejections = append(stats.first10Rejections, apfThrottledRequest{
+			auditID:    string(auditEvent.AuditID),
+			requestURI: auditEvent.RequestURI,
+			received:   auditEvent.RequestReceivedTimestamp.Time,
+		})
+	}
+}
+
+// isAPFRejection is true for 429s that were not returned by the eviction API, which uses 429 when a
+// PodDisruptionBudget does not allow the eviction.
+func isAPFRejection(auditEvent *auditv1.Event) bool {
+	if auditEvent.ResponseStatus == nil || auditEvent.ResponseStatus.Code != http.StatusTooManyRequests {
+		return false
+	}
+	if auditEvent.ObjectRef != nil && auditEvent.ObjectRef.Subresource == "eviction" {
+		return false
+	}
+	return true
+}
+
+// CreateJunits fails when a platform service account had more than apfPlatformRejectionLimit requests rejected and
+// flakes when any had fewer.
+func (a *apfTracking) CreateJunits() []*junitapi.JUnitTestCase {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	testName := `[Jira:"kube-apiserver"] platform service accounts should not be throttled by API Priority and Fairness`
+
+	usernames := []string{}
+	for username, stats := range a.usersToStats {
+		if stats.rejections > 0 && strings.HasPrefix(username, openshiftServiceAccount) {
+			usernames = append(usernames, username)
+		}
+	}
+	sort.Strings(usernames)
+
+	failures := []string{}
+	flakes := []string{}
+	for _, username := range usernames {
+		stats := a.usersToStats[username]
+		examples := []string{}
+		for _, curr := range stats.first10Rejections {
+			examples = append(examples, fmt.Sprintf("%v request=%v auditID=%v", curr.received.Round(time.Second), curr.requestURI, curr.auditID))
+		}
+		message := fmt.Sprintf("user %v had %d requests rejected with 429, check the apf summary for the flow schema and priority level that rejected them.\n%v", username, stats.rejections, strings.Join(examples, "\n"))
+		if stats.rejections > apfPlatformRejectionLimit {
+			failures = append(failures, message)
+		} else {
+			flakes = append(flakes, message)
+		}
+	}
+
+	switch {
+	case len(failures) > 0:
+		return []*junitapi.JUnitTestCase{
+			{
+				Name: testName,
+				FailureOutput: &junitapi.FailureOutput{
+					Output: fmt.Sprintf("%s\nmore details in audit log", strings.Join(append(failures, flakes...), "\n")),
+				},
+			},
+		}
+	case len(flakes) > 0:
+		return []*junitapi.JUnitTestCase{
+			{
+				Name: testName,
+				FailureOutput: &junitapi.FailureOutput{
+					Output: fmt.Sprintf("%s\nmore details in audit log", strings.Join(flakes, "\n")),
+				},
+			},
+			{Name: testName},
+		}
+	default:
+		return []*junitapi.JUnitTestCase{{Name: testName}}
+	}
+}
+
+func (a *apfTracking) WriteAuditLogSummary(artifactDir, name, timeSuffix string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	rows := make([]map[string]string, 0, len(a.usersToStats))
+	for username, curr := range a.usersToStats {
+		averageQueueWait := time.Duration(0)
+		if curr.slowQueuedRequests > 0 {
+			averageQueueWait = curr.totalQueueWait / time.Duration(curr.slowQueuedRequests)
+		}
+		rows = append(rows, map[string]string{
+			"User":                         username,
+			"Rejections":                   strconv.Itoa(curr.rejections),
+			"SlowQueuedRequests":           strconv.Itoa(curr.slowQueuedRequests),
+			"AverageQueueWaitMilliseconds": strconv.FormatInt(averageQueueWait.Milliseconds(), 10),
+			"MaxQueueWaitMilliseconds":     strconv.FormatInt(curr.maxQueueWait.Milliseconds(), 10),
+		})
+	}
+
+	dataFile := dataloader.DataFile{
+		TableName: "audit_apf_users",
+		Schema: map[string]dataloader.DataType{
+			"User":                         dataloader.DataTypeString,
+			"Rejections":                   dataloader.DataTypeInteger,
+			"SlowQueuedRequests":           dataloader.DataTypeInteger,
+			"AverageQueueWaitMilliseconds": dataloader.DataTypeInteger,
+			"MaxQueueWaitMilliseconds":     dataloader.DataTypeInteger,
+		},
+		Rows: rows,
+	}
+	fileName := filepath.Join(artifactDir, fmt.Sprintf("%s-summary%s-%s", name, timeSuffix, dataloader.AutoDataLoaderSuffix))
+	if err := dataloader.WriteDataFile(fileName, dataFile); err != nil {
+		logrus.WithError(err).Warnf("unable to write data file: %s", fileName)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package auditloganalyzer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	prometheustypes "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// apfListEvent is a list of pods as kube-apiserver audits it.  Latency annotations, including the queue wait, are only
+// present on requests that took more than 500ms.
+func apfListEvent(received time.Time, username string, annotations map[string]string) *auditv1.Event {
+	allAnnotations := map[string]string{
+		"authorization.k8s.io/decision": "allow",
+		"authorization.k8s.io/reason":   `RBAC: allowed by ClusterRoleBinding "prometheus-k8s" of ClusterRole "prometheus-k8s" to ServiceAccount "prometheus-k8s/openshift-monitoring"`,
+	}
+	for k, v := range annotations {
+		allAnnotations[k] = v
+	}
+	return &auditv1.Event{
+		Level:                    auditv1.LevelMetadata,
+		AuditID:                  "6f2c3b9e-5f1d-4b8a-9a57-2d1c0e7b4a11",
+		Stage:                    auditv1.StageResponseComplete,
+		Verb:                     "list",
+		RequestURI:               "/api/v1/pods?limit=500",
+		User:                     authnv1.UserInfo{Username: username, Groups: []string{"system:serviceaccounts", "system:authenticated"}},
+		ObjectRef:                &auditv1.ObjectReference{Resource: "pods", APIVersion: "v1"},
+		ResponseStatus:           &metav1.Status{Code: 200},
+		RequestReceivedTimestamp: metav1.NewMicroTime(received),
+		StageTimestamp:           metav1.NewMicroTime(received.Add(time.Second)),
+		Annotations:              allAnnotations,
+	}
+}
+
+// apfRejectedEvent is a request priority and fairness rejected, the status is the one the apiserver writes back.
+func apfRejectedEvent(received time.Time, username string) *auditv1.Event {
+	event := apfListEvent(received, username, nil)
+	event.ResponseStatus = &metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: "Too many requests, please try again later.",
+		Reason:  metav1.StatusReasonTooManyRequests,
+		Details: &metav1.StatusDetails{RetryAfterSeconds: 4},
+		Code:    429,
+	}
+	return event
+}
+
+// apfEvictionEvent is an eviction a PodDisruptionBudget didn't allow.
+func apfEvictionEvent(received time.Time, username string) *auditv1.Event {
+	event := apfRejectedEvent(received, username)
+	event.Verb = "create"
+	event.RequestURI = "/api/v1/namespaces/openshift-ingress/pods/router-default-5c8f7d9b6-x2x7k/eviction"
+	event.ObjectRef = &auditv1.ObjectReference{Resource: "pods", Namespace: "openshift-ingress", Name: "router-default-5c8f7d9b6-x2x7k", Subresource: "eviction", APIVersion: "v1"}
+	event.ResponseStatus = &metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: "Cannot evict pod as it would violate the pod's disruption budget.",
+		Reason:  metav1.StatusReasonTooManyRequests,
+		Code:    429,
+	}
+	return event
+}
+
+func Test_APFTracking(t *testing.T) {
+	handler := CheckForAPFThrottling()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	platformUser := "system:serviceaccount:openshift-monitoring:prometheus-k8s"
+	slow := map[string]string{
+		"apiserver.latency.k8s.io/total":          "1.52s",
+		"apiserver.latency.k8s.io/etcd":           "312.4ms",
+		"apiserver.latency.k8s.io/apf-queue-wait": "1.2s",
+	}
+
+	handler.HandleAuditLogEvent(apfListEvent(start, "user", nil), nil, nil, "node", KubeAPIServer)
+	handler.HandleAuditLogEvent(apfListEvent(start, "user", slow), nil, nil, "node", KubeAPIServer)
+	handler.HandleAuditLogEvent(apfRejectedEvent(start.Add(time.Second), "user"), nil, nil, "node", KubeAPIServer)
+	handler.HandleAuditLogEvent(apfRejectedEvent(start.Add(time.Second), platformUser), nil, nil, "node", KubeAPIServer)
+	// PDB rejections and other apiservers are not APF
+	handler.HandleAuditLogEvent(apfEvictionEvent(start.Add(time.Minute), platformUser), nil, nil, "node", KubeAPIServer)
+	handler.HandleAuditLogEvent(apfRejectedEvent(start.Add(time.Minute), platformUser), nil, nil, "node", OpenShiftAPIServer)
+
+	stats := handler.usersToStats["user"]
+	require.NotNil(t, stats)
+	assert.Equal(t, 1, stats.rejections)
+	assert.Equal(t, 1, stats.slowQueuedRequests)
+	assert.Equal(t, 1200*time.Millisecond, stats.maxQueueWait)
+	assert.Equal(t, 1, handler.usersToStats[platformUser].rejections)
+
+	// a few rejections flake
+	junits := handler.CreateJunits()
+	require.Len(t, junits, 2)
+	require.NotNil(t, junits[0].FailureOutput)
+	assert.Contains(t, junits[0].FailureOutput.Output, platformUser)
+	assert.Contains(t, junits[0].FailureOutput.Output, "had 1 requests rejected")
+	assert.Nil(t, junits[1].FailureOutput)
+
+	for i := 0; i < apfPlatformRejectionLimit; i++ {
+		handler.HandleAuditLogEvent(apfRejectedEvent(start.Add(2*time.Second), platformUser), nil, nil, "node", KubeAPIServer)
+	}
+	junits = handler.CreateJunits()
+	require.Len(t, junits, 1)
+	require.NotNil(t, junits[0].FailureOutput)
+	assert.Contains(t, junits[0].FailureOutput.Output, fmt.Sprintf("had %d requests rejected", apfPlatformRejectionLimit+1))
+}
+
+func Test_APFTrackingNoThrottling(t *testing.T) {
+	handler := CheckForAPFThrottling()
+	handler.HandleAuditLogEvent(apfListEvent(time.Now(), "system:serviceaccount:openshift-monitoring:prometheus-k8s", nil), nil, nil, "node", KubeAPIServer)
+
+	assert.Empty(t, handler.usersToStats)
+	junits := handler.CreateJunits()
+	require.Len(t, junits, 1)
+	assert.Nil(t, junits[0].FailureOutput)
+}
+
+func Test_APFSaturationIntervals(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	stream := func(priorityLevel string, offsets []time.Duration, value float64) *prometheustypes.SampleStream {
+		ret := &prometheustypes.SampleStream{Metric: prometheustypes.Metric{"priority_level": prometheustypes.LabelValue(priorityLevel)}}
+		for _, offset := range offsets {
+			ret.Values = append(ret.Values, prometheustypes.SamplePair{Timestamp: prometheustypes.TimeFromUnixNano(start.Add(offset).UnixNano()), Value: prometheustypes.SampleValue(value)})
+		}
+		return ret
+	}
+
+	intervals := apfSaturationIntervals(
+		prometheustypes.Matrix{stream("workload-low", []time.Duration{30 * time.Second, time.Minute}, 0.5)},
+		prometheustypes.Matrix{
+			stream("workload-low", []time.Duration{0, 30 * time.Second}, 1.5),
+			stream("global-default", []time.Duration{10 * time.Minute}, 2),
+		},
+	)
+	require.Len(t, intervals, 2)
+	assert.Equal(t, start, intervals[0].From)
+	assert.Equal(t, start.Add(90*time.Second), intervals[0].To)
+	assert.Equal(t, monitorapi.Error, intervals[0].Level)
+	assert.Equal(t, monitorapi.ReasonAPFPriorityLevelSaturated, intervals[0].Message.Reason)
+	assert.Equal(t, "workload-low", intervals[0].Message.Annotations[monitorapi.AnnotationPriorityLevel])
+	assert.Equal(t, "priority level workload-low rejected up to 0.50 requests per second and queued one in a hundred for up to 1.5s", intervals[0].Message.HumanMessage)
+	assert.Equal(t, monitorapi.Warning, intervals[1].Level)
+	assert.Equal(t, "global-default", intervals[1].Message.Annotations[monitorapi.AnnotationPriorityLevel])
+}
@@ -22,7 +22,8 @@ var (
 type auditLatencyRecords struct {
 	lock    sync.Mutex
 	matcher *regexp.Regexp
-	summary auditLatencySummary
+	// apiserverToSummary keeps the latencies of aggregated apiservers apart from the kube-apiserver proxy latencies.
+	apiserverToSummary map[string]*auditLatencySummary
 }
 
 type auditLatencyBucket struct {
@@ -44,14 +45,13 @@ func CheckForLatency() *auditLatencyRecords {
 		panic(err)
 	}
 
-	summary := auditLatencySummary{resourceBuckets: make(map[string]map[string]*auditLatencySummaryRecord)}
-	return &auditLatencyRecords{matcher: decimalSeconds, summary: summary}
+	return &auditLatencyRecords{matcher: decimalSeconds, apiserverToSummary: make(map[string]*auditLatencySummary)}
 }
 
 // HandleAuditLogEvent looks for latency annotations and increments counter for all latency buckets that are below
 // the latency value.  e.g a 4s latency will increment the 2,1 & 0 bucket count values.  0 is the min and will effectively be
 // all requests.  Data is collected to analyze over time for increases in latency completing requests
-func (v *auditLatencyRecords) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName string) {
+func (v *auditLatencyRecords) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName, apiserver string) {
 
 	// we only want to count the response complete events
 	if beginning != nil && auditEvent.RequestReceivedTimestamp.Before(beginning) || end != nil && end.Before(&auditEvent.RequestReceivedTimestamp) || auditEvent.Stage != auditv1.StageResponseComplete {
@@ -61,6 +61,12 @@ func (v *auditLatencyRecords) HandleAuditLogEvent(auditEvent *auditv1.Event, beg
 	v.lock.Lock()
 	defer v.lock.Unlock()
 
+	summary, ok := v.apiserverToSummary[apiserver]
+	if !ok {
+		summary = &auditLatencySummary{resourceBuckets: make(map[string]map[string]*auditLatencySummaryRecord)}
+		v.apiserverToSummary[apiserver] = summary
+	}
+
 	resourceType := "unknown"
 	if auditEvent.ObjectRef != nil && len(auditEvent.ObjectRef.Resource) > 0 {
 		resourceType = auditEvent.ObjectRef.Resource
@@ -82,9 +88,9 @@ func (v *auditLatencyRecords) HandleAuditLogEvent(auditEvent *auditv1.Event, beg
 			var summaryRecord *auditLatencySummaryRecord
 			var latencyBucket *auditLatencyBucket
 
-			if typeRecord, ok = v.summary.resourceBuckets[latencyType]; !ok {
+			if typeRecord, ok = summary.resourceBuckets[latencyType]; !ok {
 				newTypeRecord := make(map[string]*auditLatencySummaryRecord)
-				v.summary.resourceBuckets[latencyType] = newTypeRecord
+				summary.resourceBuckets[latencyType] = newTypeRecord
 				typeRecord = newTypeRecord
 			}
 
@@ -132,30 +138,50 @@ func (v *auditLatencyRecords) WriteAuditLogSummary(artifactDir, name, timeSuffix
 	var rows []map[string]string
 
 	rows = make([]map[string]string, 0)
+	for apiserver, summary := range v.apiserverToSummary {
+		rows = append(rows, summary.rows(apiserver)...)
+	}
+
+	dataFile = dataloader.DataFile{
+		TableName: "audit_latency_counts",
+		Schema:    map[string]dataloader.DataType{"APIServer": dataloader.DataTypeString, "LatencyType": dataloader.DataTypeString, "Resource": dataloader.DataTypeString, "Verb": dataloader.DataTypeString, "Bucket": dataloader.DataTypeFloat64, "Count": dataloader.DataTypeInteger},
+		Rows:      rows,
+	}
+	fileName = filepath.Join(artifactDir, fmt.Sprintf("%s-summary-counts%s-%s", name, timeSuffix, dataloader.AutoDataLoaderSuffix))
+	err = dataloader.WriteDataFile(fileName, dataFile)
+	if err != nil {
+		logrus.WithError(err).Warnf("unable to write data file: %s", fileName)
+	}
+
+	return nil
+}
+
+func (s *auditLatencySummary) rows(apiserver string) []map[string]string {
+	rows := make([]map[string]string, 0)
 	reportBuckets := buckets
 	reportBuckets = append(reportBuckets, 0.0)
-	for latencyType, latencyRecords := range v.summary.resourceBuckets {
+	for latencyType, latencyRecords := range s.resourceBuckets {
 		for resource, record := range latencyRecords {
 			for _, bucket := range reportBuckets {
 
 				if rv, ok := record.buckets[bucket]; ok {
 					foundVerbs := make(map[string]bool)
 					for verb, count := range rv.totalCounts {
-						data := map[string]string{"LatencyType": latencyType, "Resource": resource, "Verb": verb, "Bucket": fmt.Sprintf("%.0f", bucket), "Count": fmt.Sprintf("%d", count)}
+						data := map[string]string{"APIServer": apiserver, "LatencyType": latencyType, "Resource": resource, "Verb": verb, "Bucket": fmt.Sprintf("%.0f", bucket), "Count": fmt.Sprintf("%d", count)}
 						rows = append(rows, data)
 						foundVerbs[verb] = true
 					}
 
 					for _, verb := range knownVerbs {
 						if !foundVerbs[verb] {
-							data := map[string]string{"LatencyType": latencyType, "Resource": resource, "Verb": verb, "Bucket": fmt.Sprintf("%.0f", bucket), "Count": fmt.Sprintf("%d", 0)}
+							data := map[string]string{"APIServer": apiserver, "LatencyType": latencyType, "Resource": resource, "Verb": verb, "Bucket": fmt.Sprintf("%.0f", bucket), "Count": fmt.Sprintf("%d", 0)}
 							rows = append(rows, data)
 						}
 					}
 
 				} else {
 					for _, verb := range knownVerbs {
-						data := map[string]string{"LatencyType": latencyType, "Resource": resource, "Verb": verb, "Bucket": fmt.Sprintf("%.0f", bucket), "Count": fmt.Sprintf("%d", 0)}
+						data := map[string]string{"APIServer": apiserver, "LatencyType": latencyType, "Resource": resource, "Verb": verb, "Bucket": fmt.Sprintf("%.0f", bucket), "Count": fmt.Sprintf("%d", 0)}
 						rows = append(rows, data)
 					}
 				}
@@ -163,17 +189,5 @@ func (v *auditLatencyRecords) WriteAuditLogSummary(artifactDir, name, timeSuffix
 			}
 		}
 	}
-
-	dataFile = dataloader.DataFile{
-		TableName: "audit_latency_counts",
-		Schema:    map[string]dataloader.DataType{"LatencyType": dataloader.DataTypeString, "Resource": dataloader.DataTypeString, "Verb": dataloader.DataTypeString, "Bucket": dataloader.DataTypeFloat64, "Count": dataloader.DataTypeInteger},
-		Rows:      rows,
-	}
-	fileName = filepath.Join(artifactDir, fmt.Sprintf("%s-summary-counts%s-%s", name, timeSuffix, dataloader.AutoDataLoaderSuffix))
-	err = dataloader.WriteDataFile(fileName, dataFile)
-	if err != nil {
-		logrus.WithError(err).Warnf("unable to write data file: %s", fileName)
-	}
-
-	return nil
+	return rows
 }
@@ -27,7 +27,11 @@ type auditViolationRecord struct {
 	username  string
 }
 
-func (v *auditViolations) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName string) {
+func (v *auditViolations) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName, apiserver string) {
+	// pod security admission only runs in kube-apiserver
+	if apiserver != KubeAPIServer {
+		return
+	}
 	if beginning != nil && auditEvent.RequestReceivedTimestamp.Before(beginning) || end != nil && end.Before(&auditEvent.RequestReceivedTimestamp) {
 		return
 	}
@@ -61,7 +61,12 @@ func CountsOverTime(estimatedStartOfCluster metav1.Time) *countTracking {
 	}
 }
 
-func (s *countTracking) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName string) {
+func (s *countTracking) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName, apiserver string) {
+	// the request counts back the kube-apiserver 500s intervals
+	if apiserver != KubeAPIServer {
+		return
+	}
+
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
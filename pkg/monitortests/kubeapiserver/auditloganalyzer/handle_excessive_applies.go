@@ -68,7 +68,11 @@ func CheckForExcessiveApplies() *excessiveApplies {
 	}
 }
 
-func (s *excessiveApplies) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName string) {
+func (s *excessiveApplies) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName, apiserver string) {
+	// applies to aggregated resources are proxied by kube-apiserver, count them once
+	if apiserver != KubeAPIServer {
+		return
+	}
 	if beginning != nil && auditEvent.RequestReceivedTimestamp.Before(beginning) || end != nil && end.Before(&auditEvent.RequestReceivedTimestamp) {
 		return
 	}
@@ -87,7 +87,11 @@ func isApply(auditEvent *auditv1.Event) bool {
 	return true
 }
 
-func (s *invalidRequests) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName string) {
+func (s *invalidRequests) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName, apiserver string) {
+	// invalid requests to aggregated resources are proxied by kube-apiserver, count them once
+	if apiserver != KubeAPIServer {
+		return
+	}
 	if beginning != nil && auditEvent.RequestReceivedTimestamp.Before(beginning) || end != nil && end.Before(&auditEvent.RequestReceivedTimestamp) {
 		return
 	}
@@ -15,6 +15,7 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitortestframework"
 	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 	exutil "github.com/openshift/origin/test/extended/util"
@@ -30,14 +31,27 @@ var operatorWatchLimitsJSON []byte
 // platformUpperBound maps operator service account names to their upper bound limits
 type platformUpperBound map[string]int64
 
-// loadOperatorWatchLimits loads and parses the embedded operator_watch_limits.json file
-func loadOperatorWatchLimits() (map[configv1.TopologyMode]map[configv1.PlatformType]platformUpperBound, error) {
-	var limits map[string]map[string]map[string]float64
+// topologyUpperBounds maps topology and platform to the limits for that cluster shape
+type topologyUpperBounds map[configv1.TopologyMode]map[configv1.PlatformType]platformUpperBound
+
+// loadOperatorWatchLimits loads and parses the embedded operator_watch_limits.json file, which is keyed by the
+// apiserver the watches are made against.
+func loadOperatorWatchLimits() (map[string]topologyUpperBounds, error) {
+	var limits map[string]map[string]map[string]map[string]float64
 	if err := json.Unmarshal(operatorWatchLimitsJSON, &limits); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal operator watch limits: %w", err)
 	}
 
-	result := make(map[configv1.TopologyMode]map[configv1.PlatformType]platformUpperBound)
+	result := make(map[string]topologyUpperBounds)
+	for apiserver, apiserverLimits := range limits {
+		result[apiserver] = parseTopologyUpperBounds(apiserverLimits)
+	}
+
+	return result, nil
+}
+
+func parseTopologyUpperBounds(limits map[string]map[string]map[string]float64) topologyUpperBounds {
+	result := make(topologyUpperBounds)
 
 	// Map string topology names to TopologyMode constants
 	topologyMapping := map[string]configv1.TopologyMode{
@@ -80,7 +94,7 @@ func loadOperatorWatchLimits() (map[configv1.TopologyMode]map[configv1.PlatformT
 		}
 	}
 
-	return result, nil
+	return result
 }
 
 // with https://github.com/openshift/kubernetes/pull/2113 we no longer have the counts used in
@@ -99,19 +113,21 @@ func NewWatchCountTracking() *watchCountTracking {
 }
 
 type OperatorKey struct {
-	NodeName string
-	Operator string
-	Hour     int
+	APIServer string
+	NodeName  string
+	Operator  string
+	Hour      int
 }
 
 type RequestCount struct {
-	Count    int64
-	Operator string
-	NodeName string
-	Hour     int
+	APIServer string
+	Count     int64
+	Operator  string
+	NodeName  string
+	Hour      int
 }
 
-func (s *watchCountTracking) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName string) {
+func (s *watchCountTracking) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName, apiserver string) {
 	if beginning != nil && auditEvent.RequestReceivedTimestamp.Before(beginning) || end != nil && end.Before(&auditEvent.RequestReceivedTimestamp) {
 		return
 	}
@@ -125,12 +141,12 @@ func (s *watchCountTracking) HandleAuditLogEvent(auditEvent *auditv1.Event, begi
 	}
 
 	requestHour := int(auditEvent.RequestReceivedTimestamp.Sub(s.startTime).Round(time.Hour))
-	key := OperatorKey{Hour: requestHour, Operator: auditEvent.User.Username, NodeName: nodeName}
+	key := OperatorKey{APIServer: apiserver, Hour: requestHour, Operator: auditEvent.User.Username, NodeName: nodeName}
 
 	var counter *RequestCount
 	var ok bool
 	if counter, ok = s.watchRequestCountsMap[key]; !ok {
-		counter = &RequestCount{NodeName: key.NodeName, Hour: key.Hour, Operator: key.Operator, Count: 0}
+		counter = &RequestCount{APIServer: key.APIServer, NodeName: key.NodeName, Hour: key.Hour, Operator: key.Operator, Count: 0}
 		s.watchRequestCountsMap[key] = counter
 	}
 	counter.Count++
@@ -138,11 +154,12 @@ func (s *watchCountTracking) HandleAuditLogEvent(auditEvent *auditv1.Event, begi
 }
 
 func (s *watchCountTracking) SummarizeWatchCountRequests() []*RequestCount {
-	// take maximum from all hours through all nodes
+	// take maximum from all hours through all nodes, separately for every apiserver
 	watchRequestCountsMapMax := map[OperatorKey]*RequestCount{}
 	for _, requestCount := range s.watchRequestCountsMap {
 		key := OperatorKey{
-			Operator: requestCount.Operator,
+			APIServer: requestCount.APIServer,
+			Operator:  requestCount.Operator,
 		}
 		if _, exists := watchRequestCountsMapMax[key]; exists {
 			if watchRequestCountsMapMax[key].Count < requestCount.Count {
@@ -183,12 +200,12 @@ func (s *watchCountTracking) WriteAuditLogSummary(ctx context.Context, artifactD
 	rows := make([]map[string]string, 0)
 	for _, item := range watchRequestCounts {
 		operator := strings.Split(item.Operator, ":")[3]
-		rows = append(rows, map[string]string{"ControlPlaneTopology": string(infra.Status.ControlPlaneTopology), "PlatformType": string(infra.Spec.PlatformSpec.Type), "Operator": operator, "WatchRequestCount": strconv.FormatInt(item.Count, 10)})
+		rows = append(rows, map[string]string{"APIServer": item.APIServer, "ControlPlaneTopology": string(infra.Status.ControlPlaneTopology), "PlatformType": string(infra.Spec.PlatformSpec.Type), "Operator": operator, "WatchRequestCount": strconv.FormatInt(item.Count, 10)})
 	}
 
 	dataFile := dataloader.DataFile{
 		TableName: "operator_watch_requests",
-		Schema:    map[string]dataloader.DataType{"ControlPlaneTopology": dataloader.DataTypeString, "PlatformType": dataloader.DataTypeString, "Operator": dataloader.DataTypeString, "WatchRequestCount": dataloader.DataTypeInteger},
+		Schema:    map[string]dataloader.DataType{"APIServer": dataloader.DataTypeString, "ControlPlaneTopology": dataloader.DataTypeString, "PlatformType": dataloader.DataTypeString, "Operator": dataloader.DataTypeString, "WatchRequestCount": dataloader.DataTypeInteger},
 		Rows:      rows,
 	}
 	fileName := filepath.Join(artifactDir, fmt.Sprintf("operator-%s%s-%s", name, timeSuffix, dataloader.AutoDataLoaderSuffix))
@@ -250,10 +267,14 @@ func getJiraComponentForOperator(serviceAccountName string) string {
 	return component
 }
 
-// makeTestName creates the test name with JIRA component for a service account name
-func makeTestName(serviceAccountName string) string {
+// makeTestName creates the test name with JIRA component for a service account name.  Watches against kube-apiserver
+// keep the names they had before the aggregated apiservers were checked.
+func makeTestName(serviceAccountName, apiserver string) string {
 	component := getJiraComponentForOperator(serviceAccountName)
-	return fmt.Sprintf("[Jira:%q] operator service account %s should not create excessive watch requests", component, serviceAccountName)
+	if apiserver == KubeAPIServer {
+		return fmt.Sprintf("[Jira:%q] operator service account %s should not create excessive watch requests", component, serviceAccountName)
+	}
+	return fmt.Sprintf("[Jira:%q] operator service account %s should not create excessive watch requests against %s", component, serviceAccountName, apiserver)
 }
 
 func (s *watchCountTracking) CreateJunits() ([]*junitapi.JUnitTestCase, error) {
@@ -283,6 +304,23 @@ func (s *watchCountTracking) CreateJunits() ([]*junitapi.JUnitTestCase, error) {
 		return ret, nil
 	}
 
+	return s.CreateJunitsForInfrastructure(infra)
+}
+
+// CreateJunitsForInfrastructure checks the watch counts against the limits for the topology and platform of infra.
+// A nil infra skips the checks, which happens when audit logs are analyzed without the cluster configuration.
+func (s *watchCountTracking) CreateJunitsForInfrastructure(infra *configv1.Infrastructure) ([]*junitapi.JUnitTestCase, error) {
+	ret := []*junitapi.JUnitTestCase{}
+
+	testMinRequestsName := "[Jira:\"Test Framework\"] operators should have watch channel requests"
+	if infra == nil {
+		ret = append(ret, &junitapi.JUnitTestCase{
+			Name:        "[Jira:\"Test Framework\"] operator watch request tracking infrastructure check",
+			SkipMessage: &junitapi.SkipMessage{Message: "infrastructure configuration is not available"},
+		})
+		return ret, nil
+	}
+
 	// Load operator watch limits from JSON file
 	// See https://issues.redhat.com/browse/WRKLDS-291 for upper bounds computation
 	//
@@ -310,6 +348,8 @@ func (s *watchCountTracking) CreateJunits() ([]*junitapi.JUnitTestCase, error) {
 
 	// Select the appropriate limits based on topology and platform
 	var upperBound platformUpperBound
+	var topology configv1.TopologyMode
+	kubeAPIServerLimits := allLimits[KubeAPIServer]
 
 	switch infra.Status.ControlPlaneTopology {
 	case configv1.ExternalTopologyMode:
@@ -321,7 +361,8 @@ func (s *watchCountTracking) CreateJunits() ([]*junitapi.JUnitTestCase, error) {
 		return ret, nil
 
 	case configv1.SingleReplicaTopologyMode:
-		topologyLimits, exists := allLimits[configv1.SingleReplicaTopologyMode]
+		topology = configv1.SingleReplicaTopologyMode
+		topologyLimits, exists := kubeAPIServerLimits[topology]
 		if !exists {
 			ret = append(ret, &junitapi.JUnitTestCase{
 				Name:        "[Jira:\"Test Framework\"] operator watch request tracking topology check",
@@ -341,7 +382,8 @@ func (s *watchCountTracking) CreateJunits() ([]*junitapi.JUnitTestCase, error) {
 		upperBound = platformLimits
 
 	default:
-		topologyLimits, exists := allLimits[configv1.HighlyAvailableTopologyMode]
+		topology = configv1.HighlyAvailableTopologyMode
+		topologyLimits, exists := kubeAPIServerLimits[topology]
 		if !exists {
 			ret = append(ret, &junitapi.JUnitTestCase{
 				Name:        "[Jira:\"Test Framework\"] operator watch request tracking topology check",
@@ -361,10 +403,18 @@ func (s *watchCountTracking) CreateJunits() ([]*junitapi.JUnitTestCase, error) {
 		upperBound = platformLimits
 	}
 
-	watchRequestCounts := s.SummarizeWatchCountRequests()
+	// Create a map of apiserver -> operator -> watch count for easy lookup
+	watchCountByAPIServerAndOperator := map[string]map[string]*RequestCount{}
+	for _, item := range s.SummarizeWatchCountRequests() {
+		if watchCountByAPIServerAndOperator[item.APIServer] == nil {
+			watchCountByAPIServerAndOperator[item.APIServer] = map[string]*RequestCount{}
+		}
+		operator := strings.Split(item.Operator, ":")[3]
+		watchCountByAPIServerAndOperator[item.APIServer][operator] = item
+	}
 
 	// Sanity check: ensure we have at least some watch request data
-	if len(watchRequestCounts) == 0 {
+	if len(watchCountByAPIServerAndOperator[KubeAPIServer]) == 0 {
 		ret = append(ret,
 			&junitapi.JUnitTestCase{
 				Name: testMinRequestsName,
@@ -381,16 +431,31 @@ func (s *watchCountTracking) CreateJunits() ([]*junitapi.JUnitTestCase, error) {
 		Name: testMinRequestsName,
 	})
 
-	// Create a map of operator -> watch count for easy lookup
-	watchCountByOperator := make(map[string]*RequestCount)
-	for _, item := range watchRequestCounts {
-		operator := strings.Split(item.Operator, ":")[3]
-		watchCountByOperator[operator] = item
+	ret = append(ret, operatorWatchCountJunits(infra, KubeAPIServer, upperBound, watchCountByAPIServerAndOperator[KubeAPIServer])...)
+
+	// The aggregated apiservers only have limits for the operators that watch their resources.  Those limits are
+	// estimates rather than measurements of CI runs per platform and topology, so exceeding them is only recorded as
+	// a flake until measured limits replace them.  Missing limits mean there is nothing to check.
+	for _, apiserver := range AuditedAPIServers {
+		if apiserver == KubeAPIServer {
+			continue
+		}
+		platformLimits, exists := allLimits[apiserver][topology][infra.Spec.PlatformSpec.Type]
+		if !exists {
+			continue
+		}
+		ret = append(ret, monitortestframework.JUnitsToFlakes(operatorWatchCountJunits(infra, apiserver, platformLimits, watchCountByAPIServerAndOperator[apiserver]))...)
 	}
 
-	// Create one test case per operator in the upper bounds
+	return ret, nil
+}
+
+// operatorWatchCountJunits creates one test case per operator in the upper bounds of an apiserver.
+func operatorWatchCountJunits(infra *configv1.Infrastructure, apiserver string, upperBound platformUpperBound, watchCountByOperator map[string]*RequestCount) []*junitapi.JUnitTestCase {
+	ret := []*junitapi.JUnitTestCase{}
+
 	for operator, allowedCount := range upperBound {
-		testName := makeTestName(operator)
+		testName := makeTestName(operator, apiserver)
 
 		item, hasWatchData := watchCountByOperator[operator]
 
@@ -408,10 +473,10 @@ func (s *watchCountTracking) CreateJunits() ([]*junitapi.JUnitTestCase, error) {
 		allowedCount = allowedCount * 2
 		ratio := float64(item.Count) / float64(allowedCount)
 		ratio = math.Round(ratio*100) / 100
-		framework.Logf("operator=%v, watchrequestcount=%v, upperbound=%v, ratio=%v", operator, item.Count, allowedCount, ratio)
+		framework.Logf("apiserver=%v, operator=%v, watchrequestcount=%v, upperbound=%v, ratio=%v", apiserver, operator, item.Count, allowedCount, ratio)
 
 		if item.Count > allowedCount {
-			framework.Logf("Operator %q produces more watch requests than expected", operator)
+			framework.Logf("Operator %q produces more watch requests than expected against %s", operator, apiserver)
 
 			topology := "HA"
 			if infra.Status.ControlPlaneTopology == configv1.SingleReplicaTopologyMode {
@@ -420,7 +485,7 @@ func (s *watchCountTracking) CreateJunits() ([]*junitapi.JUnitTestCase, error) {
 
 			failureMessage := fmt.Sprintf(`TEST PURPOSE:
 This test monitors watch request counts from operators to detect explosive growth in watch channel usage.
-Excessive watch requests can overload the kube-apiserver and usually indicate operator bugs.
+Excessive watch requests can overload the apiservers and usually indicate operator bugs.
 
 WHAT THIS FAILURE MEANS:
 The %s operator has exceeded its expected watch request limit. This is often normal as operators
@@ -431,6 +496,7 @@ evolve and may require periodic limit increases. However, investigate before upd
 3. Review recent commits to this operator for watch-related changes
 
 FAILURE DETAILS:
+APIServer: %s
 Operator: %s
 Watch request count: %v
 Upper bound (enforced): %v
@@ -447,12 +513,12 @@ If the increase is reasonable and explainable, update the limit using:
 
   /update-operator-watch-request-limits %s %s %d --topology=%s
 
-Or manually edit: pkg/monitortests/kubeapiserver/auditloganalyzer/handle_operator_watch_count_tracking.go
+Or manually edit: pkg/monitortests/kubeapiserver/auditloganalyzer/operator_watch_limits.json
 
 For investigation guidance, see: https://search.ci.openshift.org/?search=produces+more+watch+requests+than+expected
 
 Platform: %v, Topology: %v
-`, operator, operator, item.Count, allowedCount, allowedCount/2, ratio,
+`, operator, apiserver, operator, item.Count, allowedCount, allowedCount/2, ratio,
 				operator, infra.Spec.PlatformSpec.Type, int64(math.Ceil(float64(item.Count)/2)), topology,
 				infra.Spec.PlatformSpec.Type, infra.Status.ControlPlaneTopology)
 
@@ -469,5 +535,5 @@ Platform: %v, Topology: %v
 		}
 	}
 
-	return ret, nil
+	return ret
 }
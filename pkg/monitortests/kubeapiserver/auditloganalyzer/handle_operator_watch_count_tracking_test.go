@@ -26,7 +26,7 @@ func Test_WatchCountTrackerEventHandler(t *testing.T) {
 		User:                     authnv1.UserInfo{Username: "test-operator"},
 		RequestReceivedTimestamp: mTime,
 		Annotations:              map[string]string{"apiserver.latency.k8s.io/etcd": "15.999592078s", "apiserver.latency.k8s.io/response-write": "780ns", "apiserver.latency.k8s.io/serialize-response-object": "3.746852ms", "apiserver.latency.k8s.io/total": "16.005122724s"},
-	}, &mTime, nil, "testNode")
+	}, &mTime, nil, "testNode", KubeAPIServer)
 
 	handler.HandleAuditLogEvent(&auditv1.Event{
 		AuditID:                  "audit-id",
@@ -36,14 +36,14 @@ func Test_WatchCountTrackerEventHandler(t *testing.T) {
 		User:                     authnv1.UserInfo{Username: "test-operator"},
 		RequestReceivedTimestamp: mTime,
 		Annotations:              map[string]string{"apiserver.latency.k8s.io/etcd": "15.999592078ms", "apiserver.latency.k8s.io/response-write": "780ns", "apiserver.latency.k8s.io/serialize-response-object": "3.746852ms", "apiserver.latency.k8s.io/total": "16.005122724ms"},
-	}, &mTime, nil, "testNode")
+	}, &mTime, nil, "testNode", KubeAPIServer)
 
 	handler.HandleAuditLogEvent(&auditv1.Event{
 		AuditID:                  "audit-id",
 		ObjectRef:                &auditv1.ObjectReference{Name: "testName", Resource: "testResource", Namespace: "testNamespace"},
 		RequestReceivedTimestamp: mTime,
 		Annotations:              map[string]string{"apiserver.latency.k8s.io/etcd": "15.999592078s", "apiserver.latency.k8s.io/response-write": "780ns", "apiserver.latency.k8s.io/serialize-response-object": "3.746852ms", "apiserver.latency.k8s.io/total": "16.005122724s"},
-	}, &mTime, nil, "testNode")
+	}, &mTime, nil, "testNode", KubeAPIServer)
 
 	handler.HandleAuditLogEvent(&auditv1.Event{
 		AuditID:                  "audit-id",
@@ -53,7 +53,7 @@ func Test_WatchCountTrackerEventHandler(t *testing.T) {
 		User:                     authnv1.UserInfo{Username: "test-nonoperator"},
 		RequestReceivedTimestamp: mTime,
 		Annotations:              map[string]string{},
-	}, &mTime, nil, "testNode")
+	}, &mTime, nil, "testNode", KubeAPIServer)
 
 	handler.HandleAuditLogEvent(&auditv1.Event{
 		AuditID:                  "audit-id",
@@ -63,7 +63,7 @@ func Test_WatchCountTrackerEventHandler(t *testing.T) {
 		User:                     authnv1.UserInfo{Username: "test-operator"},
 		RequestReceivedTimestamp: mTime,
 		Annotations:              map[string]string{},
-	}, &mTime, nil, "testNode2")
+	}, &mTime, nil, "testNode2", KubeAPIServer)
 
 	// 3 total operator watch requests, max of 2 for testNode
 
@@ -80,7 +80,9 @@ func Test_loadOperatorWatchLimits(t *testing.T) {
 	require.NotNil(t, limits)
 
 	// Verify HighlyAvailable topology exists and has platforms
-	haLimits, ok := limits[configv1.HighlyAvailableTopologyMode]
+	kubeAPIServerLimits, ok := limits[KubeAPIServer]
+	require.True(t, ok, "kube-apiserver limits should exist")
+	haLimits, ok := kubeAPIServerLimits[configv1.HighlyAvailableTopologyMode]
 	require.True(t, ok, "HighlyAvailable topology should exist")
 	require.NotEmpty(t, haLimits, "HighlyAvailable should have platform entries")
 
@@ -93,3 +95,47 @@ func Test_loadOperatorWatchLimits(t *testing.T) {
 	_, ok = awsLimits["ingress-operator"]
 	assert.True(t, ok, "ingress-operator should have a limit defined")
 }
+
+func Test_CreateJunitsForInfrastructurePerAPIServer(t *testing.T) {
+	handler := NewWatchCountTracking()
+	mTime := metav1.NewMicroTime(time.Now())
+	watch := func(apiserver string, count int) {
+		for i := 0; i < count; i++ {
+			handler.HandleAuditLogEvent(&auditv1.Event{
+				Verb:                     "watch",
+				Stage:                    auditv1.StageResponseComplete,
+				User:                     authnv1.UserInfo{Username: "system:serviceaccount:openshift-console-operator:console-operator"},
+				RequestReceivedTimestamp: mTime,
+			}, nil, nil, "testNode", apiserver)
+		}
+	}
+	watch(KubeAPIServer, 10)
+	watch(OpenShiftAPIServer, 1000)
+	watch(OAuthAPIServer, 1)
+
+	infra := &configv1.Infrastructure{
+		Spec:   configv1.InfrastructureSpec{PlatformSpec: configv1.PlatformSpec{Type: configv1.AWSPlatformType}},
+		Status: configv1.InfrastructureStatus{ControlPlaneTopology: configv1.HighlyAvailableTopologyMode},
+	}
+	junits, err := handler.CreateJunitsForInfrastructure(infra)
+	require.NoError(t, err)
+
+	failed, passed := map[string]bool{}, map[string]bool{}
+	for _, junit := range junits {
+		if junit.FailureOutput != nil {
+			failed[junit.Name] = true
+		} else {
+			passed[junit.Name] = true
+		}
+	}
+
+	kubeTestName := makeTestName("console-operator", KubeAPIServer)
+	openshiftTestName := makeTestName("console-operator", OpenShiftAPIServer)
+	oauthTestName := makeTestName("console-operator", OAuthAPIServer)
+	assert.Contains(t, passed, kubeTestName)
+	assert.Contains(t, passed, openshiftTestName)
+	assert.Contains(t, passed, oauthTestName)
+	assert.False(t, failed[kubeTestName], "kube-apiserver watches are under the limit")
+	assert.True(t, failed[openshiftTestName], "openshift-apiserver watches are over the limit, which is only a flake")
+	assert.False(t, failed[oauthTestName], "oauth-apiserver watches are under the limit")
+}
@@ -54,7 +54,12 @@ func CheckForRequestsDuringShutdown() *lateRequestTracking {
 	return &lateRequestTracking{}
 }
 
-func (l *lateRequestTracking) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName string) {
+func (l *lateRequestTracking) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName, apiserver string) {
+	// only the kube-apiserver shutdowns are correlated with the load balancers
+	if apiserver != KubeAPIServer {
+		return
+	}
+
 	l.lock.Lock()
 	defer l.lock.Unlock()
 
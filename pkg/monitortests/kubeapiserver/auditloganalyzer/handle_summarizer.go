@@ -1,32 +1,62 @@
 package auditloganalyzer
 
 import (
+	"sort"
+	"sync"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
-	"sync"
 )
 
 type summarizer struct {
-	lock            sync.Mutex
-	auditLogSummary *AuditLogSummary
+	lock sync.Mutex
+	// apiserverToAuditLogSummary keeps a summary per apiserver so aggregated requests are not counted twice.
+	apiserverToAuditLogSummary map[string]*AuditLogSummary
 }
 
 func NewAuditLogSummarizer() *summarizer {
 	return &summarizer{
-		auditLogSummary: NewAuditLogSummary(),
+		apiserverToAuditLogSummary: map[string]*AuditLogSummary{
+			KubeAPIServer: NewAuditLogSummary(),
+		},
 	}
 }
 
-func (s *summarizer) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName string) {
+func (s *summarizer) HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName, apiserver string) {
 	if beginning != nil && auditEvent.RequestReceivedTimestamp.Before(beginning) || end != nil && end.Before(&auditEvent.RequestReceivedTimestamp) {
 		return
 	}
 
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	s.auditLogSummary.Add(auditEvent, auditEventInfo{})
+	auditLogSummary, ok := s.apiserverToAuditLogSummary[apiserver]
+	if !ok {
+		auditLogSummary = NewAuditLogSummary()
+		s.apiserverToAuditLogSummary[apiserver] = auditLogSummary
+	}
+	auditLogSummary.Add(auditEvent, auditEventInfo{})
 }
 
+// GetAuditLogSummary returns the kube-apiserver summary.
 func (s *summarizer) GetAuditLogSummary() *AuditLogSummary {
-	return s.auditLogSummary
+	return s.GetAuditLogSummaryForAPIServer(KubeAPIServer)
+}
+
+// GetAuditLogSummaryForAPIServer returns nil if no events were logged by apiserver.
+func (s *summarizer) GetAuditLogSummaryForAPIServer(apiserver string) *AuditLogSummary {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.apiserverToAuditLogSummary[apiserver]
+}
+
+// APIServers returns the apiservers that have a summary, in order.
+func (s *summarizer) APIServers() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ret := []string{}
+	for apiserver := range s.apiserverToAuditLogSummary {
+		ret = append(ret, apiserver)
+	}
+	sort.Strings(ret)
+	return ret
 }
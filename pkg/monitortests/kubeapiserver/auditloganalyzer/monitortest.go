@@ -14,6 +14,7 @@ import (
 	"github.com/openshift/origin/pkg/monitortestframework"
 	"github.com/openshift/origin/pkg/monitortests/testframework/watchnamespaces"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"github.com/sirupsen/logrus"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -33,10 +34,18 @@ type auditLogAnalyzer struct {
 	violationChecker              *auditViolations
 	watchCountTracking            *watchCountTracking
 	latencyChecker                *auditLatencyRecords
+	apfChecker                    *apfTracking
+	apfMetrics                    *apfMetrics
 
 	countsForInstall *CountsForRun
 
 	clusterStability monitortestframework.ClusterStabilityDuringTest
+
+	// apiservers are the apiservers whose audit logs are read, MicroShift does not run the aggregated apiservers.
+	apiservers []string
+
+	// offline is set when the audit logs were read from a directory instead of a live cluster.
+	offline *offlineAuditLogSource
 }
 
 func NewAuditLogAnalyzer(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
@@ -48,7 +57,9 @@ func NewAuditLogAnalyzer(info monitortestframework.MonitorTestInitializationInfo
 		violationChecker:              CheckForViolations(),
 		watchCountTracking:            NewWatchCountTracking(),
 		latencyChecker:                CheckForLatency(),
+		apfChecker:                    CheckForAPFThrottling(),
 		clusterStability:              info.ClusterStabilityDuringTest,
+		apiservers:                    AuditedAPIServers,
 	}
 }
 
@@ -79,17 +90,14 @@ func (w *auditLogAnalyzer) StartCollection(ctx context.Context, adminRESTConfig
 	}
 	if isMicroshift, _ := exutil.IsMicroShiftCluster(kubeClient); !isMicroshift {
 		w.isTechPreview = exutil.IsTechPreviewNoUpgrade(ctx, configClient)
+	} else {
+		w.apiservers = []string{KubeAPIServer}
 	}
 
 	return nil
 }
 
-func (w *auditLogAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
-	kubeClient, err := kubernetes.NewForConfig(w.adminRESTConfig)
-	if err != nil {
-		return nil, nil, err
-	}
-
+func (w *auditLogAnalyzer) auditLogHandlers() []AuditEventHandler {
 	auditLogHandlers := []AuditEventHandler{
 		w.summarizer,
 		w.excessiveApplyChecker,
@@ -97,6 +105,7 @@ func (w *auditLogAnalyzer) CollectData(ctx context.Context, storageDir string, b
 		w.requestsDuringShutdownChecker,
 		w.violationChecker,
 		w.latencyChecker,
+		w.apfChecker,
 	}
 	if w.requestCountTracking != nil {
 		auditLogHandlers = append(auditLogHandlers, w.requestCountTracking)
@@ -105,8 +114,16 @@ func (w *auditLogAnalyzer) CollectData(ctx context.Context, storageDir string, b
 	if w.clusterStability == monitortestframework.Stable {
 		auditLogHandlers = append(auditLogHandlers, w.watchCountTracking)
 	}
+	return auditLogHandlers
+}
+
+func (w *auditLogAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	kubeClient, err := kubernetes.NewForConfig(w.adminRESTConfig)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	err = GetKubeAuditLogSummary(ctx, kubeClient, &beginning, &end, auditLogHandlers)
+	err = GetAuditLogSummary(ctx, kubeClient, w.apiservers, &beginning, &end, w.auditLogHandlers())
 
 	retIntervals := monitorapi.Intervals{}
 
@@ -166,6 +183,16 @@ func (w *auditLogAnalyzer) CollectData(ctx context.Context, storageDir string, b
 		}
 	}
 
+	collectedAPFMetrics, apfErr := collectAPFMetrics(ctx, w.adminRESTConfig, beginning, end)
+	switch {
+	case apfErr != nil:
+		// the priority and fairness summary is informational, like the other metric collectors
+		logrus.WithError(apfErr).Warn("unable to collect API Priority and Fairness metrics")
+	case collectedAPFMetrics != nil:
+		w.apfMetrics = collectedAPFMetrics
+		retIntervals = append(retIntervals, collectedAPFMetrics.intervals...)
+	}
+
 	return retIntervals, nil, err
 }
 
@@ -173,6 +200,14 @@ func (*auditLogAnalyzer) ConstructComputedIntervals(ctx context.Context, startin
 	return nil, nil
 }
 
+// platformNamespaces are the namespaces checked for excessive and invalid requests.
+func (w *auditLogAnalyzer) platformNamespaces() ([]string, error) {
+	if w.offline != nil {
+		return w.namespacesSeenInAuditLogs(), nil
+	}
+	return watchnamespaces.GetAllPlatformNamespaces()
+}
+
 func (w *auditLogAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
 	ret := []*junitapi.JUnitTestCase{}
 
@@ -203,7 +238,7 @@ func (w *auditLogAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Con
 		})
 	}
 
-	allPlatformNamespaces, err := watchnamespaces.GetAllPlatformNamespaces()
+	allPlatformNamespaces, err := w.platformNamespaces()
 	if err != nil {
 		return nil, fmt.Errorf("problem getting platform namespaces: %w", err)
 	}
@@ -417,9 +452,16 @@ func (w *auditLogAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Con
 	}
 
 	ret = append(ret, w.violationChecker.CreateJunits()...)
+	ret = append(ret, w.apfChecker.CreateJunits()...)
 
 	if w.clusterStability == monitortestframework.Stable {
-		junits, err := w.watchCountTracking.CreateJunits()
+		var junits []*junitapi.JUnitTestCase
+		var err error
+		if w.offline != nil {
+			junits, err = w.watchCountTracking.CreateJunitsForInfrastructure(w.offline.infrastructure)
+		} else {
+			junits, err = w.watchCountTracking.CreateJunits()
+		}
 		if err == nil {
 			ret = append(ret, junits...)
 		}
@@ -429,7 +471,7 @@ func (w *auditLogAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Con
 }
 
 func (w *auditLogAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	if currErr := WriteAuditLogSummary(storageDir, timeSuffix, w.summarizer.auditLogSummary); currErr != nil {
+	if currErr := WriteAuditLogSummaries(storageDir, timeSuffix, w.summarizer); currErr != nil {
 		return currErr
 	}
 
@@ -449,6 +491,22 @@ func (w *auditLogAnalyzer) WriteContentToStorage(ctx context.Context, storageDir
 		}
 	}
 
+	if w.apfChecker != nil {
+		err := w.apfChecker.WriteAuditLogSummary(storageDir, "audit-apf", timeSuffix)
+		if err != nil {
+			// print any error and continue processing
+			fmt.Printf("unable to write audit log summary for %s - %v\n", "audit-apf", err)
+		}
+	}
+
+	if w.apfMetrics != nil {
+		err := w.apfMetrics.WriteSummary(storageDir, "apf", timeSuffix)
+		if err != nil {
+			// print any error and continue processing
+			fmt.Printf("unable to write summary for %s - %v\n", "apf", err)
+		}
+	}
+
 	if w.requestCountTracking != nil {
 		err := w.requestCountTracking.CountsForRun.WriteContentToStorage(storageDir, "request-counts-by-second", timeSuffix)
 		if err != nil {
@@ -6,11 +6,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/openshift/origin/pkg/monitortestframework"
 	"github.com/openshift/origin/pkg/monitortestlibrary/nodeaccess"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -20,7 +22,25 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+const (
+	KubeAPIServer      = "kube-apiserver"
+	OpenShiftAPIServer = "openshift-apiserver"
+	OAuthAPIServer     = "oauth-apiserver"
+)
+
+// AuditedAPIServers are the apiservers whose audit logs are written to the masters.  Requests for aggregated
+// resources like routes and oauth tokens are logged by kube-apiserver as proxied requests and again, with their
+// real latency and response, by the aggregated apiserver that served them.
+var AuditedAPIServers = []string{KubeAPIServer, OpenShiftAPIServer, OAuthAPIServer}
+
 func GetKubeAuditLogSummary(ctx context.Context, kubeClient kubernetes.Interface, beginning, end *time.Time, auditLogHandlers []AuditEventHandler) error {
+	return GetAuditLogSummary(ctx, kubeClient, []string{KubeAPIServer}, beginning, end, auditLogHandlers)
+}
+
+// GetAuditLogSummary streams the audit logs of the apiservers from every master to the handlers.  Only kube-apiserver
+// logs are required.  When only the logs of aggregated apiservers could not be read, the error is a
+// monitortestframework.FlakeError so the gap is reported without failing the run.
+func GetAuditLogSummary(ctx context.Context, kubeClient kubernetes.Interface, apiservers []string, beginning, end *time.Time, auditLogHandlers []AuditEventHandler) error {
 	masterOnly, err := labels.NewRequirement("node-role.kubernetes.io/master", selection.Exists, nil)
 	if err != nil {
 		panic(err)
@@ -33,54 +53,64 @@ func GetKubeAuditLogSummary(ctx context.Context, kubeClient kubernetes.Interface
 		return err
 	}
 
-	lock := sync.Mutex{}
-	errCh := make(chan error, len(allNodes.Items))
+	requiredErrCh := make(chan error, len(allNodes.Items))
+	optionalErrCh := make(chan error, len(allNodes.Items)*len(apiservers))
 	wg := sync.WaitGroup{}
 	for _, node := range allNodes.Items {
-		wg.Add(1)
-		go func(ctx context.Context, nodeName string) {
-			defer wg.Done()
-			var microBeginning, microEnd *metav1.MicroTime
-			if nil != beginning {
-				micro := metav1.NewMicroTime(*beginning)
-				microBeginning = &micro
-			}
-			if nil != end {
-				micro := metav1.NewMicroTime(*end)
-				microEnd = &micro
-			}
-			err := getNodeKubeAuditLogSummary(ctx, kubeClient, nodeName, microBeginning, microEnd, auditLogHandlers)
-			if err != nil {
-				errCh <- err
-				return
-			}
-
-			lock.Lock()
-			defer lock.Unlock()
-		}(ctx, node.Name)
+		for _, apiserver := range apiservers {
+			wg.Add(1)
+			go func(ctx context.Context, nodeName, apiserver string) {
+				defer wg.Done()
+				var microBeginning, microEnd *metav1.MicroTime
+				if nil != beginning {
+					micro := metav1.NewMicroTime(*beginning)
+					microBeginning = &micro
+				}
+				if nil != end {
+					micro := metav1.NewMicroTime(*end)
+					microEnd = &micro
+				}
+				err := getAuditLogSummary(ctx, kubeClient, nodeName, apiserver, microBeginning, microEnd, auditLogHandlers)
+				switch {
+				case err == nil:
+				case apiserver == KubeAPIServer:
+					requiredErrCh <- err
+				default:
+					optionalErrCh <- err
+				}
+			}(ctx, node.Name, apiserver)
+		}
 	}
 	wg.Wait()
+	close(requiredErrCh)
+	close(optionalErrCh)
 
-	errs := []error{}
-	for len(errCh) > 0 {
-		err := <-errCh
-		errs = append(errs, err)
+	requiredErrs, optionalErrs := []error{}, []error{}
+	for err := range requiredErrCh {
+		requiredErrs = append(requiredErrs, err)
+	}
+	for err := range optionalErrCh {
+		optionalErrs = append(optionalErrs, err)
 	}
 
-	return utilerrors.NewAggregate(errs)
+	if len(requiredErrs) > 0 {
+		return utilerrors.NewAggregate(append(requiredErrs, optionalErrs...))
+	}
+	if len(optionalErrs) > 0 {
+		return &monitortestframework.FlakeError{Err: utilerrors.NewAggregate(optionalErrs)}
+	}
+	return nil
 }
 
 type AuditEventHandler interface {
-	HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName string)
+	// HandleAuditLogEvent is called for every event with the node and the apiserver that logged it.
+	HandleAuditLogEvent(auditEvent *auditv1.Event, beginning, end *metav1.MicroTime, nodeName, apiserver string)
 }
 
-func getNodeKubeAuditLogSummary(ctx context.Context, client kubernetes.Interface, nodeName string, beginning, end *metav1.MicroTime, auditLogHandlers []AuditEventHandler) error {
-	return getAuditLogSummary(ctx, client, nodeName, "kube-apiserver", beginning, end, auditLogHandlers)
-}
 func getAuditLogSummary(ctx context.Context, client kubernetes.Interface, nodeName, apiserver string, beginning, end *metav1.MicroTime, auditLogHandlers []AuditEventHandler) error {
 	auditLogFilenames, err := getAuditLogFilenames(ctx, client, nodeName, apiserver)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to list %s audit logs on %s: %w", apiserver, nodeName, err)
 	}
 
 	// we do not have enough memory to read all the content and then navigate it all in memory.
@@ -108,27 +138,7 @@ func getAuditLogSummary(ctx context.Context, client kubernetes.Interface, nodeNa
 				return
 			}
 
-			scanner := bufio.NewScanner(auditStream)
-			line := 0
-			for scanner.Scan() {
-				line++
-				auditLine := scanner.Bytes()
-
-				if len(auditLine) == 0 {
-					continue
-				}
-
-				auditEvent := &auditv1.Event{}
-				if err := json.Unmarshal(auditLine, auditEvent); err != nil {
-					fmt.Printf("unable to decode %q line %d: %s to audit event: %v\n", auditLogFilename, line, string(auditLine), err)
-					continue
-				}
-
-				for _, auditLogHandler := range auditLogHandlers {
-					auditLogHandler.HandleAuditLogEvent(auditEvent, beginning, end, nodeName)
-				}
-			}
-
+			handleAuditLogStream(auditStream, auditLogFilename, nodeName, apiserver, beginning, end, auditLogHandlers)
 		}(ctx, auditLogFilename, nodeName)
 	}
 	wg.Wait()
@@ -142,6 +152,30 @@ func getAuditLogSummary(ctx context.Context, client kubernetes.Interface, nodeNa
 	return utilerrors.NewAggregate(errs)
 }
 
+// handleAuditLogStream decodes one audit event per line and passes every event to every handler.
+func handleAuditLogStream(auditStream io.Reader, auditLogFilename, nodeName, apiserver string, beginning, end *metav1.MicroTime, auditLogHandlers []AuditEventHandler) {
+	scanner := bufio.NewScanner(auditStream)
+	line := 0
+	for scanner.Scan() {
+		line++
+		auditLine := scanner.Bytes()
+
+		if len(auditLine) == 0 {
+			continue
+		}
+
+		auditEvent := &auditv1.Event{}
+		if err := json.Unmarshal(auditLine, auditEvent); err != nil {
+			fmt.Printf("unable to decode %q line %d: %s to audit event: %v\n", auditLogFilename, line, string(auditLine), err)
+			continue
+		}
+
+		for _, auditLogHandler := range auditLogHandlers {
+			auditLogHandler.HandleAuditLogEvent(auditEvent, beginning, end, nodeName, apiserver)
+		}
+	}
+}
+
 func getAuditLogFilenames(ctx context.Context, client kubernetes.Interface, nodeName, apiserverName string) ([]string, error) {
 	allBytes, err := nodeaccess.GetNodeLogFile(ctx, client, nodeName, apiserverName)
 	if err != nil {
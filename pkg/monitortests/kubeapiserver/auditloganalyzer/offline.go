@@ -0,0 +1,130 @@
+package auditloganalyzer
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// offlineAuditLogSource describes audit logs that were gathered from a cluster that may no longer exist.
+type offlineAuditLogSource struct {
+	dir string
+	// infrastructure is read from the directory when it contains the cluster configuration, as must-gather does.
+	infrastructure *configv1.Infrastructure
+}
+
+// AnalyzeAuditLogDirectory runs the same handlers, junits, and summaries as the audit log monitor test against
+// the audit logs gathered into dir.  Summaries are written to storageDir.  Checks that need the live cluster are
+// adapted: platform namespaces are the ones that show up in the audit logs, request counts over time are not
+// computed, and watch count limits are only checked when dir contains the infrastructure configuration.
+func AnalyzeAuditLogDirectory(ctx context.Context, dir, storageDir, timeSuffix string, clusterStability monitortestframework.ClusterStabilityDuringTest, beginning, end *time.Time) ([]*junitapi.JUnitTestCase, error) {
+	infrastructure, err := findInfrastructure(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	analyzer := NewAuditLogAnalyzer(monitortestframework.MonitorTestInitializationInfo{
+		ClusterStabilityDuringTest: clusterStability,
+	}).(*auditLogAnalyzer)
+	analyzer.offline = &offlineAuditLogSource{
+		dir:            dir,
+		infrastructure: infrastructure,
+	}
+
+	if err := GetAuditLogSummaryFromDirectory(ctx, dir, AuditedAPIServers, beginning, end, analyzer.auditLogHandlers()); err != nil {
+		return nil, err
+	}
+
+	junits, err := analyzer.EvaluateTestsFromConstructedIntervals(ctx, nil)
+	if err != nil {
+		return junits, err
+	}
+	if err := analyzer.WriteContentToStorage(ctx, storageDir, timeSuffix, nil, nil); err != nil {
+		return junits, err
+	}
+	return junits, nil
+}
+
+// namespacesSeenInAuditLogs stands in for the namespace watcher when there is no cluster to watch.
+func (w *auditLogAnalyzer) namespacesSeenInAuditLogs() []string {
+	namespaces := sets.New[string]()
+	for namespace := range w.excessiveApplyChecker.namespacesToUserToNumberOfApplies {
+		namespaces.Insert(namespace)
+	}
+	for _, namespacesToUsers := range w.invalidRequestsChecker.verbToNamespacesTouserToNumberOf422s {
+		for namespace := range namespacesToUsers.namespacesToInvalidUserTrackers {
+			namespaces.Insert(namespace)
+		}
+	}
+
+	ret := []string{}
+	for _, namespace := range sets.List(namespaces) {
+		if platformidentification.IsPlatformNamespace(namespace) {
+			ret = append(ret, namespace)
+		}
+	}
+	return ret
+}
+
+// findInfrastructure looks for the cluster infrastructure in a must-gather, which stores it either as
+// infrastructures/cluster.yaml or as a list in infrastructures.yaml.  A missing infrastructure is not an error.
+func findInfrastructure(dir string) (*configv1.Infrastructure, error) {
+	candidates := []string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.Contains(path, "config.openshift.io") {
+			return nil
+		}
+		if d.Name() == "infrastructures.yaml" || (d.Name() == "cluster.yaml" && filepath.Base(filepath.Dir(path)) == "infrastructures") {
+			candidates = append(candidates, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(candidates)
+
+	for _, candidate := range candidates {
+		data, err := os.ReadFile(candidate)
+		if err != nil {
+			return nil, err
+		}
+		list := &configv1.InfrastructureList{}
+		if err := yaml.Unmarshal(data, list); err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", candidate, err)
+		}
+		if strings.HasSuffix(list.Kind, "List") {
+			for i := range list.Items {
+				if list.Items[i].Name == "cluster" {
+					return &list.Items[i], nil
+				}
+			}
+			continue
+		}
+
+		infrastructure := &configv1.Infrastructure{}
+		if err := yaml.Unmarshal(data, infrastructure); err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", candidate, err)
+		}
+		if infrastructure.Name == "cluster" {
+			return infrastructure, nil
+		}
+	}
+	return nil, nil
+}
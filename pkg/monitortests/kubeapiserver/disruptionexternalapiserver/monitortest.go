@@ -289,7 +289,19 @@ func (i *InvariantExternalDisruption) EvaluateTestsFromConstructedIntervals(ctx
 }
 
 func (i *InvariantExternalDisruption) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
+	errs := []error{}
+	for n := range i.disruptionCheckers {
+		// we failed and indicated it during setup.
+		if i.disruptionCheckers[n] == nil {
+			continue
+		}
+
+		if err := i.disruptionCheckers[n].WriteContentToStorage(ctx, storageDir, timeSuffix); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
 }
 
 func (i *InvariantExternalDisruption) Cleanup(ctx context.Context) error {
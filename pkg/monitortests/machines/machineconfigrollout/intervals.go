@@ -0,0 +1,207 @@
+package machineconfigrollout
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The phases a node goes through while the machine-config-daemon rolls a new config out to it. Rebootless updates
+// skip Reboot, and the time between the drain completing and the config being applied is all ConfigApply.
+const (
+	phaseCordon      = "Cordon"
+	phaseDrain       = "Drain"
+	phaseReboot      = "Reboot"
+	phaseConfigApply = "ConfigApply"
+)
+
+var phaseMessages = map[string]string{
+	phaseCordon:      "cordoning node",
+	phaseDrain:       "draining node",
+	phaseReboot:      "updating and rebooting node",
+	phaseConfigApply: "applying machine config",
+}
+
+type openPhase struct {
+	name   string
+	from   time.Time
+	pool   string
+	config string
+}
+
+// nodePhaseIntervals constructs an interval for every rollout phase of every node from the point in time intervals
+// recorded while watching the nodes. A node that was still draining, rebooting or applying its config when the run
+// ended gets a warning that lasts until end.
+func nodePhaseIntervals(startingIntervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	changes := startingIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceMachineConfigRollout &&
+			eventInterval.Locator.Type == monitorapi.LocatorTypeNode
+	})
+
+	nodeToChanges := map[string]monitorapi.Intervals{}
+	for _, change := range changes {
+		nodeName := change.Locator.Keys[monitorapi.LocatorNodeKey]
+		nodeToChanges[nodeName] = append(nodeToChanges[nodeName], change)
+	}
+	nodeNames := make([]string, 0, len(nodeToChanges))
+	for nodeName := range nodeToChanges {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+
+	ret := monitorapi.Intervals{}
+	for _, nodeName := range nodeNames {
+		var current *openPhase
+		closePhase := func(to time.Time, completed bool) {
+			if current == nil {
+				return
+			}
+			ret = append(ret, nodePhaseInterval(nodeName, *current, to, completed))
+			current = nil
+		}
+		openNext := func(name string, change monitorapi.Interval) {
+			current = &openPhase{
+				name:   name,
+				from:   change.From,
+				pool:   change.Message.Annotations[monitorapi.AnnotationMachineConfigPool],
+				config: change.Message.Annotations[monitorapi.AnnotationConfig],
+			}
+		}
+
+		for _, change := range nodeToChanges[nodeName] {
+			switch change.Message.Reason {
+			case monitorapi.NodeDrainRequestedReason:
+				closePhase(change.From, false)
+				openNext(phaseCordon, change)
+
+			case monitorapi.NodeCordonedReason:
+				if current != nil && current.name == phaseCordon {
+					closePhase(change.From, true)
+					openNext(phaseDrain, change)
+				}
+
+			case monitorapi.NodeDrainCompletedReason:
+				if current == nil || (current.name != phaseCordon && current.name != phaseDrain) {
+					continue
+				}
+				// a node that was already unschedulable is never seen being cordoned, so it was draining all along
+				current.name = phaseDrain
+				closePhase(change.From, true)
+				openNext(phaseReboot, change)
+
+			case monitorapi.NodeRebootedReason:
+				if current != nil && current.name == phaseReboot {
+					closePhase(change.From, true)
+					openNext(phaseConfigApply, change)
+				}
+
+			case monitorapi.MachineConfigDaemonStateChangedReason:
+				if change.Message.Annotations[monitorapi.AnnotationState] != stateDone || current == nil {
+					continue
+				}
+				if current.name == phaseReboot {
+					current.name = phaseConfigApply
+				}
+				closePhase(change.From, true)
+			}
+		}
+		closePhase(end, false)
+	}
+	return ret
+}
+
+func nodePhaseInterval(nodeName string, phase openPhase, to time.Time, completed bool) monitorapi.Interval {
+	level := monitorapi.Info
+	humanMessage := phaseMessages[phase.name]
+	if !completed {
+		level = monitorapi.Warning
+		humanMessage = fmt.Sprintf("%s, never completed", humanMessage)
+	}
+	mb := monitorapi.NewMessage().Reason(monitorapi.MachineConfigNodePhaseReason).
+		Constructed(monitorapi.ConstructionOwnerMachineConfigRollout).
+		WithAnnotation(monitorapi.AnnotationPhase, phase.name).
+		WithAnnotation(monitorapi.AnnotationConfig, phase.config).
+		HumanMessage(humanMessage)
+	if len(phase.pool) > 0 {
+		mb = mb.WithAnnotation(monitorapi.AnnotationMachineConfigPool, phase.pool)
+	}
+	return monitorapi.NewInterval(monitorapi.SourceMachineConfigRollout, level).
+		Locator(monitorapi.NewLocator().NodeFromName(nodeName)).
+		Message(mb).
+		Display().
+		Build(phase.from, to)
+}
+
+// poolConditionIntervals constructs an Updating or Degraded interval for every period a pool reported the condition
+// as True. A pool that had not settled yet when the run ended is charted until end.
+func poolConditionIntervals(startingIntervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	changes := startingIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceMachineConfigRollout &&
+			eventInterval.Message.Reason == monitorapi.MachineConfigPoolConditionChangedReason
+	})
+
+	type poolCondition struct {
+		pool      string
+		condition string
+	}
+	opened := map[poolCondition]monitorapi.Interval{}
+	ret := monitorapi.Intervals{}
+	closeCondition := func(key poolCondition, to time.Time) {
+		from, ok := opened[key]
+		if !ok {
+			return
+		}
+		delete(opened, key)
+
+		reason := monitorapi.MachineConfigPoolUpdatingReason
+		level := monitorapi.Info
+		if key.condition == string(mcfgv1.MachineConfigPoolDegraded) {
+			reason = monitorapi.MachineConfigPoolDegradedReason
+			level = monitorapi.Error
+		}
+		mb := monitorapi.NewMessage().Reason(reason).
+			Constructed(monitorapi.ConstructionOwnerMachineConfigRollout).
+			HumanMessage(from.Message.HumanMessage)
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceMachineConfigRollout, level).
+				Locator(from.Locator).
+				Message(mb).
+				Display().
+				Build(from.From, to),
+		)
+	}
+
+	for _, change := range changes {
+		key := poolCondition{
+			pool:      change.Locator.Keys[monitorapi.LocatorMachineConfigPoolKey],
+			condition: change.Message.Annotations[monitorapi.AnnotationCondition],
+		}
+		if change.Message.Annotations[monitorapi.AnnotationStatus] == string(corev1.ConditionTrue) {
+			if _, ok := opened[key]; !ok {
+				opened[key] = change
+			}
+			continue
+		}
+		closeCondition(key, change.From)
+	}
+
+	keys := make([]poolCondition, 0, len(opened))
+	for key := range opened {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].pool != keys[j].pool {
+			return keys[i].pool < keys[j].pool
+		}
+		return keys[i].condition < keys[j].condition
+	})
+	for _, key := range keys {
+		closeCondition(key, end)
+	}
+	return ret
+}
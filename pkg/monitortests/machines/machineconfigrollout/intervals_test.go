@@ -0,0 +1,220 @@
+package machineconfigrollout
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestPoolFromRenderedConfig(t *testing.T) {
+	tests := map[string]string{
+		"rendered-worker-0123456789abcdef":      "worker",
+		"rendered-infra-edge-0123456789abcdef":  "infra-edge",
+		"rendered-master-d3a4c0d1e4fd0f5d13c8b": "master",
+		"":                                      "",
+		"00-worker":                             "",
+	}
+	for config, expected := range tests {
+		if actual := poolFromRenderedConfig(config); actual != expected {
+			t.Errorf("expected %q for %q, got %q", expected, config, actual)
+		}
+	}
+}
+
+// nodeStep changes the node the way one step of a rollout does.
+type nodeStep struct {
+	at     time.Duration
+	change func(node *corev1.Node)
+}
+
+func annotate(key, value string) func(node *corev1.Node) {
+	return func(node *corev1.Node) {
+		node.Annotations[key] = value
+	}
+}
+
+func cordon(unschedulable bool) func(node *corev1.Node) {
+	return func(node *corev1.Node) {
+		node.Spec.Unschedulable = unschedulable
+	}
+}
+
+func reboot(node *corev1.Node) {
+	node.Status.NodeInfo.BootID = "after-reboot"
+}
+
+// rollout replays the steps against a node and returns what the watcher would have recorded.
+func rollout(from time.Time, steps []nodeStep) monitorapi.Intervals {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "worker-a",
+			Annotations: map[string]string{
+				desiredConfigAnnotation: "rendered-worker-new",
+				stateAnnotation:         stateDone,
+			},
+		},
+		Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{BootID: "before-reboot"}},
+	}
+	var intervals monitorapi.Intervals
+	for _, step := range steps {
+		oldNode := node.DeepCopy()
+		step.change(node)
+		intervals = append(intervals, nodeRolloutChanges(node, oldNode, from.Add(step.at))...)
+	}
+	return intervals
+}
+
+type phase struct {
+	name     string
+	from, to time.Duration
+	level    monitorapi.IntervalLevel
+}
+
+func TestNodePhaseIntervals(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := from.Add(time.Hour)
+	tests := []struct {
+		name     string
+		steps    []nodeStep
+		expected []phase
+	}{
+		{
+			name: "update with reboot",
+			steps: []nodeStep{
+				{at: time.Minute, change: annotate(stateAnnotation, "Working")},
+				{at: time.Minute, change: annotate(desiredDrainAnnotation, "drain-rendered-worker-new")},
+				{at: 2 * time.Minute, change: cordon(true)},
+				{at: 5 * time.Minute, change: annotate(lastAppliedDrainAnnotation, "drain-rendered-worker-new")},
+				{at: 9 * time.Minute, change: reboot},
+				{at: 10 * time.Minute, change: annotate(stateAnnotation, stateDone)},
+				{at: 11 * time.Minute, change: cordon(false)},
+			},
+			expected: []phase{
+				{name: phaseCordon, from: time.Minute, to: 2 * time.Minute, level: monitorapi.Info},
+				{name: phaseDrain, from: 2 * time.Minute, to: 5 * time.Minute, level: monitorapi.Info},
+				{name: phaseReboot, from: 5 * time.Minute, to: 9 * time.Minute, level: monitorapi.Info},
+				{name: phaseConfigApply, from: 9 * time.Minute, to: 10 * time.Minute, level: monitorapi.Info},
+			},
+		},
+		{
+			name: "rebootless update of a node that was already cordoned",
+			steps: []nodeStep{
+				{at: time.Minute, change: annotate(stateAnnotation, "Working")},
+				{at: time.Minute, change: annotate(desiredDrainAnnotation, "drain-rendered-worker-new")},
+				{at: 3 * time.Minute, change: annotate(lastAppliedDrainAnnotation, "drain-rendered-worker-new")},
+				{at: 5 * time.Minute, change: annotate(stateAnnotation, stateDone)},
+			},
+			expected: []phase{
+				{name: phaseDrain, from: time.Minute, to: 3 * time.Minute, level: monitorapi.Info},
+				{name: phaseConfigApply, from: 3 * time.Minute, to: 5 * time.Minute, level: monitorapi.Info},
+			},
+		},
+		{
+			name: "drain that never completes",
+			steps: []nodeStep{
+				{at: time.Minute, change: annotate(desiredDrainAnnotation, "drain-rendered-worker-new")},
+				{at: 2 * time.Minute, change: cordon(true)},
+				{at: 30 * time.Minute, change: func(node *corev1.Node) {
+					node.Annotations[reasonAnnotation] = "failed to drain node: worker-a after 1 hour"
+					node.Annotations[stateAnnotation] = stateDegraded
+				}},
+			},
+			expected: []phase{
+				{name: phaseCordon, from: time.Minute, to: 2 * time.Minute, level: monitorapi.Info},
+				{name: phaseDrain, from: 2 * time.Minute, to: time.Hour, level: monitorapi.Warning},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intervals := nodePhaseIntervals(rollout(from, tt.steps), end)
+			actual := []phase{}
+			for _, interval := range intervals {
+				if interval.Message.Annotations[monitorapi.AnnotationMachineConfigPool] != "worker" {
+					t.Errorf("expected the phase to be attributed to the worker pool: %s", interval.String())
+				}
+				actual = append(actual, phase{
+					name:  interval.Message.Annotations[monitorapi.AnnotationPhase],
+					from:  interval.From.Sub(from),
+					to:    interval.To.Sub(from),
+					level: interval.Level,
+				})
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNodeRolloutChangesDrainFailed(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	steps := []nodeStep{{change: func(node *corev1.Node) {
+		node.Annotations[reasonAnnotation] = "failed to drain node: worker-a after 1 hour"
+		node.Annotations[stateAnnotation] = stateDegraded
+	}}}
+	failed := rollout(now, steps).Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Message.Reason == monitorapi.NodeDrainFailedReason
+	})
+	if len(failed) != 1 || failed[0].Level != monitorapi.Error {
+		t.Errorf("expected a single drain failure, got %v", failed)
+	}
+}
+
+func newPool(conditions ...mcfgv1.MachineConfigPoolCondition) *mcfgv1.MachineConfigPool {
+	return &mcfgv1.MachineConfigPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker"},
+		Status:     mcfgv1.MachineConfigPoolStatus{Conditions: conditions},
+	}
+}
+
+func TestPoolConditionIntervals(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := from.Add(time.Hour)
+
+	updating := mcfgv1.MachineConfigPoolCondition{Type: mcfgv1.MachineConfigPoolUpdating, Status: corev1.ConditionTrue}
+	updated := mcfgv1.MachineConfigPoolCondition{Type: mcfgv1.MachineConfigPoolUpdating, Status: corev1.ConditionFalse}
+	degraded := mcfgv1.MachineConfigPoolCondition{Type: mcfgv1.MachineConfigPoolDegraded, Status: corev1.ConditionTrue, Message: "Node worker-a is reporting: failed to drain node"}
+	healthy := mcfgv1.MachineConfigPoolCondition{Type: mcfgv1.MachineConfigPoolDegraded, Status: corev1.ConditionFalse}
+
+	pools := []*mcfgv1.MachineConfigPool{
+		newPool(updated, healthy),
+		newPool(updating, healthy),
+		newPool(updating, degraded),
+		newPool(updated, degraded),
+	}
+	var changes monitorapi.Intervals
+	changes = append(changes, poolConditionChanges(pools[0], nil, from)...)
+	for i := 1; i < len(pools); i++ {
+		changes = append(changes, poolConditionChanges(pools[i], pools[i-1], from.Add(time.Duration(i)*10*time.Minute))...)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("expected a change for Updating starting, Degraded starting and Updating finishing, got %v", changes)
+	}
+
+	intervals := poolConditionIntervals(changes, end)
+	expected := []struct {
+		reason   monitorapi.IntervalReason
+		from, to time.Duration
+	}{
+		{reason: monitorapi.MachineConfigPoolUpdatingReason, from: 10 * time.Minute, to: 30 * time.Minute},
+		{reason: monitorapi.MachineConfigPoolDegradedReason, from: 20 * time.Minute, to: time.Hour},
+	}
+	if len(intervals) != len(expected) {
+		t.Fatalf("expected %d intervals, got %v", len(expected), intervals)
+	}
+	for i, interval := range intervals {
+		if interval.Message.Reason != expected[i].reason || interval.From.Sub(from) != expected[i].from || interval.To.Sub(from) != expected[i].to {
+			t.Errorf("expected %s from %s to %s, got %s", expected[i].reason, expected[i].from, expected[i].to, interval.String())
+		}
+		if interval.Locator.Keys[monitorapi.LocatorMachineConfigPoolKey] != "worker" {
+			t.Errorf("expected the worker pool locator, got %v", interval.Locator)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package machineconfigrollout
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+
+	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	drainDurationTestName = "[sig-mco] nodes should drain within the historical norm for their machine config pool"
+	degradedPoolTestName  = "[sig-mco] machine config pools should not become degraded"
+
+	// defaultDrainDurationLimit applies to pools and job types without enough historical drain durations. The
+	// machine-config-controller retries a drain for an hour before it gives up and degrades the node, a drain
+	// taking a quarter of that is worth a look even without data to compare it to. It is a guess rather than a
+	// measurement, so drains over it only flake.
+	defaultDrainDurationLimit = 15 * time.Minute
+)
+
+// drainDurations holds the historical drain durations in the same format as the backend disruption data, with the
+// pool name in place of the backend name. Until it is filled from CI runs every drain is held to the default limit.
+//
+//go:embed drain_durations.json
+var drainDurations []byte
+
+var (
+	readDrainDurations    sync.Once
+	drainDurationsMatcher *historicaldata.DisruptionBestMatcher
+)
+
+func getDrainDurations() *historicaldata.DisruptionBestMatcher {
+	readDrainDurations.Do(
+		func() {
+			var err error
+			drainDurationsMatcher, err = historicaldata.NewDisruptionMatcher(drainDurations)
+			if err != nil {
+				panic(err)
+			}
+		})
+
+	return drainDurationsMatcher
+}
+
+// drainLimitFunc returns how long a drain of a node in the pool may take, where that limit came from and whether it
+// is historical.
+type drainLimitFunc func(pool string) (time.Duration, string, bool)
+
+func historicalDrainLimit(matcher *historicaldata.DisruptionBestMatcher, jobType *platformidentification.JobType) drainLimitFunc {
+	return func(pool string) (time.Duration, string, bool) {
+		if jobType == nil {
+			return defaultDrainDurationLimit, "the default limit, the job type is unknown", false
+		}
+		p99, details, err := matcher.BestMatchP99(pool, *jobType)
+		if err != nil || p99 == nil {
+			return defaultDrainDurationLimit, fmt.Sprintf("the default limit, there is no historical data %s", details), false
+		}
+		return *p99, fmt.Sprintf("the historical P99 %s", details), true
+	}
+}
+
+// drainDurationJUnits fails when a node took longer to drain than the historical limit for its pool, and flakes when
+// it only took longer than the default limit. No junit is returned when no node was drained.
+func drainDurationJUnits(finalIntervals monitorapi.Intervals, drainLimit drainLimitFunc) []*junitapi.JUnitTestCase {
+	drains := finalIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Message.Reason == monitorapi.MachineConfigNodePhaseReason &&
+			eventInterval.Message.Annotations[monitorapi.AnnotationPhase] == phaseDrain
+	})
+	if len(drains) == 0 {
+		return nil
+	}
+
+	var failures []string
+	flakeOnly := true
+	for _, drain := range drains {
+		pool := drain.Message.Annotations[monitorapi.AnnotationMachineConfigPool]
+		limit, limitSource, historical := drainLimit(pool)
+		duration := drain.To.Sub(drain.From)
+		if duration <= limit {
+			continue
+		}
+		if historical {
+			flakeOnly = false
+		}
+		failures = append(failures, fmt.Sprintf("node/%s in pool %q took %s to drain, longer than %s allowed by %s: %s",
+			drain.Locator.Keys[monitorapi.LocatorNodeKey], pool, duration.Round(time.Second), limit.Round(time.Second), limitSource, drain.String()))
+	}
+	if len(failures) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: drainDurationTestName}}
+	}
+	ret := []*junitapi.JUnitTestCase{
+		{
+			Name: drainDurationTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: strings.Join(failures, "\n"),
+			},
+			SystemOut: strings.Join(failures, "\n"),
+		},
+	}
+	if flakeOnly {
+		// Mark the test as a flake
+		ret = append(ret, &junitapi.JUnitTestCase{Name: drainDurationTestName})
+	}
+	return ret
+}
+
+// degradedPoolJUnits fails when a pool became Degraded during the run. Pools that were already Degraded when the
+// monitor started are not counted, they have no previous status to transition from.
+func degradedPoolJUnits(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	degradations := finalIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		if eventInterval.Message.Reason != monitorapi.MachineConfigPoolConditionChangedReason {
+			return false
+		}
+		annotations := eventInterval.Message.Annotations
+		_, observedTransition := annotations[monitorapi.AnnotationPreviousStatus]
+		return observedTransition &&
+			annotations[monitorapi.AnnotationCondition] == string(mcfgv1.MachineConfigPoolDegraded) &&
+			annotations[monitorapi.AnnotationStatus] == string(corev1.ConditionTrue)
+	})
+	if len(degradations) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: degradedPoolTestName}}
+	}
+
+	failures := []string{}
+	for _, degradation := range degradations {
+		failures = append(failures, fmt.Sprintf("machineconfigpool/%s became degraded: %s",
+			degradation.Locator.Keys[monitorapi.LocatorMachineConfigPoolKey], degradation.String()))
+	}
+	sort.Strings(failures)
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: degradedPoolTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: strings.Join(failures, "\n"),
+			},
+			SystemOut: strings.Join(failures, "\n"),
+		},
+	}
+}
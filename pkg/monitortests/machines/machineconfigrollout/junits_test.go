@@ -0,0 +1,97 @@
+package machineconfigrollout
+
+import (
+	"testing"
+	"time"
+
+	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junittest"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+func drainInterval(nodeName, pool string, from time.Time, duration time.Duration) monitorapi.Interval {
+	return nodePhaseInterval(nodeName, openPhase{name: phaseDrain, from: from, pool: pool, config: "rendered-" + pool + "-new"}, from.Add(duration), true)
+}
+
+func TestDrainDurationJUnits(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	jobType := &platformidentification.JobType{Release: "4.22", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	matcher := historicaldata.NewDisruptionMatcherWithHistoricalData(map[historicaldata.DataKey]historicaldata.DisruptionStatisticalData{
+		{BackendName: "worker", JobType: *jobType}: {P99: 120, JobRuns: 200},
+	})
+
+	tests := []struct {
+		name          string
+		intervals     monitorapi.Intervals
+		jobType       *platformidentification.JobType
+		expectJUnits  int
+		expectFailure string
+	}{
+		{
+			name: "no drains",
+			intervals: monitorapi.Intervals{
+				nodePhaseInterval("worker-a", openPhase{name: phaseReboot, from: from, pool: "worker"}, from.Add(time.Hour), true),
+			},
+			jobType: jobType,
+		},
+		{
+			name: "within the historical norm",
+			intervals: monitorapi.Intervals{
+				drainInterval("worker-a", "worker", from, time.Minute),
+			},
+			jobType:      jobType,
+			expectJUnits: 1,
+		},
+		{
+			name: "longer than the historical norm",
+			intervals: monitorapi.Intervals{
+				drainInterval("worker-a", "worker", from, time.Minute),
+				drainInterval("worker-b", "worker", from, 5*time.Minute),
+				drainInterval("master-b", "master", from, 20*time.Minute),
+			},
+			jobType:       jobType,
+			expectJUnits:  1,
+			expectFailure: `node/worker-b in pool "worker" took 5m0s to drain, longer than 2m0s allowed by the historical P99`,
+		},
+		{
+			name: "pool without historical data flakes",
+			intervals: monitorapi.Intervals{
+				drainInterval("master-a", "master", from, 5*time.Minute),
+				drainInterval("master-b", "master", from, 20*time.Minute),
+			},
+			jobType:       jobType,
+			expectJUnits:  2,
+			expectFailure: `node/master-b in pool "master" took 20m0s to drain, longer than 15m0s allowed by the default limit`,
+		},
+		{
+			name: "unknown job type",
+			intervals: monitorapi.Intervals{
+				drainInterval("worker-b", "worker", from, 5*time.Minute),
+			},
+			expectJUnits: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			junits := drainDurationJUnits(tt.intervals, historicalDrainLimit(matcher, tt.jobType))
+			// a failure followed by a pass is a flake
+			junittest.ExpectJUnits(t, junits, drainDurationTestName, tt.expectJUnits, tt.expectFailure, len(tt.expectFailure) == 0 || tt.expectJUnits == 2)
+		})
+	}
+}
+
+func TestDegradedPoolJUnits(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	degraded := mcfgv1.MachineConfigPoolCondition{Type: mcfgv1.MachineConfigPoolDegraded, Status: corev1.ConditionTrue}
+	healthy := mcfgv1.MachineConfigPoolCondition{Type: mcfgv1.MachineConfigPoolDegraded, Status: corev1.ConditionFalse}
+
+	alreadyDegraded := poolConditionChanges(newPool(degraded), nil, now)
+	junittest.ExpectSingleJUnit(t, degradedPoolJUnits(alreadyDegraded), degradedPoolTestName, true, "")
+
+	becameDegraded := poolConditionChanges(newPool(degraded), newPool(healthy), now)
+	junittest.ExpectSingleJUnit(t, degradedPoolJUnits(becameDegraded), degradedPoolTestName, true, "machineconfigpool/worker became degraded")
+}
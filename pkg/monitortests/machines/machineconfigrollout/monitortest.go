@@ -0,0 +1,105 @@
+package machineconfigrollout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clientconfigv1 "github.com/openshift/client-go/config/clientset/versioned"
+	machineconfigclient "github.com/openshift/client-go/machineconfiguration/clientset/versioned"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+// machineConfigRolloutWatcher charts how machine config rollouts progress through the pools and their nodes.
+type machineConfigRolloutWatcher struct {
+	notSupportedReason error
+	jobType            *platformidentification.JobType
+}
+
+func NewMachineConfigRolloutWatcher() monitortestframework.MonitorTest {
+	return &machineConfigRolloutWatcher{}
+}
+
+func (w *machineConfigRolloutWatcher) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "platform MicroShift not supported"}
+		return w.notSupportedReason
+	}
+	configClient, err := clientconfigv1.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	if ok, err := exutil.IsHypershift(ctx, configClient); err != nil {
+		return fmt.Errorf("unable to determine if cluster is Hypershift: %v", err)
+	} else if ok {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "platform Hypershift not supported"}
+		return w.notSupportedReason
+	}
+
+	// without a job type the drains are held to the default limit rather than their historical norm
+	w.jobType, err = platformidentification.GetJobType(ctx, adminRESTConfig)
+	if err != nil {
+		logrus.WithError(err).Warn("unable to determine the job type, drain durations will use the default limit")
+	}
+
+	machineConfigClient, err := machineconfigclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	startPoolMonitoring(ctx, recorder, machineConfigClient)
+	startNodeMonitoring(ctx, recorder, kubeClient)
+	return nil
+}
+
+func (w *machineConfigRolloutWatcher) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return w.notSupportedReason
+}
+
+func (w *machineConfigRolloutWatcher) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	// the node and pool watches record their changes as they happen, the phases are built from them later
+	return nil, nil, w.notSupportedReason
+}
+
+func (w *machineConfigRolloutWatcher) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	constructedIntervals := monitorapi.Intervals{}
+	constructedIntervals = append(constructedIntervals, nodePhaseIntervals(startingIntervals, end)...)
+	constructedIntervals = append(constructedIntervals, poolConditionIntervals(startingIntervals, end)...)
+	return constructedIntervals, nil
+}
+
+func (w *machineConfigRolloutWatcher) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	junits := []*junitapi.JUnitTestCase{}
+	junits = append(junits, drainDurationJUnits(finalIntervals, historicalDrainLimit(getDrainDurations(), w.jobType))...)
+	junits = append(junits, degradedPoolJUnits(finalIntervals)...)
+	return junits, nil
+}
+
+func (w *machineConfigRolloutWatcher) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *machineConfigRolloutWatcher) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
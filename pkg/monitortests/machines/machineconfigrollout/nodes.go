@@ -0,0 +1,133 @@
+package machineconfigrollout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	corev1 "k8s.io/api/core/v1"
+	informercorev1 "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Node annotations maintained by the machine-config-daemon and the machine-config-controller.
+const (
+	desiredConfigAnnotation    = "machineconfiguration.openshift.io/desiredConfig"
+	stateAnnotation            = "machineconfiguration.openshift.io/state"
+	reasonAnnotation           = "machineconfiguration.openshift.io/reason"
+	desiredDrainAnnotation     = "machineconfiguration.openshift.io/desiredDrain"
+	lastAppliedDrainAnnotation = "machineconfiguration.openshift.io/lastAppliedDrain"
+
+	// drainPrefix marks a desiredDrain requesting that the node be drained, as opposed to uncordoned.
+	drainPrefix = "drain-"
+
+	stateDone           = "Done"
+	stateDegraded       = "Degraded"
+	stateUnreconcilable = "Unreconcilable"
+)
+
+func startNodeMonitoring(ctx context.Context, m monitorapi.RecorderWriter, client kubernetes.Interface) {
+	nodeInformer := informercorev1.NewNodeInformer(client, time.Hour, nil)
+	nodeInformer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, obj interface{}) {
+				node, ok := obj.(*corev1.Node)
+				if !ok {
+					return
+				}
+				oldNode, ok := old.(*corev1.Node)
+				if !ok {
+					return
+				}
+				m.AddIntervals(nodeRolloutChanges(node, oldNode, time.Now())...)
+			},
+		},
+	)
+
+	go nodeInformer.Run(ctx.Done())
+}
+
+// nodeRolloutChanges returns a point in time interval for every step of a machine config rollout that the node
+// took between oldNode and node.
+func nodeRolloutChanges(node, oldNode *corev1.Node, now time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	desiredConfig := node.Annotations[desiredConfigAnnotation]
+	pool := poolFromRenderedConfig(desiredConfig)
+
+	message := func(reason monitorapi.IntervalReason, humanMessage string) *monitorapi.MessageBuilder {
+		mb := monitorapi.NewMessage().Reason(reason).
+			WithAnnotation(monitorapi.AnnotationConfig, desiredConfig).
+			HumanMessage(humanMessage)
+		if len(pool) > 0 {
+			mb = mb.WithAnnotation(monitorapi.AnnotationMachineConfigPool, pool)
+		}
+		return mb
+	}
+	record := func(level monitorapi.IntervalLevel, mb *monitorapi.MessageBuilder) {
+		intervals = append(intervals,
+			monitorapi.NewInterval(monitorapi.SourceMachineConfigRollout, level).
+				Locator(monitorapi.NewLocator().NodeFromName(node.Name)).
+				Message(mb).
+				Build(now, now))
+	}
+
+	oldState, state := oldNode.Annotations[stateAnnotation], node.Annotations[stateAnnotation]
+	if oldState != state {
+		level := monitorapi.Info
+		humanMessage := fmt.Sprintf("machine-config-daemon state changed from %q to %q", oldState, state)
+		reason := node.Annotations[reasonAnnotation]
+		if state == stateDegraded || state == stateUnreconcilable {
+			level = monitorapi.Warning
+		}
+		if state != stateDone && len(reason) > 0 {
+			humanMessage = fmt.Sprintf("%s: %s", humanMessage, reason)
+		}
+		record(level, message(monitorapi.MachineConfigDaemonStateChangedReason, humanMessage).
+			WithAnnotation(monitorapi.AnnotationState, state))
+
+		// the daemon reports a failed drain as a degraded state with the drain error as its reason
+		if state == stateDegraded && strings.Contains(strings.ToLower(reason), "drain") {
+			record(monitorapi.Error, message(monitorapi.NodeDrainFailedReason, reason))
+		}
+	}
+
+	if !oldNode.Spec.Unschedulable && node.Spec.Unschedulable {
+		record(monitorapi.Info, message(monitorapi.NodeCordonedReason, "node was cordoned"))
+	}
+	if oldNode.Spec.Unschedulable && !node.Spec.Unschedulable {
+		record(monitorapi.Info, message(monitorapi.NodeUncordonedReason, "node was uncordoned"))
+	}
+
+	desiredDrain := node.Annotations[desiredDrainAnnotation]
+	if desiredDrain != oldNode.Annotations[desiredDrainAnnotation] && strings.HasPrefix(desiredDrain, drainPrefix) {
+		record(monitorapi.Info, message(monitorapi.NodeDrainRequestedReason, "machine-config-daemon requested a drain"))
+	}
+	lastAppliedDrain := node.Annotations[lastAppliedDrainAnnotation]
+	if lastAppliedDrain != oldNode.Annotations[lastAppliedDrainAnnotation] &&
+		lastAppliedDrain == desiredDrain && strings.HasPrefix(lastAppliedDrain, drainPrefix) {
+		record(monitorapi.Info, message(monitorapi.NodeDrainCompletedReason, "drain completed"))
+	}
+
+	oldBootID, bootID := oldNode.Status.NodeInfo.BootID, node.Status.NodeInfo.BootID
+	if len(oldBootID) > 0 && len(bootID) > 0 && oldBootID != bootID {
+		record(monitorapi.Info, message(monitorapi.NodeRebootedReason, "node rebooted"))
+	}
+
+	return intervals
+}
+
+// poolFromRenderedConfig returns the pool a rendered config like rendered-worker-0123456789abcdef was rendered for.
+func poolFromRenderedConfig(config string) string {
+	pool := strings.TrimPrefix(config, "rendered-")
+	if pool == config {
+		return ""
+	}
+	if i := strings.LastIndex(pool, "-"); i > 0 {
+		return pool[:i]
+	}
+	return ""
+}
@@ -0,0 +1,127 @@
+package machineconfigrollout
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary"
+
+	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
+	machineconfigclient "github.com/openshift/client-go/machineconfiguration/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/cache"
+)
+
+// watchedPoolConditions are the pool conditions charted as intervals.
+var watchedPoolConditions = []mcfgv1.MachineConfigPoolConditionType{
+	mcfgv1.MachineConfigPoolUpdating,
+	mcfgv1.MachineConfigPoolDegraded,
+}
+
+func startPoolMonitoring(ctx context.Context, m monitorapi.RecorderWriter, client machineconfigclient.Interface) {
+	listWatch := cache.NewListWatchFromClient(client.MachineconfigurationV1().RESTClient(), "machineconfigpools", "", fields.Everything())
+	customStore := monitortestlibrary.NewMonitoringStore(
+		"machineconfigpools",
+		[]monitortestlibrary.ObjCreateFunc{
+			func(obj interface{}) []monitorapi.Interval {
+				return poolConditionChanges(obj.(*mcfgv1.MachineConfigPool), nil, time.Now())
+			},
+			func(obj interface{}) []monitorapi.Interval {
+				return poolMachineCountChanges(obj.(*mcfgv1.MachineConfigPool), nil, time.Now())
+			},
+		},
+		[]monitortestlibrary.ObjUpdateFunc{
+			func(obj, oldObj interface{}) []monitorapi.Interval {
+				return poolConditionChanges(obj.(*mcfgv1.MachineConfigPool), oldObj.(*mcfgv1.MachineConfigPool), time.Now())
+			},
+			func(obj, oldObj interface{}) []monitorapi.Interval {
+				return poolMachineCountChanges(obj.(*mcfgv1.MachineConfigPool), oldObj.(*mcfgv1.MachineConfigPool), time.Now())
+			},
+		},
+		nil,
+		m,
+		m,
+	)
+	reflector := cache.NewReflector(listWatch, &mcfgv1.MachineConfigPool{}, customStore, 0)
+	go reflector.Run(ctx.Done())
+}
+
+// poolConditionChanges returns a point in time interval for every watched condition of the pool that changed status.
+// When oldPool is nil the pool was observed for the first time, and only the conditions that are already True are
+// returned, without a previous status.
+func poolConditionChanges(pool, oldPool *mcfgv1.MachineConfigPool, now time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	for _, conditionType := range watchedPoolConditions {
+		condition := findPoolCondition(pool.Status.Conditions, conditionType)
+		if condition == nil {
+			continue
+		}
+		var previous *mcfgv1.MachineConfigPoolCondition
+		if oldPool != nil {
+			previous = findPoolCondition(oldPool.Status.Conditions, conditionType)
+			if previous != nil && previous.Status == condition.Status {
+				continue
+			}
+		} else if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		level := monitorapi.Info
+		if conditionType == mcfgv1.MachineConfigPoolDegraded && condition.Status == corev1.ConditionTrue {
+			level = monitorapi.Error
+		}
+		humanMessage := fmt.Sprintf("%s=%s", conditionType, condition.Status)
+		if len(condition.Reason) > 0 {
+			humanMessage = fmt.Sprintf("%s %s", humanMessage, condition.Reason)
+		}
+		if len(condition.Message) > 0 {
+			humanMessage = fmt.Sprintf("%s: %s", humanMessage, condition.Message)
+		}
+		mb := monitorapi.NewMessage().Reason(monitorapi.MachineConfigPoolConditionChangedReason).
+			WithAnnotation(monitorapi.AnnotationCondition, string(conditionType)).
+			WithAnnotation(monitorapi.AnnotationStatus, string(condition.Status)).
+			HumanMessage(humanMessage)
+		if oldPool != nil {
+			previousStatus := corev1.ConditionUnknown
+			if previous != nil {
+				previousStatus = previous.Status
+			}
+			mb = mb.WithAnnotation(monitorapi.AnnotationPreviousStatus, string(previousStatus))
+		}
+		intervals = append(intervals,
+			monitorapi.NewInterval(monitorapi.SourceMachineConfigRollout, level).
+				Locator(monitorapi.NewLocator().MachineConfigPool(pool.Name)).
+				Message(mb).
+				Build(now, now))
+	}
+	return intervals
+}
+
+// poolMachineCountChanges returns a point in time interval with the number of machines in the pool when the pool is
+// first observed and whenever that number changes.
+func poolMachineCountChanges(pool, oldPool *mcfgv1.MachineConfigPool, now time.Time) monitorapi.Intervals {
+	if oldPool != nil && oldPool.Status.MachineCount == pool.Status.MachineCount {
+		return nil
+	}
+	return monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceMachineConfigRollout, monitorapi.Info).
+			Locator(monitorapi.NewLocator().MachineConfigPool(pool.Name)).
+			Message(monitorapi.NewMessage().Reason(monitorapi.MachineConfigPoolMachineCountChangedReason).
+				WithAnnotation(monitorapi.AnnotationCount, strconv.Itoa(int(pool.Status.MachineCount))).
+				HumanMessage(fmt.Sprintf("pool has %d machines", pool.Status.MachineCount))).
+			Build(now, now),
+	}
+}
+
+func findPoolCondition(conditions []mcfgv1.MachineConfigPoolCondition, conditionType mcfgv1.MachineConfigPoolConditionType) *mcfgv1.MachineConfigPoolCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
@@ -129,7 +129,15 @@ func (w *availability) EvaluateTestsFromConstructedIntervals(ctx context.Context
 }
 
 func (w *availability) WriteContentToStorage(ctx context.Context, storageDir string, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return w.notSupportedReason
+	if w.notSupportedReason != nil {
+		return w.notSupportedReason
+	}
+	// we failed and indicated it during setup.
+	if w.disruptionChecker == nil {
+		return nil
+	}
+
+	return w.disruptionChecker.WriteContentToStorage(ctx, storageDir, timeSuffix)
 }
 
 func (w *availability) Cleanup(ctx context.Context) error {
@@ -164,8 +164,20 @@ func (w *availability) EvaluateTestsFromConstructedIntervals(ctx context.Context
 	return junits, utilerrors.NewAggregate(errs)
 }
 
-func (*availability) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return nil
+func (w *availability) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	errs := []error{}
+	for i := range w.disruptionCheckers {
+		// we failed and indicated it during setup.
+		if w.disruptionCheckers[i] == nil {
+			continue
+		}
+
+		if err := w.disruptionCheckers[i].WriteContentToStorage(ctx, storageDir, timeSuffix); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
 }
 
 func (w *availability) Cleanup(ctx context.Context) error {
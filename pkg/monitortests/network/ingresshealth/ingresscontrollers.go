@@ -0,0 +1,175 @@
+package ingresshealth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorclient "github.com/openshift/client-go/operator/clientset/versioned"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/cache"
+)
+
+const ingressOperatorNamespace = "openshift-ingress-operator"
+
+// watchedIngressControllerConditions are the IngressController conditions charted as intervals.
+var watchedIngressControllerConditions = []string{
+	operatorv1.OperatorStatusTypeDegraded,
+	operatorv1.OperatorStatusTypeProgressing,
+}
+
+func startIngressControllerMonitoring(ctx context.Context, m monitorapi.RecorderWriter, client operatorclient.Interface) {
+	listWatch := cache.NewListWatchFromClient(client.OperatorV1().RESTClient(), "ingresscontrollers", ingressOperatorNamespace, fields.Everything())
+	customStore := monitortestlibrary.NewMonitoringStore(
+		"ingresscontrollers",
+		[]monitortestlibrary.ObjCreateFunc{
+			func(obj interface{}) []monitorapi.Interval {
+				return ingressControllerConditionChanges(obj.(*operatorv1.IngressController), nil, time.Now())
+			},
+		},
+		[]monitortestlibrary.ObjUpdateFunc{
+			func(obj, oldObj interface{}) []monitorapi.Interval {
+				return ingressControllerConditionChanges(obj.(*operatorv1.IngressController), oldObj.(*operatorv1.IngressController), time.Now())
+			},
+		},
+		nil,
+		m,
+		m,
+	)
+	reflector := cache.NewReflector(listWatch, &operatorv1.IngressController{}, customStore, 0)
+	go reflector.Run(ctx.Done())
+}
+
+// ingressControllerConditionChanges returns a point in time interval for every watched condition of the
+// IngressController that changed status. When oldIngressController is nil it was observed for the first time, and
+// only the conditions that are already True are returned, without a previous status.
+func ingressControllerConditionChanges(ingressController, oldIngressController *operatorv1.IngressController, now time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	for _, conditionType := range watchedIngressControllerConditions {
+		condition := findOperatorCondition(ingressController.Status.Conditions, conditionType)
+		if condition == nil {
+			continue
+		}
+		var previous *operatorv1.OperatorCondition
+		if oldIngressController != nil {
+			previous = findOperatorCondition(oldIngressController.Status.Conditions, conditionType)
+			if previous != nil && previous.Status == condition.Status {
+				continue
+			}
+		} else if condition.Status != operatorv1.ConditionTrue {
+			continue
+		}
+
+		level := monitorapi.Info
+		if conditionType == operatorv1.OperatorStatusTypeDegraded && condition.Status == operatorv1.ConditionTrue {
+			level = monitorapi.Error
+		}
+		humanMessage := fmt.Sprintf("%s=%s", conditionType, condition.Status)
+		if len(condition.Reason) > 0 {
+			humanMessage = fmt.Sprintf("%s %s", humanMessage, condition.Reason)
+		}
+		if len(condition.Message) > 0 {
+			humanMessage = fmt.Sprintf("%s: %s", humanMessage, condition.Message)
+		}
+		mb := monitorapi.NewMessage().Reason(monitorapi.IngressControllerConditionChangedReason).
+			WithAnnotation(monitorapi.AnnotationCondition, conditionType).
+			WithAnnotation(monitorapi.AnnotationStatus, string(condition.Status)).
+			HumanMessage(humanMessage)
+		if oldIngressController != nil {
+			previousStatus := operatorv1.ConditionUnknown
+			if previous != nil {
+				previousStatus = previous.Status
+			}
+			mb = mb.WithAnnotation(monitorapi.AnnotationPreviousStatus, string(previousStatus))
+		}
+		intervals = append(intervals,
+			monitorapi.NewInterval(monitorapi.SourceIngressMonitor, level).
+				Locator(monitorapi.NewLocator().IngressController(ingressController.Name)).
+				Message(mb).
+				Build(now, now))
+	}
+	return intervals
+}
+
+func findOperatorCondition(conditions []operatorv1.OperatorCondition, conditionType string) *operatorv1.OperatorCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// ingressControllerConditionIntervals constructs a Degraded or Progressing interval for every period an
+// IngressController reported the condition as True, up to end for an IngressController that never recovered.
+func ingressControllerConditionIntervals(startingIntervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	changes := startingIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceIngressMonitor &&
+			eventInterval.Message.Reason == monitorapi.IngressControllerConditionChangedReason
+	})
+
+	type controllerCondition struct {
+		ingressController string
+		condition         string
+	}
+	opened := map[controllerCondition]monitorapi.Interval{}
+	ret := monitorapi.Intervals{}
+	closeCondition := func(key controllerCondition, to time.Time) {
+		from, ok := opened[key]
+		if !ok {
+			return
+		}
+		delete(opened, key)
+
+		reason := monitorapi.IngressControllerProgressingReason
+		level := monitorapi.Warning
+		if key.condition == operatorv1.OperatorStatusTypeDegraded {
+			reason = monitorapi.IngressControllerDegradedReason
+			level = monitorapi.Error
+		}
+		mb := monitorapi.NewMessage().Reason(reason).
+			Constructed(monitorapi.ConstructionOwnerIngressHealth).
+			HumanMessage(from.Message.HumanMessage)
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceIngressMonitor, level).
+				Locator(from.Locator).
+				Message(mb).
+				Display().
+				Build(from.From, to),
+		)
+	}
+
+	for _, change := range changes {
+		key := controllerCondition{
+			ingressController: change.Locator.Keys[monitorapi.LocatorIngressControllerKey],
+			condition:         change.Message.Annotations[monitorapi.AnnotationCondition],
+		}
+		if change.Message.Annotations[monitorapi.AnnotationStatus] == string(operatorv1.ConditionTrue) {
+			if _, ok := opened[key]; !ok {
+				opened[key] = change
+			}
+			continue
+		}
+		closeCondition(key, change.From)
+	}
+
+	keys := make([]controllerCondition, 0, len(opened))
+	for key := range opened {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].ingressController != keys[j].ingressController {
+			return keys[i].ingressController < keys[j].ingressController
+		}
+		return keys[i].condition < keys[j].condition
+	})
+	for _, key := range keys {
+		closeCondition(key, end)
+	}
+	return ret
+}
@@ -0,0 +1,286 @@
+package ingresshealth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func newIngressController(conditions ...operatorv1.OperatorCondition) *operatorv1.IngressController {
+	return &operatorv1.IngressController{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ingressOperatorNamespace, Name: "default"},
+		Status:     operatorv1.IngressControllerStatus{Conditions: conditions},
+	}
+}
+
+func TestIngressControllerConditionIntervals(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := from.Add(time.Hour)
+	settled := newIngressController(
+		operatorv1.OperatorCondition{Type: operatorv1.OperatorStatusTypeDegraded, Status: operatorv1.ConditionFalse},
+		operatorv1.OperatorCondition{Type: operatorv1.OperatorStatusTypeProgressing, Status: operatorv1.ConditionFalse},
+	)
+	progressing := newIngressController(
+		operatorv1.OperatorCondition{Type: operatorv1.OperatorStatusTypeDegraded, Status: operatorv1.ConditionFalse},
+		operatorv1.OperatorCondition{Type: operatorv1.OperatorStatusTypeProgressing, Status: operatorv1.ConditionTrue, Reason: "IngressControllerProgressing"},
+	)
+	degraded := newIngressController(
+		operatorv1.OperatorCondition{Type: operatorv1.OperatorStatusTypeDegraded, Status: operatorv1.ConditionTrue, Reason: "DeploymentUnavailable"},
+		operatorv1.OperatorCondition{Type: operatorv1.OperatorStatusTypeProgressing, Status: operatorv1.ConditionTrue, Reason: "IngressControllerProgressing"},
+	)
+
+	if changes := ingressControllerConditionChanges(settled, nil, from); len(changes) != 0 {
+		t.Errorf("expected no changes for a settled ingresscontroller, got %d", len(changes))
+	}
+	if changes := ingressControllerConditionChanges(progressing, progressing, from); len(changes) != 0 {
+		t.Errorf("expected no changes when the conditions did not change, got %d", len(changes))
+	}
+
+	intervals := monitorapi.Intervals{}
+	intervals = append(intervals, ingressControllerConditionChanges(settled, nil, from)...)
+	intervals = append(intervals, ingressControllerConditionChanges(progressing, settled, from.Add(time.Minute))...)
+	intervals = append(intervals, ingressControllerConditionChanges(degraded, progressing, from.Add(2*time.Minute))...)
+	intervals = append(intervals, ingressControllerConditionChanges(progressing, degraded, from.Add(5*time.Minute))...)
+	intervals = append(intervals, ingressControllerConditionChanges(settled, progressing, from.Add(8*time.Minute))...)
+	intervals = append(intervals, ingressControllerConditionChanges(degraded, settled, from.Add(50*time.Minute))...)
+
+	constructed := ingressControllerConditionIntervals(intervals, end)
+	expected := []struct {
+		reason   monitorapi.IntervalReason
+		level    monitorapi.IntervalLevel
+		from, to time.Time
+	}{
+		{monitorapi.IngressControllerDegradedReason, monitorapi.Error, from.Add(2 * time.Minute), from.Add(5 * time.Minute)},
+		{monitorapi.IngressControllerProgressingReason, monitorapi.Warning, from.Add(time.Minute), from.Add(8 * time.Minute)},
+		{monitorapi.IngressControllerDegradedReason, monitorapi.Error, from.Add(50 * time.Minute), end},
+		{monitorapi.IngressControllerProgressingReason, monitorapi.Warning, from.Add(50 * time.Minute), end},
+	}
+	if len(constructed) != len(expected) {
+		t.Fatalf("expected %d intervals, got %d: %v", len(expected), len(constructed), constructed)
+	}
+	for i, e := range expected {
+		actual := constructed[i]
+		if actual.Message.Reason != e.reason || actual.Level != e.level || !actual.From.Equal(e.from) || !actual.To.Equal(e.to) {
+			t.Errorf("interval %d: expected %s %s from %s to %s, got %s", i, e.level, e.reason, e.from, e.to, actual.String())
+		}
+		if actual.Locator.Keys[monitorapi.LocatorIngressControllerKey] != "default" {
+			t.Errorf("interval %d: expected ingresscontroller/default, got %s", i, actual.Locator.OldLocator())
+		}
+	}
+}
+
+func newRouterDeployment(generation int64, updated, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  routerNamespace,
+			Name:       "router-default",
+			Generation: generation,
+			Labels:     map[string]string{owningIngressControllerLabel: "default"},
+		},
+		Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: generation,
+			Replicas:           2,
+			UpdatedReplicas:    updated,
+			AvailableReplicas:  available,
+		},
+	}
+}
+
+func TestRouterRolloutIntervals(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := from.Add(time.Hour)
+	rolledOut := newRouterDeployment(1, 2, 2)
+	rollingOut := newRouterDeployment(2, 1, 2)
+	rolledOutAgain := newRouterDeployment(2, 2, 2)
+
+	unowned := rollingOut.DeepCopy()
+	unowned.Labels = nil
+	if changes := routerRolloutChanges(unowned, nil, from); len(changes) != 0 {
+		t.Errorf("expected no changes for a deployment that is not a router, got %d", len(changes))
+	}
+	if changes := routerRolloutChanges(rolledOut, nil, from); len(changes) != 0 {
+		t.Errorf("expected no changes for a router that was not rolling out, got %d", len(changes))
+	}
+
+	intervals := monitorapi.Intervals{}
+	intervals = append(intervals, routerRolloutChanges(rollingOut, rolledOut, from.Add(time.Minute))...)
+	intervals = append(intervals, routerRolloutChanges(rollingOut, rollingOut, from.Add(2*time.Minute))...)
+	intervals = append(intervals, routerRolloutChanges(rolledOutAgain, rollingOut, from.Add(4*time.Minute))...)
+	intervals = append(intervals, routerRolloutChanges(newRouterDeployment(3, 0, 2), rolledOutAgain, from.Add(40*time.Minute))...)
+
+	constructed := routerRolloutIntervals(intervals, end)
+	if len(constructed) != 2 {
+		t.Fatalf("expected 2 rollouts, got %d: %v", len(constructed), constructed)
+	}
+	if completed := constructed[0]; completed.Level != monitorapi.Info || !completed.From.Equal(from.Add(time.Minute)) || !completed.To.Equal(from.Add(4*time.Minute)) {
+		t.Errorf("expected a completed rollout from 1m to 4m, got %s", completed.String())
+	}
+	if unfinished := constructed[1]; unfinished.Level != monitorapi.Warning || !unfinished.From.Equal(from.Add(40*time.Minute)) || !unfinished.To.Equal(end) {
+		t.Errorf("expected an unfinished rollout from 40m to the end, got %s", unfinished.String())
+	}
+	for _, rollout := range constructed {
+		if rollout.Message.Reason != monitorapi.RouterRolloutReason || rollout.Locator.Keys[monitorapi.LocatorDeploymentKey] != "router-default" {
+			t.Errorf("expected a rollout of deployment/router-default, got %s", rollout.String())
+		}
+	}
+}
+
+func newRoute(name string, created time.Time) *routev1.Route {
+	return &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "e2e-test", Name: name, CreationTimestamp: metav1.Time{Time: created}},
+	}
+}
+
+func admittedBy(route *routev1.Route, routerName string, status corev1.ConditionStatus) *routev1.Route {
+	ret := route.DeepCopy()
+	ret.Status.Ingress = append(ret.Status.Ingress, routev1.RouteIngress{
+		RouterName: routerName,
+		Conditions: []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: status}},
+	})
+	return ret
+}
+
+// routeLifecycle records the route being created at from, and admitted by the default router after admitAfter if it
+// is set.
+func routeLifecycle(name string, from time.Time, admitAfter time.Duration) monitorapi.Intervals {
+	created := newRoute(name, from)
+	intervals := routeAdmissionChanges(created, nil, from.Add(time.Second))
+	if admitAfter == 0 {
+		return intervals
+	}
+	return append(intervals, routeAdmissionChanges(admittedBy(created, "default", corev1.ConditionTrue), created, from.Add(admitAfter))...)
+}
+
+func TestRouteAdmissionIntervals(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := from.Add(time.Hour)
+
+	created := newRoute("sharded", from)
+	admittedByDefault := admittedBy(created, "default", corev1.ConditionTrue)
+	rejectedBySharded := admittedBy(admittedByDefault, "sharded", corev1.ConditionFalse)
+	deleted := monitorapi.NewInterval(monitorapi.SourceIngressMonitor, monitorapi.Info).
+		Locator(monitorapi.NewLocator().Route("e2e-test", "short-lived")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.RouteDeletedReason)).
+		Build(from.Add(20*time.Second), from.Add(20*time.Second))
+
+	intervals := monitorapi.Intervals{}
+	intervals = append(intervals, routeAdmissionChanges(created, nil, from.Add(time.Second))...)
+	intervals = append(intervals, routeAdmissionChanges(admittedByDefault, created, from.Add(3*time.Second))...)
+	intervals = append(intervals, routeAdmissionChanges(rejectedBySharded, admittedByDefault, from.Add(5*time.Second))...)
+	intervals = append(intervals, routeAdmissionChanges(rejectedBySharded, rejectedBySharded, from.Add(6*time.Second))...)
+	intervals = append(intervals, routeLifecycle("short-lived", from.Add(10*time.Second), 0)...)
+	intervals = append(intervals, deleted)
+	intervals = append(intervals, routeLifecycle("ignored", from.Add(50*time.Minute), 0)...)
+	intervals = append(intervals, routeLifecycle("unselected", from.Add(50*time.Minute), 0)...)
+
+	selected := func(namespace, name string) bool {
+		return name != "unselected"
+	}
+	constructed := routeAdmissionIntervals(intervals, selected, end)
+	expected := []struct {
+		route, status, router string
+		to                    time.Time
+	}{
+		{"sharded", admissionAdmitted, "default", from.Add(3 * time.Second)},
+		{"sharded", admissionRejected, "sharded", from.Add(5 * time.Second)},
+		{"short-lived", admissionDeleted, "", from.Add(20 * time.Second)},
+		{"ignored", admissionPending, "", end},
+		{"unselected", admissionUnselected, "", end},
+	}
+	if len(constructed) != len(expected) {
+		t.Fatalf("expected %d intervals, got %d: %v", len(expected), len(constructed), constructed)
+	}
+	for i, e := range expected {
+		actual := constructed[i]
+		if actual.Locator.Keys[monitorapi.LocatorRouteKey] != e.route ||
+			actual.Message.Annotations[monitorapi.AnnotationStatus] != e.status ||
+			actual.Message.Annotations[monitorapi.AnnotationRouter] != e.router ||
+			!actual.To.Equal(e.to) {
+			t.Errorf("interval %d: expected route/%s %s by %q until %s, got %s", i, e.route, e.status, e.router, e.to, actual.String())
+		}
+	}
+}
+
+func TestSelectedByIngressControllers(t *testing.T) {
+	shard := func(name string, namespaceSelector, routeSelector *metav1.LabelSelector) *operatorv1.IngressController {
+		return &operatorv1.IngressController{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ingressOperatorNamespace, Name: name},
+			Spec:       operatorv1.IngressControllerSpec{NamespaceSelector: namespaceSelector, RouteSelector: routeSelector},
+		}
+	}
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	route := func(namespace, name string, labels map[string]string) *routev1.Route {
+		return &routev1.Route{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+	}
+	instances := func(objs ...runtime.Object) monitorapi.InstanceMap {
+		ret := monitorapi.InstanceMap{}
+		for _, obj := range objs {
+			accessor, _ := meta.Accessor(obj)
+			ret[monitorapi.InstanceKey{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}] = obj
+		}
+		return ret
+	}
+	byLabel := func(key, value string) *metav1.LabelSelector {
+		return &metav1.LabelSelector{MatchLabels: map[string]string{key: value}}
+	}
+
+	namespaces := instances(namespace("e2e-test", nil), namespace("e2e-shard", map[string]string{"type": "sharded"}))
+	routes := instances(
+		route("e2e-test", "plain", nil),
+		route("e2e-test", "labeled", map[string]string{"type": "sharded"}),
+		route("e2e-shard", "plain", nil),
+	)
+
+	tests := []struct {
+		name               string
+		ingressControllers monitorapi.InstanceMap
+		selected           []string
+	}{
+		{
+			name:     "no ingresscontrollers recorded",
+			selected: []string{"e2e-test/plain", "e2e-test/labeled", "e2e-shard/plain", "e2e-test/unrecorded"},
+		},
+		{
+			name:               "default ingresscontroller selects everything",
+			ingressControllers: instances(shard("default", nil, nil)),
+			selected:           []string{"e2e-test/plain", "e2e-test/labeled", "e2e-shard/plain", "e2e-test/unrecorded"},
+		},
+		{
+			name:               "route sharding",
+			ingressControllers: instances(shard("sharded", nil, byLabel("type", "sharded"))),
+			selected:           []string{"e2e-test/labeled"},
+		},
+		{
+			name:               "namespace sharding",
+			ingressControllers: instances(shard("sharded", byLabel("type", "sharded"), nil)),
+			selected:           []string{"e2e-shard/plain"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected := selectedByIngressControllers(tt.ingressControllers, namespaces, routes)
+			for _, key := range []string{"e2e-test/plain", "e2e-test/labeled", "e2e-shard/plain", "e2e-test/unrecorded"} {
+				parts := strings.SplitN(key, "/", 2)
+				expected := sets.New(tt.selected...).Has(key)
+				if actual := selected(parts[0], parts[1]); actual != expected {
+					t.Errorf("route %s: expected selected %v, got %v", key, expected, actual)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package ingresshealth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/allowedbackenddisruption"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/monitortestlibrary/utility"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	routeAdmissionTestName        = "[sig-network-edge] routes should be admitted by the router promptly"
+	routerChurnDisruptionTestName = "[sig-network-edge] router rollouts and reloads should not disrupt ingress"
+
+	// routeAdmissionLimit is how long a router may take to admit a new route. The router syncs route changes within a
+	// few seconds, even on a busy cluster the haproxy reload that follows rarely takes more than a few more. It is an
+	// estimate rather than a measurement, so slow admissions only flake until there is historical data to judge them.
+	routeAdmissionLimit = 30 * time.Second
+
+	// routerReloadWindow is how long before a logged reload its disruption is attributed to it.
+	routerReloadWindow = 10 * time.Second
+)
+
+// routeAdmissionJUnits flakes when a route took longer than routeAdmissionLimit to be admitted, or was never admitted
+// and existed for longer than that. Rejections are the router's answer and are not counted, neither are routes no
+// IngressController selects. No junit is returned when no route was created during the run.
+func routeAdmissionJUnits(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	admissions := finalIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceIngressMonitor &&
+			eventInterval.Message.Reason == monitorapi.RouteAdmissionReason
+	})
+	if len(admissions) == 0 {
+		return nil
+	}
+
+	var failures []string
+	for _, admission := range admissions {
+		outcome := admission.Message.Annotations[monitorapi.AnnotationStatus]
+		if outcome != admissionAdmitted && outcome != admissionPending {
+			continue
+		}
+		duration := admission.To.Sub(admission.From)
+		if duration <= routeAdmissionLimit {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("route/%s -n %s waited %s for admission, longer than %s: %s",
+			admission.Locator.Keys[monitorapi.LocatorRouteKey], admission.Locator.Keys[monitorapi.LocatorNamespaceKey],
+			duration.Round(time.Second), routeAdmissionLimit, admission.String()))
+	}
+	if len(failures) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: routeAdmissionTestName}}
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: routeAdmissionTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: strings.Join(failures, "\n"),
+			},
+			SystemOut: strings.Join(failures, "\n"),
+		},
+		// Mark the test as a flake
+		{Name: routeAdmissionTestName},
+	}
+}
+
+// allowedDisruptionFunc returns the disruption allowed for the backend over a whole run, false when there is no
+// historical data for it.
+type allowedDisruptionFunc func(backend string) (time.Duration, bool)
+
+func historicalAllowedDisruption(jobType *platformidentification.JobType) allowedDisruptionFunc {
+	return func(backend string) (time.Duration, bool) {
+		if jobType == nil {
+			return 0, false
+		}
+		allowed, _, err := allowedbackenddisruption.GetAllowedDisruption(backend, *jobType)
+		if err != nil || allowed == nil {
+			return 0, false
+		}
+		return *allowed, true
+	}
+}
+
+// routerChurnDisruptionJUnits fails when a backend reached through a route was disrupted while router pods were
+// rolling out or haproxy was reloading for longer than the backend is allowed to be disrupted over a whole run. Both
+// are supposed to be invisible to clients, but the old pods and processes only drain for so long. Backends without
+// historical data are not judged. No junit is returned when no router rolled out or reloaded.
+func routerChurnDisruptionJUnits(finalIntervals monitorapi.Intervals, allowed allowedDisruptionFunc) []*junitapi.JUnitTestCase {
+	var churn monitorapi.Intervals
+	for _, eventInterval := range finalIntervals {
+		if eventInterval.Source != monitorapi.SourceIngressMonitor {
+			continue
+		}
+		switch eventInterval.Message.Reason {
+		case monitorapi.RouterRolloutReason:
+			churn = append(churn, eventInterval)
+		case monitorapi.RouterReloadedReason:
+			// the reload is logged once the new haproxy runs, the connections refused while it started came just before
+			eventInterval.From = eventInterval.From.Add(-routerReloadWindow)
+			churn = append(churn, eventInterval)
+		}
+	}
+	if len(churn) == 0 {
+		return nil
+	}
+	disruptions := finalIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		_, throughRoute := eventInterval.Locator.Keys[monitorapi.LocatorRouteKey]
+		return eventInterval.Source == monitorapi.SourceDisruption &&
+			eventInterval.Message.Reason == monitorapi.DisruptionBeganEventReason &&
+			throughRoute
+	})
+
+	disrupted := map[string]time.Duration{}
+	examples := map[string][]string{}
+	var backends []string
+	for _, disruption := range disruptions {
+		var during *monitorapi.Interval
+		for i := range churn {
+			if utility.IntervalsOverlap(churn[i], disruption) {
+				during = &churn[i]
+				break
+			}
+		}
+		if during == nil {
+			continue
+		}
+		backend := disruption.Locator.Keys[monitorapi.LocatorBackendDisruptionNameKey]
+		if _, ok := disrupted[backend]; !ok {
+			backends = append(backends, backend)
+		}
+		disrupted[backend] += disruption.To.Sub(disruption.From)
+		examples[backend] = append(examples[backend], fmt.Sprintf("%s: %s", during.Message.HumanMessage, disruption.String()))
+	}
+
+	var failures []string
+	for _, backend := range backends {
+		limit, ok := allowed(backend)
+		if !ok || disrupted[backend] <= limit {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("%s was disrupted for %s while routers rolled out or reloaded, more than the %s allowed:\n%s",
+			backend, disrupted[backend].Round(time.Second), limit, strings.Join(examples[backend], "\n")))
+	}
+	if len(failures) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: routerChurnDisruptionTestName}}
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: routerChurnDisruptionTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: strings.Join(failures, "\n"),
+			},
+			SystemOut: strings.Join(failures, "\n"),
+		},
+	}
+}
@@ -0,0 +1,160 @@
+package ingresshealth
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junittest"
+)
+
+func TestRouteAdmissionJUnits(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := from.Add(time.Hour)
+	rejected := newRoute("rejected", from)
+
+	tests := []struct {
+		name          string
+		intervals     monitorapi.Intervals
+		expectJUnit   bool
+		expectFailure string
+	}{
+		{
+			name: "no routes created",
+		},
+		{
+			name:        "route admitted promptly",
+			intervals:   routeLifecycle("app", from, 5*time.Second),
+			expectJUnit: true,
+		},
+		{
+			name: "route rejected",
+			intervals: append(routeAdmissionChanges(rejected, nil, from),
+				routeAdmissionChanges(admittedBy(rejected, "default", corev1.ConditionFalse), rejected, from.Add(5*time.Minute))...),
+			expectJUnit: true,
+		},
+		{
+			name:          "route admitted slowly",
+			intervals:     routeLifecycle("app", from, 2*time.Minute),
+			expectJUnit:   true,
+			expectFailure: "route/app -n e2e-test waited 2m0s for admission, longer than 30s",
+		},
+		{
+			name:          "route never admitted",
+			intervals:     routeLifecycle("app", from.Add(30*time.Minute), 0),
+			expectJUnit:   true,
+			expectFailure: "route/app -n e2e-test waited 30m0s for admission, longer than 30s",
+		},
+		{
+			name:        "route no ingresscontroller selects",
+			intervals:   routeLifecycle("unselected", from.Add(30*time.Minute), 0),
+			expectJUnit: true,
+		},
+	}
+	selected := func(namespace, name string) bool {
+		return name != "unselected"
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finalIntervals := append(tt.intervals, routeAdmissionIntervals(tt.intervals, selected, end)...)
+			count := 0
+			switch {
+			case len(tt.expectFailure) > 0:
+				// the failure is followed by a pass, slow admissions flake
+				count = 2
+			case tt.expectJUnit:
+				count = 1
+			}
+			junittest.ExpectJUnits(t, routeAdmissionJUnits(finalIntervals), routeAdmissionTestName, count, tt.expectFailure, true)
+		})
+	}
+}
+
+func TestRouterChurnDisruptionJUnits(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rollout := monitorapi.NewInterval(monitorapi.SourceIngressMonitor, monitorapi.Info).
+		Locator(monitorapi.NewLocator().DeploymentFromName(routerNamespace, "router-default")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.RouterRolloutReason).HumanMessage("router pods of ingresscontroller/default started rolling out")).
+		Build(from.Add(10*time.Minute), from.Add(15*time.Minute))
+	reload := monitorapi.NewInterval(monitorapi.SourceIngressMonitor, monitorapi.Info).
+		Locator(monitorapi.NewLocator().ContainerFromNames(routerNamespace, "router-default-1", "uid", routerContainer)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.RouterReloadedReason).HumanMessage("router reloaded haproxy")).
+		Build(from.Add(30*time.Minute), from.Add(30*time.Minute))
+	disruption := func(locator monitorapi.Locator, at time.Time) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+			Locator(locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason)).
+			Build(at, at.Add(4*time.Second))
+	}
+	throughRoute := monitorapi.NewLocator().LocateRouteForDisruptionCheck("ingress-to-console-new-connections", "openshift-tests",
+		"openshift-console", "console", monitorapi.NewConnectionType)
+	throughOtherRoute := monitorapi.NewLocator().LocateRouteForDisruptionCheck("ingress-to-oauth-server-new-connections", "openshift-tests",
+		"openshift-authentication", "oauth-openshift", monitorapi.NewConnectionType)
+	notThroughRoute := monitorapi.NewLocator().LocateDisruptionCheck("kube-api-new-connections", "openshift-tests", monitorapi.NewConnectionType)
+	allowed := func(backend string) (time.Duration, bool) {
+		if backend == "ingress-to-console-new-connections" {
+			return 5 * time.Second, true
+		}
+		return 0, false
+	}
+
+	tests := []struct {
+		name          string
+		intervals     monitorapi.Intervals
+		expectJUnit   bool
+		expectFailure string
+	}{
+		{
+			name:      "no rollouts or reloads",
+			intervals: monitorapi.Intervals{disruption(throughRoute, from.Add(12*time.Minute))},
+		},
+		{
+			name:        "route disrupted outside of the rollout",
+			intervals:   monitorapi.Intervals{rollout, disruption(throughRoute, from.Add(20*time.Minute))},
+			expectJUnit: true,
+		},
+		{
+			name:        "other backend disrupted during the rollout",
+			intervals:   monitorapi.Intervals{rollout, disruption(notThroughRoute, from.Add(12*time.Minute))},
+			expectJUnit: true,
+		},
+		{
+			name:        "route disrupted during the rollout within the allowed disruption",
+			intervals:   monitorapi.Intervals{rollout, disruption(throughRoute, from.Add(12*time.Minute))},
+			expectJUnit: true,
+		},
+		{
+			name: "route without allowed disruption disrupted during the rollout",
+			intervals: monitorapi.Intervals{rollout,
+				disruption(throughOtherRoute, from.Add(12*time.Minute)), disruption(throughOtherRoute, from.Add(13*time.Minute))},
+			expectJUnit: true,
+		},
+		{
+			name: "route disrupted during the rollout longer than allowed",
+			intervals: monitorapi.Intervals{rollout,
+				disruption(throughRoute, from.Add(12*time.Minute)), disruption(throughRoute, from.Add(13*time.Minute))},
+			expectJUnit:   true,
+			expectFailure: "ingress-to-console-new-connections was disrupted for 8s while routers rolled out or reloaded, more than the 5s allowed",
+		},
+		{
+			name: "route disrupted just before a reload longer than allowed",
+			intervals: monitorapi.Intervals{reload,
+				disruption(throughRoute, from.Add(30*time.Minute-5*time.Second)), disruption(throughRoute, from.Add(30*time.Minute-8*time.Second))},
+			expectJUnit:   true,
+			expectFailure: "ingress-to-console-new-connections was disrupted for 8s while routers rolled out or reloaded, more than the 5s allowed",
+		},
+		{
+			name: "route disrupted well before a reload",
+			intervals: monitorapi.Intervals{reload,
+				disruption(throughRoute, from.Add(29*time.Minute)), disruption(throughRoute, from.Add(29*time.Minute+10*time.Second))},
+			expectJUnit: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			junittest.ExpectSingleJUnit(t, routerChurnDisruptionJUnits(tt.intervals, allowed), routerChurnDisruptionTestName, tt.expectJUnit, tt.expectFailure)
+		})
+	}
+}
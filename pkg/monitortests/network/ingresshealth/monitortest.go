@@ -0,0 +1,113 @@
+package ingresshealth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	operatorclient "github.com/openshift/client-go/operator/clientset/versioned"
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+// ingressHealthWatcher charts the health of the ingress path: IngressController conditions, rollouts of the router
+// pods, haproxy reloads and how long routes wait to be admitted.
+type ingressHealthWatcher struct {
+	kubeClient         kubernetes.Interface
+	jobType            *platformidentification.JobType
+	notSupportedReason error
+}
+
+func NewIngressHealthWatcher() monitortestframework.MonitorTest {
+	return &ingressHealthWatcher{}
+}
+
+func (w *ingressHealthWatcher) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *ingressHealthWatcher) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	ingressAvailable, err := exutil.DoesApiResourceExist(adminRESTConfig, "ingresscontrollers", "operator.openshift.io")
+	if err != nil {
+		return err
+	}
+	if !ingressAvailable {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "cluster has no ingress operator"}
+		return w.notSupportedReason
+	}
+
+	operatorClient, err := operatorclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	routeClient, err := routeclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	startIngressControllerMonitoring(ctx, recorder, operatorClient)
+	startRouterMonitoring(ctx, recorder, w.kubeClient)
+	startRouteMonitoring(ctx, recorder, routeClient)
+
+	// without a job type there is no allowed disruption, rollouts and reloads are still charted
+	w.jobType, err = platformidentification.GetJobType(ctx, adminRESTConfig)
+	if err != nil {
+		logrus.WithError(err).Warn("unable to determine the job type, disruption during router rollouts and reloads will not be judged")
+	}
+	return nil
+}
+
+func (w *ingressHealthWatcher) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+
+	// the rest is streamed into the shared recorder, only the reloads have to be read from the router logs
+	localRecorder := monitor.NewRecorder()
+	if err := collectRouterReloads(ctx, w.kubeClient, localRecorder, beginning); err != nil {
+		return nil, nil, fmt.Errorf("unable to scan router logs: %w", err)
+	}
+	return localRecorder.Intervals(time.Time{}, time.Time{}), nil, nil
+}
+
+func (w *ingressHealthWatcher) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	constructedIntervals := monitorapi.Intervals{}
+	constructedIntervals = append(constructedIntervals, ingressControllerConditionIntervals(startingIntervals, end)...)
+	constructedIntervals = append(constructedIntervals, routerRolloutIntervals(startingIntervals, end)...)
+	constructedIntervals = append(constructedIntervals, routeAdmissionIntervals(startingIntervals,
+		selectedByIngressControllers(recordedResources["ingresscontrollers"], recordedResources["namespaces"], recordedResources["routes"]), end)...)
+	return constructedIntervals, nil
+}
+
+func (w *ingressHealthWatcher) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	junits := []*junitapi.JUnitTestCase{}
+	junits = append(junits, routeAdmissionJUnits(finalIntervals)...)
+	junits = append(junits, routerChurnDisruptionJUnits(finalIntervals, historicalAllowedDisruption(w.jobType))...)
+	return junits, nil
+}
+
+func (w *ingressHealthWatcher) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *ingressHealthWatcher) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
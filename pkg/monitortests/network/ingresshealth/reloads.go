@@ -0,0 +1,67 @@
+package ingresshealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/podaccess"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// routerPodLabel is set by the ingress operator on the router pods of every IngressController.
+	routerPodLabel = "ingresscontroller.operator.openshift.io/deployment-ingresscontroller"
+
+	routerContainer = "router"
+
+	// routerReloadedLog is logged by the router every time it reloads haproxy with a new configuration, which it does
+	// for most route and endpoint changes.
+	routerReloadedLog = "router reloaded"
+)
+
+// collectRouterReloads reads the log of every router pod and records a point in time interval for every haproxy
+// reload after beginning. The logs of router pods replaced during the run are gone, their reloads are missed.
+func collectRouterReloads(ctx context.Context, kubeClient kubernetes.Interface, recorder monitorapi.RecorderWriter, beginning time.Time) error {
+	pods, err := kubeClient.CoreV1().Pods(routerNamespace).List(ctx, metav1.ListOptions{LabelSelector: routerPodLabel})
+	if err != nil {
+		return fmt.Errorf("couldn't list router pods: %w", err)
+	}
+
+	errs := []error{}
+	for _, pod := range pods.Items {
+		// pending pods have not logged anything yet
+		if pod.Status.Phase == corev1.PodPending || pod.Status.Phase == corev1.PodUnknown {
+			continue
+		}
+		streamer := podaccess.NewOneTimePodStreamer(kubeClient, pod.Namespace, pod.Name, routerContainer, routerReloadHandler{recorder: recorder, afterTime: beginning})
+		if err := streamer.ReadLog(ctx); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("error reading log for pods/%s -n %s -c %s: %w", pod.Name, pod.Namespace, routerContainer, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type routerReloadHandler struct {
+	recorder  monitorapi.RecorderWriter
+	afterTime time.Time
+}
+
+func (h routerReloadHandler) HandleLogLine(logLine podaccess.LogLineContent) {
+	if logLine.Instant.Before(h.afterTime) || !strings.Contains(logLine.Line, routerReloadedLog) {
+		return
+	}
+	h.recorder.AddIntervals(
+		monitorapi.NewInterval(monitorapi.SourceIngressMonitor, monitorapi.Info).
+			Locator(logLine.Locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.RouterReloadedReason).HumanMessage("router reloaded haproxy")).
+			Build(logLine.Instant, logLine.Instant),
+	)
+}
@@ -0,0 +1,150 @@
+package ingresshealth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	appsv1 "k8s.io/api/apps/v1"
+	informerappsv1 "k8s.io/client-go/informers/apps/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	routerNamespace = "openshift-ingress"
+
+	// owningIngressControllerLabel is set by the ingress operator on the router deployment of every IngressController.
+	owningIngressControllerLabel = "ingresscontroller.operator.openshift.io/owning-ingresscontroller"
+)
+
+func startRouterMonitoring(ctx context.Context, m monitorapi.RecorderWriter, client kubernetes.Interface) {
+	deploymentInformer := informerappsv1.NewDeploymentInformer(client, routerNamespace, time.Hour, nil)
+	deploymentInformer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				deployment, ok := obj.(*appsv1.Deployment)
+				if !ok {
+					return
+				}
+				m.AddIntervals(routerRolloutChanges(deployment, nil, time.Now())...)
+			},
+			UpdateFunc: func(old, obj interface{}) {
+				deployment, ok := obj.(*appsv1.Deployment)
+				if !ok {
+					return
+				}
+				oldDeployment, ok := old.(*appsv1.Deployment)
+				if !ok {
+					return
+				}
+				m.AddIntervals(routerRolloutChanges(deployment, oldDeployment, time.Now())...)
+			},
+		},
+	)
+
+	go deploymentInformer.Run(ctx.Done())
+}
+
+// rolloutComplete tells whether every replica of the deployment runs the latest template and is available.
+func rolloutComplete(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	return status.ObservedGeneration >= deployment.Generation &&
+		status.UpdatedReplicas == replicas &&
+		status.Replicas == replicas &&
+		status.AvailableReplicas == replicas
+}
+
+// routerRolloutChanges returns a point in time interval when a router deployment starts or completes rolling out new
+// router pods. When oldDeployment is nil the deployment was observed for the first time, and an interval is only
+// returned if it is already rolling out.
+func routerRolloutChanges(deployment, oldDeployment *appsv1.Deployment, now time.Time) monitorapi.Intervals {
+	ingressController, ok := deployment.Labels[owningIngressControllerLabel]
+	if !ok {
+		return nil
+	}
+	complete := rolloutComplete(deployment)
+	if oldDeployment == nil && complete {
+		return nil
+	}
+	if oldDeployment != nil && rolloutComplete(oldDeployment) == complete {
+		return nil
+	}
+
+	mb := monitorapi.NewMessage().Reason(monitorapi.RouterRolloutStartedReason).
+		HumanMessagef("router pods of ingresscontroller/%s started rolling out", ingressController)
+	if complete {
+		mb = monitorapi.NewMessage().Reason(monitorapi.RouterRolloutCompletedReason).
+			HumanMessagef("router pods of ingresscontroller/%s rolled out", ingressController)
+	}
+	return monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceIngressMonitor, monitorapi.Info).
+			Locator(monitorapi.NewLocator().DeploymentFromName(deployment.Namespace, deployment.Name)).
+			Message(mb).
+			Build(now, now),
+	}
+}
+
+// routerRolloutIntervals constructs an interval for every rollout of a router deployment. Rollouts still in progress
+// at end are closed there.
+func routerRolloutIntervals(startingIntervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	changes := startingIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceIngressMonitor &&
+			(eventInterval.Message.Reason == monitorapi.RouterRolloutStartedReason ||
+				eventInterval.Message.Reason == monitorapi.RouterRolloutCompletedReason)
+	})
+
+	started := map[string]monitorapi.Interval{}
+	ret := monitorapi.Intervals{}
+	closeRollout := func(deployment string, to time.Time, completed bool) {
+		from, ok := started[deployment]
+		if !ok {
+			return
+		}
+		delete(started, deployment)
+
+		level := monitorapi.Info
+		humanMessage := from.Message.HumanMessage
+		if !completed {
+			level = monitorapi.Warning
+			humanMessage = fmt.Sprintf("%s, never completed", humanMessage)
+		}
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceIngressMonitor, level).
+				Locator(from.Locator).
+				Message(monitorapi.NewMessage().Reason(monitorapi.RouterRolloutReason).
+					Constructed(monitorapi.ConstructionOwnerIngressHealth).
+					HumanMessage(humanMessage)).
+				Display().
+				Build(from.From, to),
+		)
+	}
+
+	for _, change := range changes {
+		deployment := change.Locator.Keys[monitorapi.LocatorDeploymentKey]
+		if change.Message.Reason == monitorapi.RouterRolloutStartedReason {
+			if _, ok := started[deployment]; !ok {
+				started[deployment] = change
+			}
+			continue
+		}
+		closeRollout(deployment, change.From, true)
+	}
+
+	deployments := make([]string, 0, len(started))
+	for deployment := range started {
+		deployments = append(deployments, deployment)
+	}
+	sort.Strings(deployments)
+	for _, deployment := range deployments {
+		closeRollout(deployment, end, false)
+	}
+	return ret
+}
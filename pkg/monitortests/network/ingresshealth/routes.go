@@ -0,0 +1,291 @@
+package ingresshealth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// The outcomes of a route admission, recorded as the status of the RouteAdmission interval.
+const (
+	admissionAdmitted = "Admitted"
+	admissionRejected = "Rejected"
+	admissionDeleted  = "Deleted"
+	admissionPending  = "Pending"
+
+	// admissionUnselected is the outcome of a route no IngressController selects, no router is expected to admit it.
+	admissionUnselected = "Unselected"
+)
+
+func startRouteMonitoring(ctx context.Context, m monitorapi.RecorderWriter, client routeclient.Interface) {
+	// only the routes created while the monitor runs have an admission to measure
+	startTime := time.Now()
+	createdDuringRun := func(route *routev1.Route) bool {
+		return !route.CreationTimestamp.Time.Before(startTime.Truncate(time.Second))
+	}
+
+	listWatch := cache.NewListWatchFromClient(client.RouteV1().RESTClient(), "routes", "", fields.Everything())
+	customStore := monitortestlibrary.NewMonitoringStore(
+		"routes",
+		[]monitortestlibrary.ObjCreateFunc{
+			func(obj interface{}) []monitorapi.Interval {
+				route := obj.(*routev1.Route)
+				if !createdDuringRun(route) {
+					return nil
+				}
+				return routeAdmissionChanges(route, nil, time.Now())
+			},
+		},
+		[]monitortestlibrary.ObjUpdateFunc{
+			func(obj, oldObj interface{}) []monitorapi.Interval {
+				route := obj.(*routev1.Route)
+				if !createdDuringRun(route) {
+					return nil
+				}
+				return routeAdmissionChanges(route, oldObj.(*routev1.Route), time.Now())
+			},
+		},
+		[]monitortestlibrary.ObjDeleteFunc{
+			func(obj interface{}) []monitorapi.Interval {
+				route := obj.(*routev1.Route)
+				if !createdDuringRun(route) {
+					return nil
+				}
+				return monitorapi.Intervals{
+					monitorapi.NewInterval(monitorapi.SourceIngressMonitor, monitorapi.Info).
+						Locator(monitorapi.NewLocator().Route(route.Namespace, route.Name)).
+						Message(monitorapi.NewMessage().Reason(monitorapi.RouteDeletedReason).HumanMessage("route deleted")).
+						BuildNow(),
+				}
+			},
+		},
+		m,
+		m,
+	)
+	reflector := cache.NewReflector(listWatch, &routev1.Route{}, customStore, 0)
+	go reflector.Run(ctx.Done())
+}
+
+// routeAdmissionChanges returns a point in time interval when the route is created, and whenever a router admits or
+// rejects it. When oldRoute is nil the route was observed for the first time, its creation is recorded at its
+// creation timestamp since the watch may see it late.
+func routeAdmissionChanges(route, oldRoute *routev1.Route, now time.Time) monitorapi.Intervals {
+	locator := monitorapi.NewLocator().Route(route.Namespace, route.Name)
+	var intervals monitorapi.Intervals
+	if oldRoute == nil {
+		created := route.CreationTimestamp.Time
+		intervals = append(intervals,
+			monitorapi.NewInterval(monitorapi.SourceIngressMonitor, monitorapi.Info).
+				Locator(locator).
+				Message(monitorapi.NewMessage().Reason(monitorapi.RouteCreatedReason).HumanMessage("route created")).
+				Build(created, created))
+		oldRoute = &routev1.Route{}
+	}
+
+	for _, ingress := range route.Status.Ingress {
+		condition := findAdmittedCondition(ingress.Conditions)
+		if condition == nil || condition.Status == corev1.ConditionUnknown {
+			continue
+		}
+		if previous := findAdmittedCondition(routerConditions(oldRoute.Status.Ingress, ingress.RouterName)); previous != nil && previous.Status == condition.Status {
+			continue
+		}
+
+		level := monitorapi.Info
+		reason := monitorapi.RouteAdmittedReason
+		humanMessage := fmt.Sprintf("route admitted by router %s", ingress.RouterName)
+		if condition.Status == corev1.ConditionFalse {
+			level = monitorapi.Warning
+			reason = monitorapi.RouteRejectedReason
+			humanMessage = fmt.Sprintf("route rejected by router %s: %s %s", ingress.RouterName, condition.Reason, condition.Message)
+		}
+		intervals = append(intervals,
+			monitorapi.NewInterval(monitorapi.SourceIngressMonitor, level).
+				Locator(locator).
+				Message(monitorapi.NewMessage().Reason(reason).
+					WithAnnotation(monitorapi.AnnotationRouter, ingress.RouterName).
+					HumanMessage(humanMessage)).
+				Build(now, now))
+	}
+	return intervals
+}
+
+func routerConditions(ingresses []routev1.RouteIngress, routerName string) []routev1.RouteIngressCondition {
+	for _, ingress := range ingresses {
+		if ingress.RouterName == routerName {
+			return ingress.Conditions
+		}
+	}
+	return nil
+}
+
+func findAdmittedCondition(conditions []routev1.RouteIngressCondition) *routev1.RouteIngressCondition {
+	for i := range conditions {
+		if conditions[i].Type == routev1.RouteAdmitted {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// routeSelectedFunc tells whether any IngressController selects the route, which a router must then admit or reject.
+type routeSelectedFunc func(namespace, name string) bool
+
+// selectedByIngressControllers matches the recorded routes and their namespaces against the route and namespace
+// selectors of the recorded IngressControllers. A route or namespace that was not recorded is matched with no labels.
+// When no IngressController was recorded every route is considered selected.
+func selectedByIngressControllers(ingressControllers, namespaces, routes monitorapi.InstanceMap) routeSelectedFunc {
+	namespaceLabels := map[string]labels.Set{}
+	for _, obj := range namespaces {
+		if namespace, ok := obj.(*corev1.Namespace); ok {
+			namespaceLabels[namespace.Name] = namespace.Labels
+		}
+	}
+	routeLabels := map[string]labels.Set{}
+	for _, obj := range routes {
+		if route, ok := obj.(*routev1.Route); ok {
+			routeLabels[route.Namespace+"/"+route.Name] = route.Labels
+		}
+	}
+
+	type shard struct {
+		namespaceSelector labels.Selector
+		routeSelector     labels.Selector
+	}
+	var shards []shard
+	for _, obj := range ingressControllers {
+		ingressController, ok := obj.(*operatorv1.IngressController)
+		if !ok {
+			continue
+		}
+		namespaceSelector, err := labelSelectorAsSelector(ingressController.Spec.NamespaceSelector)
+		if err != nil {
+			continue
+		}
+		routeSelector, err := labelSelectorAsSelector(ingressController.Spec.RouteSelector)
+		if err != nil {
+			continue
+		}
+		shards = append(shards, shard{namespaceSelector: namespaceSelector, routeSelector: routeSelector})
+	}
+
+	return func(namespace, name string) bool {
+		if len(shards) == 0 {
+			return true
+		}
+		for _, shard := range shards {
+			if shard.namespaceSelector.Matches(namespaceLabels[namespace]) && shard.routeSelector.Matches(routeLabels[namespace+"/"+name]) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// labelSelectorAsSelector converts an IngressController selector, which selects everything when it is not set.
+func labelSelectorAsSelector(selector *metav1.LabelSelector) (labels.Selector, error) {
+	if selector == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+// routeAdmissionIntervals constructs an interval from the creation of every route until each router first admitted or
+// rejected it. A route no router responded to gets a single interval until it was deleted or until end, marked
+// unselected when no IngressController selects it.
+func routeAdmissionIntervals(startingIntervals monitorapi.Intervals, selected routeSelectedFunc, end time.Time) monitorapi.Intervals {
+	changes := startingIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceIngressMonitor &&
+			eventInterval.Locator.Type == monitorapi.LocatorTypeRoute
+	})
+
+	type routeState struct {
+		created   monitorapi.Interval
+		responded map[string]bool
+	}
+	routes := map[string]*routeState{}
+	ret := monitorapi.Intervals{}
+	admission := func(state *routeState, router, outcome string, to time.Time) {
+		if len(router) == 0 && !selected(state.created.Locator.Keys[monitorapi.LocatorNamespaceKey], state.created.Locator.Keys[monitorapi.LocatorRouteKey]) {
+			outcome = admissionUnselected
+		}
+		level := monitorapi.Info
+		humanMessage := fmt.Sprintf("route %s", admissionMessages[outcome])
+		if outcome == admissionRejected || outcome == admissionPending {
+			level = monitorapi.Warning
+		}
+		mb := monitorapi.NewMessage().Reason(monitorapi.RouteAdmissionReason).
+			Constructed(monitorapi.ConstructionOwnerIngressHealth).
+			WithAnnotation(monitorapi.AnnotationStatus, outcome)
+		if len(router) > 0 {
+			mb = mb.WithAnnotation(monitorapi.AnnotationRouter, router)
+			humanMessage = fmt.Sprintf("%s by router %s", humanMessage, router)
+		}
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceIngressMonitor, level).
+				Locator(state.created.Locator).
+				Message(mb.HumanMessage(humanMessage)).
+				Display().
+				Build(state.created.From, to))
+	}
+
+	for _, change := range changes {
+		key := change.Locator.OldLocator()
+		switch change.Message.Reason {
+		case monitorapi.RouteCreatedReason:
+			routes[key] = &routeState{created: change, responded: map[string]bool{}}
+
+		case monitorapi.RouteAdmittedReason, monitorapi.RouteRejectedReason:
+			state, ok := routes[key]
+			router := change.Message.Annotations[monitorapi.AnnotationRouter]
+			if !ok || state.responded[router] {
+				continue
+			}
+			state.responded[router] = true
+			outcome := admissionAdmitted
+			if change.Message.Reason == monitorapi.RouteRejectedReason {
+				outcome = admissionRejected
+			}
+			admission(state, router, outcome, change.From)
+
+		case monitorapi.RouteDeletedReason:
+			if state, ok := routes[key]; ok && len(state.responded) == 0 {
+				admission(state, "", admissionDeleted, change.From)
+			}
+			delete(routes, key)
+		}
+	}
+
+	keys := make([]string, 0, len(routes))
+	for key := range routes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if state := routes[key]; len(state.responded) == 0 {
+			admission(state, "", admissionPending, end)
+		}
+	}
+	return ret
+}
+
+var admissionMessages = map[string]string{
+	admissionAdmitted:   "admitted",
+	admissionRejected:   "rejected",
+	admissionDeleted:    "deleted before it was admitted",
+	admissionPending:    "was never admitted",
+	admissionUnselected: "was not selected by any ingresscontroller",
+}
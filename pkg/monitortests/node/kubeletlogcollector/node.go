@@ -111,6 +111,7 @@ func eventsFromKubeletLogs(nodeName string, kubeletLog []byte) monitorapi.Interv
 	ret := monitorapi.Intervals{}
 
 	parse := kubeletlogparser.NewEtcdStaticPodEventsFromKubelet()
+	plegUnhealthyIntervals := monitorapi.Intervals{}
 
 	scanner := bufio.NewScanner(bytes.NewBuffer(kubeletLog))
 	for scanner.Scan() {
@@ -126,9 +127,11 @@ func eventsFromKubeletLogs(nodeName string, kubeletLog []byte) monitorapi.Interv
 		ret = append(ret, anonymousCertConnectionError(nodeLocator, currLine)...)
 		ret = append(ret, leaseUpdateError(nodeLocator, currLine)...)
 		ret = append(ret, leaseFailBackOff(nodeLocator, currLine)...)
+		plegUnhealthyIntervals = append(plegUnhealthyIntervals, plegUnhealthy(nodeLocator, currLine)...)
 		ret = append(ret, parse(nodeName, currLine)...)
 		ret = append(ret, kubeletPanicDetected(nodeName, currLine)...)
 	}
+	ret = append(ret, mergePLEGUnhealthy(plegUnhealthyIntervals)...)
 
 	return ret
 }
@@ -601,6 +604,66 @@ func leaseFailBackOff(nodeLocator monitorapi.Locator, logLine string) monitorapi
 	}
 }
 
+var plegUnhealthyRegex = regexp.MustCompile(`PLEG is not healthy: pleg was last seen active (?P<LASTSEEN>[0-9a-zµ.]+) ago; threshold is (?P<THRESHOLD>[0-9a-zµ.]+)`)
+
+// plegUnhealthy extracts how long the pod lifecycle event generator has been stuck from kubelet logs of the form:
+// "Skipping pod synchronization" err="PLEG is not healthy: pleg was last seen active 3m5.1s ago; threshold is 3m0s"
+// The interval spans the time the PLEG was not seen active.
+func plegUnhealthy(nodeLocator monitorapi.Locator, logLine string) monitorapi.Intervals {
+	if !strings.Contains(logLine, "PLEG is not healthy") {
+		return nil
+	}
+	subMatches := plegUnhealthyRegex.FindStringSubmatch(logLine)
+	if len(subMatches) == 0 {
+		return nil
+	}
+	lastSeen, err := time.ParseDuration(subMatches[1])
+	if err != nil {
+		return nil
+	}
+
+	logTime := utility.SystemdJournalLogTime(logLine, time.Now().Year())
+	return monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Warning).
+			Locator(nodeLocator).
+			Message(
+				monitorapi.NewMessage().Reason(monitorapi.NodePLEGUnhealthyReason).
+					WithAnnotation(monitorapi.AnnotationDuration, lastSeen.String()).
+					HumanMessagef("PLEG was last seen active %s ago, threshold is %s", lastSeen, subMatches[2]),
+			).
+			Build(logTime.Add(-lastSeen), logTime),
+	}
+}
+
+// mergePLEGUnhealthy merges the intervals of the "PLEG is not healthy" lines of a node into one per unhealthy period.
+// The kubelet logs the line about every second while the PLEG is stuck, every one of them reaching back to when the
+// PLEG was last seen active, so the lines of a period overlap. The merged interval keeps the message of the last line,
+// which reports how long the whole period lasted.
+func mergePLEGUnhealthy(intervals monitorapi.Intervals) monitorapi.Intervals {
+	if len(intervals) == 0 {
+		return nil
+	}
+	sort.SliceStable(intervals, func(i, j int) bool {
+		return intervals[i].From.Before(intervals[j].From)
+	})
+
+	ret := monitorapi.Intervals{}
+	period := intervals[0]
+	for _, curr := range intervals[1:] {
+		if curr.From.After(period.To) {
+			ret = append(ret, period)
+			period = curr
+			continue
+		}
+		if curr.To.After(period.To) {
+			from := period.From
+			period = curr
+			period.From = from
+		}
+	}
+	return append(ret, period)
+}
+
 // Our tests will flag an error if leases are failing more than 3 times in 33 seconds.
 // So we will find the first lease failure and then see if more than 3 failures around leases happen
 // If that is the case, we will flag that lease failure as important and fail the test.
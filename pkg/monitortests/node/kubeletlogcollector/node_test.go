@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -1281,3 +1282,67 @@ func TestCrioPanicDetected(t *testing.T) {
 		t.Errorf("Expected nil for non-panic log, got: %v", intervals)
 	}
 }
+
+func TestPLEGUnhealthy(t *testing.T) {
+	nodeLocator := monitorapi.NewLocator().NodeFromName("worker-a")
+	line := `Sep 27 08:59:59.857303 worker-a kubenswrapper[2341]: I0927 08:59:59.857303    2341 kubelet.go:2342] "Skipping pod synchronization" err="PLEG is not healthy: pleg was last seen active 3m5.5s ago; threshold is 3m0s"`
+
+	intervals := plegUnhealthy(nodeLocator, line)
+	if len(intervals) != 1 {
+		t.Fatalf("expected an interval, got %v", intervals)
+	}
+	if intervals[0].Message.Reason != monitorapi.NodePLEGUnhealthyReason || intervals[0].Message.Annotations[monitorapi.AnnotationDuration] != "3m5.5s" {
+		t.Errorf("unexpected interval %s", intervals[0].String())
+	}
+	if duration := intervals[0].To.Sub(intervals[0].From); duration != 3*time.Minute+5500*time.Millisecond {
+		t.Errorf("expected the interval to span the time PLEG was not seen active, got %v", duration)
+	}
+
+	if intervals := plegUnhealthy(nodeLocator, `"Skipping pod synchronization" err="PLEG is not healthy: pleg has yet to be successful"`); intervals != nil {
+		t.Errorf("expected nil without a duration, got %v", intervals)
+	}
+	if intervals := plegUnhealthy(nodeLocator, "normal log line"); intervals != nil {
+		t.Errorf("expected nil for an unrelated line, got %v", intervals)
+	}
+}
+
+func TestMergePLEGUnhealthy(t *testing.T) {
+	plegLine := func(at, lastSeen string) string {
+		return fmt.Sprintf(`Sep 27 %s worker-a kubenswrapper[2341]: I0927 %s    2341 kubelet.go:2342] "Skipping pod synchronization" err="PLEG is not healthy: pleg was last seen active %s ago; threshold is 3m0s"`, at, at, lastSeen)
+	}
+	kubeletLog := strings.Join([]string{
+		plegLine("08:59:59.500000", "3m0.5s"),
+		plegLine("09:00:00.500000", "3m1.5s"),
+		plegLine("09:00:01.500000", "3m2.5s"),
+		// the PLEG recovered at 09:00:02 and got stuck again
+		plegLine("09:03:05.000000", "3m3s"),
+	}, "\n")
+
+	intervals := eventsFromKubeletLogs("worker-a", []byte(kubeletLog)).Filter(func(interval monitorapi.Interval) bool {
+		return interval.Message.Reason == monitorapi.NodePLEGUnhealthyReason
+	})
+	if len(intervals) != 2 {
+		t.Fatalf("expected an interval per unhealthy period, got %v", intervals)
+	}
+	year := time.Now().Year()
+	expected := []struct {
+		from, to time.Time
+		duration string
+	}{
+		{
+			from:     time.Date(year, time.September, 27, 8, 56, 59, 0, time.UTC),
+			to:       time.Date(year, time.September, 27, 9, 0, 1, 500000000, time.UTC),
+			duration: "3m2.5s",
+		},
+		{
+			from:     time.Date(year, time.September, 27, 9, 0, 2, 0, time.UTC),
+			to:       time.Date(year, time.September, 27, 9, 3, 5, 0, time.UTC),
+			duration: "3m3s",
+		},
+	}
+	for i, interval := range intervals {
+		if !interval.From.Equal(expected[i].from) || !interval.To.Equal(expected[i].to) || interval.Message.Annotations[monitorapi.AnnotationDuration] != expected[i].duration {
+			t.Errorf("period %d: expected %v to %v lasting %s, got %s", i, expected[i].from, expected[i].to, expected[i].duration, interval.String())
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package nodehealth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/utility"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const unhealthyNodeTestName = "[sig-node] test failures should not cluster on a single unhealthy node"
+
+// minimumClusteredFailures is the number of failed tests a single unhealthy node has to be behind before it is
+// reported, a couple of failures next to an unhealthy node are as likely a coincidence.
+const minimumClusteredFailures = 3
+
+// unhealthyNodeJUnits looks for a node that was the only unhealthy one while at least half of the failed tests ran,
+// which points at the node rather than at the tests. It flakes since the failed tests already fail the run. No junit
+// is returned when no e2e tests ran.
+func unhealthyNodeJUnits(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	tests := finalIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceE2ETest && eventInterval.Display
+	})
+	if len(tests) == 0 {
+		return nil
+	}
+	unhealthy := finalIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceNodeHealth && eventInterval.Level == monitorapi.Error
+	})
+
+	failedTests := 0
+	nodeFailures := map[string][]string{}
+	nodeSignals := map[string]sets.Set[string]{}
+	for _, test := range tests {
+		if test.Message.Annotations[monitorapi.AnnotationStatus] != "Failed" {
+			continue
+		}
+		failedTests++
+
+		nodes := sets.New[string]()
+		for _, period := range unhealthy {
+			if utility.IntervalsOverlap(period, test) {
+				nodes.Insert(period.Locator.Keys[monitorapi.LocatorNodeKey])
+			}
+		}
+		if nodes.Len() != 1 {
+			continue
+		}
+		node := sets.List(nodes)[0]
+		nodeFailures[node] = append(nodeFailures[node], test.Locator.Keys[monitorapi.LocatorE2ETestKey])
+		for _, period := range unhealthy {
+			if period.Locator.Keys[monitorapi.LocatorNodeKey] == node && utility.IntervalsOverlap(period, test) {
+				if _, ok := nodeSignals[node]; !ok {
+					nodeSignals[node] = sets.New[string]()
+				}
+				nodeSignals[node].Insert(strings.Split(period.Message.Annotations["signals"], ",")...)
+			}
+		}
+	}
+
+	nodes := make([]string, 0, len(nodeFailures))
+	for node := range nodeFailures {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	var failures []string
+	for _, node := range nodes {
+		testNames := nodeFailures[node]
+		if len(testNames) < minimumClusteredFailures || 2*len(testNames) < failedTests {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("node/%s was the only unhealthy node during %d of the %d failed tests, with %s:\n%s",
+			node, len(testNames), failedTests, strings.Join(sets.List(nodeSignals[node]), ", "), strings.Join(testNames, "\n")))
+	}
+	if len(failures) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: unhealthyNodeTestName}}
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: unhealthyNodeTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: strings.Join(failures, "\n\n"),
+			},
+			SystemOut: strings.Join(failures, "\n\n"),
+		},
+		{Name: unhealthyNodeTestName},
+	}
+}
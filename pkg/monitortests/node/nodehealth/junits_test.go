@@ -0,0 +1,114 @@
+package nodehealth
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestUnhealthyNodeJUnits(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return from.Add(time.Duration(minutes) * time.Minute)
+	}
+	// tests runs a test a minute from start, the first failed ones fail
+	tests := func(start, count, failed int) monitorapi.Intervals {
+		var ret monitorapi.Intervals
+		for i := 0; i < count; i++ {
+			status := "Passed"
+			if i < failed {
+				status = "Failed"
+			}
+			ret = append(ret, monitorapi.NewInterval(monitorapi.SourceE2ETest, monitorapi.Info).
+				Locator(monitorapi.NewLocator().E2ETest(fmt.Sprintf("test %d", start+i))).
+				Message(monitorapi.NewMessage().WithAnnotation(monitorapi.AnnotationStatus, status)).
+				Display().
+				Build(at(start+i), at(start+i+1).Add(-time.Second)))
+		}
+		return ret
+	}
+	unhealthy := func(node string, from, to int) monitorapi.Intervals {
+		return healthIntervals([]healthPeriod{{node: node, signals: sets.New(signalNotReady), from: at(from), to: at(to)}})
+	}
+	degraded := func(node string, from, to int) monitorapi.Intervals {
+		return healthIntervals([]healthPeriod{{node: node, signals: sets.New(signalCPUPressure), from: at(from), to: at(to)}})
+	}
+	concat := func(intervals ...monitorapi.Intervals) monitorapi.Intervals {
+		var ret monitorapi.Intervals
+		for _, i := range intervals {
+			ret = append(ret, i...)
+		}
+		return ret
+	}
+
+	testCases := []struct {
+		name          string
+		intervals     monitorapi.Intervals
+		count         int
+		expectFailure string
+	}{
+		{
+			name:      "no tests ran",
+			intervals: unhealthy("worker-a", 0, 10),
+		},
+		{
+			name:      "tests failed on healthy nodes",
+			intervals: concat(tests(0, 10, 5), degraded("worker-a", 0, 10)),
+			count:     1,
+		},
+		{
+			name:          "tests failed while a node was unhealthy",
+			intervals:     concat(tests(0, 10, 4), unhealthy("worker-a", 0, 4)),
+			count:         2,
+			expectFailure: "node/worker-a was the only unhealthy node during 4 of the 4 failed tests, with NotReady:\ntest 0\ntest 1\ntest 2\ntest 3",
+		},
+		{
+			name:      "too few failed tests",
+			intervals: concat(tests(0, 10, 2), unhealthy("worker-a", 0, 4)),
+			count:     1,
+		},
+		{
+			name:      "most failed tests ran while the node was healthy",
+			intervals: concat(tests(0, 10, 3), tests(20, 10, 4), unhealthy("worker-a", 0, 4)),
+			count:     1,
+		},
+		{
+			name:      "several nodes were unhealthy",
+			intervals: concat(tests(0, 10, 4), unhealthy("worker-a", 0, 4), unhealthy("worker-b", 0, 4)),
+			count:     1,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			junits := unhealthyNodeJUnits(tt.intervals)
+			if len(junits) != tt.count {
+				t.Fatalf("expected %d junits, got %d", tt.count, len(junits))
+			}
+			for _, junit := range junits {
+				if junit.Name != unhealthyNodeTestName {
+					t.Errorf("unexpected junit %q", junit.Name)
+				}
+			}
+			if tt.count == 0 {
+				return
+			}
+			if len(tt.expectFailure) == 0 {
+				if junits[0].FailureOutput != nil {
+					t.Errorf("unexpected failure: %s", junits[0].FailureOutput.Output)
+				}
+				return
+			}
+			if junits[0].FailureOutput == nil || !strings.HasPrefix(junits[0].FailureOutput.Output, tt.expectFailure) {
+				t.Errorf("expected failure to start with %q, got %v", tt.expectFailure, junits[0].FailureOutput)
+			}
+			if junits[1].FailureOutput != nil {
+				t.Errorf("expected the second junit to pass so the test flakes")
+			}
+		})
+	}
+}
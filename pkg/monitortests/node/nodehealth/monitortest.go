@@ -0,0 +1,67 @@
+package nodehealth
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// nodeHealthAnalyzer combines the signals about every node recorded by the other monitor tests, from kubelet logs,
+// node conditions and metrics, into a health timeline of the node.
+type nodeHealthAnalyzer struct {
+	signals []nodeSignal
+	periods []healthPeriod
+}
+
+func NewNodeHealthAnalyzer() monitortestframework.MonitorTest {
+	return &nodeHealthAnalyzer{}
+}
+
+func (w *nodeHealthAnalyzer) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *nodeHealthAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *nodeHealthAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (w *nodeHealthAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	w.signals = nodeSignals(startingIntervals, end)
+	w.periods = healthPeriods(w.signals)
+	return healthIntervals(w.periods), nil
+}
+
+func (w *nodeHealthAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return unhealthyNodeJUnits(finalIntervals), nil
+}
+
+func (w *nodeHealthAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	dataFile := summaryDataFile(summarize(w.signals, w.periods))
+	if len(dataFile.Rows) == 0 {
+		logrus.Info("No node health signals to summarize")
+		return nil
+	}
+
+	fileName := filepath.Join(storageDir, fmt.Sprintf("node-health-summary%s-%s", timeSuffix, dataloader.AutoDataLoaderSuffix))
+	if err := dataloader.WriteDataFile(fileName, dataFile); err != nil {
+		logrus.WithError(err).Warnf("unable to write data file: %s", fileName)
+	}
+	return nil
+}
+
+func (*nodeHealthAnalyzer) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,183 @@
+package nodehealth
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/podaccess"
+)
+
+// signal is a kind of trouble a node had.
+type signal string
+
+const (
+	signalNotReady         signal = "NotReady"
+	signalUnreachable      signal = "Unreachable"
+	signalPLEGUnhealthy    signal = "PLEGUnhealthy"
+	signalCoreDump         signal = "CoreDump"
+	signalNetlinkStorm     signal = "NetlinkStorm"
+	signalMemoryPressure   signal = "MemoryPressure"
+	signalCPUPressure      signal = "CPUPressure"
+	signalProbeFailure     signal = "ProbeFailure"
+	signalContainerRestart signal = "ContainerRestart"
+)
+
+// signalWeights are how much every signal lowers the health of a node. Losing the node weighs the most, contention
+// for resources the least since it only slows the workloads down.
+var signalWeights = map[signal]int{
+	signalNotReady:         5,
+	signalUnreachable:      5,
+	signalPLEGUnhealthy:    3,
+	signalCoreDump:         2,
+	signalNetlinkStorm:     2,
+	signalMemoryPressure:   2,
+	signalCPUPressure:      1,
+	signalProbeFailure:     1,
+	signalContainerRestart: 1,
+}
+
+// minimumSpan is how long a signal recorded at an instant affects its node, so that signals close to each other
+// overlap in the timeline.
+const minimumSpan = time.Minute
+
+var (
+	probeFailureReasons = sets.New[monitorapi.IntervalReason](
+		monitorapi.ContainerReasonReadinessFailed,
+		monitorapi.ContainerReasonReadinessErrored,
+		monitorapi.ContainerReasonStartupProbeFailed,
+	)
+	// failingProbeStatuses are the statuses of the kubelet SyncLoop probe events that report a failure.
+	failingProbeStatuses  = sets.New("not ready", "unhealthy")
+	memoryPressureReasons = sets.New[monitorapi.IntervalReason](
+		monitorapi.HighMemoryWorkingSetReason,
+		monitorapi.LowMemoryAvailableReason,
+		monitorapi.ContainerOOMKilledReason,
+	)
+)
+
+// nodeSignal is a signal of a node over a span of time.
+type nodeSignal struct {
+	node     string
+	signal   signal
+	from, to time.Time
+}
+
+// signalOf returns the signal an interval reports. Probe failures and restarts in e2e namespaces are caused by the
+// tests more often than by the node.
+func signalOf(interval monitorapi.Interval) (signal, bool) {
+	reason := interval.Message.Reason
+	switch {
+	case interval.Source == monitorapi.SourceUnexpectedReady:
+		return signalNotReady, true
+	case interval.Source == monitorapi.SourceUnreachable && reason == monitorapi.NodeUnreachable:
+		return signalUnreachable, true
+	case interval.Source == monitorapi.SourceKubeletLog && reason == monitorapi.NodePLEGUnhealthyReason:
+		return signalPLEGUnhealthy, true
+	case interval.Source == monitorapi.SourceSystemdCoreDumpLog:
+		return signalCoreDump, true
+	case interval.Source == monitorapi.SourceNetworkManagerLog && strings.Contains(interval.Message.HumanMessage, "too many netlink events"):
+		return signalNetlinkStorm, true
+	case interval.Source == monitorapi.SourceMemoryMonitor && memoryPressureReasons.Has(reason):
+		return signalMemoryPressure, true
+	case interval.Source == monitorapi.SourceCPUMonitor:
+		return signalCPUPressure, true
+	}
+
+	if monitorapi.IsInE2ENamespace(interval) {
+		return "", false
+	}
+	switch {
+	case interval.Source == monitorapi.SourceKubeletLog && probeFailureReasons.Has(reason):
+		return signalProbeFailure, true
+	case interval.Locator.Type == monitorapi.LocatorTypeKubeletSyncLoopProbe && failingProbeStatuses.Has(interval.Message.Annotations[monitorapi.AnnotationStatus]):
+		return signalProbeFailure, true
+	case interval.Source == monitorapi.SourcePodMonitor && reason == monitorapi.ContainerReasonRestarted:
+		return signalContainerRestart, true
+	}
+	return "", false
+}
+
+// nodeOf returns the node an interval happened on, looking the pod up when the interval is about a container.
+func nodeOf(interval monitorapi.Interval, podToNode map[podaccess.NonUniquePodKey]string) string {
+	if node := interval.Locator.Keys[monitorapi.LocatorNodeKey]; len(node) > 0 {
+		return node
+	}
+	if node := interval.Message.Annotations[monitorapi.AnnotationNode]; len(node) > 0 {
+		return node
+	}
+	pod := monitorapi.PodFrom(interval.Locator)
+	return podToNode[podaccess.NonUniquePodKey{Namespace: pod.Namespace, Name: pod.Name}]
+}
+
+// nodeSignals returns every signal of every node, sorted by node and start.
+func nodeSignals(startingIntervals monitorapi.Intervals, end time.Time) []nodeSignal {
+	podToNode := podaccess.NonUniquePodToNode(startingIntervals)
+
+	ret := notReadySignals(startingIntervals, end)
+	for _, interval := range startingIntervals {
+		s, ok := signalOf(interval)
+		if !ok {
+			continue
+		}
+		node := nodeOf(interval, podToNode)
+		if len(node) == 0 {
+			continue
+		}
+		to := interval.To
+		if to.IsZero() || to.After(end) {
+			to = end
+		}
+		if to.Before(interval.From.Add(minimumSpan)) {
+			to = interval.From.Add(minimumSpan)
+		}
+		ret = append(ret, nodeSignal{node: node, signal: s, from: interval.From, to: to})
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].node != ret[j].node {
+			return ret[i].node < ret[j].node
+		}
+		return ret[i].from.Before(ret[j].from)
+	})
+	return ret
+}
+
+// notReadySignals pairs the node monitor observations of nodes going NotReady with their return to Ready, nodes
+// still NotReady are so until end.
+func notReadySignals(startingIntervals monitorapi.Intervals, end time.Time) []nodeSignal {
+	observations := startingIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		if eventInterval.Source != monitorapi.SourceNodeMonitor {
+			return false
+		}
+		switch eventInterval.Message.Reason {
+		case monitorapi.NodeNotReadyReason, "Ready", monitorapi.NodeDeleted:
+			return true
+		}
+		return false
+	})
+	sort.SliceStable(observations, func(i, j int) bool {
+		return observations[i].From.Before(observations[j].From)
+	})
+
+	var ret []nodeSignal
+	notReadySince := map[string]time.Time{}
+	for _, observation := range observations {
+		node := observation.Locator.Keys[monitorapi.LocatorNodeKey]
+		since, notReady := notReadySince[node]
+		switch {
+		case observation.Message.Reason == monitorapi.NodeNotReadyReason && !notReady:
+			notReadySince[node] = observation.From
+		case observation.Message.Reason != monitorapi.NodeNotReadyReason && notReady:
+			ret = append(ret, nodeSignal{node: node, signal: signalNotReady, from: since, to: observation.From})
+			delete(notReadySince, node)
+		}
+	}
+	for node, since := range notReadySince {
+		ret = append(ret, nodeSignal{node: node, signal: signalNotReady, from: since, to: end})
+	}
+	return ret
+}
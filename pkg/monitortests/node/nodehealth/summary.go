@@ -0,0 +1,86 @@
+package nodehealth
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/dataloader"
+)
+
+const (
+	// summaryDegraded and summaryUnhealthy are the rows summarizing the health periods of a node, next to the rows of
+	// its signals.
+	summaryDegraded  = "Degraded"
+	summaryUnhealthy = "Unhealthy"
+)
+
+type summaryRow struct {
+	count   int
+	seconds float64
+}
+
+// summarize counts every signal of every node and how long it lasted, along with how long the node was degraded and
+// unhealthy.
+func summarize(signals []nodeSignal, periods []healthPeriod) map[string]map[string]*summaryRow {
+	ret := map[string]map[string]*summaryRow{}
+	add := func(node, name string, duration time.Duration) {
+		if _, ok := ret[node]; !ok {
+			ret[node] = map[string]*summaryRow{}
+		}
+		row, ok := ret[node][name]
+		if !ok {
+			row = &summaryRow{}
+			ret[node][name] = row
+		}
+		row.count++
+		row.seconds += duration.Seconds()
+	}
+	for _, s := range signals {
+		add(s.node, string(s.signal), s.to.Sub(s.from))
+	}
+	for _, period := range periods {
+		add(period.node, summaryDegraded, period.to.Sub(period.from))
+		if period.score() >= unhealthyScore {
+			add(period.node, summaryUnhealthy, period.to.Sub(period.from))
+		}
+	}
+	return ret
+}
+
+// summaryDataFile holds a row for every signal of every node. Rows are sorted so they are stable between runs.
+func summaryDataFile(summary map[string]map[string]*summaryRow) dataloader.DataFile {
+	nodes := make([]string, 0, len(summary))
+	for node := range summary {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	rows := []map[string]string{}
+	for _, node := range nodes {
+		names := make([]string, 0, len(summary[node]))
+		for name := range summary[node] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			rows = append(rows, map[string]string{
+				"Node":    node,
+				"Signal":  name,
+				"Count":   fmt.Sprintf("%d", summary[node][name].count),
+				"Seconds": fmt.Sprintf("%.0f", summary[node][name].seconds),
+			})
+		}
+	}
+
+	return dataloader.DataFile{
+		TableName: "node_health_summary",
+		Schema: map[string]dataloader.DataType{
+			"Node":    dataloader.DataTypeString,
+			"Signal":  dataloader.DataTypeString,
+			"Count":   dataloader.DataTypeInteger,
+			"Seconds": dataloader.DataTypeFloat64,
+		},
+		Rows: rows,
+	}
+}
@@ -0,0 +1,114 @@
+package nodehealth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// unhealthyScore is the score from which a node is unhealthy rather than degraded. Any single signal that takes the
+// node away from its workloads reaches it, as does a combination of lesser ones.
+const unhealthyScore = 5
+
+// healthPeriod is a span of time over which a node had the same set of signals.
+type healthPeriod struct {
+	node     string
+	signals  sets.Set[signal]
+	from, to time.Time
+}
+
+func (p healthPeriod) score() int {
+	score := 0
+	for s := range p.signals {
+		score += signalWeights[s]
+	}
+	return score
+}
+
+// healthPeriods splits the signals of every node at every point one starts or ends, and merges the adjacent spans
+// that have the same signals. Spans without any signal are left out.
+func healthPeriods(signals []nodeSignal) []healthPeriod {
+	byNode := map[string][]nodeSignal{}
+	var nodes []string
+	for _, s := range signals {
+		if _, ok := byNode[s.node]; !ok {
+			nodes = append(nodes, s.node)
+		}
+		byNode[s.node] = append(byNode[s.node], s)
+	}
+	sort.Strings(nodes)
+
+	var ret []healthPeriod
+	for _, node := range nodes {
+		nodeSignals := byNode[node]
+		var boundaries []time.Time
+		for _, s := range nodeSignals {
+			boundaries = append(boundaries, s.from, s.to)
+		}
+		sort.Slice(boundaries, func(i, j int) bool {
+			return boundaries[i].Before(boundaries[j])
+		})
+
+		var current *healthPeriod
+		for i := 0; i+1 < len(boundaries); i++ {
+			from, to := boundaries[i], boundaries[i+1]
+			if !from.Before(to) {
+				continue
+			}
+			active := sets.New[signal]()
+			for _, s := range nodeSignals {
+				if s.from.Before(to) && s.to.After(from) {
+					active.Insert(s.signal)
+				}
+			}
+			switch {
+			case active.Len() == 0:
+				current = nil
+			case current != nil && current.to.Equal(from) && current.signals.Equal(active):
+				current.to = to
+			default:
+				ret = append(ret, healthPeriod{node: node, signals: active, from: from, to: to})
+				current = &ret[len(ret)-1]
+			}
+		}
+	}
+	return ret
+}
+
+// healthIntervals charts the health periods of every node, the unhealthy ones as errors.
+func healthIntervals(periods []healthPeriod) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, period := range periods {
+		score := period.score()
+		level := monitorapi.Warning
+		if score >= unhealthyScore {
+			level = monitorapi.Error
+		}
+		signals := signalNames(period.signals)
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceNodeHealth, level).
+				Locator(monitorapi.NewLocator().NodeFromName(period.node)).
+				Message(monitorapi.NewMessage().Reason(monitorapi.NodeHealthDegradedReason).
+					Constructed(monitorapi.ConstructionOwnerNodeHealth).
+					WithAnnotation("score", fmt.Sprintf("%d", score)).
+					WithAnnotation("signals", strings.Join(signals, ",")).
+					HumanMessagef("health score %d from %s", score, strings.Join(signals, ", "))).
+				Display().
+				Build(period.from, period.to))
+	}
+	return ret
+}
+
+func signalNames(signals sets.Set[signal]) []string {
+	ret := make([]string, 0, signals.Len())
+	for s := range signals {
+		ret = append(ret, string(s))
+	}
+	sort.Strings(ret)
+	return ret
+}
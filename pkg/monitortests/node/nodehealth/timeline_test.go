@@ -0,0 +1,145 @@
+package nodehealth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func nodeInterval(source monitorapi.IntervalSource, reason monitorapi.IntervalReason, node string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(source, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().NodeFromName(node)).
+		Message(monitorapi.NewMessage().Reason(reason).HumanMessage(string(reason))).
+		Build(from, to)
+}
+
+func TestNodeSignals(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := from.Add(time.Hour)
+	restart := func(namespace, pod string, at time.Time) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourcePodMonitor, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().ContainerFromNames(namespace, pod, "", "app")).
+			Message(monitorapi.NewMessage().Reason(monitorapi.ContainerReasonRestarted)).
+			Build(at, at)
+	}
+	withNodeKey := func(interval monitorapi.Interval, node string) monitorapi.Interval {
+		interval.Locator.Keys[monitorapi.LocatorNodeKey] = node
+		return interval
+	}
+	// kubelet logs locate the pods they mention on their node
+	kubeletLog := func(namespace, pod, node string) monitorapi.Interval {
+		return withNodeKey(monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Info).
+			Locator(monitorapi.NewLocator().PodFromNames(namespace, pod, "")).
+			Message(monitorapi.NewMessage().HumanMessage("sync")).
+			Build(from, from), node)
+	}
+
+	signals := nodeSignals(monitorapi.Intervals{
+		nodeInterval(monitorapi.SourceNodeMonitor, monitorapi.NodeNotReadyReason, "worker-a", from.Add(10*time.Minute), from.Add(10*time.Minute)),
+		nodeInterval(monitorapi.SourceNodeMonitor, "Ready", "worker-a", from.Add(15*time.Minute), from.Add(15*time.Minute)),
+		nodeInterval(monitorapi.SourceNodeMonitor, monitorapi.NodeNotReadyReason, "worker-b", from.Add(50*time.Minute), from.Add(50*time.Minute)),
+		nodeInterval(monitorapi.SourceKubeletLog, monitorapi.NodePLEGUnhealthyReason, "worker-a", from.Add(7*time.Minute), from.Add(10*time.Minute)),
+		// the node of the restarted container is only known from the pod
+		kubeletLog("openshift-dns", "dns-default-x", "worker-a"),
+		restart("openshift-dns", "dns-default-x", from.Add(20*time.Minute)),
+		// tests restart their own containers
+		withNodeKey(restart("e2e-test-restart", "crasher", from.Add(20*time.Minute)), "worker-a"),
+		nodeInterval(monitorapi.SourceNodeMonitor, "Ready", "worker-c", from, from),
+	}, end)
+
+	expected := []nodeSignal{
+		{node: "worker-a", signal: signalPLEGUnhealthy, from: from.Add(7 * time.Minute), to: from.Add(10 * time.Minute)},
+		{node: "worker-a", signal: signalNotReady, from: from.Add(10 * time.Minute), to: from.Add(15 * time.Minute)},
+		{node: "worker-a", signal: signalContainerRestart, from: from.Add(20 * time.Minute), to: from.Add(21 * time.Minute)},
+		{node: "worker-b", signal: signalNotReady, from: from.Add(50 * time.Minute), to: end},
+	}
+	if len(signals) != len(expected) {
+		t.Fatalf("expected %d signals, got %+v", len(expected), signals)
+	}
+	for i := range expected {
+		if signals[i] != expected[i] {
+			t.Errorf("signal %d: expected %+v, got %+v", i, expected[i], signals[i])
+		}
+	}
+}
+
+func TestHealthPeriods(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return from.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	periods := healthPeriods([]nodeSignal{
+		{node: "worker-a", signal: signalCPUPressure, from: at(0), to: at(30)},
+		{node: "worker-a", signal: signalNotReady, from: at(10), to: at(15)},
+		{node: "worker-a", signal: signalCPUPressure, from: at(20), to: at(25)},
+		{node: "worker-a", signal: signalProbeFailure, from: at(40), to: at(41)},
+		{node: "worker-b", signal: signalPLEGUnhealthy, from: at(5), to: at(8)},
+		{node: "worker-b", signal: signalMemoryPressure, from: at(6), to: at(8)},
+	})
+	expected := []struct {
+		node     string
+		signals  []string
+		score    int
+		from, to time.Time
+	}{
+		{node: "worker-a", signals: []string{"CPUPressure"}, score: 1, from: at(0), to: at(10)},
+		{node: "worker-a", signals: []string{"CPUPressure", "NotReady"}, score: 6, from: at(10), to: at(15)},
+		// the second CPU pressure interval overlaps the first, it doesn't split the period
+		{node: "worker-a", signals: []string{"CPUPressure"}, score: 1, from: at(15), to: at(30)},
+		{node: "worker-a", signals: []string{"ProbeFailure"}, score: 1, from: at(40), to: at(41)},
+		{node: "worker-b", signals: []string{"PLEGUnhealthy"}, score: 3, from: at(5), to: at(6)},
+		{node: "worker-b", signals: []string{"MemoryPressure", "PLEGUnhealthy"}, score: 5, from: at(6), to: at(8)},
+	}
+	if len(periods) != len(expected) {
+		t.Fatalf("expected %d periods, got %+v", len(expected), periods)
+	}
+	for i, period := range periods {
+		signals := signalNames(period.signals)
+		if period.node != expected[i].node || period.score() != expected[i].score || !period.from.Equal(expected[i].from) || !period.to.Equal(expected[i].to) ||
+			len(signals) != len(expected[i].signals) {
+			t.Errorf("period %d: expected %+v, got %+v", i, expected[i], period)
+			continue
+		}
+		for j := range signals {
+			if signals[j] != expected[i].signals[j] {
+				t.Errorf("period %d: expected signals %v, got %v", i, expected[i].signals, signals)
+			}
+		}
+	}
+
+	intervals := healthIntervals(periods)
+	if intervals[1].Level != monitorapi.Error || intervals[0].Level != monitorapi.Warning || intervals[5].Level != monitorapi.Error {
+		t.Errorf("expected the periods scoring at least %d to be errors, got %v", unhealthyScore, intervals)
+	}
+	if intervals[1].Message.Annotations["signals"] != "CPUPressure,NotReady" {
+		t.Errorf("unexpected signals annotation %s", intervals[1].String())
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	signals := []nodeSignal{
+		{node: "worker-a", signal: signalCPUPressure, from: from, to: from.Add(10 * time.Minute)},
+		{node: "worker-a", signal: signalNotReady, from: from.Add(5 * time.Minute), to: from.Add(7 * time.Minute)},
+	}
+	dataFile := summaryDataFile(summarize(signals, healthPeriods(signals)))
+
+	expected := []map[string]string{
+		{"Node": "worker-a", "Signal": "CPUPressure", "Count": "1", "Seconds": "600"},
+		{"Node": "worker-a", "Signal": "Degraded", "Count": "3", "Seconds": "600"},
+		{"Node": "worker-a", "Signal": "NotReady", "Count": "1", "Seconds": "120"},
+		{"Node": "worker-a", "Signal": "Unhealthy", "Count": "1", "Seconds": "120"},
+	}
+	if len(dataFile.Rows) != len(expected) {
+		t.Fatalf("expected %d rows, got %v", len(expected), dataFile.Rows)
+	}
+	for i := range expected {
+		for column, value := range expected[i] {
+			if dataFile.Rows[i][column] != value {
+				t.Errorf("row %d: expected %s %s, got %v", i, column, value, dataFile.Rows[i])
+			}
+		}
+	}
+}
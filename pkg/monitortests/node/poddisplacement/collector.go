@@ -1,4 +1,4 @@
-package ginkgo
+package poddisplacement
 
 import (
 	"context"
@@ -6,14 +6,13 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
-	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 type PodElement struct {
@@ -25,6 +24,9 @@ type PodElement struct {
 	CreationTimestamp metav1.Time  `json:"creationTimestamp"`
 	DeletionTimestamp *metav1.Time `json:"deletionTimestamp"`
 	Events            []string     `json:"events"`
+
+	// observed is when the informer saw the pod, it picks the events the pod is tagged with.
+	observed time.Time
 }
 
 func (pe *PodElement) String() string {
@@ -46,22 +48,6 @@ func NewPodCollector() *PodCollector {
 	}
 }
 
-func SetupNewPodCollector(ctx context.Context) (*PodCollector, error) {
-	cfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
-	clusterConfig, err := cfg.ClientConfig()
-	if err != nil {
-		return nil, fmt.Errorf("could not load client configuration: %v", err)
-	}
-	client, err := kubernetes.NewForConfig(clusterConfig)
-	if err != nil {
-		return nil, err
-	}
-
-	pc := NewPodCollector()
-	pc.Setup(ctx, informers.NewSharedInformerFactory(client, 10*time.Minute))
-	return pc, nil
-}
-
 type Edge struct {
 	In, Out *PodElement
 }
@@ -89,10 +75,9 @@ func (pd PodDisplacements) Dump(minChainLen int) string {
 }
 
 type PodCollector struct {
+	lock     sync.Mutex
 	elements map[string][]*PodElement
 
-	events []string
-
 	podInformer cache.SharedIndexInformer
 
 	// podDisplacements stores for each owner a list of pod replacements
@@ -145,27 +130,48 @@ func (pc *PodCollector) Run(ctx context.Context) {
 	go pc.podInformer.Run(ctx.Done())
 }
 
-func (pc *PodCollector) SetEvents(events []string) {
-	pc.events = events
-}
-
 func (pc *PodCollector) JsonDump() ([]byte, error) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
 	bytes, err := json.Marshal(pc.elements)
 	return bytes, err
 }
 
 func (pc *PodCollector) Import(data []byte) error {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
 	return json.Unmarshal(data, &pc.elements)
 }
 
 func (pc *PodCollector) Record(element *PodElement) {
-	element.Events = append([]string{}, pc.events...)
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
 
+	if element.observed.IsZero() {
+		element.observed = time.Now()
+	}
 	key := element.KindOwnerKey()
 	pc.elements[key] = append(pc.elements[key], element)
 }
 
+// TagEvents sets the events of every recorded pod to the events the suite was in when the pod was observed.
+func (pc *PodCollector) TagEvents(eventsAt func(time.Time) []string) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	for _, elements := range pc.elements {
+		for _, element := range elements {
+			element.Events = eventsAt(element.observed)
+		}
+	}
+}
+
 func (pc *PodCollector) ComputePodTransitions() {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
 	pc.podDisplacements = PodDisplacements{}
 
 	for key, podElements := range pc.elements {
@@ -204,7 +210,7 @@ func (pc *PodCollector) computeKindOwnerPodTransitions(kindOwner string, element
 	// remove duplicates
 	uniquePods := map[string]*PodElement{}
 	for _, elm := range elements {
-		if _, exists := uniquePods[elm.PodName]; !exists {
+		if _, exists := uniquePods[elm.UniqueKey()]; !exists {
 			uniquePods[elm.UniqueKey()] = elm
 		} else {
 			// Add missing DeletionTimestamp
@@ -256,5 +262,8 @@ func (pc *PodCollector) computeKindOwnerPodTransitions(kindOwner string, element
 }
 
 func (pc *PodCollector) PodDisplacements() PodDisplacements {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
 	return pc.podDisplacements
 }
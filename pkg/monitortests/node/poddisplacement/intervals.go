@@ -0,0 +1,178 @@
+package poddisplacement
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// maxPlatformRescheduleChain is the longest chain of replacements a platform workload may have on a run without an upgrade.
+	maxPlatformRescheduleChain = 5
+	// maxPlatformRescheduleChainDuringUpgrade is higher because every node is drained at least once during an upgrade.
+	maxPlatformRescheduleChainDuringUpgrade = 10
+
+	// setupEvent, upgradeEvent and postUpgradeEvent are the events pods are tagged with in the pod placement data.
+	setupEvent       = "Setup"
+	upgradeEvent     = "Upgrade"
+	postUpgradeEvent = "PostUpgrade"
+)
+
+// eventsFromTestBuckets returns the events of the suite at a point in time: Setup until the Early test bucket is done,
+// Upgrade until the Late test bucket starts and PostUpgrade after that.
+func eventsFromTestBuckets(startingIntervals monitorapi.Intervals) func(time.Time) []string {
+	var earlyDone, lateStarted time.Time
+	for _, interval := range startingIntervals {
+		if interval.Source != monitorapi.SourceTestBucket {
+			continue
+		}
+		switch interval.Locator.Keys[monitorapi.LocatorTestBucketKey] {
+		case "Early":
+			earlyDone = interval.To
+		case "Late":
+			lateStarted = interval.From
+		}
+	}
+	return func(at time.Time) []string {
+		switch {
+		case !lateStarted.IsZero() && !at.Before(lateStarted):
+			return []string{postUpgradeEvent}
+		case !earlyDone.IsZero() && !at.Before(earlyDone):
+			return []string{upgradeEvent}
+		}
+		return []string{setupEvent}
+	}
+}
+
+// intervalsFromPodDisplacements creates one interval for every edge in every displacement chain.  The interval spans
+// from the deletion of the displaced pod until the creation of its replacement, clipped to the collection window.
+// The informer lists pods created long before the run, so replacements that finished before beginning are dropped.
+func intervalsFromPodDisplacements(podDisplacements PodDisplacements, beginning, end time.Time) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, chains := range podDisplacements {
+		for _, chain := range chains {
+			if len(chain) == 0 {
+				continue
+			}
+			chainName := chain[0].In.PodName
+			for i, edge := range chain {
+				if edge.In.DeletionTimestamp == nil {
+					continue
+				}
+				from := edge.In.DeletionTimestamp.Time
+				to := edge.Out.CreationTimestamp.Time
+				if to.Before(from) {
+					to = from
+				}
+				if !beginning.IsZero() && to.Before(beginning) {
+					continue
+				}
+				if !end.IsZero() && from.After(end) {
+					continue
+				}
+				if from.Before(beginning) {
+					from = beginning
+				}
+				if !end.IsZero() && to.After(end) {
+					to = end
+				}
+				ret = append(ret,
+					monitorapi.NewInterval(monitorapi.SourcePodDisplacement, monitorapi.Info).
+						Locator(monitorapi.NewLocator().PodDisplacement(edge.Out.Namespace, edge.Out.Kind, edge.Out.KindName, edge.Out.PodName, edge.Out.Node)).
+						Message(monitorapi.NewMessage().Reason(monitorapi.PodDisplaced).
+							WithAnnotation(monitorapi.AnnotationPreviousPod, edge.In.PodName).
+							WithAnnotation(monitorapi.AnnotationPreviousNode, edge.In.Node).
+							WithAnnotation(monitorapi.AnnotationChain, chainName).
+							WithAnnotation(monitorapi.AnnotationChainLength, strconv.Itoa(len(chain))).
+							HumanMessagef("replacement %d of %d for %s/%s", i+1, len(chain), edge.Out.Kind, edge.Out.KindName)).
+						Display().
+						Build(from, to),
+				)
+			}
+		}
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+type displacementChain struct {
+	namespace string
+	owner     string
+	chain     string
+	length    int
+	nodes     []string
+	last      time.Time
+}
+
+func (c displacementChain) String() string {
+	return fmt.Sprintf("ns/%s owner/%s chain/%s was rescheduled %d times, last at %s, nodes: %s",
+		c.namespace, c.owner, c.chain, c.length, c.last.UTC().Format(time.RFC3339), strings.Join(c.nodes, " -> "))
+}
+
+func excessivePlatformReschedulingJunits(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	const testName = "[sig-node] platform workloads should not be rescheduled excessively"
+
+	maxChainLength := maxPlatformRescheduleChain
+	if platformidentification.DidUpgradeHappenDuringCollection(finalIntervals, time.Time{}, time.Time{}) {
+		maxChainLength = maxPlatformRescheduleChainDuringUpgrade
+	}
+
+	chains := map[string]*displacementChain{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourcePodDisplacement {
+			continue
+		}
+		namespace := interval.Locator.Keys[monitorapi.LocatorNamespaceKey]
+		if !platformidentification.IsPlatformNamespace(namespace) {
+			continue
+		}
+		owner := interval.Locator.Keys[monitorapi.LocatorOwnerKey]
+		chainName := interval.Message.Annotations[monitorapi.AnnotationChain]
+		key := strings.Join([]string{namespace, owner, chainName}, "/")
+		chain, ok := chains[key]
+		if !ok {
+			length, _ := strconv.Atoi(interval.Message.Annotations[monitorapi.AnnotationChainLength])
+			chain = &displacementChain{
+				namespace: namespace,
+				owner:     owner,
+				chain:     chainName,
+				length:    length,
+				nodes:     []string{interval.Message.Annotations[monitorapi.AnnotationPreviousNode]},
+			}
+			chains[key] = chain
+		}
+		chain.nodes = append(chain.nodes, interval.Locator.Keys[monitorapi.LocatorNodeKey])
+		if interval.To.After(chain.last) {
+			chain.last = interval.To
+		}
+	}
+
+	failures := []string{}
+	for _, chain := range chains {
+		if chain.length > maxChainLength {
+			failures = append(failures, chain.String())
+		}
+	}
+	sort.Strings(failures)
+
+	if len(failures) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: testName}}
+	}
+
+	return []*junitapi.JUnitTestCase{
+		{
+			Name:      testName,
+			SystemOut: strings.Join(failures, "\n"),
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d platform pod chains were rescheduled more than %d times:\n%s",
+					len(failures), maxChainLength, strings.Join(failures, "\n")),
+			},
+		},
+	}
+}
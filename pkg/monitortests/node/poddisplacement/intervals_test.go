@@ -0,0 +1,183 @@
+package poddisplacement
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func displacedPods(namespace, kindName string, count int, start time.Time) []*PodElement {
+	ret := []*PodElement{}
+	for i := 0; i < count; i++ {
+		created := metav1.NewTime(start.Add(time.Duration(i) * time.Minute))
+		element := &PodElement{
+			Namespace:         namespace,
+			Kind:              "ReplicaSet",
+			KindName:          kindName,
+			PodName:           fmt.Sprintf("%s-%d", kindName, i),
+			Node:              fmt.Sprintf("node-%d", i%3),
+			CreationTimestamp: created,
+		}
+		if i < count-1 {
+			deleted := metav1.NewTime(created.Add(50 * time.Second))
+			element.DeletionTimestamp = &deleted
+		}
+		ret = append(ret, element)
+	}
+	return ret
+}
+
+func TestIntervalsFromPodDisplacements(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pc := NewPodCollector()
+	for _, element := range displacedPods("openshift-foo", "foo-1234", 4, start) {
+		pc.Record(element)
+	}
+	pc.ComputePodTransitions()
+
+	intervals := intervalsFromPodDisplacements(pc.PodDisplacements(), time.Time{}, time.Time{})
+	if len(intervals) != 3 {
+		t.Fatalf("expected 3 intervals, got %d: %v", len(intervals), intervals)
+	}
+	for i, interval := range intervals {
+		if interval.Locator.Keys[monitorapi.LocatorOwnerKey] != "ReplicaSet/foo-1234" {
+			t.Errorf("unexpected owner: %v", interval.Locator.Keys)
+		}
+		if interval.Message.Annotations[monitorapi.AnnotationChain] != "foo-1234-0" {
+			t.Errorf("unexpected chain: %v", interval.Message.Annotations)
+		}
+		if interval.Message.Annotations[monitorapi.AnnotationChainLength] != "3" {
+			t.Errorf("unexpected chain length: %v", interval.Message.Annotations)
+		}
+		if expected := fmt.Sprintf("foo-1234-%d", i+1); interval.Locator.Keys[monitorapi.LocatorPodKey] != expected {
+			t.Errorf("expected pod %v, got %v", expected, interval.Locator.Keys[monitorapi.LocatorPodKey])
+		}
+		if !interval.To.After(interval.From) {
+			t.Errorf("expected replacement to be created after deletion: %v", interval)
+		}
+	}
+}
+
+func TestExcessivePlatformReschedulingJunits(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		namespace    string
+		podCount     int
+		expectedFail bool
+	}{
+		{
+			name:      "short platform chain passes",
+			namespace: "openshift-foo",
+			podCount:  3,
+		},
+		{
+			name:         "long platform chain fails",
+			namespace:    "openshift-foo",
+			podCount:     maxPlatformRescheduleChain + 2,
+			expectedFail: true,
+		},
+		{
+			name:      "long e2e chain is ignored",
+			namespace: "e2e-test-foo",
+			podCount:  maxPlatformRescheduleChain + 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := NewPodCollector()
+			for _, element := range displacedPods(tt.namespace, "foo-1234", tt.podCount, start) {
+				pc.Record(element)
+			}
+			pc.ComputePodTransitions()
+
+			junits := excessivePlatformReschedulingJunits(intervalsFromPodDisplacements(pc.PodDisplacements(), time.Time{}, time.Time{}))
+			failed := false
+			for _, junit := range junits {
+				if junit.FailureOutput != nil {
+					failed = true
+				}
+			}
+			if failed != tt.expectedFail {
+				t.Errorf("expected failure %v, got %v", tt.expectedFail, failed)
+			}
+			if len(junits) != 1 {
+				t.Errorf("expected a single junit, the registry decides whether failures flake, got %d", len(junits))
+			}
+		})
+	}
+}
+
+func TestIntervalsFromPodDisplacementsClipped(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pc := NewPodCollector()
+	for _, element := range displacedPods("openshift-foo", "foo-1234", 4, start) {
+		pc.Record(element)
+	}
+	pc.ComputePodTransitions()
+
+	// the first replacement finished before the run began, the last one is still going on when it ends
+	beginning := start.Add(70 * time.Second)
+	end := start.Add(170 * time.Second)
+	intervals := intervalsFromPodDisplacements(pc.PodDisplacements(), beginning, end)
+	if len(intervals) != 2 {
+		t.Fatalf("expected 2 intervals, got %d: %v", len(intervals), intervals)
+	}
+	if !intervals[0].From.Equal(start.Add(110 * time.Second)) {
+		t.Errorf("unexpected first interval %v", intervals[0])
+	}
+	if !intervals[1].To.Equal(end) {
+		t.Errorf("expected the last interval to end at %v, got %v", end, intervals[1])
+	}
+}
+
+func TestComputePodTransitionsDeduplicates(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pc := NewPodCollector()
+	// every pod is seen more than once, a copy without the deletion timestamp must not replace the deleted pod
+	for _, element := range displacedPods("openshift-foo", "foo-1234", 3, start) {
+		stale := *element
+		stale.DeletionTimestamp = nil
+		pc.Record(element)
+		pc.Record(&stale)
+	}
+	pc.ComputePodTransitions()
+
+	chains := pc.PodDisplacements()["openshift-foo/ReplicaSet/foo-1234"]
+	if len(chains) != 1 || len(chains[0]) != 2 {
+		t.Fatalf("expected a single chain of 2 replacements, got %v", chains)
+	}
+}
+
+func TestEventsFromTestBuckets(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket := func(name string, from, to time.Time) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceTestBucket, monitorapi.Info).
+			Locator(monitorapi.NewLocator().TestBucket(name)).
+			Message(monitorapi.NewMessage().HumanMessage(name)).
+			Build(from, to)
+	}
+	eventsAt := eventsFromTestBuckets(monitorapi.Intervals{
+		bucket("Early", start.Add(time.Minute), start.Add(10*time.Minute)),
+		bucket("Late", start.Add(60*time.Minute), start.Add(70*time.Minute)),
+	})
+
+	for at, expected := range map[time.Duration]string{
+		5 * time.Minute:  setupEvent,
+		30 * time.Minute: upgradeEvent,
+		65 * time.Minute: postUpgradeEvent,
+	} {
+		if actual := eventsAt(start.Add(at)); len(actual) != 1 || actual[0] != expected {
+			t.Errorf("at %v: expected %v, got %v", at, expected, actual)
+		}
+	}
+	if actual := eventsFromTestBuckets(nil)(start); actual[0] != setupEvent {
+		t.Errorf("expected %v without test buckets, got %v", setupEvent, actual)
+	}
+}
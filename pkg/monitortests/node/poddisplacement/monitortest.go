@@ -0,0 +1,103 @@
+package poddisplacement
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// Dump pod displacements with at least 3 instances
+	minChainLen = 3
+)
+
+type podDisplacementAnalyzer struct {
+	podCollector   *PodCollector
+	stopCollection context.CancelFunc
+}
+
+// NewPodDisplacementAnalyzer tracks every pod that is deleted and replaced by a new pod of the same owner.
+// Replacements are strung together into chains so that workloads which are rescheduled over and over are visible.
+func NewPodDisplacementAnalyzer() monitortestframework.MonitorTest {
+	return &podDisplacementAnalyzer{}
+}
+
+func (w *podDisplacementAnalyzer) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *podDisplacementAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+
+	collectionCtx, cancel := context.WithCancel(ctx)
+	w.stopCollection = cancel
+
+	w.podCollector = NewPodCollector()
+	w.podCollector.Setup(collectionCtx, informers.NewSharedInformerFactory(kubeClient, 10*time.Minute))
+	w.podCollector.Run(collectionCtx)
+
+	return nil
+}
+
+func (w *podDisplacementAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.podCollector == nil {
+		return nil, nil, nil
+	}
+	w.stopCollection()
+
+	w.podCollector.ComputePodTransitions()
+	return intervalsFromPodDisplacements(w.podCollector.PodDisplacements(), beginning, end), nil, nil
+}
+
+func (w *podDisplacementAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	if w.podCollector != nil {
+		w.podCollector.TagEvents(eventsFromTestBuckets(startingIntervals))
+	}
+	return nil, nil
+}
+
+func (*podDisplacementAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return excessivePlatformReschedulingJunits(finalIntervals), nil
+}
+
+func (w *podDisplacementAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	if w.podCollector == nil {
+		return nil
+	}
+
+	// these keep the names and location they had when run-suite wrote them, tools that read them depend on both.
+	data, err := w.podCollector.JsonDump()
+	if err != nil {
+		return fmt.Errorf("unable to dump pod placement data: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(storageDir, "pod-placement-data.json"), data, 0644); err != nil {
+		return fmt.Errorf("unable to write pod placement data: %w", err)
+	}
+
+	chains := w.podCollector.PodDisplacements().Dump(minChainLen)
+	if err := os.WriteFile(filepath.Join(storageDir, "pod-transitions.txt"), []byte(chains), 0644); err != nil {
+		return fmt.Errorf("unable to write pod transitions: %w", err)
+	}
+
+	return nil
+}
+
+func (w *podDisplacementAnalyzer) Cleanup(ctx context.Context) error {
+	if w.stopCollection != nil {
+		w.stopCollection()
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package podstartuplatency
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	startupRegressionTestName = "[sig-node] platform pods should start within their historical startup latency"
+
+	// regressionTolerance is added to the baseline before a pod counts as regressed. Creation timestamps have second
+	// precision and the kubelet events are aggregated, a couple of seconds either way is noise.
+	regressionTolerance = 10 * time.Second
+)
+
+// startupBaseline holds the historical total startup latency of platform workloads in the same format as the backend
+// disruption data, with "<namespace>/<owner>" in place of the backend name. It is empty for now: the regression check
+// is only groundwork and judges no workload until P99 data from CI runs is added for a job type.
+//
+//go:embed pod_startup_baseline.json
+var startupBaseline []byte
+
+var (
+	readStartupBaseline    sync.Once
+	startupBaselineMatcher *historicaldata.DisruptionBestMatcher
+)
+
+func getStartupBaseline() *historicaldata.DisruptionBestMatcher {
+	readStartupBaseline.Do(
+		func() {
+			var err error
+			startupBaselineMatcher, err = historicaldata.NewDisruptionMatcher(startupBaseline)
+			if err != nil {
+				panic(err)
+			}
+		})
+
+	return startupBaselineMatcher
+}
+
+// baselineFunc returns the P99 total startup latency of the workload, false when there is no baseline for it.
+type baselineFunc func(namespace, owner string) (time.Duration, bool)
+
+func historicalBaseline(matcher *historicaldata.DisruptionBestMatcher, jobType *platformidentification.JobType) baselineFunc {
+	return func(namespace, owner string) (time.Duration, bool) {
+		if jobType == nil {
+			return 0, false
+		}
+		p99, _, err := matcher.BestMatchP99(namespace+"/"+owner, *jobType)
+		if err != nil || p99 == nil {
+			return 0, false
+		}
+		return *p99, true
+	}
+}
+
+// startupRegressionJUnits fails when a platform pod took longer to become ready than the baseline of its workload.
+// Workloads without a baseline are not judged. No junit is returned when no platform pod with a baseline was started,
+// which includes every run until pod_startup_baseline.json has data for the job type.
+func startupRegressionJUnits(startups []podStartup, baseline baselineFunc) []*junitapi.JUnitTestCase {
+	judged := 0
+	failures := []string{}
+	for _, startup := range startups {
+		if !platformidentification.IsPlatformNamespace(startup.namespace) {
+			continue
+		}
+		limit, ok := baseline(startup.namespace, startup.owner)
+		if !ok {
+			continue
+		}
+		judged++
+		durations := startup.durations()
+		total := durations[phaseTotal]
+		if total <= limit+regressionTolerance {
+			continue
+		}
+		breakdown := []string{}
+		for _, phase := range phases[:len(phases)-1] {
+			breakdown = append(breakdown, fmt.Sprintf("%s=%s", phase, durations[phase].Round(time.Second)))
+		}
+		failures = append(failures, fmt.Sprintf("%s took %s to become ready, longer than the historical P99 of %s: %s",
+			startup, total.Round(time.Second), limit.Round(time.Second), strings.Join(breakdown, " ")))
+	}
+	if judged == 0 {
+		return nil
+	}
+	if len(failures) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: startupRegressionTestName}}
+	}
+	sort.Strings(failures)
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: startupRegressionTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: strings.Join(failures, "\n"),
+			},
+			SystemOut: strings.Join(failures, "\n"),
+		},
+	}
+}
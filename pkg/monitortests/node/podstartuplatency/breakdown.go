@@ -0,0 +1,201 @@
+package podstartuplatency
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// The phases a pod goes through from being created until it is ready. A pod whose images were already present on
+// the node spends no time in ImagePull.
+const (
+	phaseScheduling     = "Scheduling"
+	phaseImagePull      = "ImagePull"
+	phaseContainerStart = "ContainerStart"
+	phaseReadiness      = "Readiness"
+	phaseTotal          = "Total"
+)
+
+var phases = []string{phaseScheduling, phaseImagePull, phaseContainerStart, phaseReadiness, phaseTotal}
+
+// pulledReason is the reason of the kubelet event recorded once the image of a container is available, whether it
+// was pulled or already present on the node.
+const pulledReason monitorapi.IntervalReason = "Pulled"
+
+// podStartup is when a pod reached each step of its startup.
+type podStartup struct {
+	namespace string
+	name      string
+	uid       string
+	owner     string
+	node      string
+
+	created           time.Time
+	scheduled         time.Time
+	imagesPulled      time.Time
+	containersStarted time.Time
+	ready             time.Time
+}
+
+func (s podStartup) String() string {
+	return fmt.Sprintf("pod/%s -n %s (%s) on %s", s.name, s.namespace, s.owner, s.node)
+}
+
+// durations breaks the time the pod took to become ready into its phases.
+func (s podStartup) durations() map[string]time.Duration {
+	pulled := s.scheduled
+	if s.imagesPulled.After(pulled) {
+		pulled = s.imagesPulled
+	}
+	return map[string]time.Duration{
+		phaseScheduling:     nonNegative(s.scheduled.Sub(s.created)),
+		phaseImagePull:      nonNegative(pulled.Sub(s.scheduled)),
+		phaseContainerStart: nonNegative(s.containersStarted.Sub(pulled)),
+		phaseReadiness:      nonNegative(s.ready.Sub(s.containersStarted)),
+		phaseTotal:          nonNegative(s.ready.Sub(s.created)),
+	}
+}
+
+// The creation timestamp has second precision while the rest is observed by the watch, so the first phase can come
+// out slightly negative.
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// podEvents are the point in time intervals recorded for a single pod.
+type podEvents struct {
+	scheduled       time.Time
+	containerStarts map[string]time.Time
+	containerReady  map[string]time.Time
+}
+
+// podStartups reconstructs the startup of every pod that was created after beginning and became ready. Mirror pods
+// are skipped, the kubelet starts them without the scheduler and their creation time is when the API learned of them.
+func podStartups(intervals monitorapi.Intervals, recordedPods monitorapi.InstanceMap, beginning time.Time) []podStartup {
+	events := map[string]*podEvents{}
+	pulls := map[string][]time.Time{}
+	for _, interval := range intervals {
+		switch interval.Source {
+		case monitorapi.SourcePodMonitor:
+			uid := interval.Locator.Keys[monitorapi.LocatorUIDKey]
+			if len(uid) == 0 {
+				continue
+			}
+			podEvent, ok := events[uid]
+			if !ok {
+				podEvent = &podEvents{containerStarts: map[string]time.Time{}, containerReady: map[string]time.Time{}}
+				events[uid] = podEvent
+			}
+			container := interval.Locator.Keys[monitorapi.LocatorContainerKey]
+			switch interval.Message.Reason {
+			case monitorapi.PodReasonScheduled:
+				podEvent.scheduled = interval.From
+			case monitorapi.ContainerReasonContainerStart:
+				setFirst(podEvent.containerStarts, container, interval.From)
+			case monitorapi.ContainerReasonReady:
+				setFirst(podEvent.containerReady, container, interval.From)
+			}
+
+		case monitorapi.SourceKubeEvent:
+			if interval.Message.Reason != pulledReason {
+				continue
+			}
+			name := interval.Locator.Keys[monitorapi.LocatorPodKey]
+			if len(name) == 0 {
+				continue
+			}
+			key := interval.Locator.Keys[monitorapi.LocatorNamespaceKey] + "/" + name
+			pulls[key] = append(pulls[key], interval.From)
+		}
+	}
+
+	startups := []podStartup{}
+	for _, obj := range recordedPods {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || pod.CreationTimestamp.Time.Before(beginning) {
+			continue
+		}
+		if _, isMirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; isMirror {
+			continue
+		}
+		podEvent, ok := events[string(pod.UID)]
+		if !ok || podEvent.scheduled.IsZero() {
+			continue
+		}
+
+		startup := podStartup{
+			namespace: pod.Namespace,
+			name:      pod.Name,
+			uid:       string(pod.UID),
+			owner:     podOwner(pod),
+			node:      pod.Spec.NodeName,
+			created:   pod.CreationTimestamp.Time,
+			scheduled: podEvent.scheduled,
+		}
+		// init containers have to finish before the containers start, so only the containers count
+		complete := len(pod.Spec.Containers) > 0
+		for _, container := range pod.Spec.Containers {
+			started, ok := podEvent.containerStarts[container.Name]
+			ready, isReady := podEvent.containerReady[container.Name]
+			if !ok || !isReady {
+				complete = false
+				break
+			}
+			if started.After(startup.containersStarted) {
+				startup.containersStarted = started
+			}
+			if ready.After(startup.ready) {
+				startup.ready = ready
+			}
+		}
+		if !complete {
+			continue
+		}
+		// the pod name may be reused by a later pod, only the pulls for this one count
+		for _, pulled := range pulls[pod.Namespace+"/"+pod.Name] {
+			if pulled.Before(startup.created) || pulled.After(startup.containersStarted) {
+				continue
+			}
+			if pulled.After(startup.imagesPulled) {
+				startup.imagesPulled = pulled
+			}
+		}
+		startups = append(startups, startup)
+	}
+
+	sort.Slice(startups, func(i, j int) bool {
+		if startups[i].namespace != startups[j].namespace {
+			return startups[i].namespace < startups[j].namespace
+		}
+		return startups[i].name < startups[j].name
+	})
+	return startups
+}
+
+func setFirst(times map[string]time.Time, key string, t time.Time) {
+	if existing, ok := times[key]; !ok || t.Before(existing) {
+		times[key] = t
+	}
+}
+
+// podOwner names the workload the pod belongs to. Pods of a Deployment are attributed to the Deployment rather than
+// to the ReplicaSet of the current revision so that a rollout does not split the workload.
+func podOwner(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "Pod/" + pod.Name
+	}
+	if hash, ok := pod.Labels["pod-template-hash"]; ok && owner.Kind == "ReplicaSet" && strings.HasSuffix(owner.Name, "-"+hash) {
+		return "Deployment/" + strings.TrimSuffix(owner.Name, "-"+hash)
+	}
+	return owner.Kind + "/" + owner.Name
+}
@@ -0,0 +1,204 @@
+package podstartuplatency
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junittest"
+)
+
+func newPod(namespace, name string, created time.Time, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         namespace,
+			Name:              name,
+			UID:               types.UID(name + "-uid"),
+			CreationTimestamp: metav1.NewTime(created),
+			Labels:            map[string]string{"pod-template-hash": "7d9f8b6c5"},
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "etcd-operator-7d9f8b6c5", Controller: ptr.To(true)},
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: "master-0"},
+	}
+	for _, container := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: container})
+	}
+	return pod
+}
+
+func podEvent(pod *corev1.Pod, reason monitorapi.IntervalReason, at time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourcePodMonitor, monitorapi.Info).
+		Locator(monitorapi.NewLocator().PodFromPod(pod)).
+		Message(monitorapi.NewMessage().Reason(reason)).
+		Build(at, at)
+}
+
+func containerEvent(pod *corev1.Pod, container string, reason monitorapi.IntervalReason, at time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourcePodMonitor, monitorapi.Info).
+		Locator(monitorapi.NewLocator().ContainerFromPod(pod, container)).
+		Message(monitorapi.NewMessage().Reason(reason)).
+		Build(at, at)
+}
+
+func pulledEvent(pod *corev1.Pod, at time.Time) monitorapi.Interval {
+	event := &corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name},
+		Message:        `Successfully pulled image "quay.io/openshift/etcd" in 4s`,
+	}
+	return monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Info).
+		Locator(monitorapi.NewLocator().KubeEvent(event)).
+		Message(monitorapi.NewMessage().Reason(pulledReason).HumanMessage(event.Message)).
+		Build(at, at)
+}
+
+func recorded(pods ...*corev1.Pod) monitorapi.InstanceMap {
+	ret := monitorapi.InstanceMap{}
+	for _, pod := range pods {
+		ret[monitorapi.InstanceKey{Namespace: pod.Namespace, Name: pod.Name, UID: string(pod.UID)}] = pod
+	}
+	return ret
+}
+
+func TestPodStartups(t *testing.T) {
+	beginning := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	created := beginning.Add(time.Minute)
+	at := func(seconds int) time.Time { return created.Add(time.Duration(seconds) * time.Second) }
+
+	pod := newPod("openshift-etcd-operator", "etcd-operator-7d9f8b6c5-abcde", created, "operator", "sidecar")
+	intervals := monitorapi.Intervals{
+		podEvent(pod, monitorapi.PodReasonCreated, at(0)),
+		podEvent(pod, monitorapi.PodReasonScheduled, at(2)),
+		pulledEvent(pod, at(5)),
+		pulledEvent(pod, at(12)),
+		containerEvent(pod, "operator", monitorapi.ContainerReasonContainerStart, at(13)),
+		containerEvent(pod, "sidecar", monitorapi.ContainerReasonContainerStart, at(14)),
+		containerEvent(pod, "sidecar", monitorapi.ContainerReasonReady, at(15)),
+		containerEvent(pod, "operator", monitorapi.ContainerReasonReady, at(30)),
+		// readiness flapping later does not move the startup
+		containerEvent(pod, "operator", monitorapi.ContainerReasonNotReady, at(100)),
+		containerEvent(pod, "operator", monitorapi.ContainerReasonReady, at(110)),
+	}
+
+	preexisting := newPod("openshift-etcd-operator", "preexisting", beginning.Add(-time.Hour), "operator")
+	neverReady := newPod("openshift-etcd-operator", "never-ready", created, "operator")
+	intervals = append(intervals,
+		podEvent(preexisting, monitorapi.PodReasonScheduled, at(0)),
+		containerEvent(preexisting, "operator", monitorapi.ContainerReasonContainerStart, at(0)),
+		containerEvent(preexisting, "operator", monitorapi.ContainerReasonReady, at(0)),
+		podEvent(neverReady, monitorapi.PodReasonScheduled, at(1)),
+		containerEvent(neverReady, "operator", monitorapi.ContainerReasonContainerStart, at(3)),
+	)
+
+	startups := podStartups(intervals, recorded(pod, preexisting, neverReady), beginning)
+	if len(startups) != 1 {
+		t.Fatalf("expected only the pod created during the run that became ready, got %v", startups)
+	}
+	if startups[0].owner != "Deployment/etcd-operator" {
+		t.Errorf("expected the pod to be attributed to its deployment, got %s", startups[0].owner)
+	}
+
+	expected := map[string]time.Duration{
+		phaseScheduling:     2 * time.Second,
+		phaseImagePull:      10 * time.Second,
+		phaseContainerStart: 2 * time.Second,
+		phaseReadiness:      16 * time.Second,
+		phaseTotal:          30 * time.Second,
+	}
+	if actual := startups[0].durations(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestStartupRegressionJUnits(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	startup := func(namespace, owner string, total time.Duration) podStartup {
+		return podStartup{
+			namespace: namespace, name: "pod", owner: owner, node: "master-0",
+			created: created, scheduled: created, containersStarted: created, ready: created.Add(total),
+		}
+	}
+	baseline := func(namespace, owner string) (time.Duration, bool) {
+		if owner == "Deployment/known" {
+			return time.Minute, true
+		}
+		return 0, false
+	}
+
+	tests := []struct {
+		name          string
+		startups      []podStartup
+		expectJUnit   bool
+		expectFailure string
+	}{
+		{
+			name:     "only workload pods",
+			startups: []podStartup{startup("e2e-test-abcde", "Deployment/known", time.Hour)},
+		},
+		{
+			name:        "within the baseline",
+			startups:    []podStartup{startup("openshift-etcd", "Deployment/known", time.Minute+5*time.Second)},
+			expectJUnit: true,
+		},
+		{
+			name:     "no baseline",
+			startups: []podStartup{startup("openshift-etcd", "Deployment/unknown", time.Hour)},
+		},
+		{
+			name: "workloads with and without a baseline",
+			startups: []podStartup{
+				startup("openshift-etcd", "Deployment/unknown", time.Hour),
+				startup("openshift-etcd", "Deployment/known", time.Minute),
+			},
+			expectJUnit: true,
+		},
+		{
+			name:          "regressed",
+			startups:      []podStartup{startup("openshift-etcd", "Deployment/known", 2*time.Minute)},
+			expectJUnit:   true,
+			expectFailure: "pod/pod -n openshift-etcd (Deployment/known) on master-0 took 2m0s to become ready, longer than the historical P99 of 1m0s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			junittest.ExpectSingleJUnit(t, startupRegressionJUnits(tt.startups, baseline), startupRegressionTestName, tt.expectJUnit, tt.expectFailure)
+		})
+	}
+}
+
+func TestStartupDataFile(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	startups := []podStartup{}
+	for i := 1; i <= 10; i++ {
+		startups = append(startups, podStartup{
+			namespace: "openshift-etcd", owner: "Deployment/etcd-operator",
+			created: created, scheduled: created, containersStarted: created, ready: created.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	dataFile := startupDataFile(summarize(startups))
+	var totals []map[string]string
+	for _, row := range dataFile.Rows {
+		if row["Phase"] == phaseTotal {
+			totals = append(totals, row)
+		}
+	}
+	if len(totals) != 2 {
+		t.Fatalf("expected a total row for the namespace and for the owner, got %v", totals)
+	}
+	for _, row := range totals {
+		if row["Pods"] != "10" || row["P50Seconds"] != "5.000" || row["P90Seconds"] != "9.000" || row["P99Seconds"] != "10.000" {
+			t.Errorf("unexpected percentiles: %v", row)
+		}
+		if _, ok := dataFile.Schema["P99Seconds"]; !ok {
+			t.Errorf("expected the percentile columns in the schema")
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package podstartuplatency
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// podStartupLatencyAnalyzer breaks the startup of every pod created during the run into its phases, using the pod
+// intervals from the pod-lifecycle monitor test and the image pull events from the event-collector.
+type podStartupLatencyAnalyzer struct {
+	jobType  *platformidentification.JobType
+	startups []podStartup
+}
+
+func NewPodStartupLatencyAnalyzer() monitortestframework.MonitorTest {
+	return &podStartupLatencyAnalyzer{}
+}
+
+func (w *podStartupLatencyAnalyzer) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *podStartupLatencyAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	// without a job type no workload has a baseline, the latencies are still summarized
+	var err error
+	w.jobType, err = platformidentification.GetJobType(ctx, adminRESTConfig)
+	if err != nil {
+		logrus.WithError(err).Warn("unable to determine the job type, pod startup latencies will not be compared to a baseline")
+	}
+	return nil
+}
+
+func (w *podStartupLatencyAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (w *podStartupLatencyAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	// the pods are only available here, so the startups are reconstructed now and evaluated later
+	w.startups = podStartups(startingIntervals, recordedResources["pods"], beginning)
+	return nil, nil
+}
+
+func (w *podStartupLatencyAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return startupRegressionJUnits(w.startups, historicalBaseline(getStartupBaseline(), w.jobType)), nil
+}
+
+func (w *podStartupLatencyAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	if len(w.startups) == 0 {
+		return nil
+	}
+	fileName := filepath.Join(storageDir, fmt.Sprintf("pod-startup-latency%s-%s", timeSuffix, dataloader.AutoDataLoaderSuffix))
+	if err := dataloader.WriteDataFile(fileName, startupDataFile(summarize(w.startups))); err != nil {
+		logrus.WithError(err).Warnf("unable to write data file: %s", fileName)
+	}
+	return nil
+}
+
+func (w *podStartupLatencyAnalyzer) Cleanup(ctx context.Context) error {
+	return nil
+}
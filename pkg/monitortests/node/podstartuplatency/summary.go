@@ -0,0 +1,119 @@
+package podstartuplatency
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/openshift/origin/pkg/dataloader"
+)
+
+const (
+	groupNamespace = "Namespace"
+	groupOwner     = "Owner"
+)
+
+var percentiles = []struct {
+	value  float64
+	column string
+}{
+	{value: 0.5, column: "P50Seconds"},
+	{value: 0.9, column: "P90Seconds"},
+	{value: 0.99, column: "P99Seconds"},
+}
+
+// startupGroup is a set of pods summarized together, either all the pods of a namespace or all the pods of a
+// workload.
+type startupGroup struct {
+	groupType string
+	namespace string
+	owner     string
+	durations map[string][]float64
+}
+
+// summarize groups the startups by namespace and by owner. Groups are sorted so the rows are stable between runs.
+func summarize(startups []podStartup) []*startupGroup {
+	groups := map[string]*startupGroup{}
+	add := func(groupType, namespace, owner string, startup podStartup) {
+		key := groupType + "/" + namespace + "/" + owner
+		group, ok := groups[key]
+		if !ok {
+			group = &startupGroup{groupType: groupType, namespace: namespace, owner: owner, durations: map[string][]float64{}}
+			groups[key] = group
+		}
+		for phase, duration := range startup.durations() {
+			group.durations[phase] = append(group.durations[phase], duration.Seconds())
+		}
+	}
+	for _, startup := range startups {
+		add(groupNamespace, startup.namespace, "", startup)
+		add(groupOwner, startup.namespace, startup.owner, startup)
+	}
+
+	ret := make([]*startupGroup, 0, len(groups))
+	for _, group := range groups {
+		ret = append(ret, group)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].groupType != ret[j].groupType {
+			return ret[i].groupType < ret[j].groupType
+		}
+		if ret[i].namespace != ret[j].namespace {
+			return ret[i].namespace < ret[j].namespace
+		}
+		return ret[i].owner < ret[j].owner
+	})
+	return ret
+}
+
+// percentile is the nearest-rank percentile of the values.
+func percentile(values []float64, p float64) float64 {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// startupDataFile holds a row for every phase of every group.
+func startupDataFile(groups []*startupGroup) dataloader.DataFile {
+	rows := []map[string]string{}
+	for _, group := range groups {
+		for _, phase := range phases {
+			durations := group.durations[phase]
+			if len(durations) == 0 {
+				continue
+			}
+			row := map[string]string{
+				"GroupType": group.groupType,
+				"Namespace": group.namespace,
+				"Owner":     group.owner,
+				"Phase":     phase,
+				"Pods":      strconv.Itoa(len(durations)),
+			}
+			for _, p := range percentiles {
+				row[p.column] = strconv.FormatFloat(percentile(durations, p.value), 'f', 3, 64)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	schema := map[string]dataloader.DataType{
+		"GroupType": dataloader.DataTypeString,
+		"Namespace": dataloader.DataTypeString,
+		"Owner":     dataloader.DataTypeString,
+		"Phase":     dataloader.DataTypeString,
+		"Pods":      dataloader.DataTypeInteger,
+	}
+	for _, p := range percentiles {
+		schema[p.column] = dataloader.DataTypeFloat64
+	}
+
+	return dataloader.DataFile{
+		TableName: "pod_startup_latency",
+		Schema:    schema,
+		Rows:      rows,
+	}
+}
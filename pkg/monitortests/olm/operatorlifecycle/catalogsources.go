@@ -0,0 +1,138 @@
+package operatorlifecycle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// The gRPC connectivity states OLM reports for the connection to a catalog source, and the state recorded when the
+// catalog source is deleted.
+const (
+	connectionReady            = "READY"
+	connectionIdle             = "IDLE"
+	connectionTransientFailure = "TRANSIENT_FAILURE"
+	connectionDeleted          = "Deleted"
+)
+
+// connected tells whether OLM can serve content from the catalog source. An idle connection is reestablished on the
+// next request.
+func connected(state string) bool {
+	return state == connectionReady || state == connectionIdle
+}
+
+func startCatalogSourceMonitoring(ctx context.Context, m monitorapi.RecorderWriter, client dynamic.Interface) {
+	startOLMInformer(ctx, client, "catalogsources", "",
+		func(catalogSource, oldCatalogSource *unstructured.Unstructured) {
+			m.AddIntervals(catalogSourceConnectionChanges(catalogSource, oldCatalogSource, time.Now())...)
+		},
+		func(catalogSource *unstructured.Unstructured) {
+			if len(connectionState(catalogSource)) == 0 {
+				return
+			}
+			m.AddIntervals(catalogSourceConnectionChange(catalogSource, connectionDeleted, connectionState(catalogSource), "catalog source deleted", time.Now()))
+		},
+	)
+}
+
+func connectionState(catalogSource *unstructured.Unstructured) string {
+	return nestedString(catalogSource, "status", "connectionState", "lastObservedState")
+}
+
+func catalogSourceConnectionChange(catalogSource *unstructured.Unstructured, state, previousState, humanMessage string, now time.Time) monitorapi.Interval {
+	level := monitorapi.Info
+	if state == connectionTransientFailure {
+		level = monitorapi.Warning
+	}
+	mb := monitorapi.NewMessage().Reason(monitorapi.CatalogSourceConnectionChangedReason).
+		WithAnnotation(monitorapi.AnnotationStatus, state).
+		HumanMessage(humanMessage)
+	if len(previousState) > 0 {
+		mb = mb.WithAnnotation(monitorapi.AnnotationPreviousStatus, previousState)
+	}
+	return monitorapi.NewInterval(monitorapi.SourceOLMMonitor, level).
+		Locator(monitorapi.NewLocator().CatalogSource(catalogSource.GetNamespace(), catalogSource.GetName())).
+		Message(mb).
+		Build(now, now)
+}
+
+// catalogSourceConnectionChanges returns a point in time interval when the state of the connection to a gRPC catalog
+// source changed. When oldCatalogSource is nil it was observed for the first time, and an interval is only returned if
+// it is not connected. Catalog sources that are not served over gRPC have no connection state.
+func catalogSourceConnectionChanges(catalogSource, oldCatalogSource *unstructured.Unstructured, now time.Time) monitorapi.Intervals {
+	state := connectionState(catalogSource)
+	previousState := connectionState(oldCatalogSource)
+	if len(state) == 0 || state == previousState {
+		return nil
+	}
+	if oldCatalogSource == nil && connected(state) {
+		return nil
+	}
+	humanMessage := fmt.Sprintf("connection to catalog source is %s", state)
+	if address := nestedString(catalogSource, "status", "connectionState", "address"); len(address) > 0 {
+		humanMessage = fmt.Sprintf("%s at %s", humanMessage, address)
+	}
+	return monitorapi.Intervals{catalogSourceConnectionChange(catalogSource, state, previousState, humanMessage, now)}
+}
+
+// catalogSourceDisconnectedIntervals constructs an interval for every period a catalog source lost its connection,
+// from a connected state until it was connected again or deleted. Catalog sources that were never connected, as they
+// are while their pod starts, did not lose anything. A catalog source that never reconnected is disconnected until
+// end.
+func catalogSourceDisconnectedIntervals(startingIntervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	changes := startingIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceOLMMonitor &&
+			eventInterval.Message.Reason == monitorapi.CatalogSourceConnectionChangedReason
+	})
+
+	lost := map[string]monitorapi.Interval{}
+	ret := monitorapi.Intervals{}
+	closeDisconnect := func(key string, to time.Time, reconnected bool) {
+		from, ok := lost[key]
+		if !ok {
+			return
+		}
+		delete(lost, key)
+
+		humanMessage := fmt.Sprintf("catalog source lost its connection: %s", from.Message.HumanMessage)
+		if !reconnected {
+			humanMessage = fmt.Sprintf("%s, never reconnected", humanMessage)
+		}
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceOLMMonitor, monitorapi.Warning).
+				Locator(from.Locator).
+				Message(monitorapi.NewMessage().Reason(monitorapi.CatalogSourceDisconnectedReason).
+					Constructed(monitorapi.ConstructionOwnerOperatorLifecycle).
+					HumanMessage(humanMessage)).
+				Display().
+				Build(from.From, to),
+		)
+	}
+
+	for _, change := range changes {
+		key := change.Locator.OldLocator()
+		state := change.Message.Annotations[monitorapi.AnnotationStatus]
+		switch {
+		case connected(state) || state == connectionDeleted:
+			closeDisconnect(key, change.From, true)
+		case connected(change.Message.Annotations[monitorapi.AnnotationPreviousStatus]):
+			lost[key] = change
+		}
+	}
+
+	keys := make([]string, 0, len(lost))
+	for key := range lost {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		closeDisconnect(key, end, false)
+	}
+	return ret
+}
@@ -0,0 +1,92 @@
+package operatorlifecycle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const (
+	csvPhaseSucceeded = "Succeeded"
+
+	// copiedCSVLabel marks the copies OLM makes of a ClusterServiceVersion into every namespace its operator watches.
+	// The copies mirror the phase of the original and would only repeat it, with an operator installed for all
+	// namespaces there is one in every namespace. They are left out of the watch.
+	copiedCSVLabel = "olm.copiedFrom"
+)
+
+// settledCSVPhases are the phases a ClusterServiceVersion stays in once it is done installing.
+var settledCSVPhases = sets.New(csvPhaseSucceeded, phaseDeleted)
+
+func startClusterServiceVersionMonitoring(ctx context.Context, m monitorapi.RecorderWriter, client dynamic.Interface) {
+	// only the ClusterServiceVersions created while the monitor runs are replacements made during the run
+	startTime := time.Now()
+	startOLMInformer(ctx, client, "clusterserviceversions", "!"+copiedCSVLabel,
+		func(csv, oldCSV *unstructured.Unstructured) {
+			if oldCSV == nil && !csv.GetCreationTimestamp().Time.Before(startTime.Truncate(time.Second)) {
+				m.AddIntervals(clusterServiceVersionCreated(csv))
+			}
+			m.AddIntervals(clusterServiceVersionPhaseChanges(csv, oldCSV, time.Now())...)
+		},
+		func(csv *unstructured.Unstructured) {
+			m.AddIntervals(phaseChange(monitorapi.NewLocator().ClusterServiceVersion(csv.GetNamespace(), csv.GetName()),
+				monitorapi.ClusterServiceVersionPhaseChangedReason, phaseDeleted, nestedString(csv, "status", "phase"),
+				"clusterserviceversion deleted", time.Now()))
+		},
+	)
+}
+
+// clusterServiceVersionCreated returns a point in time interval at the creation of the ClusterServiceVersion, with the
+// ClusterServiceVersion it replaces when it is an upgrade.
+func clusterServiceVersionCreated(csv *unstructured.Unstructured) monitorapi.Interval {
+	created := csv.GetCreationTimestamp().Time
+	mb := monitorapi.NewMessage().Reason(monitorapi.ClusterServiceVersionCreatedReason).
+		HumanMessage("clusterserviceversion created")
+	if replaces := nestedString(csv, "spec", "replaces"); len(replaces) > 0 {
+		mb = mb.WithAnnotation(monitorapi.AnnotationReplaces, replaces).
+			HumanMessagef("clusterserviceversion created to replace %s", replaces)
+	}
+	return monitorapi.NewInterval(monitorapi.SourceOLMMonitor, monitorapi.Info).
+		Locator(monitorapi.NewLocator().ClusterServiceVersion(csv.GetNamespace(), csv.GetName())).
+		Message(mb).
+		Build(created, created)
+}
+
+// clusterServiceVersionPhaseChanges returns a point in time interval when the ClusterServiceVersion moved to another
+// phase. When oldCSV is nil it was observed for the first time, and an interval is only returned if it is still
+// installing or failed.
+func clusterServiceVersionPhaseChanges(csv, oldCSV *unstructured.Unstructured, now time.Time) monitorapi.Intervals {
+	phase := nestedString(csv, "status", "phase")
+	previousPhase := nestedString(oldCSV, "status", "phase")
+	if len(phase) == 0 || phase == previousPhase {
+		return nil
+	}
+	if oldCSV == nil && settledCSVPhases.Has(phase) {
+		return nil
+	}
+
+	humanMessage := phase
+	if reason := nestedString(csv, "status", "reason"); len(reason) > 0 {
+		humanMessage = fmt.Sprintf("%s %s", humanMessage, reason)
+	}
+	if message := nestedString(csv, "status", "message"); len(message) > 0 {
+		humanMessage = fmt.Sprintf("%s: %s", humanMessage, message)
+	}
+	return monitorapi.Intervals{
+		phaseChange(monitorapi.NewLocator().ClusterServiceVersion(csv.GetNamespace(), csv.GetName()),
+			monitorapi.ClusterServiceVersionPhaseChangedReason, phase, previousPhase, humanMessage, now),
+	}
+}
+
+// clusterServiceVersionPhaseIntervals constructs an interval for every period a ClusterServiceVersion spent installing,
+// failing or being replaced.
+func clusterServiceVersionPhaseIntervals(startingIntervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	return phaseIntervals(startingIntervals, monitorapi.ClusterServiceVersionPhaseChangedReason, monitorapi.ClusterServiceVersionPhaseReason,
+		settledCSVPhases, end)
+}
@@ -0,0 +1,71 @@
+package operatorlifecycle
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// there are no vendored clients for the OLM APIs, the resources are watched as unstructured objects.
+func olmResource(resource string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1alpha1", Resource: resource}
+}
+
+// startOLMInformer watches the objects of the resource matching labelSelector in every namespace, all of them when it
+// is empty. onChange is called with a nil oldObj when an object is first observed.
+func startOLMInformer(ctx context.Context, client dynamic.Interface, resource, labelSelector string,
+	onChange func(obj, oldObj *unstructured.Unstructured), onDelete func(obj *unstructured.Unstructured)) {
+	informer := dynamicinformer.NewFilteredDynamicInformer(client, olmResource(resource), metav1.NamespaceAll, time.Hour, cache.Indexers{},
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = labelSelector
+		}).Informer()
+	informer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				u, ok := obj.(*unstructured.Unstructured)
+				if !ok {
+					return
+				}
+				onChange(u, nil)
+			},
+			UpdateFunc: func(old, obj interface{}) {
+				u, ok := obj.(*unstructured.Unstructured)
+				if !ok {
+					return
+				}
+				oldU, ok := old.(*unstructured.Unstructured)
+				if !ok {
+					return
+				}
+				onChange(u, oldU)
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				u, ok := obj.(*unstructured.Unstructured)
+				if !ok {
+					return
+				}
+				onDelete(u)
+			},
+		},
+	)
+
+	go informer.Run(ctx.Done())
+}
+
+// nestedString returns the string at fields of obj, or an empty string when it is not set.
+func nestedString(obj *unstructured.Unstructured, fields ...string) string {
+	if obj == nil {
+		return ""
+	}
+	value, _, _ := unstructured.NestedString(obj.Object, fields...)
+	return value
+}
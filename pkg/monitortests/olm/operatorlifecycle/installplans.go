@@ -0,0 +1,89 @@
+package operatorlifecycle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// settledInstallPlanPhases are the phases an InstallPlan stays in once it is done. A Failed InstallPlan is never
+// retried, but it is charted until it is deleted so that the failure stands out.
+var settledInstallPlanPhases = sets.New("Complete", phaseDeleted)
+
+func startInstallPlanMonitoring(ctx context.Context, m monitorapi.RecorderWriter, client dynamic.Interface) {
+	startOLMInformer(ctx, client, "installplans", "",
+		func(installPlan, oldInstallPlan *unstructured.Unstructured) {
+			m.AddIntervals(installPlanChanges(installPlan, oldInstallPlan, time.Now())...)
+		},
+		func(installPlan *unstructured.Unstructured) {
+			m.AddIntervals(phaseChange(monitorapi.NewLocator().InstallPlan(installPlan.GetNamespace(), installPlan.GetName()),
+				monitorapi.InstallPlanPhaseChangedReason, phaseDeleted, nestedString(installPlan, "status", "phase"),
+				"installplan deleted", time.Now()))
+		},
+	)
+}
+
+// installPlanChanges returns a point in time interval when the InstallPlan moved to another phase, and when it was
+// approved. When oldInstallPlan is nil it was observed for the first time and its phase is only returned if it is not
+// done yet. Automatic approvals are set when the InstallPlan is created, only manual approvals are returned.
+func installPlanChanges(installPlan, oldInstallPlan *unstructured.Unstructured, now time.Time) monitorapi.Intervals {
+	locator := monitorapi.NewLocator().InstallPlan(installPlan.GetNamespace(), installPlan.GetName())
+	var intervals monitorapi.Intervals
+
+	phase := nestedString(installPlan, "status", "phase")
+	previousPhase := nestedString(oldInstallPlan, "status", "phase")
+	if len(phase) > 0 && phase != previousPhase && (oldInstallPlan != nil || !settledInstallPlanPhases.Has(phase)) {
+		humanMessage := phase
+		if phase == phaseFailed {
+			if message := installPlanFailure(installPlan); len(message) > 0 {
+				humanMessage = fmt.Sprintf("%s: %s", humanMessage, message)
+			}
+		}
+		intervals = append(intervals,
+			phaseChange(locator, monitorapi.InstallPlanPhaseChangedReason, phase, previousPhase, humanMessage, now))
+	}
+
+	if oldInstallPlan != nil {
+		approved, _, _ := unstructured.NestedBool(installPlan.Object, "spec", "approved")
+		previouslyApproved, _, _ := unstructured.NestedBool(oldInstallPlan.Object, "spec", "approved")
+		if approved && !previouslyApproved {
+			csvNames, _, _ := unstructured.NestedStringSlice(installPlan.Object, "spec", "clusterServiceVersionNames")
+			intervals = append(intervals,
+				monitorapi.NewInterval(monitorapi.SourceOLMMonitor, monitorapi.Info).
+					Locator(locator).
+					Message(monitorapi.NewMessage().Reason(monitorapi.InstallPlanApprovedReason).
+						HumanMessagef("installplan approved to install %s", strings.Join(csvNames, ", "))).
+					Build(now, now))
+		}
+	}
+	return intervals
+}
+
+// installPlanFailure returns the message of the Installed condition, which is where OLM explains why an InstallPlan
+// failed.
+func installPlanFailure(installPlan *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(installPlan.Object, "status", "conditions")
+	for _, condition := range conditions {
+		condition, ok := condition.(map[string]interface{})
+		if !ok || condition["type"] != "Installed" {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		return message
+	}
+	return ""
+}
+
+// installPlanPhaseIntervals constructs an interval for every period an InstallPlan spent planning, waiting for
+// approval, installing or failed.
+func installPlanPhaseIntervals(startingIntervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	return phaseIntervals(startingIntervals, monitorapi.InstallPlanPhaseChangedReason, monitorapi.InstallPlanPhaseReason,
+		settledInstallPlanPhases, end)
+}
@@ -0,0 +1,239 @@
+package operatorlifecycle
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const testNamespace = "openshift-operators"
+
+func newOLMObject(kind, name string, spec, status map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "operators.coreos.com/v1alpha1",
+		"kind":       kind,
+		"spec":       spec,
+		"status":     status,
+	}}
+	obj.SetNamespace(testNamespace)
+	obj.SetName(name)
+	return obj
+}
+
+func newCSV(name, phase string) *unstructured.Unstructured {
+	return newOLMObject("ClusterServiceVersion", name, map[string]interface{}{}, map[string]interface{}{"phase": phase})
+}
+
+// csvLifecycle records the ClusterServiceVersion moving through phases, a minute apart starting at from.
+func csvLifecycle(name string, from time.Time, phases ...string) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	var previous *unstructured.Unstructured
+	for i, phase := range phases {
+		csv := newCSV(name, phase)
+		intervals = append(intervals, clusterServiceVersionPhaseChanges(csv, previous, from.Add(time.Duration(i)*time.Minute))...)
+		previous = csv
+	}
+	return intervals
+}
+
+func TestClusterServiceVersionPhaseIntervals(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := from.Add(time.Hour)
+
+	if changes := clusterServiceVersionPhaseChanges(newCSV("installed", "Succeeded"), nil, from); len(changes) != 0 {
+		t.Errorf("expected no changes for an installed clusterserviceversion, got %d", len(changes))
+	}
+	if changes := clusterServiceVersionPhaseChanges(newCSV("installing", "Installing"), newCSV("installing", "Installing"), from); len(changes) != 0 {
+		t.Errorf("expected no changes when the phase did not change, got %d", len(changes))
+	}
+
+	intervals := monitorapi.Intervals{}
+	intervals = append(intervals, csvLifecycle("etcd.v0.9.4", from, "Pending", "InstallReady", "Installing", "Succeeded")...)
+	intervals = append(intervals, csvLifecycle("broken.v1.0.0", from.Add(10*time.Minute), "Pending", "Failed")...)
+	intervals = append(intervals, csvLifecycle("stuck.v1.0.0", from.Add(50*time.Minute), "Installing")...)
+
+	constructed := clusterServiceVersionPhaseIntervals(intervals, end)
+	expected := []struct {
+		csv, phase string
+		level      monitorapi.IntervalLevel
+		from, to   time.Time
+	}{
+		{"etcd.v0.9.4", "Pending", monitorapi.Info, from, from.Add(time.Minute)},
+		{"etcd.v0.9.4", "InstallReady", monitorapi.Info, from.Add(time.Minute), from.Add(2 * time.Minute)},
+		{"etcd.v0.9.4", "Installing", monitorapi.Info, from.Add(2 * time.Minute), from.Add(3 * time.Minute)},
+		{"broken.v1.0.0", "Pending", monitorapi.Info, from.Add(10 * time.Minute), from.Add(11 * time.Minute)},
+		{"broken.v1.0.0", "Failed", monitorapi.Error, from.Add(11 * time.Minute), end},
+		{"stuck.v1.0.0", "Installing", monitorapi.Warning, from.Add(50 * time.Minute), end},
+	}
+	if len(constructed) != len(expected) {
+		t.Fatalf("expected %d intervals, got %d: %v", len(expected), len(constructed), constructed)
+	}
+	for i, e := range expected {
+		actual := constructed[i]
+		if actual.Locator.Keys[monitorapi.LocatorClusterServiceVersionKey] != e.csv ||
+			actual.Message.Annotations[monitorapi.AnnotationPhase] != e.phase ||
+			actual.Level != e.level || !actual.From.Equal(e.from) || !actual.To.Equal(e.to) {
+			t.Errorf("interval %d: expected %s %s %s from %s to %s, got %s", i, e.level, e.csv, e.phase, e.from, e.to, actual.String())
+		}
+	}
+}
+
+func TestClusterServiceVersionCreated(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	csv := newCSV("etcd.v0.9.4", "Pending")
+	csv.SetCreationTimestamp(metav1.Time{Time: created})
+	if interval := clusterServiceVersionCreated(csv); len(interval.Message.Annotations[monitorapi.AnnotationReplaces]) != 0 {
+		t.Errorf("expected a fresh install to replace nothing, got %s", interval.String())
+	}
+
+	csv.Object["spec"] = map[string]interface{}{"replaces": "etcd.v0.9.2"}
+	interval := clusterServiceVersionCreated(csv)
+	if interval.Message.Annotations[monitorapi.AnnotationReplaces] != "etcd.v0.9.2" || !interval.From.Equal(created) {
+		t.Errorf("expected etcd.v0.9.4 to replace etcd.v0.9.2 at its creation, got %s", interval.String())
+	}
+}
+
+func newInstallPlan(phase string, approved bool) *unstructured.Unstructured {
+	return newOLMObject("InstallPlan", "install-abcde",
+		map[string]interface{}{
+			"approval":                   "Manual",
+			"approved":                   approved,
+			"clusterServiceVersionNames": []interface{}{"etcd.v0.9.4"},
+		},
+		map[string]interface{}{"phase": phase})
+}
+
+func TestInstallPlanChanges(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	failed := newInstallPlan("Failed", true)
+	failed.Object["status"].(map[string]interface{})["conditions"] = []interface{}{
+		map[string]interface{}{"type": "Installed", "status": "False", "message": "error creating csv etcd.v0.9.4"},
+	}
+
+	tests := []struct {
+		name                 string
+		installPlan, old     *unstructured.Unstructured
+		expectedReasons      []monitorapi.IntervalReason
+		expectedHumanMessage string
+	}{
+		{
+			name:        "complete when first seen",
+			installPlan: newInstallPlan("Complete", true),
+		},
+		{
+			name:            "waiting for approval when first seen",
+			installPlan:     newInstallPlan("RequiresApproval", false),
+			expectedReasons: []monitorapi.IntervalReason{monitorapi.InstallPlanPhaseChangedReason},
+		},
+		{
+			name:                 "approved",
+			installPlan:          newInstallPlan("RequiresApproval", true),
+			old:                  newInstallPlan("RequiresApproval", false),
+			expectedReasons:      []monitorapi.IntervalReason{monitorapi.InstallPlanApprovedReason},
+			expectedHumanMessage: "installplan approved to install etcd.v0.9.4",
+		},
+		{
+			name:            "approved and installing",
+			installPlan:     newInstallPlan("Installing", true),
+			old:             newInstallPlan("RequiresApproval", false),
+			expectedReasons: []monitorapi.IntervalReason{monitorapi.InstallPlanPhaseChangedReason, monitorapi.InstallPlanApprovedReason},
+		},
+		{
+			name:                 "failed",
+			installPlan:          failed,
+			old:                  newInstallPlan("Installing", true),
+			expectedReasons:      []monitorapi.IntervalReason{monitorapi.InstallPlanPhaseChangedReason},
+			expectedHumanMessage: "Failed: error creating csv etcd.v0.9.4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := installPlanChanges(tt.installPlan, tt.old, now)
+			if len(changes) != len(tt.expectedReasons) {
+				t.Fatalf("expected %d changes, got %d: %v", len(tt.expectedReasons), len(changes), changes)
+			}
+			for i, reason := range tt.expectedReasons {
+				if changes[i].Message.Reason != reason {
+					t.Errorf("change %d: expected %s, got %s", i, reason, changes[i].Message.Reason)
+				}
+			}
+			if len(tt.expectedHumanMessage) > 0 && changes[0].Message.HumanMessage != tt.expectedHumanMessage {
+				t.Errorf("expected %q, got %q", tt.expectedHumanMessage, changes[0].Message.HumanMessage)
+			}
+		})
+	}
+}
+
+func newCatalogSource(state string) *unstructured.Unstructured {
+	return newOLMObject("CatalogSource", "redhat-operators", map[string]interface{}{"sourceType": "grpc"},
+		map[string]interface{}{"connectionState": map[string]interface{}{
+			"address":           "redhat-operators.openshift-marketplace.svc:50051",
+			"lastObservedState": state,
+		}})
+}
+
+// catalogSourceLifecycle records the connection to the catalog source moving through states, a minute apart starting
+// at from.
+func catalogSourceLifecycle(from time.Time, states ...string) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	var previous *unstructured.Unstructured
+	for i, state := range states {
+		catalogSource := newCatalogSource(state)
+		intervals = append(intervals, catalogSourceConnectionChanges(catalogSource, previous, from.Add(time.Duration(i)*time.Minute))...)
+		previous = catalogSource
+	}
+	return intervals
+}
+
+func TestCatalogSourceDisconnectedIntervals(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := from.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		intervals monitorapi.Intervals
+		expected  [][2]time.Time
+	}{
+		{
+			name:      "connected when first seen",
+			intervals: catalogSourceLifecycle(from, "READY", "IDLE", "READY"),
+		},
+		{
+			name:      "connecting for the first time",
+			intervals: catalogSourceLifecycle(from, "CONNECTING", "READY"),
+		},
+		{
+			name:      "lost and regained the connection",
+			intervals: catalogSourceLifecycle(from, "READY", "TRANSIENT_FAILURE", "CONNECTING", "READY"),
+			expected:  [][2]time.Time{{from.Add(time.Minute), from.Add(3 * time.Minute)}},
+		},
+		{
+			name:      "never reconnected",
+			intervals: catalogSourceLifecycle(from, "READY", "TRANSIENT_FAILURE"),
+			expected:  [][2]time.Time{{from.Add(time.Minute), end}},
+		},
+		{
+			name: "deleted while disconnected",
+			intervals: append(catalogSourceLifecycle(from, "READY", "TRANSIENT_FAILURE"),
+				catalogSourceConnectionChange(newCatalogSource("TRANSIENT_FAILURE"), connectionDeleted, "TRANSIENT_FAILURE", "catalog source deleted", from.Add(5*time.Minute))),
+			expected: [][2]time.Time{{from.Add(time.Minute), from.Add(5 * time.Minute)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			constructed := catalogSourceDisconnectedIntervals(tt.intervals, end)
+			if len(constructed) != len(tt.expected) {
+				t.Fatalf("expected %d intervals, got %d: %v", len(tt.expected), len(constructed), constructed)
+			}
+			for i, e := range tt.expected {
+				if !constructed[i].From.Equal(e[0]) || !constructed[i].To.Equal(e[1]) {
+					t.Errorf("interval %d: expected %s to %s, got %s", i, e[0], e[1], constructed[i].String())
+				}
+			}
+		})
+	}
+}
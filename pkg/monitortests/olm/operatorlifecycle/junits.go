@@ -0,0 +1,167 @@
+package operatorlifecycle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	csvFailedTestName         = "[sig-operator] OLM ClusterServiceVersions should not fail to install or upgrade"
+	csvReplacedTestName       = "[sig-operator] OLM ClusterServiceVersions should not be replaced repeatedly"
+	catalogDisconnectTestName = "[sig-operator] OLM catalog sources should not lose their connection"
+
+	// maxCSVReplacements is how many times an operator may be upgraded during a run. Upgrade jobs can move an operator
+	// along its channel a step or two, more than that means OLM keeps resolving new replacements.
+	maxCSVReplacements = 2
+
+	// catalogDisconnectLimit is how long a catalog source may stay disconnected. Its pod is rescheduled when its node
+	// drains, and pulling a large index image onto the new node can take a few minutes.
+	catalogDisconnectLimit = 10 * time.Minute
+)
+
+// outsideE2ENamespaces keeps the OLM intervals of the operators that make up the cluster. The OLM e2e tests install
+// operators of their own, and some of them are meant to fail.
+func outsideE2ENamespaces(reason monitorapi.IntervalReason) monitorapi.EventIntervalMatchesFunc {
+	return func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceOLMMonitor &&
+			eventInterval.Message.Reason == reason &&
+			!monitorapi.IsInE2ENamespace(eventInterval)
+	}
+}
+
+func failedJUnit(name string, failures []string) *junitapi.JUnitTestCase {
+	return &junitapi.JUnitTestCase{
+		Name: name,
+		FailureOutput: &junitapi.FailureOutput{
+			Output: strings.Join(failures, "\n"),
+		},
+		SystemOut: strings.Join(failures, "\n"),
+	}
+}
+
+// csvFailedJUnits fails when a ClusterServiceVersion was still Failed at the end of the run, and flakes when it failed
+// and recovered. No junit is returned when no ClusterServiceVersion changed phase.
+func csvFailedJUnits(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	changes := finalIntervals.Filter(outsideE2ENamespaces(monitorapi.ClusterServiceVersionPhaseChangedReason))
+	if len(changes) == 0 {
+		return nil
+	}
+
+	lastPhases := map[string]string{}
+	failures := map[string]monitorapi.Interval{}
+	for _, change := range changes {
+		key := change.Locator.OldLocator()
+		lastPhases[key] = change.Message.Annotations[monitorapi.AnnotationPhase]
+		if lastPhases[key] == phaseFailed {
+			failures[key] = change
+		}
+	}
+
+	keys := make([]string, 0, len(failures))
+	for key := range failures {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var stillFailed, recovered []string
+	for _, key := range keys {
+		failure := failures[key]
+		csv := fmt.Sprintf("clusterserviceversion/%s -n %s",
+			failure.Locator.Keys[monitorapi.LocatorClusterServiceVersionKey], failure.Locator.Keys[monitorapi.LocatorNamespaceKey])
+		if lastPhases[key] == phaseFailed {
+			stillFailed = append(stillFailed, fmt.Sprintf("%s is still failed: %s", csv, failure.String()))
+			continue
+		}
+		recovered = append(recovered, fmt.Sprintf("%s failed and recovered: %s", csv, failure.String()))
+	}
+
+	switch {
+	case len(stillFailed) > 0:
+		return []*junitapi.JUnitTestCase{failedJUnit(csvFailedTestName, append(stillFailed, recovered...))}
+	case len(recovered) > 0:
+		// OLM retries the install, a failure it recovers from is reported as a flake
+		return []*junitapi.JUnitTestCase{failedJUnit(csvFailedTestName, recovered), {Name: csvFailedTestName}}
+	}
+	return []*junitapi.JUnitTestCase{{Name: csvFailedTestName}}
+}
+
+// csvReplacedJUnits fails when an operator was upgraded more than maxCSVReplacements times, following the chain of
+// ClusterServiceVersions created during the run through the one each replaces. No junit is returned when no
+// ClusterServiceVersion replaced another.
+func csvReplacedJUnits(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	created := finalIntervals.Filter(outsideE2ENamespaces(monitorapi.ClusterServiceVersionCreatedReason))
+
+	// ClusterServiceVersion names are only unique within their namespace
+	replaces := map[string]string{}
+	var replacements monitorapi.Intervals
+	for _, csv := range created {
+		replaced := csv.Message.Annotations[monitorapi.AnnotationReplaces]
+		if len(replaced) == 0 {
+			continue
+		}
+		namespace := csv.Locator.Keys[monitorapi.LocatorNamespaceKey]
+		replaces[namespace+"/"+csv.Locator.Keys[monitorapi.LocatorClusterServiceVersionKey]] = namespace + "/" + replaced
+		replacements = append(replacements, csv)
+	}
+	if len(replacements) == 0 {
+		return nil
+	}
+
+	// the chain of an operator starts at the ClusterServiceVersion that was installed before the run
+	chains := map[string][]string{}
+	for _, csv := range replacements {
+		name := csv.Locator.Keys[monitorapi.LocatorNamespaceKey] + "/" + csv.Locator.Keys[monitorapi.LocatorClusterServiceVersionKey]
+		root := replaces[name]
+		// a chain cannot be longer than the replacements, this only guards against a cycle
+		for steps := 0; len(replaces[root]) > 0 && steps < len(replacements); steps++ {
+			root = replaces[root]
+		}
+		chains[root] = append(chains[root], name)
+	}
+
+	roots := make([]string, 0, len(chains))
+	for root := range chains {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+	var failures []string
+	for _, root := range roots {
+		chain := chains[root]
+		if len(chain) <= maxCSVReplacements {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("clusterserviceversion %s was replaced %d times, more than %d: %s -> %s",
+			root, len(chain), maxCSVReplacements, root, strings.Join(chain, " -> ")))
+	}
+	if len(failures) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: csvReplacedTestName}}
+	}
+	return []*junitapi.JUnitTestCase{failedJUnit(csvReplacedTestName, failures)}
+}
+
+// catalogDisconnectJUnits fails when a catalog source stayed disconnected for longer than catalogDisconnectLimit. No
+// junit is returned when no catalog source connection changed state.
+func catalogDisconnectJUnits(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	if len(finalIntervals.Filter(outsideE2ENamespaces(monitorapi.CatalogSourceConnectionChangedReason))) == 0 {
+		return nil
+	}
+
+	var failures []string
+	for _, disconnect := range finalIntervals.Filter(outsideE2ENamespaces(monitorapi.CatalogSourceDisconnectedReason)) {
+		duration := disconnect.To.Sub(disconnect.From)
+		if duration <= catalogDisconnectLimit {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("catalogsource/%s -n %s was disconnected for %s, longer than %s: %s",
+			disconnect.Locator.Keys[monitorapi.LocatorCatalogSourceKey], disconnect.Locator.Keys[monitorapi.LocatorNamespaceKey],
+			duration.Round(time.Second), catalogDisconnectLimit, disconnect.String()))
+	}
+	if len(failures) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: catalogDisconnectTestName}}
+	}
+	return []*junitapi.JUnitTestCase{failedJUnit(catalogDisconnectTestName, failures)}
+}
@@ -0,0 +1,153 @@
+package operatorlifecycle
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junittest"
+)
+
+func TestCSVFailedJUnits(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	inE2ENamespace := func(intervals monitorapi.Intervals) monitorapi.Intervals {
+		for i := range intervals {
+			intervals[i].Locator = monitorapi.NewLocator().ClusterServiceVersion("e2e-test-olm", "broken.v1.0.0")
+		}
+		return intervals
+	}
+
+	tests := []struct {
+		name          string
+		intervals     monitorapi.Intervals
+		count         int
+		expectFailure string
+		expectPass    bool
+	}{
+		{
+			name: "no phase changes",
+		},
+		{
+			name:       "installed",
+			intervals:  csvLifecycle("etcd.v0.9.4", from, "Pending", "Installing", "Succeeded"),
+			count:      1,
+			expectPass: true,
+		},
+		{
+			name:      "failed in an e2e namespace",
+			intervals: inE2ENamespace(csvLifecycle("broken.v1.0.0", from, "Pending", "Failed")),
+		},
+		{
+			name:          "failed and recovered",
+			intervals:     csvLifecycle("etcd.v0.9.4", from, "Installing", "Failed", "Pending", "Succeeded"),
+			count:         2,
+			expectFailure: "clusterserviceversion/etcd.v0.9.4 -n openshift-operators failed and recovered",
+			expectPass:    true,
+		},
+		{
+			name:          "still failed",
+			intervals:     csvLifecycle("etcd.v0.9.4", from, "Installing", "Failed"),
+			count:         1,
+			expectFailure: "clusterserviceversion/etcd.v0.9.4 -n openshift-operators is still failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			junittest.ExpectJUnits(t, csvFailedJUnits(tt.intervals), csvFailedTestName, tt.count, tt.expectFailure, tt.expectPass)
+		})
+	}
+}
+
+func TestCSVReplacedJUnits(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	// upgrades records every version replacing the one before it, a minute apart
+	upgrades := func(versions ...string) monitorapi.Intervals {
+		var intervals monitorapi.Intervals
+		for i := 1; i < len(versions); i++ {
+			csv := newCSV(versions[i], "Pending")
+			csv.SetCreationTimestamp(metav1.Time{Time: from.Add(time.Duration(i) * time.Minute)})
+			csv.Object["spec"] = map[string]interface{}{"replaces": versions[i-1]}
+			intervals = append(intervals, clusterServiceVersionCreated(csv))
+		}
+		return intervals
+	}
+
+	tests := []struct {
+		name          string
+		intervals     monitorapi.Intervals
+		count         int
+		expectFailure string
+	}{
+		{
+			name:      "fresh install",
+			intervals: monitorapi.Intervals{clusterServiceVersionCreated(newCSV("etcd.v0.9.4", "Pending"))},
+		},
+		{
+			name:      "upgraded twice",
+			intervals: upgrades("etcd.v0.9.2", "etcd.v0.9.3", "etcd.v0.9.4"),
+			count:     1,
+		},
+		{
+			name:      "two operators upgraded twice",
+			intervals: append(upgrades("etcd.v0.9.2", "etcd.v0.9.3", "etcd.v0.9.4"), upgrades("prometheus.v1", "prometheus.v2", "prometheus.v3")...),
+			count:     1,
+		},
+		{
+			name:          "upgraded three times",
+			intervals:     upgrades("etcd.v0.9.1", "etcd.v0.9.2", "etcd.v0.9.3", "etcd.v0.9.4"),
+			count:         1,
+			expectFailure: "clusterserviceversion openshift-operators/etcd.v0.9.1 was replaced 3 times, more than 2: openshift-operators/etcd.v0.9.1 -> openshift-operators/etcd.v0.9.2 -> openshift-operators/etcd.v0.9.3 -> openshift-operators/etcd.v0.9.4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			junittest.ExpectJUnits(t, csvReplacedJUnits(tt.intervals), csvReplacedTestName, tt.count, tt.expectFailure, len(tt.expectFailure) == 0)
+		})
+	}
+}
+
+func TestCatalogDisconnectJUnits(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := from.Add(time.Hour)
+	// disconnectedFor records the catalog source losing its connection at from and regaining it after duration
+	disconnectedFor := func(duration time.Duration) monitorapi.Intervals {
+		intervals := catalogSourceLifecycle(from, "READY", "TRANSIENT_FAILURE")
+		return append(intervals, catalogSourceConnectionChanges(newCatalogSource("READY"), newCatalogSource("TRANSIENT_FAILURE"), from.Add(time.Minute+duration))...)
+	}
+
+	tests := []struct {
+		name          string
+		intervals     monitorapi.Intervals
+		count         int
+		expectFailure string
+	}{
+		{
+			name: "no connection changes",
+		},
+		{
+			name:      "briefly disconnected",
+			intervals: disconnectedFor(3 * time.Minute),
+			count:     1,
+		},
+		{
+			name:          "disconnected for too long",
+			intervals:     disconnectedFor(20 * time.Minute),
+			count:         1,
+			expectFailure: "catalogsource/redhat-operators -n openshift-operators was disconnected for 20m0s, longer than 10m0s",
+		},
+		{
+			name:          "never reconnected",
+			intervals:     catalogSourceLifecycle(from, "READY", "TRANSIENT_FAILURE"),
+			count:         1,
+			expectFailure: "catalogsource/redhat-operators -n openshift-operators was disconnected for 59m0s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finalIntervals := append(tt.intervals, catalogSourceDisconnectedIntervals(tt.intervals, end)...)
+			junittest.ExpectJUnits(t, catalogDisconnectJUnits(finalIntervals), catalogDisconnectTestName, tt.count, tt.expectFailure, len(tt.expectFailure) == 0)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package operatorlifecycle
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+// operatorLifecycleWatcher charts how OLM installs and upgrades operators: the phases of their ClusterServiceVersions
+// and InstallPlans, and the connection to the catalog sources they are resolved from.
+type operatorLifecycleWatcher struct {
+	notSupportedReason error
+}
+
+func NewOperatorLifecycleWatcher() monitortestframework.MonitorTest {
+	return &operatorLifecycleWatcher{}
+}
+
+func (w *operatorLifecycleWatcher) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *operatorLifecycleWatcher) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	// OLM is an optional capability
+	olmAvailable, err := exutil.DoesApiResourceExist(adminRESTConfig, "clusterserviceversions", "operators.coreos.com")
+	if err != nil {
+		return err
+	}
+	if !olmAvailable {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "cluster has no operator lifecycle manager"}
+		return w.notSupportedReason
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	startClusterServiceVersionMonitoring(ctx, recorder, dynamicClient)
+	startInstallPlanMonitoring(ctx, recorder, dynamicClient)
+	startCatalogSourceMonitoring(ctx, recorder, dynamicClient)
+	return nil
+}
+
+func (w *operatorLifecycleWatcher) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	// every CSV, install plan and catalog source change was recorded by its watch when it happened
+	return nil, nil, w.notSupportedReason
+}
+
+func (w *operatorLifecycleWatcher) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	constructedIntervals := monitorapi.Intervals{}
+	constructedIntervals = append(constructedIntervals, clusterServiceVersionPhaseIntervals(startingIntervals, end)...)
+	constructedIntervals = append(constructedIntervals, installPlanPhaseIntervals(startingIntervals, end)...)
+	constructedIntervals = append(constructedIntervals, catalogSourceDisconnectedIntervals(startingIntervals, end)...)
+	return constructedIntervals, nil
+}
+
+func (w *operatorLifecycleWatcher) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	junits := []*junitapi.JUnitTestCase{}
+	junits = append(junits, csvFailedJUnits(finalIntervals)...)
+	junits = append(junits, csvReplacedJUnits(finalIntervals)...)
+	junits = append(junits, catalogDisconnectJUnits(finalIntervals)...)
+	return junits, nil
+}
+
+func (w *operatorLifecycleWatcher) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *operatorLifecycleWatcher) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
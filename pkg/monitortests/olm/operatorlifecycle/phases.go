@@ -0,0 +1,96 @@
+package operatorlifecycle
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const (
+	// phaseFailed is the phase a ClusterServiceVersion or InstallPlan ends up in when OLM gives up on it.
+	phaseFailed = "Failed"
+	// phaseDeleted is not an OLM phase, it is recorded when the object is deleted so that its last phase is closed.
+	phaseDeleted = "Deleted"
+)
+
+// phaseChange returns a point in time interval for an object that moved to phase. previousPhase is empty when the
+// object was observed for the first time.
+func phaseChange(locator monitorapi.Locator, reason monitorapi.IntervalReason, phase, previousPhase, humanMessage string, now time.Time) monitorapi.Interval {
+	level := monitorapi.Info
+	if phase == phaseFailed {
+		level = monitorapi.Error
+	}
+	mb := monitorapi.NewMessage().Reason(reason).
+		WithAnnotation(monitorapi.AnnotationPhase, phase).
+		HumanMessage(humanMessage)
+	if len(previousPhase) > 0 {
+		mb = mb.WithAnnotation(monitorapi.AnnotationPreviousPhase, previousPhase)
+	}
+	return monitorapi.NewInterval(monitorapi.SourceOLMMonitor, level).
+		Locator(locator).
+		Message(mb).
+		Build(now, now)
+}
+
+// phaseIntervals constructs a constructedReason interval for every period an object spent in a phase that is not
+// settled, from the changedReason intervals recorded for it. An object that was still installing, or still failed,
+// when the run ended is charted in that phase until end.
+func phaseIntervals(startingIntervals monitorapi.Intervals, changedReason, constructedReason monitorapi.IntervalReason, settled sets.Set[string], end time.Time) monitorapi.Intervals {
+	changes := startingIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceOLMMonitor &&
+			eventInterval.Message.Reason == changedReason
+	})
+
+	opened := map[string]monitorapi.Interval{}
+	ret := monitorapi.Intervals{}
+	closePhase := func(key string, to time.Time, completed bool) {
+		from, ok := opened[key]
+		if !ok {
+			return
+		}
+		delete(opened, key)
+
+		phase := from.Message.Annotations[monitorapi.AnnotationPhase]
+		level := monitorapi.Info
+		humanMessage := from.Message.HumanMessage
+		switch {
+		case phase == phaseFailed:
+			level = monitorapi.Error
+		case !completed:
+			level = monitorapi.Warning
+			humanMessage = fmt.Sprintf("%s, never completed", humanMessage)
+		}
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceOLMMonitor, level).
+				Locator(from.Locator).
+				Message(monitorapi.NewMessage().Reason(constructedReason).
+					Constructed(monitorapi.ConstructionOwnerOperatorLifecycle).
+					WithAnnotation(monitorapi.AnnotationPhase, phase).
+					HumanMessage(humanMessage)).
+				Display().
+				Build(from.From, to),
+		)
+	}
+
+	for _, change := range changes {
+		key := change.Locator.OldLocator()
+		closePhase(key, change.From, true)
+		if !settled.Has(change.Message.Annotations[monitorapi.AnnotationPhase]) {
+			opened[key] = change
+		}
+	}
+
+	keys := make([]string, 0, len(opened))
+	for key := range opened {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		closePhase(key, end, false)
+	}
+	return ret
+}
@@ -2,40 +2,27 @@ package legacystoragemonitortests
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 )
 
-type throttlingMessage struct {
-	cloud   string
-	message *regexp.Regexp
-}
-
-var throttlingMessages = []*regexp.Regexp{
-	// GCE
-	regexp.MustCompile("googleapi: Error 403: Quota exceeded"),
-	// AWS
-	regexp.MustCompile("RequestLimitExceeded"),
-}
-
+// testAPIQuotaEvents is a deprecated alias of the per component "cloud API calls from ... should not be throttled"
+// tests of the cloud-api-throttling monitor test, kept so the history of the test name continues. It no longer matches
+// event messages itself, it flakes when the cloud-api-throttling monitor test found any throttled call.
 func testAPIQuotaEvents(events monitorapi.Intervals) []*junitapi.JUnitTestCase {
 	const testName = "[sig-arch] cloud API quota should not be exceeded"
 
 	var matches []string
-	for i := range events {
-		event := events[i]
-		for _, msg := range throttlingMessages {
-			if msg.MatchString(event.Message.HumanMessage) {
-				matches = append(matches, event.Message.HumanMessage)
-			}
+	for _, event := range events {
+		if event.Source == monitorapi.SourceCloudThrottling && event.Message.Reason == monitorapi.CloudAPIThrottledReason {
+			matches = append(matches, event.Message.HumanMessage)
 		}
 	}
 
 	if len(matches) > 0 {
-		output := fmt.Sprintf("Underlying cloud was rate limiting OCP's API calls:\n%s", strings.Join(matches, "\n"))
+		output := fmt.Sprintf("Underlying cloud was rate limiting OCP's API calls, see the cloud API throttling tests per component:\n%s", strings.Join(matches, "\n"))
 		return []*junitapi.JUnitTestCase{
 			{
 				Name: testName,
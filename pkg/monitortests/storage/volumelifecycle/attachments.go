@@ -0,0 +1,203 @@
+package volumelifecycle
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	storagev1 "k8s.io/api/storage/v1"
+	informerstoragev1 "k8s.io/client-go/informers/storage/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+func startAttachmentMonitoring(ctx context.Context, m monitorapi.RecorderWriter, client kubernetes.Interface) {
+	attachmentInformer := informerstoragev1.NewVolumeAttachmentInformer(client, time.Hour, nil)
+	attachmentInformer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				attachment, ok := obj.(*storagev1.VolumeAttachment)
+				if !ok {
+					return
+				}
+				m.AddIntervals(attachmentChanges(attachment, nil, time.Now())...)
+			},
+			UpdateFunc: func(old, obj interface{}) {
+				attachment, ok := obj.(*storagev1.VolumeAttachment)
+				if !ok {
+					return
+				}
+				oldAttachment, ok := old.(*storagev1.VolumeAttachment)
+				if !ok {
+					return
+				}
+				m.AddIntervals(attachmentChanges(attachment, oldAttachment, time.Now())...)
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				attachment, ok := obj.(*storagev1.VolumeAttachment)
+				if !ok {
+					return
+				}
+				m.AddIntervals(attachmentDeleted(attachment, time.Now()))
+			},
+		},
+	)
+
+	go attachmentInformer.Run(ctx.Done())
+}
+
+func attachmentLocator(attachment *storagev1.VolumeAttachment) monitorapi.Locator {
+	var persistentVolume string
+	if attachment.Spec.Source.PersistentVolumeName != nil {
+		persistentVolume = *attachment.Spec.Source.PersistentVolumeName
+	}
+	return monitorapi.NewLocator().VolumeAttachment(attachment.Name, persistentVolume, attachment.Spec.NodeName)
+}
+
+func attachmentInterval(attachment *storagev1.VolumeAttachment, level monitorapi.IntervalLevel, reason monitorapi.IntervalReason, humanMessage string, now time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceStorageMonitor, level).
+		Locator(attachmentLocator(attachment)).
+		Message(monitorapi.NewMessage().Reason(reason).
+			WithAnnotation(monitorapi.AnnotationCSIDriver, attachment.Spec.Attacher).
+			HumanMessage(humanMessage)).
+		Build(now, now)
+}
+
+// attachmentChanges returns a point in time interval for every step of the attach or detach that the attachment took
+// between oldAttachment and attachment. Attachments that were already attached when first seen only get VolumeAttached,
+// their attach happened before the monitor started.
+func attachmentChanges(attachment, oldAttachment *storagev1.VolumeAttachment, now time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	if oldAttachment == nil {
+		if attachment.Status.Attached {
+			intervals = append(intervals, attachmentInterval(attachment, monitorapi.Info, monitorapi.VolumeAttachedReason, "volume is attached", now))
+		} else {
+			intervals = append(intervals, attachmentInterval(attachment, monitorapi.Info, monitorapi.VolumeAttachRequestedReason, "volume attach requested", now))
+		}
+		oldAttachment = &storagev1.VolumeAttachment{Status: storagev1.VolumeAttachmentStatus{Attached: attachment.Status.Attached}}
+	}
+
+	if attachment.Status.Attached && !oldAttachment.Status.Attached {
+		intervals = append(intervals, attachmentInterval(attachment, monitorapi.Info, monitorapi.VolumeAttachedReason, "volume attached", now))
+	}
+	if attachment.Status.AttachError != nil && !sameError(attachment.Status.AttachError, oldAttachment.Status.AttachError) {
+		intervals = append(intervals, attachmentInterval(attachment, monitorapi.Warning, monitorapi.VolumeAttachErrorReason, attachment.Status.AttachError.Message, now))
+	}
+	if attachment.DeletionTimestamp != nil && oldAttachment.DeletionTimestamp == nil {
+		intervals = append(intervals, attachmentInterval(attachment, monitorapi.Info, monitorapi.VolumeDetachRequestedReason, "volume detach requested", now))
+	}
+	if attachment.Status.DetachError != nil && !sameError(attachment.Status.DetachError, oldAttachment.Status.DetachError) {
+		intervals = append(intervals, attachmentInterval(attachment, monitorapi.Warning, monitorapi.VolumeDetachErrorReason, attachment.Status.DetachError.Message, now))
+	}
+	return intervals
+}
+
+// sameError tells whether the attacher reported the same error again, which it does on every retry.
+func sameError(err, oldErr *storagev1.VolumeError) bool {
+	return oldErr != nil && err.Message == oldErr.Message
+}
+
+func attachmentDeleted(attachment *storagev1.VolumeAttachment, now time.Time) monitorapi.Interval {
+	return attachmentInterval(attachment, monitorapi.Info, monitorapi.VolumeDetachedReason, "volume detached", now)
+}
+
+// attachmentIntervals constructs a VolumeAttaching interval from every attach request until the volume was attached,
+// and a VolumeDetaching interval from every detach request until the attachment was removed. Attaches and detaches
+// still in progress at end never completed.
+func attachmentIntervals(startingIntervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	changes := startingIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceStorageMonitor &&
+			eventInterval.Locator.Type == monitorapi.LocatorTypeVolumeAttachment
+	})
+
+	// the name of an attachment is derived from the driver, the volume and the node, so attaching the same volume to
+	// the same node again reuses it
+	nameToChanges := map[string]monitorapi.Intervals{}
+	for _, change := range changes {
+		name := change.Locator.Keys[monitorapi.LocatorVolumeAttachmentKey]
+		nameToChanges[name] = append(nameToChanges[name], change)
+	}
+	names := make([]string, 0, len(nameToChanges))
+	for name := range nameToChanges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ret := monitorapi.Intervals{}
+	for _, name := range names {
+		var attaching, detaching *monitorapi.Interval
+		var lastError string
+		closeOperation := func(from *monitorapi.Interval, reason monitorapi.IntervalReason, to time.Time, completed bool) {
+			if from == nil {
+				return
+			}
+			ret = append(ret, operationInterval(*from, reason, to, completed, lastError))
+			lastError = ""
+		}
+
+		for i := range nameToChanges[name] {
+			change := nameToChanges[name][i]
+			switch change.Message.Reason {
+			case monitorapi.VolumeAttachRequestedReason:
+				closeOperation(attaching, monitorapi.VolumeAttachingReason, change.From, false)
+				attaching = &change
+				lastError = ""
+
+			case monitorapi.VolumeAttachedReason:
+				closeOperation(attaching, monitorapi.VolumeAttachingReason, change.From, true)
+				attaching = nil
+
+			case monitorapi.VolumeAttachErrorReason, monitorapi.VolumeDetachErrorReason:
+				lastError = change.Message.HumanMessage
+
+			case monitorapi.VolumeDetachRequestedReason:
+				// a volume may be detached before the attach ever completed
+				closeOperation(attaching, monitorapi.VolumeAttachingReason, change.From, false)
+				attaching = nil
+				detaching = &change
+				lastError = ""
+
+			case monitorapi.VolumeDetachedReason:
+				closeOperation(attaching, monitorapi.VolumeAttachingReason, change.From, false)
+				attaching = nil
+				closeOperation(detaching, monitorapi.VolumeDetachingReason, change.From, true)
+				detaching = nil
+			}
+		}
+		closeOperation(attaching, monitorapi.VolumeAttachingReason, end, false)
+		closeOperation(detaching, monitorapi.VolumeDetachingReason, end, false)
+	}
+	return ret
+}
+
+// neverCompletedMessage starts the message of attaches and detaches that were still in progress at the end of the run.
+const neverCompletedMessage = "never completed"
+
+func operationInterval(from monitorapi.Interval, reason monitorapi.IntervalReason, to time.Time, completed bool, lastError string) monitorapi.Interval {
+	level := monitorapi.Info
+	humanMessage := "volume attached"
+	if reason == monitorapi.VolumeDetachingReason {
+		humanMessage = "volume detached"
+	}
+	if !completed {
+		level = monitorapi.Warning
+		humanMessage = neverCompletedMessage
+	}
+	if len(lastError) > 0 {
+		level = monitorapi.Warning
+		humanMessage = humanMessage + ", last error: " + lastError
+	}
+	return monitorapi.NewInterval(monitorapi.SourceStorageMonitor, level).
+		Locator(from.Locator).
+		Message(monitorapi.NewMessage().Reason(reason).
+			Constructed(monitorapi.ConstructionOwnerVolumeLifecycle).
+			WithAnnotation(monitorapi.AnnotationCSIDriver, from.Message.Annotations[monitorapi.AnnotationCSIDriver]).
+			HumanMessage(humanMessage)).
+		Display().
+		Build(from.From, to)
+}
@@ -0,0 +1,185 @@
+package volumelifecycle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	corev1 "k8s.io/api/core/v1"
+	informercorev1 "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Claim annotations set by the persistent volume controller once it hands a claim to a provisioner. They stay on the
+// claim after it is bound, which makes them the cheapest way to tell which driver a claim belongs to.
+const (
+	storageProvisionerAnnotation     = "volume.kubernetes.io/storage-provisioner"
+	betaStorageProvisionerAnnotation = "volume.beta.kubernetes.io/storage-provisioner"
+)
+
+func startClaimMonitoring(ctx context.Context, m monitorapi.RecorderWriter, client kubernetes.Interface) {
+	claimInformer := informercorev1.NewPersistentVolumeClaimInformer(client, "", time.Hour, nil)
+	claimInformer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				claim, ok := obj.(*corev1.PersistentVolumeClaim)
+				if !ok {
+					return
+				}
+				m.AddIntervals(claimPhaseChanges(claim, nil, time.Now())...)
+			},
+			UpdateFunc: func(old, obj interface{}) {
+				claim, ok := obj.(*corev1.PersistentVolumeClaim)
+				if !ok {
+					return
+				}
+				oldClaim, ok := old.(*corev1.PersistentVolumeClaim)
+				if !ok {
+					return
+				}
+				m.AddIntervals(claimPhaseChanges(claim, oldClaim, time.Now())...)
+			},
+		},
+	)
+
+	go claimInformer.Run(ctx.Done())
+}
+
+// claimPhaseChanges returns a point in time interval when the claim is first seen or its phase changed between
+// oldClaim and claim.
+func claimPhaseChanges(claim, oldClaim *corev1.PersistentVolumeClaim, now time.Time) monitorapi.Intervals {
+	phase := claim.Status.Phase
+	mb := monitorapi.NewMessage().Reason(monitorapi.PersistentVolumeClaimPhaseChangedReason).
+		WithAnnotation(monitorapi.AnnotationPhase, string(phase))
+	if oldClaim == nil {
+		mb = mb.HumanMessagef("claim is %s", phase)
+	} else {
+		if oldClaim.Status.Phase == phase {
+			return nil
+		}
+		mb = mb.WithAnnotation(monitorapi.AnnotationPreviousPhase, string(oldClaim.Status.Phase)).
+			HumanMessagef("claim phase changed from %s to %s", oldClaim.Status.Phase, phase)
+	}
+	if driver := claimDriver(claim); len(driver) > 0 {
+		mb = mb.WithAnnotation(monitorapi.AnnotationCSIDriver, driver)
+	}
+	if len(claim.Spec.VolumeName) > 0 {
+		mb = mb.WithAnnotation(monitorapi.AnnotationPersistentVolume, claim.Spec.VolumeName)
+	}
+
+	level := monitorapi.Info
+	if phase == corev1.ClaimLost {
+		level = monitorapi.Error
+	}
+	return monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceStorageMonitor, level).
+			Locator(monitorapi.NewLocator().PersistentVolumeClaim(claim.Namespace, claim.Name)).
+			Message(mb).
+			Build(now, now),
+	}
+}
+
+func claimDriver(claim *corev1.PersistentVolumeClaim) string {
+	if driver := claim.Annotations[storageProvisionerAnnotation]; len(driver) > 0 {
+		return driver
+	}
+	return claim.Annotations[betaStorageProvisionerAnnotation]
+}
+
+// claimPendingIntervals constructs an interval for every period a claim waited in Pending before it was bound. Claims
+// still pending at end were never bound.
+func claimPendingIntervals(startingIntervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	changes := startingIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceStorageMonitor &&
+			eventInterval.Message.Reason == monitorapi.PersistentVolumeClaimPhaseChangedReason
+	})
+
+	pending := map[string]monitorapi.Interval{}
+	ret := monitorapi.Intervals{}
+	closePending := func(key string, last monitorapi.Interval, to time.Time, bound bool) {
+		from, ok := pending[key]
+		if !ok {
+			return
+		}
+		delete(pending, key)
+
+		level := monitorapi.Info
+		humanMessage := "claim was bound"
+		if !bound {
+			level = monitorapi.Warning
+			humanMessage = "claim was never bound"
+		}
+		mb := monitorapi.NewMessage().Reason(monitorapi.PersistentVolumeClaimPendingReason).
+			Constructed(monitorapi.ConstructionOwnerVolumeLifecycle).
+			HumanMessage(humanMessage)
+		// the provisioner and the volume are only known once the claim made some progress
+		for _, annotation := range []monitorapi.AnnotationKey{monitorapi.AnnotationCSIDriver, monitorapi.AnnotationPersistentVolume} {
+			if value := last.Message.Annotations[annotation]; len(value) > 0 {
+				mb = mb.WithAnnotation(annotation, value)
+			}
+		}
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceStorageMonitor, level).
+				Locator(from.Locator).
+				Message(mb).
+				Display().
+				Build(from.From, to),
+		)
+	}
+
+	for _, change := range changes {
+		key := change.Locator.OldLocator()
+		switch corev1.PersistentVolumeClaimPhase(change.Message.Annotations[monitorapi.AnnotationPhase]) {
+		case corev1.ClaimPending:
+			if _, ok := pending[key]; !ok {
+				pending[key] = change
+			}
+		case corev1.ClaimBound:
+			closePending(key, change, change.From, true)
+		default:
+			closePending(key, change, change.From, false)
+		}
+	}
+
+	keys := make([]string, 0, len(pending))
+	for key := range pending {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		closePending(key, pending[key], end, false)
+	}
+	return ret
+}
+
+// claimVolume is the volume a claim was bound to and the driver that provisioned it.
+type claimVolume struct {
+	volume string
+	driver string
+}
+
+// claimVolumes maps every claim that was seen bound to its volume, keyed by namespace/name.
+func claimVolumes(startingIntervals monitorapi.Intervals) map[string]claimVolume {
+	ret := map[string]claimVolume{}
+	for _, interval := range startingIntervals {
+		if interval.Source != monitorapi.SourceStorageMonitor ||
+			interval.Message.Reason != monitorapi.PersistentVolumeClaimPhaseChangedReason {
+			continue
+		}
+		volume := interval.Message.Annotations[monitorapi.AnnotationPersistentVolume]
+		if len(volume) == 0 {
+			continue
+		}
+		key := namespacedKey(interval.Locator.Keys[monitorapi.LocatorNamespaceKey], interval.Locator.Keys[monitorapi.LocatorPersistentVolumeClaimKey])
+		ret[key] = claimVolume{volume: volume, driver: interval.Message.Annotations[monitorapi.AnnotationCSIDriver]}
+	}
+	return ret
+}
+
+func namespacedKey(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
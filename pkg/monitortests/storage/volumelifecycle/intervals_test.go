@@ -0,0 +1,298 @@
+package volumelifecycle
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const testDriver = "ebs.csi.aws.com"
+
+func newAttachment(volume, nodeName string) *storagev1.VolumeAttachment {
+	return &storagev1.VolumeAttachment{
+		ObjectMeta: metav1.ObjectMeta{Name: "csi-" + volume + "-" + nodeName},
+		Spec: storagev1.VolumeAttachmentSpec{
+			Attacher: testDriver,
+			NodeName: nodeName,
+			Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: ptr.To(volume)},
+		},
+	}
+}
+
+func attached(attachment *storagev1.VolumeAttachment) *storagev1.VolumeAttachment {
+	ret := attachment.DeepCopy()
+	ret.Status.Attached = true
+	return ret
+}
+
+func deleting(attachment *storagev1.VolumeAttachment, at time.Time) *storagev1.VolumeAttachment {
+	ret := attachment.DeepCopy()
+	ret.DeletionTimestamp = &metav1.Time{Time: at}
+	return ret
+}
+
+// attachmentLifecycle records the attachment being requested, attached after attachAfter, and detached after
+// detachAfter if it is set.
+func attachmentLifecycle(volume, nodeName string, from time.Time, attachAfter, detachAfter time.Duration) monitorapi.Intervals {
+	requested := newAttachment(volume, nodeName)
+	intervals := attachmentChanges(requested, nil, from)
+	if attachAfter == 0 {
+		return intervals
+	}
+	intervals = append(intervals, attachmentChanges(attached(requested), requested, from.Add(attachAfter))...)
+	if detachAfter == 0 {
+		return intervals
+	}
+	detachAt := from.Add(detachAfter)
+	intervals = append(intervals, attachmentChanges(deleting(attached(requested), detachAt), attached(requested), detachAt)...)
+	return append(intervals, attachmentDeleted(requested, detachAt.Add(30*time.Second)))
+}
+
+func TestAttachmentChanges(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	requested := newAttachment("pvc-1234", "worker-a")
+	failing := requested.DeepCopy()
+	failing.Status.AttachError = &storagev1.VolumeError{Message: "rpc error: volume is in use"}
+
+	tests := []struct {
+		name            string
+		attachment, old *storagev1.VolumeAttachment
+		expectedReasons []monitorapi.IntervalReason
+	}{
+		{
+			name:            "attach requested",
+			attachment:      requested,
+			expectedReasons: []monitorapi.IntervalReason{monitorapi.VolumeAttachRequestedReason},
+		},
+		{
+			name:            "already attached when first seen",
+			attachment:      attached(requested),
+			expectedReasons: []monitorapi.IntervalReason{monitorapi.VolumeAttachedReason},
+		},
+		{
+			name:            "attach error",
+			attachment:      failing,
+			old:             requested,
+			expectedReasons: []monitorapi.IntervalReason{monitorapi.VolumeAttachErrorReason},
+		},
+		{
+			name:       "attach retried with the same error",
+			attachment: failing,
+			old:        failing,
+		},
+		{
+			name:            "attached",
+			attachment:      attached(requested),
+			old:             failing,
+			expectedReasons: []monitorapi.IntervalReason{monitorapi.VolumeAttachedReason},
+		},
+		{
+			name:            "detach requested",
+			attachment:      deleting(attached(requested), now),
+			old:             attached(requested),
+			expectedReasons: []monitorapi.IntervalReason{monitorapi.VolumeDetachRequestedReason},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intervals := attachmentChanges(tt.attachment, tt.old, now)
+			if len(intervals) != len(tt.expectedReasons) {
+				t.Fatalf("expected %v, got %v", tt.expectedReasons, intervals)
+			}
+			for i, interval := range intervals {
+				if interval.Message.Reason != tt.expectedReasons[i] {
+					t.Errorf("expected %s, got %s", tt.expectedReasons[i], interval.Message.Reason)
+				}
+				if interval.Message.Annotations[monitorapi.AnnotationCSIDriver] != testDriver {
+					t.Errorf("expected the driver to be annotated, got %v", interval.Message.Annotations)
+				}
+				if interval.Locator.Keys[monitorapi.LocatorPersistentVolumeKey] != "pvc-1234" || interval.Locator.Keys[monitorapi.LocatorNodeKey] != "worker-a" {
+					t.Errorf("unexpected locator %v", interval.Locator)
+				}
+			}
+		})
+	}
+}
+
+func TestAttachmentIntervals(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := from.Add(time.Hour)
+
+	startingIntervals := monitorapi.Intervals{}
+	startingIntervals = append(startingIntervals, attachmentLifecycle("pvc-a", "worker-a", from, 20*time.Second, 10*time.Minute)...)
+	startingIntervals = append(startingIntervals, attachmentLifecycle("pvc-b", "worker-b", from, 0, 0)...)
+
+	intervals := attachmentIntervals(startingIntervals, end)
+	type expected struct {
+		reason    monitorapi.IntervalReason
+		duration  time.Duration
+		level     monitorapi.IntervalLevel
+		completed bool
+	}
+	expectations := []expected{
+		{reason: monitorapi.VolumeAttachingReason, duration: 20 * time.Second, level: monitorapi.Info, completed: true},
+		{reason: monitorapi.VolumeDetachingReason, duration: 30 * time.Second, level: monitorapi.Info, completed: true},
+		{reason: monitorapi.VolumeAttachingReason, duration: time.Hour, level: monitorapi.Warning},
+	}
+	if len(intervals) != len(expectations) {
+		t.Fatalf("expected %d intervals, got %v", len(expectations), intervals)
+	}
+	for i, interval := range intervals {
+		expectation := expectations[i]
+		if interval.Message.Reason != expectation.reason || interval.To.Sub(interval.From) != expectation.duration || interval.Level != expectation.level {
+			t.Errorf("expected %+v, got %v", expectation, interval)
+		}
+		if neverCompleted := strings.HasPrefix(interval.Message.HumanMessage, neverCompletedMessage); neverCompleted == expectation.completed {
+			t.Errorf("unexpected message %q", interval.Message.HumanMessage)
+		}
+		if interval.Message.Annotations[monitorapi.AnnotationCSIDriver] != testDriver {
+			t.Errorf("expected the driver to be annotated, got %v", interval.Message.Annotations)
+		}
+	}
+}
+
+func claimPhase(namespace, name string, phase corev1.PersistentVolumeClaimPhase, volume string, at time.Time) monitorapi.Interval {
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        name,
+			Annotations: map[string]string{storageProvisionerAnnotation: testDriver},
+		},
+		Spec:   corev1.PersistentVolumeClaimSpec{VolumeName: volume},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: phase},
+	}
+	return claimPhaseChanges(claim, nil, at)[0]
+}
+
+func TestClaimPendingIntervals(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := from.Add(time.Hour)
+
+	intervals := claimPendingIntervals(monitorapi.Intervals{
+		claimPhase("e2e-test", "bound", corev1.ClaimPending, "", from),
+		claimPhase("e2e-test", "never-bound", corev1.ClaimPending, "", from.Add(time.Second)),
+		claimPhase("e2e-test", "bound", corev1.ClaimBound, "pvc-1234", from.Add(15*time.Second)),
+		claimPhase("e2e-test", "preexisting", corev1.ClaimBound, "pvc-5678", from.Add(20*time.Second)),
+	}, end)
+	if len(intervals) != 2 {
+		t.Fatalf("expected a pending interval for the two claims that were pending, got %v", intervals)
+	}
+
+	bound := intervals[0]
+	if bound.Locator.Keys[monitorapi.LocatorPersistentVolumeClaimKey] != "bound" || bound.To.Sub(bound.From) != 15*time.Second || bound.Level != monitorapi.Info {
+		t.Errorf("unexpected interval for the bound claim: %v", bound)
+	}
+	if bound.Message.Annotations[monitorapi.AnnotationPersistentVolume] != "pvc-1234" || bound.Message.Annotations[monitorapi.AnnotationCSIDriver] != testDriver {
+		t.Errorf("expected the volume and driver to be annotated, got %v", bound.Message.Annotations)
+	}
+	neverBound := intervals[1]
+	if neverBound.Locator.Keys[monitorapi.LocatorPersistentVolumeClaimKey] != "never-bound" || !neverBound.To.Equal(end) || neverBound.Level != monitorapi.Warning {
+		t.Errorf("unexpected interval for the claim that was never bound: %v", neverBound)
+	}
+}
+
+func podWithClaim(name, nodeName, claim string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "e2e-test", Name: name, UID: types.UID(name + "-uid")},
+		Spec: corev1.PodSpec{
+			NodeName:   nodeName,
+			Containers: []corev1.Container{{Name: "app"}},
+			Volumes: []corev1.Volume{
+				{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim}}},
+				{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{}}},
+			},
+		},
+	}
+}
+
+func podEvent(pod *corev1.Pod, reason monitorapi.IntervalReason, at time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourcePodMonitor, monitorapi.Info).
+		Locator(monitorapi.NewLocator().PodFromPod(pod)).
+		Message(monitorapi.NewMessage().Reason(reason)).
+		Build(at, at)
+}
+
+func containerEvent(pod *corev1.Pod, reason monitorapi.IntervalReason, at time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourcePodMonitor, monitorapi.Info).
+		Locator(monitorapi.NewLocator().ContainerFromPod(pod, "app")).
+		Message(monitorapi.NewMessage().Reason(reason)).
+		Build(at, at)
+}
+
+func kubeletEvent(pod *corev1.Pod, reason monitorapi.IntervalReason, message string, at time.Time) monitorapi.Interval {
+	event := &corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name},
+		Source:         corev1.EventSource{Component: "kubelet", Host: pod.Spec.NodeName},
+		Message:        message,
+	}
+	return monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Info).
+		Locator(monitorapi.NewLocator().KubeEvent(event)).
+		Message(monitorapi.NewMessage().Reason(reason).HumanMessage(message)).
+		Build(at, at)
+}
+
+func TestMountIntervals(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := from.Add(time.Hour)
+	at := func(seconds int) time.Time { return from.Add(time.Duration(seconds) * time.Second) }
+
+	mounted := podWithClaim("mounted", "worker-a", "data-mounted")
+	stuck := podWithClaim("stuck", "worker-b", "data-stuck")
+	recordedPods := monitorapi.InstanceMap{
+		{Namespace: mounted.Namespace, Name: mounted.Name, UID: string(mounted.UID)}: mounted,
+		{Namespace: stuck.Namespace, Name: stuck.Name, UID: string(stuck.UID)}:       stuck,
+	}
+
+	startingIntervals := monitorapi.Intervals{
+		claimPhase("e2e-test", "data-mounted", corev1.ClaimBound, "pvc-a", at(0)),
+		claimPhase("e2e-test", "data-stuck", corev1.ClaimBound, "pvc-b", at(0)),
+		podEvent(mounted, monitorapi.PodReasonScheduled, at(1)),
+		podEvent(stuck, monitorapi.PodReasonScheduled, at(1)),
+	}
+	startingIntervals = append(startingIntervals, attachmentLifecycle("pvc-a", "worker-a", at(1), 9*time.Second, 0)...)
+	startingIntervals = append(startingIntervals,
+		kubeletEvent(mounted, failedMountReason, `MountVolume.MountDevice failed for volume "pvc-a"`, at(5)),
+		kubeletEvent(mounted, pullingReason, `Pulling image "quay.io/openshift/app"`, at(25)),
+		containerEvent(mounted, monitorapi.ContainerReasonContainerStart, at(40)),
+		kubeletEvent(stuck, failedMountReason, `Unable to attach or mount volumes`, at(120)),
+		podEvent(mounted, monitorapi.PodReasonGracefulDeleteStarted, at(600)),
+		containerEvent(mounted, monitorapi.ContainerReasonContainerExit, at(610)),
+		podEvent(mounted, monitorapi.PodReasonDeleted, at(625)),
+	)
+
+	intervals := mountIntervals(startingIntervals, recordedPods, end)
+	if len(intervals) != 3 {
+		t.Fatalf("expected a mount for both pods and an unmount for the deleted one, got %v", intervals)
+	}
+
+	byPodAndReason := map[string]monitorapi.Interval{}
+	for _, interval := range intervals {
+		byPodAndReason[interval.Locator.Keys[monitorapi.LocatorPodKey]+"/"+string(interval.Message.Reason)] = interval
+	}
+	mount := byPodAndReason["mounted/VolumeMounting"]
+	stuckMount := byPodAndReason["stuck/VolumeMounting"]
+	unmount := byPodAndReason["mounted/VolumeUnmounting"]
+	if mount.Message.Reason != monitorapi.VolumeMountingReason || !mount.From.Equal(at(10)) || !mount.To.Equal(at(25)) {
+		t.Errorf("expected the mount from the attach until the image pull, got %v", mount)
+	}
+	if mount.Level != monitorapi.Warning || !strings.Contains(mount.Message.HumanMessage, "after 1 mount failures") {
+		t.Errorf("expected the failed mount to be reported, got %v", mount)
+	}
+	if mount.Message.Annotations[monitorapi.AnnotationCSIDriver] != testDriver || mount.Message.Annotations[monitorapi.AnnotationPersistentVolume] != "pvc-a" {
+		t.Errorf("expected the volume and driver to be annotated, got %v", mount.Message.Annotations)
+	}
+	if stuckMount.Locator.Keys[monitorapi.LocatorPodKey] != "stuck" || !stuckMount.To.Equal(end) || !strings.Contains(stuckMount.Message.HumanMessage, "never mounted") {
+		t.Errorf("expected the mount of the stuck pod to last until the end, got %v", stuckMount)
+	}
+	if unmount.Message.Reason != monitorapi.VolumeUnmountingReason || !unmount.From.Equal(at(610)) || !unmount.To.Equal(at(625)) {
+		t.Errorf("expected the unmount from the container exit until the pod was deleted, got %v", unmount)
+	}
+}
@@ -0,0 +1,220 @@
+package volumelifecycle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	stuckAttachTestName  = "[sig-storage] volumes should not be stuck attaching after a node drain"
+	csiDriverRestartName = "[sig-storage] CSI driver pods should not restart during the run"
+
+	// stuckAttachLimit is how long a volume may take to attach to its new node after a drain moved its pod. The
+	// attach-detach controller force detaches a volume from the old node after six minutes of waiting for the unmount,
+	// an attach taking longer than that is not going to be explained by the old node holding on to the volume.
+	stuckAttachLimit = 6 * time.Minute
+
+	// rebootRestartGrace is how long after a node reboot the restarts of the containers on it are expected.
+	rebootRestartGrace = 10 * time.Minute
+
+	// drainPhase is the machine config rollout phase during which the machine-config-daemon drains a node.
+	drainPhase = "Drain"
+
+	// csiContainerPrefix is shared by the driver containers and the sidecars of the CSI driver pods OpenShift ships.
+	csiContainerPrefix = "csi-"
+)
+
+// attachmentSpan is the time a volume was attached, or being attached, to a node.
+type attachmentSpan struct {
+	node string
+	from time.Time
+	to   time.Time
+}
+
+func (s attachmentSpan) overlaps(from, to time.Time) bool {
+	return !s.from.After(to) && (s.to.IsZero() || !s.to.Before(from))
+}
+
+// attachmentSpans returns the spans of every persistent volume, from the attach request or the first time it was
+// seen attached until it was detached.
+func attachmentSpans(finalIntervals monitorapi.Intervals) map[string][]attachmentSpan {
+	open := map[string]*attachmentSpan{}
+	volumes := map[string]string{}
+	ret := map[string][]attachmentSpan{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceStorageMonitor || interval.Locator.Type != monitorapi.LocatorTypeVolumeAttachment {
+			continue
+		}
+		volume := interval.Locator.Keys[monitorapi.LocatorPersistentVolumeKey]
+		if len(volume) == 0 {
+			continue
+		}
+		name := interval.Locator.Keys[monitorapi.LocatorVolumeAttachmentKey]
+		switch interval.Message.Reason {
+		case monitorapi.VolumeAttachRequestedReason, monitorapi.VolumeAttachedReason:
+			if _, ok := open[name]; !ok {
+				open[name] = &attachmentSpan{node: interval.Locator.Keys[monitorapi.LocatorNodeKey], from: interval.From}
+				volumes[name] = volume
+			}
+		case monitorapi.VolumeDetachedReason:
+			if span, ok := open[name]; ok {
+				span.to = interval.From
+				ret[volume] = append(ret[volume], *span)
+				delete(open, name)
+			}
+		}
+	}
+	for name, span := range open {
+		ret[volumes[name]] = append(ret[volumes[name]], *span)
+	}
+	return ret
+}
+
+// stuckAttachJUnits fails when a volume that was attached to a node while it drained took longer than
+// stuckAttachLimit to attach to the next node, or never attached to it. No junit is returned when no drained node had
+// volumes attached.
+func stuckAttachJUnits(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	drains := finalIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Message.Reason == monitorapi.MachineConfigNodePhaseReason &&
+			eventInterval.Message.Annotations[monitorapi.AnnotationPhase] == drainPhase
+	})
+	attaches := finalIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceStorageMonitor &&
+			eventInterval.Message.Reason == monitorapi.VolumeAttachingReason
+	})
+	spans := attachmentSpans(finalIntervals)
+	volumes := make([]string, 0, len(spans))
+	for volume := range spans {
+		volumes = append(volumes, volume)
+	}
+	sort.Strings(volumes)
+
+	var movedVolumes int
+	var failures []string
+	for _, drain := range drains {
+		drainedNode := drain.Locator.Keys[monitorapi.LocatorNodeKey]
+		for _, volume := range volumes {
+			wasAttached := false
+			for _, span := range spans[volume] {
+				if span.node == drainedNode && span.overlaps(drain.From, drain.To) {
+					wasAttached = true
+					break
+				}
+			}
+			if !wasAttached {
+				continue
+			}
+			movedVolumes++
+
+			// only the first attach elsewhere after the drain started belongs to the drain
+			for _, attach := range attaches {
+				if attach.Locator.Keys[monitorapi.LocatorPersistentVolumeKey] != volume ||
+					attach.Locator.Keys[monitorapi.LocatorNodeKey] == drainedNode ||
+					attach.From.Before(drain.From) {
+					continue
+				}
+				duration := attach.To.Sub(attach.From)
+				if duration > stuckAttachLimit {
+					outcome := "to attach"
+					if strings.HasPrefix(attach.Message.HumanMessage, neverCompletedMessage) {
+						outcome = "without attaching"
+					}
+					failures = append(failures, fmt.Sprintf("volume %s waited %s %s to node/%s after node/%s drained, longer than %s: %s",
+						volume, duration.Round(time.Second), outcome, attach.Locator.Keys[monitorapi.LocatorNodeKey], drainedNode, stuckAttachLimit, attach.String()))
+				}
+				break
+			}
+		}
+	}
+	if movedVolumes == 0 {
+		return nil
+	}
+	if len(failures) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: stuckAttachTestName}}
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: stuckAttachTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: strings.Join(failures, "\n"),
+			},
+			SystemOut: strings.Join(failures, "\n"),
+		},
+	}
+}
+
+// csiDriverPods returns the namespace/name of every recorded platform pod running a CSI driver or one of its sidecars.
+// The storage e2e tests deploy their own drivers, which they restart on purpose.
+func csiDriverPods(recordedPods monitorapi.InstanceMap) sets.Set[string] {
+	ret := sets.New[string]()
+	for _, obj := range recordedPods {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || !platformidentification.IsPlatformNamespace(pod.Namespace) {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			if strings.HasPrefix(container.Name, csiContainerPrefix) {
+				ret.Insert(namespacedKey(pod.Namespace, pod.Name))
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// csiDriverRestartJUnits fails when a container of a CSI driver pod restarted during the run. A node reboot restarts
+// every container on the node, those restarts are not counted.
+func csiDriverRestartJUnits(finalIntervals monitorapi.Intervals, driverPods sets.Set[string]) []*junitapi.JUnitTestCase {
+	reboots := map[string][]time.Time{}
+	for _, interval := range finalIntervals {
+		if interval.Message.Reason == monitorapi.NodeRebootedReason {
+			node := interval.Locator.Keys[monitorapi.LocatorNodeKey]
+			reboots[node] = append(reboots[node], interval.From)
+		}
+	}
+	afterReboot := func(node string, at time.Time) bool {
+		for _, reboot := range reboots[node] {
+			if !at.Before(reboot) && at.Sub(reboot) <= rebootRestartGrace {
+				return true
+			}
+		}
+		return false
+	}
+
+	failures := []string{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourcePodMonitor || interval.Message.Reason != monitorapi.ContainerReasonRestarted {
+			continue
+		}
+		keys := interval.Locator.Keys
+		if !driverPods.Has(namespacedKey(keys[monitorapi.LocatorNamespaceKey], keys[monitorapi.LocatorPodKey])) {
+			continue
+		}
+		if afterReboot(keys[monitorapi.LocatorNodeKey], interval.From) {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("container %s of pod/%s -n %s on node/%s restarted: %s",
+			keys[monitorapi.LocatorContainerKey], keys[monitorapi.LocatorPodKey], keys[monitorapi.LocatorNamespaceKey], keys[monitorapi.LocatorNodeKey], interval.String()))
+	}
+	if len(failures) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: csiDriverRestartName}}
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: csiDriverRestartName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: strings.Join(failures, "\n"),
+			},
+			SystemOut: strings.Join(failures, "\n"),
+		},
+	}
+}
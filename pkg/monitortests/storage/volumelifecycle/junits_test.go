@@ -0,0 +1,140 @@
+package volumelifecycle
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junittest"
+)
+
+func drainPhaseInterval(nodeName string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceMachineConfigRollout, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName(nodeName)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.MachineConfigNodePhaseReason).
+			WithAnnotation(monitorapi.AnnotationPhase, drainPhase)).
+		Build(from, to)
+}
+
+func TestStuckAttachJUnits(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := from.Add(time.Hour)
+
+	// pvc-a moves from worker-a to worker-b when worker-a drains
+	moved := func(reattachAfter time.Duration) monitorapi.Intervals {
+		intervals := monitorapi.Intervals{}
+		intervals = append(intervals, attachmentLifecycle("pvc-a", "worker-a", from, 10*time.Second, 10*time.Minute)...)
+		intervals = append(intervals, attachmentLifecycle("pvc-a", "worker-b", from.Add(9*time.Minute), reattachAfter, 0)...)
+		return intervals
+	}
+
+	tests := []struct {
+		name          string
+		intervals     monitorapi.Intervals
+		expectJUnit   bool
+		expectFailure string
+	}{
+		{
+			name:      "no drains",
+			intervals: moved(time.Minute),
+		},
+		{
+			name: "drained node had no volumes",
+			intervals: append(moved(time.Minute),
+				drainPhaseInterval("worker-c", from.Add(5*time.Minute), from.Add(8*time.Minute))),
+		},
+		{
+			name: "volume attached after the drain",
+			intervals: append(moved(2*time.Minute),
+				drainPhaseInterval("worker-a", from.Add(5*time.Minute), from.Add(8*time.Minute))),
+			expectJUnit: true,
+		},
+		{
+			name: "volume slow to attach after the drain",
+			intervals: append(moved(8*time.Minute),
+				drainPhaseInterval("worker-a", from.Add(5*time.Minute), from.Add(8*time.Minute))),
+			expectJUnit:   true,
+			expectFailure: "volume pvc-a waited 8m0s to attach to node/worker-b after node/worker-a drained, longer than 6m0s",
+		},
+		{
+			name: "volume never attached after the drain",
+			intervals: append(moved(0),
+				drainPhaseInterval("worker-a", from.Add(5*time.Minute), from.Add(8*time.Minute))),
+			expectJUnit:   true,
+			expectFailure: "volume pvc-a waited 51m0s without attaching to node/worker-b after node/worker-a drained",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finalIntervals := append(tt.intervals, attachmentIntervals(tt.intervals, end)...)
+			junittest.ExpectSingleJUnit(t, stuckAttachJUnits(finalIntervals), stuckAttachTestName, tt.expectJUnit, tt.expectFailure)
+		})
+	}
+}
+
+func TestCSIDriverRestartJUnits(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	driverPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-cluster-csi-drivers", Name: "aws-ebs-csi-driver-node-abcde", UID: "driver-uid"},
+		Spec: corev1.PodSpec{
+			NodeName:   "worker-a",
+			Containers: []corev1.Container{{Name: "csi-driver"}, {Name: "csi-node-driver-registrar"}},
+		},
+	}
+	workloadPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "e2e-test", Name: "app", UID: "app-uid"},
+		Spec:       corev1.PodSpec{NodeName: "worker-a", Containers: []corev1.Container{{Name: "app"}}},
+	}
+	testDriverPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "e2e-csi-mock-volumes-1234", Name: "csi-mockplugin-0", UID: "mock-uid"},
+		Spec:       corev1.PodSpec{NodeName: "worker-a", Containers: []corev1.Container{{Name: "csi-provisioner"}, {Name: "mock"}}},
+	}
+	driverPods := csiDriverPods(monitorapi.InstanceMap{
+		{Namespace: driverPod.Namespace, Name: driverPod.Name, UID: "driver-uid"}:       driverPod,
+		{Namespace: workloadPod.Namespace, Name: workloadPod.Name, UID: "app-uid"}:      workloadPod,
+		{Namespace: testDriverPod.Namespace, Name: testDriverPod.Name, UID: "mock-uid"}: testDriverPod,
+	})
+	if !driverPods.Equal(sets.New("openshift-cluster-csi-drivers/aws-ebs-csi-driver-node-abcde")) {
+		t.Fatalf("expected only the driver pod, got %v", sets.List(driverPods))
+	}
+
+	restart := func(pod *corev1.Pod, container string, at time.Time) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourcePodMonitor, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().ContainerFromPod(pod, container)).
+			Message(monitorapi.NewMessage().Reason(monitorapi.ContainerReasonRestarted)).
+			Build(at, at)
+	}
+	reboot := monitorapi.NewInterval(monitorapi.SourceMachineConfigRollout, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("worker-a")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.NodeRebootedReason)).
+		Build(from, from)
+
+	tests := []struct {
+		name          string
+		intervals     monitorapi.Intervals
+		expectFailure string
+	}{
+		{
+			name:      "workload restart",
+			intervals: monitorapi.Intervals{restart(workloadPod, "app", from)},
+		},
+		{
+			name:      "driver restarted by a node reboot",
+			intervals: monitorapi.Intervals{reboot, restart(driverPod, "csi-driver", from.Add(2*time.Minute))},
+		},
+		{
+			name:          "driver restart",
+			intervals:     monitorapi.Intervals{reboot, restart(driverPod, "csi-driver", from.Add(30*time.Minute))},
+			expectFailure: "container csi-driver of pod/aws-ebs-csi-driver-node-abcde -n openshift-cluster-csi-drivers on node/worker-a restarted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			junittest.ExpectSingleJUnit(t, csiDriverRestartJUnits(tt.intervals, driverPods), csiDriverRestartName, true, tt.expectFailure)
+		})
+	}
+}
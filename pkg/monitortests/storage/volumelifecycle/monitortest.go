@@ -0,0 +1,69 @@
+package volumelifecycle
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// volumeLifecycleWatcher charts how claims are bound and how their volumes are attached, mounted, unmounted and
+// detached, tagging every step with the CSI driver responsible for it.
+type volumeLifecycleWatcher struct {
+	driverPods sets.Set[string]
+}
+
+func NewVolumeLifecycleWatcher() monitortestframework.MonitorTest {
+	return &volumeLifecycleWatcher{}
+}
+
+func (w *volumeLifecycleWatcher) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *volumeLifecycleWatcher) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	startClaimMonitoring(ctx, recorder, kubeClient)
+	startAttachmentMonitoring(ctx, recorder, kubeClient)
+	return nil
+}
+
+func (w *volumeLifecycleWatcher) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	// claims and attachments are recorded as they change, mounts are built from the pods and kubelet events recorded
+	// by other monitor tests
+	return nil, nil, nil
+}
+
+func (w *volumeLifecycleWatcher) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	w.driverPods = csiDriverPods(recordedResources["pods"])
+
+	constructedIntervals := monitorapi.Intervals{}
+	constructedIntervals = append(constructedIntervals, claimPendingIntervals(startingIntervals, end)...)
+	constructedIntervals = append(constructedIntervals, attachmentIntervals(startingIntervals, end)...)
+	constructedIntervals = append(constructedIntervals, mountIntervals(startingIntervals, recordedResources["pods"], end)...)
+	return constructedIntervals, nil
+}
+
+func (w *volumeLifecycleWatcher) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	junits := []*junitapi.JUnitTestCase{}
+	junits = append(junits, stuckAttachJUnits(finalIntervals)...)
+	junits = append(junits, csiDriverRestartJUnits(finalIntervals, w.driverPods)...)
+	return junits, nil
+}
+
+func (*volumeLifecycleWatcher) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*volumeLifecycleWatcher) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,230 @@
+package volumelifecycle
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Reasons of the kubelet events that bound the mount of a pod's volumes. The kubelet waits for every volume of the pod
+// to be mounted before it creates the sandbox and pulls images, so the first pull means the mounts are done even for
+// filesystem volumes, which get no event of their own when they mount successfully.
+const (
+	failedMountReason     monitorapi.IntervalReason = "FailedMount"
+	successfulMountReason monitorapi.IntervalReason = "SuccessfulMountVolume"
+	pullingReason         monitorapi.IntervalReason = "Pulling"
+	pulledReason          monitorapi.IntervalReason = "Pulled"
+)
+
+// podVolumeEvents are the points in time of a single pod that bound the mount and unmount of its volumes.
+type podVolumeEvents struct {
+	scheduled     time.Time
+	firstStart    time.Time
+	lastExit      time.Time
+	deleteStarted time.Time
+	deleted       time.Time
+}
+
+// podClaims returns the claims the pod mounts, including the ones created for its generic ephemeral volumes.
+func podClaims(pod *corev1.Pod) []string {
+	var claims []string
+	for _, volume := range pod.Spec.Volumes {
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			claims = append(claims, volume.PersistentVolumeClaim.ClaimName)
+		case volume.Ephemeral != nil:
+			claims = append(claims, fmt.Sprintf("%s-%s", pod.Name, volume.Name))
+		}
+	}
+	return claims
+}
+
+// mountIntervals constructs a VolumeMounting interval for every claim of every pod scheduled during the run, and a
+// VolumeUnmounting interval for every claim of every pod deleted during the run.
+//
+// A mount starts when the pod is scheduled, or once its volume is attached to the node if that came later, and ends
+// with the first kubelet event that follows the mounts. An unmount starts once the pod is being deleted and its
+// containers have exited, and ends when the pod is removed from the API, which the kubelet holds off on until the
+// volumes of the pod are unmounted.
+func mountIntervals(startingIntervals monitorapi.Intervals, recordedPods monitorapi.InstanceMap, end time.Time) monitorapi.Intervals {
+	volumes := claimVolumes(startingIntervals)
+	attached := map[string][]time.Time{}
+	uidToEvents := map[string]*podVolumeEvents{}
+	kubeletEvents := map[string]monitorapi.Intervals{}
+	for _, interval := range startingIntervals {
+		switch interval.Source {
+		case monitorapi.SourceStorageMonitor:
+			if interval.Message.Reason != monitorapi.VolumeAttachedReason {
+				continue
+			}
+			key := volumeNodeKey(interval.Locator.Keys[monitorapi.LocatorPersistentVolumeKey], interval.Locator.Keys[monitorapi.LocatorNodeKey])
+			attached[key] = append(attached[key], interval.From)
+
+		case monitorapi.SourcePodMonitor:
+			uid := interval.Locator.Keys[monitorapi.LocatorUIDKey]
+			if len(uid) == 0 {
+				continue
+			}
+			events, ok := uidToEvents[uid]
+			if !ok {
+				events = &podVolumeEvents{}
+				uidToEvents[uid] = events
+			}
+			switch interval.Message.Reason {
+			case monitorapi.PodReasonScheduled:
+				events.scheduled = interval.From
+			case monitorapi.ContainerReasonContainerStart:
+				if events.firstStart.IsZero() || interval.From.Before(events.firstStart) {
+					events.firstStart = interval.From
+				}
+			case monitorapi.ContainerReasonContainerExit:
+				if interval.From.After(events.lastExit) {
+					events.lastExit = interval.From
+				}
+			case monitorapi.PodReasonGracefulDeleteStarted:
+				events.deleteStarted = interval.From
+			case monitorapi.PodReasonDeleted:
+				events.deleted = interval.From
+			}
+
+		case monitorapi.SourceKubeEvent:
+			switch interval.Message.Reason {
+			case failedMountReason, successfulMountReason, pullingReason, pulledReason:
+			default:
+				continue
+			}
+			name := interval.Locator.Keys[monitorapi.LocatorPodKey]
+			if len(name) == 0 {
+				continue
+			}
+			key := namespacedKey(interval.Locator.Keys[monitorapi.LocatorNamespaceKey], name)
+			kubeletEvents[key] = append(kubeletEvents[key], interval)
+		}
+	}
+
+	ret := monitorapi.Intervals{}
+	for _, obj := range recordedPods {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			continue
+		}
+		claims := podClaims(pod)
+		events, ok := uidToEvents[string(pod.UID)]
+		if len(claims) == 0 || !ok {
+			continue
+		}
+
+		for _, claim := range claims {
+			volume := volumes[namespacedKey(pod.Namespace, claim)]
+			if mount, ok := podMount(pod, claim, volume, events, kubeletEvents[namespacedKey(pod.Namespace, pod.Name)], attached, end); ok {
+				ret = append(ret, mount)
+			}
+			if unmount, ok := podUnmount(pod, claim, volume, events); ok {
+				ret = append(ret, unmount)
+			}
+		}
+	}
+
+	sort.Sort(ret)
+	return ret
+}
+
+func podMount(pod *corev1.Pod, claim string, volume claimVolume, events *podVolumeEvents, kubeletEvents monitorapi.Intervals, attached map[string][]time.Time, end time.Time) (monitorapi.Interval, bool) {
+	if events.scheduled.IsZero() {
+		return monitorapi.Interval{}, false
+	}
+
+	// the pod name may be reused by a later pod, only the events after this one was scheduled count
+	mounted := events.firstStart
+	var failures []monitorapi.Interval
+	for _, event := range kubeletEvents {
+		if event.From.Before(events.scheduled) {
+			continue
+		}
+		if event.Message.Reason == failedMountReason {
+			failures = append(failures, event)
+			continue
+		}
+		if mounted.IsZero() || event.From.Before(mounted) {
+			mounted = event.From
+		}
+	}
+	completed := !mounted.IsZero()
+	to := mounted
+	if !completed {
+		to = end
+		if !events.deleted.IsZero() {
+			to = events.deleted
+		}
+	}
+
+	from := events.scheduled
+	for _, attachedAt := range attached[volumeNodeKey(volume.volume, pod.Spec.NodeName)] {
+		if attachedAt.After(from) && !attachedAt.After(to) {
+			from = attachedAt
+		}
+	}
+
+	level := monitorapi.Info
+	humanMessage := fmt.Sprintf("volume of claim %s mounted", claim)
+	if !completed {
+		level = monitorapi.Warning
+		humanMessage = fmt.Sprintf("volume of claim %s was never mounted", claim)
+	}
+	var failureCount int
+	var lastFailure string
+	for _, failure := range failures {
+		if failure.From.After(to) {
+			continue
+		}
+		failureCount++
+		lastFailure = failure.Message.HumanMessage
+	}
+	if failureCount > 0 {
+		level = monitorapi.Warning
+		humanMessage = fmt.Sprintf("%s after %d mount failures, the last one: %s", humanMessage, failureCount, lastFailure)
+	}
+
+	return volumeInterval(pod, claim, volume, monitorapi.VolumeMountingReason, level, humanMessage, from, to), true
+}
+
+func podUnmount(pod *corev1.Pod, claim string, volume claimVolume, events *podVolumeEvents) (monitorapi.Interval, bool) {
+	if events.deleted.IsZero() || events.deleteStarted.IsZero() {
+		return monitorapi.Interval{}, false
+	}
+	from := events.deleteStarted
+	if events.lastExit.After(from) {
+		from = events.lastExit
+	}
+	if from.After(events.deleted) {
+		return monitorapi.Interval{}, false
+	}
+	humanMessage := fmt.Sprintf("volume of claim %s unmounted", claim)
+	return volumeInterval(pod, claim, volume, monitorapi.VolumeUnmountingReason, monitorapi.Info, humanMessage, from, events.deleted), true
+}
+
+func volumeInterval(pod *corev1.Pod, claim string, volume claimVolume, reason monitorapi.IntervalReason, level monitorapi.IntervalLevel, humanMessage string, from, to time.Time) monitorapi.Interval {
+	mb := monitorapi.NewMessage().Reason(reason).
+		Constructed(monitorapi.ConstructionOwnerVolumeLifecycle).
+		WithAnnotation(monitorapi.AnnotationPersistentVolumeClaim, claim).
+		HumanMessage(humanMessage)
+	if len(volume.volume) > 0 {
+		mb = mb.WithAnnotation(monitorapi.AnnotationPersistentVolume, volume.volume)
+	}
+	if len(volume.driver) > 0 {
+		mb = mb.WithAnnotation(monitorapi.AnnotationCSIDriver, volume.driver)
+	}
+	return monitorapi.NewInterval(monitorapi.SourceStorageMonitor, level).
+		Locator(monitorapi.NewLocator().PodFromPod(pod)).
+		Message(mb).
+		Display().
+		Build(from, to)
+}
+
+func volumeNodeKey(volume, nodeName string) string {
+	return fmt.Sprintf("%s@%s", volume, nodeName)
+}
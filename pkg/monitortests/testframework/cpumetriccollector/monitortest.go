@@ -78,32 +78,43 @@ func (w *cpuMetricCollector) CollectData(ctx context.Context, storageDir string,
 }
 
 func (w *cpuMetricCollector) collectCPUMetricsFromPrometheus(ctx context.Context, restConfig *rest.Config, startTime time.Time) ([]monitorapi.Interval, error) {
+	prometheusClient, kubeClient, intervals, err := newPrometheusClient(ctx, restConfig)
+	if err != nil || prometheusClient == nil {
+		return intervals, err
+	}
+
+	return w.collectCPUMetricsFromPrometheusClient(ctx, prometheusClient, kubeClient, startTime)
+}
+
+// newPrometheusClient returns a client for the in-cluster Prometheus once Thanos can reach it. The client is nil
+// when the cluster has no monitoring stack to query.
+func newPrometheusClient(ctx context.Context, restConfig *rest.Config) (prometheusv1.API, *kubernetes.Clientset, []monitorapi.Interval, error) {
 	kubeClient, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 	routeClient, err := routeclient.NewForConfig(restConfig)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	_, err = kubeClient.CoreV1().Namespaces().Get(ctx, "openshift-monitoring", metav1.GetOptions{})
 	if apierrors.IsNotFound(err) {
-		return []monitorapi.Interval{}, nil
+		return nil, nil, []monitorapi.Interval{}, nil
 	} else if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	prometheusClient, err := metrics.NewPrometheusClient(ctx, kubeClient, routeClient)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Prometheus client: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to create Prometheus client: %w", err)
 	}
 
 	if intervals, err := prometheus.EnsureThanosQueriersConnectedToPromSidecars(ctx, prometheusClient); err != nil {
-		return intervals, fmt.Errorf("failed to check Thanos querier connection to Prometheus sidecars: %w", err)
+		return nil, nil, intervals, fmt.Errorf("failed to check Thanos querier connection to Prometheus sidecars: %w", err)
 	}
 
-	return w.collectCPUMetricsFromPrometheusClient(ctx, prometheusClient, kubeClient, startTime)
+	return prometheusClient, kubeClient, nil, nil
 }
 
 func (w *cpuMetricCollector) collectCPUMetricsFromPrometheusClient(ctx context.Context, prometheusClient prometheusv1.API, kubeClient *kubernetes.Clientset, startTime time.Time) ([]monitorapi.Interval, error) {
@@ -0,0 +1,358 @@
+package cpumetriccollector
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"time"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	prometheustypes "github.com/prometheus/common/model"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// PressureThresholds are the values beyond which a node or container is considered under pressure.
+type PressureThresholds struct {
+	// MemoryWorkingSetPercent is the share of the node memory capacity in use by the working set of all cgroups.
+	MemoryWorkingSetPercent float64
+	// MemoryAvailablePercent is the share of node memory available for new allocations, pressure is below it.
+	MemoryAvailablePercent float64
+	// DiskUsagePercent and InodeUsagePercent apply to the fullest node filesystem.
+	DiskUsagePercent  float64
+	InodeUsagePercent float64
+	// OOMKills is the number of OOM kills of a single container within a minute.
+	OOMKills float64
+}
+
+// DefaultPressureThresholds stay ahead of the kubelet hard eviction thresholds, memory.available<100Mi,
+// nodefs.available<10% and nodefs.inodesFree<5%, so pressure shows up before pods are evicted.
+func DefaultPressureThresholds() PressureThresholds {
+	return PressureThresholds{
+		MemoryWorkingSetPercent: 90.0,
+		MemoryAvailablePercent:  10.0,
+		DiskUsagePercent:        85.0,
+		InodeUsagePercent:       90.0,
+		OOMKills:                1,
+	}
+}
+
+// filesystemSelector matches the filesystems backing the root, /var and the container storage, excluding /boot.
+const filesystemSelector = `fstype=~"xfs|ext4",mountpoint!~"/boot.*"`
+
+// pressureQuery is a metric that is charted whenever its value crosses the threshold.
+type pressureQuery struct {
+	name   string
+	source monitorapi.IntervalSource
+	reason monitorapi.IntervalReason
+	query  string
+	// label holds the node the value belongs to, unset for container queries
+	label     string
+	threshold float64
+	// below is set when values under the threshold are the ones under pressure
+	below bool
+	// count is set for counters, where reaching the threshold is enough
+	count bool
+	// message describes the pressure, formatted with the threshold
+	message string
+}
+
+func (q pressureQuery) exceeds(value float64) bool {
+	switch {
+	case q.below:
+		return value < q.threshold
+	case q.count:
+		// the increase is extrapolated, a single kill can come out slightly below one
+		return math.Round(value) >= q.threshold
+	default:
+		return value > q.threshold
+	}
+}
+
+func pressureQueries(thresholds PressureThresholds) []pressureQuery {
+	return []pressureQuery{
+		{
+			name:      "MemoryWorkingSet",
+			source:    monitorapi.SourceMemoryMonitor,
+			reason:    monitorapi.HighMemoryWorkingSetReason,
+			query:     `100 * sum by (node) (container_memory_working_set_bytes{id="/"}) / sum by (node) (kube_node_status_capacity{resource="memory"})`,
+			label:     "node",
+			threshold: thresholds.MemoryWorkingSetPercent,
+			message:   "memory working set above %.1f%% of capacity",
+		},
+		{
+			name:      "MemoryAvailable",
+			source:    monitorapi.SourceMemoryMonitor,
+			reason:    monitorapi.LowMemoryAvailableReason,
+			query:     `100 * node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes`,
+			label:     "instance",
+			threshold: thresholds.MemoryAvailablePercent,
+			below:     true,
+			message:   "available memory below %.1f%%",
+		},
+		{
+			name:      "DiskUsage",
+			source:    monitorapi.SourceDiskMonitor,
+			reason:    monitorapi.HighDiskUsageReason,
+			query:     fmt.Sprintf(`100 * max by (instance) (1 - node_filesystem_avail_bytes{%[1]s} / node_filesystem_size_bytes{%[1]s})`, filesystemSelector),
+			label:     "instance",
+			threshold: thresholds.DiskUsagePercent,
+			message:   "filesystem usage above %.1f%%",
+		},
+		{
+			name:      "InodeUsage",
+			source:    monitorapi.SourceDiskMonitor,
+			reason:    monitorapi.HighInodeUsageReason,
+			query:     fmt.Sprintf(`100 * max by (instance) (1 - node_filesystem_files_free{%[1]s} / node_filesystem_files{%[1]s})`, filesystemSelector),
+			label:     "instance",
+			threshold: thresholds.InodeUsagePercent,
+			message:   "inode usage above %.1f%%",
+		},
+		{
+			name:      "OOMKills",
+			source:    monitorapi.SourceMemoryMonitor,
+			reason:    monitorapi.ContainerOOMKilledReason,
+			query:     `sum by (namespace, pod, container) (increase(container_oom_events_total{container!=""}[1m]))`,
+			threshold: thresholds.OOMKills,
+			count:     true,
+			message:   "container OOM killed at least %.0f times within a minute",
+		},
+	}
+}
+
+type pressureDataPoint struct {
+	timestamp time.Time
+	metric    string
+	nodeName  string
+	nodeRole  string
+	value     float64
+}
+
+// nodePressureMetricCollector charts memory, disk and inode pressure on nodes and OOM kills of containers the same way
+// cpuMetricCollector charts high CPU.
+type nodePressureMetricCollector struct {
+	adminRESTConfig *rest.Config
+	thresholds      PressureThresholds
+	dataPoints      []pressureDataPoint
+}
+
+func NewNodePressureMetricCollector() monitortestframework.MonitorTest {
+	return NewNodePressureMetricCollectorWithThresholds(DefaultPressureThresholds())
+}
+
+func NewNodePressureMetricCollectorWithThresholds(thresholds PressureThresholds) monitortestframework.MonitorTest {
+	return &nodePressureMetricCollector{thresholds: thresholds}
+}
+
+func (w *nodePressureMetricCollector) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *nodePressureMetricCollector) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
+	return nil
+}
+
+func (w *nodePressureMetricCollector) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	const testName = "[Monitor:node-pressure-metric-collector][Jira:\"Node / Kubelet\"] monitor test node-pressure-metric-collector collection"
+	logger := logrus.WithField("MonitorTest", "NodePressureMetricCollector")
+
+	prometheusClient, kubeClient, intervals, err := newPrometheusClient(ctx, w.adminRESTConfig)
+	if err == nil && prometheusClient != nil {
+		intervals, err = w.collectPressureMetricsFromPrometheusClient(ctx, prometheusClient, kubeClient, beginning)
+	}
+	if err != nil {
+		// same as the CPU collector, Thanos may briefly lose its sidecars after disruptive operations
+		logger.WithError(err).Warn("failed during collection; recording as flake")
+		return nil, []*junitapi.JUnitTestCase{
+			{
+				Name: testName,
+				FailureOutput: &junitapi.FailureOutput{
+					Output: fmt.Sprintf("failed during collection\n%v", err),
+				},
+			},
+			{Name: testName},
+		}, nil
+	}
+
+	logger.Infof("collected %d node pressure intervals", len(intervals))
+	return intervals, nil, nil
+}
+
+func (w *nodePressureMetricCollector) collectPressureMetricsFromPrometheusClient(ctx context.Context, prometheusClient prometheusv1.API, kubeClient *kubernetes.Clientset, startTime time.Time) ([]monitorapi.Interval, error) {
+	logger := logrus.WithField("func", "collectPressureMetricsFromPrometheusClient")
+
+	nodeList, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to list nodes, node role information may be incomplete")
+		nodeList = &corev1.NodeList{}
+	}
+	nodeInfoMap := buildNodeInfoMap(nodeList)
+
+	timeRange := prometheusv1.Range{
+		Start: startTime,
+		End:   time.Now(),
+		Step:  30 * time.Second,
+	}
+
+	ret := []monitorapi.Interval{}
+	for _, query := range pressureQueries(w.thresholds) {
+		promVal, warnings, err := prometheusClient.QueryRange(ctx, query.query, timeRange)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s: %w", query.name, err)
+		}
+		for _, warning := range warnings {
+			logger.Warnf("%s metric query warning: %s", query.name, warning)
+		}
+		w.collectDataPointsFromMetrics(query, promVal, nodeInfoMap)
+		ret = append(ret, createIntervalsFromPressureMetrics(logger, query, promVal, nodeInfoMap)...)
+	}
+	return ret, nil
+}
+
+func (w *nodePressureMetricCollector) collectDataPointsFromMetrics(query pressureQuery, promVal prometheustypes.Value, nodeInfoMap map[string]nodeInfo) {
+	// container OOM kills are only charted, the timeline is per node
+	if len(query.label) == 0 || promVal.Type() != prometheustypes.ValMatrix {
+		return
+	}
+	for _, promSampleStream := range promVal.(prometheustypes.Matrix) {
+		nodeName, nodeRole := nodeFromMetric(query, promSampleStream.Metric, nodeInfoMap)
+		for _, currValue := range promSampleStream.Values {
+			w.dataPoints = append(w.dataPoints, pressureDataPoint{
+				timestamp: currValue.Timestamp.Time(),
+				metric:    query.name,
+				nodeName:  nodeName,
+				nodeRole:  nodeRole,
+				value:     float64(currValue.Value),
+			})
+		}
+	}
+}
+
+// nodeFromMetric resolves the node a sample belongs to, falling back to the raw label for nodes that are gone.
+func nodeFromMetric(query pressureQuery, metric prometheustypes.Metric, nodeInfoMap map[string]nodeInfo) (string, string) {
+	value := string(metric[prometheustypes.LabelName(query.label)])
+	info := nodeInfoMap[value]
+	if info.name == "" {
+		return value, info.nodeRole
+	}
+	return info.name, info.nodeRole
+}
+
+func createIntervalsFromPressureMetrics(logger logrus.FieldLogger, query pressureQuery, promVal prometheustypes.Value, nodeInfoMap map[string]nodeInfo) []monitorapi.Interval {
+	ret := []monitorapi.Interval{}
+	if promVal.Type() != prometheustypes.ValMatrix {
+		logger.WithField("type", promVal.Type()).Warning("unhandled prometheus value type received")
+		return ret
+	}
+
+	for _, promSampleStream := range promVal.(prometheustypes.Matrix) {
+		var locator monitorapi.Locator
+		if len(query.label) > 0 {
+			locator = monitorapi.NewLocator().NodeFromNameWithRole(nodeFromMetric(query, promSampleStream.Metric, nodeInfoMap))
+		} else {
+			metric := promSampleStream.Metric
+			locator = monitorapi.NewLocator().ContainerFromNames(string(metric["namespace"]), string(metric["pod"]), "", string(metric["container"]))
+		}
+
+		// Track consecutive samples beyond the threshold, keeping the worst value seen
+		var start, end *time.Time
+		var peak float64
+		for _, currValue := range promSampleStream.Values {
+			currTime := currValue.Timestamp.Time()
+			value := float64(currValue.Value)
+			if !query.exceeds(value) {
+				if start != nil {
+					ret = append(ret, createPressureInterval(query, locator, *start, *end, peak))
+					start, end = nil, nil
+				}
+				continue
+			}
+			if start == nil {
+				start = &currTime
+				peak = value
+			} else if (query.below && value < peak) || (!query.below && value > peak) {
+				peak = value
+			}
+			end = &currTime
+		}
+		if start != nil {
+			ret = append(ret, createPressureInterval(query, locator, *start, *end, peak))
+		}
+	}
+	return ret
+}
+
+func createPressureInterval(query pressureQuery, locator monitorapi.Locator, start, end time.Time, peak float64) monitorapi.Interval {
+	return monitorapi.NewInterval(query.source, monitorapi.Warning).
+		Locator(locator).
+		Message(monitorapi.NewMessage().
+			Reason(query.reason).
+			HumanMessage(fmt.Sprintf(query.message, query.threshold)).
+			WithAnnotation("threshold", fmt.Sprintf("%.1f", query.threshold)).
+			WithAnnotation("peak", fmt.Sprintf("%.2f", peak))).
+		Display().
+		Build(start, end)
+}
+
+func (*nodePressureMetricCollector) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (w *nodePressureMetricCollector) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	// This monitor test is purely for data collection, not for generating test cases
+	return nil, nil
+}
+
+func (w *nodePressureMetricCollector) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	logger := logrus.WithField("func", "WriteContentToStorage")
+
+	if len(w.dataPoints) == 0 {
+		logger.Info("No node pressure data points to export")
+		return nil
+	}
+
+	rows := make([]map[string]string, 0, len(w.dataPoints))
+	for _, dp := range w.dataPoints {
+		rows = append(rows, map[string]string{
+			"Timestamp": dp.timestamp.Format(time.RFC3339),
+			"Metric":    dp.metric,
+			"NodeName":  dp.nodeName,
+			"NodeRole":  dp.nodeRole,
+			"Value":     fmt.Sprintf("%.2f", dp.value),
+		})
+	}
+
+	dataFile := dataloader.DataFile{
+		TableName: "node_pressure_timeline",
+		Schema: map[string]dataloader.DataType{
+			"Timestamp": dataloader.DataTypeTimestamp,
+			"Metric":    dataloader.DataTypeString,
+			"NodeName":  dataloader.DataTypeString,
+			"NodeRole":  dataloader.DataTypeString,
+			"Value":     dataloader.DataTypeFloat64,
+		},
+		Rows: rows,
+	}
+
+	fileName := filepath.Join(storageDir, fmt.Sprintf("node-pressure-timeline%s-%s", timeSuffix, dataloader.AutoDataLoaderSuffix))
+	if err := dataloader.WriteDataFile(fileName, dataFile); err != nil {
+		logger.WithError(err).Warnf("Failed to write node pressure timeline autodl file: %s", fileName)
+		return err
+	}
+
+	logger.Infof("Wrote %d node pressure data points to autodl file: %s", len(rows), fileName)
+	return nil
+}
+
+func (*nodePressureMetricCollector) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,117 @@
+package cpumetriccollector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	prometheustypes "github.com/prometheus/common/model"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findPressureQuery(t *testing.T, reason monitorapi.IntervalReason) pressureQuery {
+	for _, query := range pressureQueries(DefaultPressureThresholds()) {
+		if query.reason == reason {
+			return query
+		}
+	}
+	t.Fatalf("no pressure query for %s", reason)
+	return pressureQuery{}
+}
+
+func sampleMatrix(metric prometheustypes.Metric, values []float64) prometheustypes.Matrix {
+	timestamps := createTimestamps("2024-01-01T10:00:00Z", len(values), 30*time.Second)
+	samples := make([]prometheustypes.SamplePair, len(values))
+	for i, value := range values {
+		samples[i] = prometheustypes.SamplePair{
+			Timestamp: prometheustypes.Time(timestamps[i].Unix() * 1000),
+			Value:     prometheustypes.SampleValue(value),
+		}
+	}
+	return prometheustypes.Matrix{{Metric: metric, Values: samples}}
+}
+
+func TestCreateIntervalsFromPressureMetrics(t *testing.T) {
+	logger := logrus.WithField("test", "pressure")
+	nodeInfoMap := map[string]nodeInfo{
+		"test-node-1": {name: "test-node-1", nodeRole: "worker"},
+	}
+
+	testCases := []struct {
+		name         string
+		reason       monitorapi.IntervalReason
+		metric       prometheustypes.Metric
+		values       []float64
+		expectedPeak []string
+	}{
+		{
+			name:   "memory working set below threshold",
+			reason: monitorapi.HighMemoryWorkingSetReason,
+			metric: prometheustypes.Metric{"node": "test-node-1"},
+			values: []float64{70.0, 85.0, 89.9},
+		},
+		{
+			name:         "memory working set above threshold twice",
+			reason:       monitorapi.HighMemoryWorkingSetReason,
+			metric:       prometheustypes.Metric{"node": "test-node-1"},
+			values:       []float64{91.0, 95.5, 80.0, 92.0},
+			expectedPeak: []string{"95.50", "92.00"},
+		},
+		{
+			name:         "available memory is under pressure below the threshold",
+			reason:       monitorapi.LowMemoryAvailableReason,
+			metric:       prometheustypes.Metric{"instance": "test-node-1"},
+			values:       []float64{40.0, 8.0, 3.5, 6.0, 30.0},
+			expectedPeak: []string{"3.50"},
+		},
+		{
+			name:         "inode usage through the end of the window",
+			reason:       monitorapi.HighInodeUsageReason,
+			metric:       prometheustypes.Metric{"instance": "test-node-1"},
+			values:       []float64{50.0, 91.0, 93.0},
+			expectedPeak: []string{"93.00"},
+		},
+		{
+			name:         "extrapolated OOM kill counts as one",
+			reason:       monitorapi.ContainerOOMKilledReason,
+			metric:       prometheustypes.Metric{"namespace": "e2e-test", "pod": "memory-hog", "container": "hog"},
+			values:       []float64{0, 0.9, 0, 0.2},
+			expectedPeak: []string{"0.90"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query := findPressureQuery(t, tc.reason)
+			intervals := createIntervalsFromPressureMetrics(logger, query, sampleMatrix(tc.metric, tc.values), nodeInfoMap)
+			require.Len(t, intervals, len(tc.expectedPeak))
+
+			for i, interval := range intervals {
+				assert.Equal(t, query.source, interval.Source)
+				assert.Equal(t, tc.reason, interval.Message.Reason)
+				assert.Equal(t, tc.expectedPeak[i], interval.Message.Annotations["peak"])
+				if len(query.label) > 0 {
+					assert.Equal(t, "test-node-1", interval.Locator.Keys[monitorapi.LocatorNodeKey])
+					assert.Equal(t, "worker", interval.Locator.Keys[monitorapi.LocatorNodeRoleKey])
+				} else {
+					assert.Equal(t, "memory-hog", interval.Locator.Keys[monitorapi.LocatorPodKey])
+					assert.Equal(t, "hog", interval.Locator.Keys[monitorapi.LocatorContainerKey])
+				}
+			}
+		})
+	}
+}
+
+func TestPressureQueriesUseConfiguredThresholds(t *testing.T) {
+	thresholds := DefaultPressureThresholds()
+	thresholds.DiskUsagePercent = 50.0
+
+	for _, query := range pressureQueries(thresholds) {
+		if query.reason == monitorapi.HighDiskUsageReason {
+			assert.True(t, query.exceeds(60.0))
+			assert.False(t, query.exceeds(40.0))
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package disruptioncauseattribution
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/disruptionlibrary"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/client-go/rest"
+)
+
+// disruptionCauseAttribution annotates every disruption with the cluster events that most likely caused it:
+// apiserver shutdowns, node reboots and NotReady, load balancer and on-prem haproxy/keepalived changes, and etcd
+// leader changes that overlap or shortly precede it.
+//
+// The attribution intervals are computed from the raw intervals.  The intervals other monitor tests compute, like
+// graceful shutdown windows and node reboot phases, are only available when writing content, so the data file is
+// attributed again from the final intervals.
+type disruptionCauseAttribution struct {
+}
+
+func NewDisruptionCauseAttribution() monitortestframework.MonitorTest {
+	return &disruptionCauseAttribution{}
+}
+
+func (*disruptionCauseAttribution) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (*disruptionCauseAttribution) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (*disruptionCauseAttribution) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (*disruptionCauseAttribution) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return disruptionlibrary.AttributeDisruptionCauses(startingIntervals), nil
+}
+
+func (*disruptionCauseAttribution) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (*disruptionCauseAttribution) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	disruptions := finalIntervals.Filter(disruptionlibrary.IsDisruptionBegan)
+	if len(disruptions) == 0 {
+		return nil
+	}
+
+	index := disruptionlibrary.NewCauseIndex(finalIntervals)
+	rows := []map[string]string{}
+	for _, disruption := range disruptions {
+		row := map[string]string{
+			"Backend":         monitorapi.BackendDisruptionNameFromLocator(disruption.Locator),
+			"From":            disruption.From.UTC().Format(time.RFC3339),
+			"DurationSeconds": fmt.Sprintf("%d", int(disruption.To.Sub(disruption.From).Seconds())),
+			"Cause":           string(disruptionlibrary.CauseUnknown),
+			"Subject":         "",
+			"Score":           "0",
+		}
+		if causes := index.LikelyCauses(disruption); len(causes) > 0 {
+			row["Cause"] = string(causes[0].Kind)
+			row["Subject"] = causes[0].Subject
+			row["Score"] = fmt.Sprintf("%.2f", causes[0].Score)
+		}
+		rows = append(rows, row)
+	}
+
+	dataFile := dataloader.DataFile{
+		TableName: "disruption_cause_attribution",
+		Schema: map[string]dataloader.DataType{
+			"Backend":         dataloader.DataTypeString,
+			"From":            dataloader.DataTypeTimestamp,
+			"DurationSeconds": dataloader.DataTypeInteger,
+			"Cause":           dataloader.DataTypeString,
+			"Subject":         dataloader.DataTypeString,
+			"Score":           dataloader.DataTypeFloat64,
+		},
+		Rows: rows,
+	}
+	fileName := filepath.Join(storageDir, fmt.Sprintf("disruption-cause-attribution%s-%s", timeSuffix, dataloader.AutoDataLoaderSuffix))
+	return dataloader.WriteDataFile(fileName, dataFile)
+}
+
+func (*disruptionCauseAttribution) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -152,7 +152,10 @@ func (w *cloudAvailability) WriteContentToStorage(ctx context.Context, storageDi
 	// Move tcpdump pcap file to storage directory
 	utility.MoveTcpdumpToStorage(w.tcpdumpHook, storageDir)
 
-	return nil
+	if w.disruptionChecker == nil {
+		return nil
+	}
+	return w.disruptionChecker.WriteContentToStorage(ctx, storageDir, timeSuffix)
 }
 
 func (w *cloudAvailability) Cleanup(ctx context.Context) error {
@@ -108,7 +108,10 @@ func (w *availability) WriteContentToStorage(ctx context.Context, storageDir, ti
 	// Move tcpdump pcap file to storage directory
 	utility.MoveTcpdumpToStorage(w.tcpdumpHook, storageDir)
 
-	return nil
+	if w.disruptionChecker == nil {
+		return nil
+	}
+	return w.disruptionChecker.WriteContentToStorage(ctx, storageDir, timeSuffix)
 }
 
 func (w *availability) Cleanup(ctx context.Context) error {
@@ -0,0 +1,145 @@
+package highcputestanalyzer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/utility"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+)
+
+// pressureReasons are the intervals of the node pressure collector an e2e test can overlap.
+var pressureReasons = sets.New[monitorapi.IntervalReason](
+	monitorapi.HighMemoryWorkingSetReason,
+	monitorapi.LowMemoryAvailableReason,
+	monitorapi.HighDiskUsageReason,
+	monitorapi.HighInodeUsageReason,
+	monitorapi.ContainerOOMKilledReason,
+)
+
+// nodePressureTestAnalyzer looks for e2e tests that overlap with memory, disk or inode pressure and container OOM kills,
+// and generates a data file with the results the same way highCPUTestAnalyzer does for high CPU.
+type nodePressureTestAnalyzer struct{}
+
+func NewNodePressureTestAnalyzer() monitortestframework.MonitorTest {
+	return &nodePressureTestAnalyzer{}
+}
+
+func (w *nodePressureTestAnalyzer) PrepareCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *nodePressureTestAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *nodePressureTestAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (*nodePressureTestAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (*nodePressureTestAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (*nodePressureTestAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	rows := findE2EIntervalsOverlappingPressure(finalIntervals)
+
+	dataFile := dataloader.DataFile{
+		TableName: "node_pressure_e2e_tests",
+		Schema: map[string]dataloader.DataType{
+			"TestName":  dataloader.DataTypeString,
+			"Pressure":  dataloader.DataTypeString,
+			"Locations": dataloader.DataTypeString,
+			"Success":   dataloader.DataTypeInteger,
+		},
+		Rows: rows,
+	}
+
+	fileName := filepath.Join(storageDir, fmt.Sprintf("node-pressure-e2etests%s-%s", timeSuffix, dataloader.AutoDataLoaderSuffix))
+	if err := dataloader.WriteDataFile(fileName, dataFile); err != nil {
+		logrus.WithError(err).Warnf("unable to write data file: %s", fileName)
+	}
+
+	return nil
+}
+
+func (*nodePressureTestAnalyzer) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+// findE2EIntervalsOverlappingPressure finds E2E test intervals that overlap with node pressure intervals. A test gets a
+// row for every kind of pressure it overlapped, listing the nodes or containers that were under it.
+func findE2EIntervalsOverlappingPressure(intervals monitorapi.Intervals) []map[string]string {
+	pressureIntervals := intervals.Filter(func(interval monitorapi.Interval) bool {
+		return (interval.Source == monitorapi.SourceMemoryMonitor || interval.Source == monitorapi.SourceDiskMonitor) &&
+			pressureReasons.Has(interval.Message.Reason)
+	})
+
+	// Filter for E2E test intervals, but the summary ones, not the started/finished variants
+	e2eTestIntervals := intervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceE2ETest && interval.Display
+	})
+
+	rows := []map[string]string{}
+	var failedTests int
+	for _, testInterval := range e2eTestIntervals {
+		testName, exists := testInterval.Locator.Keys[monitorapi.LocatorE2ETestKey]
+		if !exists {
+			continue
+		}
+
+		locations := map[monitorapi.IntervalReason]sets.Set[string]{}
+		for _, pressureInterval := range pressureIntervals {
+			if !utility.IntervalsOverlap(pressureInterval, testInterval) {
+				continue
+			}
+			reason := pressureInterval.Message.Reason
+			if _, ok := locations[reason]; !ok {
+				locations[reason] = sets.New[string]()
+			}
+			locations[reason].Insert(pressureInterval.Locator.OldLocator())
+		}
+		if len(locations) == 0 {
+			continue
+		}
+
+		success := "0"
+		if status, exists := testInterval.Message.Annotations[monitorapi.AnnotationStatus]; exists && status == "Passed" {
+			success = "1"
+		} else {
+			failedTests++
+		}
+
+		reasons := make([]string, 0, len(locations))
+		for reason := range locations {
+			reasons = append(reasons, string(reason))
+		}
+		sort.Strings(reasons)
+		for _, reason := range reasons {
+			rows = append(rows, map[string]string{
+				"TestName":  testName,
+				"Pressure":  reason,
+				"Locations": strings.Join(sets.List(locations[monitorapi.IntervalReason(reason)]), "; "),
+				"Success":   success,
+			})
+		}
+	}
+
+	logrus.Infof("Node pressure correlated tests: %d rows, %d failed tests", len(rows), failedTests)
+
+	return rows
+}
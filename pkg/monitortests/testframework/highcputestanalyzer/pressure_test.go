@@ -0,0 +1,46 @@
+package highcputestanalyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindE2EIntervalsOverlappingPressure(t *testing.T) {
+	now := time.Now()
+
+	e2eTest := func(name, status string, from, to time.Time) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceE2ETest, monitorapi.Info).
+			Locator(monitorapi.NewLocator().E2ETest(name)).
+			Message(monitorapi.NewMessage().WithAnnotation(monitorapi.AnnotationStatus, status)).
+			Display().
+			Build(from, to)
+	}
+	pressure := func(source monitorapi.IntervalSource, reason monitorapi.IntervalReason, node string, from, to time.Time) monitorapi.Interval {
+		return monitorapi.NewInterval(source, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().NodeFromName(node)).
+			Message(monitorapi.NewMessage().Reason(reason)).
+			Display().
+			Build(from, to)
+	}
+
+	intervals := monitorapi.Intervals{
+		e2eTest("failing test", "Failed", now, now.Add(10*time.Minute)),
+		e2eTest("passing test", "Passed", now.Add(20*time.Minute), now.Add(30*time.Minute)),
+		e2eTest("unaffected test", "Failed", now.Add(40*time.Minute), now.Add(50*time.Minute)),
+		pressure(monitorapi.SourceMemoryMonitor, monitorapi.LowMemoryAvailableReason, "node-a", now.Add(5*time.Minute), now.Add(25*time.Minute)),
+		pressure(monitorapi.SourceMemoryMonitor, monitorapi.LowMemoryAvailableReason, "node-b", now.Add(8*time.Minute), now.Add(9*time.Minute)),
+		pressure(monitorapi.SourceDiskMonitor, monitorapi.HighDiskUsageReason, "node-a", now.Add(time.Minute), now.Add(2*time.Minute)),
+		// high CPU is charted by the high CPU analyzer
+		pressure(monitorapi.SourceCPUMonitor, monitorapi.IntervalReason("HighCPUUsage"), "node-c", now.Add(40*time.Minute), now.Add(50*time.Minute)),
+	}
+
+	expected := []map[string]string{
+		{"TestName": "failing test", "Pressure": "HighDiskUsage", "Locations": "node/node-a", "Success": "0"},
+		{"TestName": "failing test", "Pressure": "LowMemoryAvailable", "Locations": "node/node-a; node/node-b", "Success": "0"},
+		{"TestName": "passing test", "Pressure": "LowMemoryAvailable", "Locations": "node/node-a", "Success": "1"},
+	}
+	assert.Equal(t, expected, findE2EIntervalsOverlappingPressure(intervals))
+}
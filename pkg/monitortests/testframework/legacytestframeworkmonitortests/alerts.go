@@ -106,6 +106,9 @@ func RunAlertTests(jobType *platformidentification.JobType,
 		ret = append(ret, junit...)
 	}
 
+	// Exceptions to the alert expectations that expired no longer apply, so point at them to be cleaned up:
+	ret = append(ret, allowedalerts.GetAlertCatalog().ExpiryJUnits(jobType, time.Now())...)
+
 	pendingIntervals := events.Filter(monitorapi.AlertPending())
 	firingIntervals := events.Filter(monitorapi.AlertFiring())
 
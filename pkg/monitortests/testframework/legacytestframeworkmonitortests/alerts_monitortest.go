@@ -24,13 +24,11 @@ type legacyAlertsMonitorTests struct {
 	duration                   time.Duration
 	recordedResources          monitorapi.ResourcesMap
 	clusterStabilityDuringTest *monitortestframework.ClusterStabilityDuringTest
-	flakeJunits                monitortestframework.FlakeJunits
 }
 
-func NewLegacyAlertsMonitorTests(info monitortestframework.MonitorTestInitializationInfo, flakeJunits monitortestframework.FlakeJunits) monitortestframework.MonitorTest {
+func NewLegacyAlertsMonitorTests(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
 	return &legacyAlertsMonitorTests{
 		clusterStabilityDuringTest: &info.ClusterStabilityDuringTest,
-		flakeJunits:                flakeJunits,
 	}
 }
 
@@ -71,9 +69,6 @@ func (w *legacyAlertsMonitorTests) EvaluateTestsFromConstructedIntervals(ctx con
 			w.adminRESTConfig, w.duration, w.recordedResources)...)
 	}
 
-	if w.flakeJunits {
-		junits = monitortestframework.JUnitsToFlakes(junits)
-	}
 	return junits, nil
 }
 
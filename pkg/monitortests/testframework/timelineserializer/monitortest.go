@@ -70,6 +70,19 @@ func (*timelineSerializer) WriteContentToStorage(ctx context.Context, storageDir
 	if err != nil {
 		errs = append(errs, err)
 	}
+	// the node and test bucket intervals in this timeline are only context, skip it when no pod was displaced.
+	if len(finalIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourcePodDisplacement
+	})) > 0 {
+		err = NewNonSpyglassEventIntervalRenderer("pod-displacements", BelongsInPodDisplacement).WriteRunData(storageDir, nil, customOrderedEvents, timeSuffix)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	err = NewOfflineViewerRenderer("everything", BelongsInEverything).WriteRunData(storageDir, nil, finalIntervals, timeSuffix)
+	if err != nil {
+		errs = append(errs, err)
+	}
 	err = NewPodEventIntervalRenderer().WriteRunData(storageDir, nil, customOrderedEvents, timeSuffix)
 	if err != nil {
 		errs = append(errs, err)
@@ -91,6 +91,9 @@ func BelongsInSpyglass(eventInterval monitorapi.Interval) bool {
 	if eventInterval.Source == monitorapi.SourcePodState {
 		return false
 	}
+	if eventInterval.Source == monitorapi.SourcePodDisplacement {
+		return false
+	}
 	// Pathologically repeating kube events:
 	if eventInterval.Source == monitorapi.SourceKubeEvent {
 		if eventInterval.Message.Annotations[monitorapi.AnnotationPathological] != "true" {
@@ -118,6 +121,15 @@ func BelongsInOperatorRollout(eventInterval monitorapi.Interval) bool {
 	return true
 }
 
+// BelongsInPodDisplacement shows pods being replaced next to the node and test bucket activity that usually explains it.
+func BelongsInPodDisplacement(eventInterval monitorapi.Interval) bool {
+	switch eventInterval.Source {
+	case monitorapi.SourcePodDisplacement, monitorapi.SourceNodeState, monitorapi.SourceTestBucket:
+		return true
+	}
+	return false
+}
+
 func BelongsInKubeAPIServer(eventInterval monitorapi.Interval) bool {
 	if monitorapi.IsE2ETest(eventInterval.Locator) {
 		return false
@@ -1,7 +1,9 @@
 package timelineserializer
 
 import (
+	"bytes"
 	_ "embed"
+	"os"
 	"testing"
 
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
@@ -27,3 +29,37 @@ func TestBelongsInKubeAPIServer(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderOfflineViewer(t *testing.T) {
+	inputIntervals, err := monitorserialization.IntervalsFromJSON(skipE2e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	viewerHTML, err := RenderOfflineViewer("<run>", inputIntervals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, placeholder := range []string{"EVENT_INTERVAL_TITLE_GOES_HERE", "EVENT_INTERVAL_COLUMNAR_GOES_HERE"} {
+		if bytes.Contains(viewerHTML, []byte(placeholder)) {
+			t.Errorf("placeholder %s was not replaced", placeholder)
+		}
+	}
+	if !bytes.Contains(viewerHTML, []byte("&lt;run&gt;")) {
+		t.Error("expected title to be escaped")
+	}
+}
+
+func TestOfflineViewerSkipsEmptyRuns(t *testing.T) {
+	dir := t.TempDir()
+	if err := NewOfflineViewerRenderer("everything", BelongsInEverything).WriteRunData(dir, nil, nil, "_suffix"); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no viewer without intervals, got %v", entries)
+	}
+}
@@ -0,0 +1,62 @@
+package timelineserializer
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+// viewerTemplate is a self-contained page that renders intervals without spyglass or any network access.
+// It is embedded directly rather than through bindata so the page and its decoder stay next to each other.
+//
+//go:embed viewer.html
+var viewerTemplate []byte
+
+type offlineViewerRenderer struct {
+	name   string
+	filter monitorapi.EventIntervalMatchesFunc
+}
+
+// NewOfflineViewerRenderer writes e2e-timelines_<name><timeSuffix>-viewer.html when any interval matches the filter.
+// Intervals are embedded in the columnar encoding, which keeps runs with hundreds of thousands of intervals small
+// enough to open in a browser.
+func NewOfflineViewerRenderer(name string, filter monitorapi.EventIntervalMatchesFunc) offlineViewerRenderer {
+	return offlineViewerRenderer{
+		name:   name,
+		filter: filter,
+	}
+}
+
+func (r offlineViewerRenderer) WriteRunData(artifactDir string, _ monitorapi.ResourcesMap, events monitorapi.Intervals, timeSuffix string) error {
+	intervals := events.Filter(r.filter)
+	if len(intervals) == 0 {
+		return nil
+	}
+	viewerHTML, err := RenderOfflineViewer(fmt.Sprintf("Intervals - %s%s", r.name, timeSuffix), intervals)
+	if err != nil {
+		return err
+	}
+	viewerPath := filepath.Join(artifactDir, fmt.Sprintf("e2e-timelines_%s%s-viewer.html", r.name, timeSuffix))
+	return os.WriteFile(viewerPath, viewerHTML, 0644)
+}
+
+// RenderOfflineViewer produces the offline viewer page for the intervals.
+func RenderOfflineViewer(title string, intervals monitorapi.Intervals) ([]byte, error) {
+	columnar, err := monitorserialization.IntervalsToColumnar(intervals)
+	if err != nil {
+		return nil, err
+	}
+	encoded := make([]byte, base64.StdEncoding.EncodedLen(len(columnar)))
+	base64.StdEncoding.Encode(encoded, columnar)
+
+	viewerHTML := bytes.ReplaceAll(viewerTemplate, []byte("EVENT_INTERVAL_TITLE_GOES_HERE"), []byte(html.EscapeString(title)))
+	viewerHTML = bytes.ReplaceAll(viewerHTML, []byte("EVENT_INTERVAL_COLUMNAR_GOES_HERE"), encoded)
+	return viewerHTML, nil
+}
@@ -74,9 +74,15 @@ func startClusterOperatorMonitoring(ctx context.Context, m monitorapi.RecorderWr
 				}
 			}
 			if changes := findOperatorVersionChange(oldCO.Status.Versions, co.Status.Versions); len(changes) > 0 {
+				msg := monitorapi.NewMessage().HumanMessagef("versions: %v", strings.Join(changes, ", "))
+				for _, version := range co.Status.Versions {
+					if version.Name == "operator" {
+						msg = msg.WithAnnotation(monitorapi.AnnotationOperatorVersion, version.Version)
+					}
+				}
 				intervals = append(intervals, monitorapi.NewInterval(monitorapi.SourceClusterOperatorMonitor, monitorapi.Info).
 					Locator(monitorapi.NewLocator().ClusterOperator(co.Name)).
-					Message(monitorapi.NewMessage().HumanMessagef("versions: %v", strings.Join(changes, ", "))).
+					Message(msg).
 					Build(intervalTime, intervalTime))
 			}
 			return intervals
@@ -121,6 +121,9 @@ func recordAddOrUpdateEvent(
 	if obj.Reason != "" {
 		message = message.Reason(monitorapi.IntervalReason(obj.Reason))
 	}
+	if hop, ok := obj.Annotations[monitorapi.UpgradeHopEventAnnotation]; ok {
+		message = message.WithAnnotation(monitorapi.AnnotationUpgradeHop, hop)
+	}
 
 	// special case some very common events
 	switch obj.Reason {
@@ -35,10 +35,12 @@ import (
 
 	"github.com/openshift/origin/pkg/clioptions/clusterdiscovery"
 	"github.com/openshift/origin/pkg/clioptions/clusterinfo"
+	"github.com/openshift/origin/pkg/clusterstability"
 	"github.com/openshift/origin/pkg/defaultmonitortests"
 	e2e_analysis "github.com/openshift/origin/pkg/e2eanalysis"
 	"github.com/openshift/origin/pkg/monitor"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitor/otlpexport"
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
 	"github.com/openshift/origin/pkg/monitortestframework"
 	"github.com/openshift/origin/pkg/riskanalysis"
@@ -48,15 +50,6 @@ import (
 	"github.com/openshift/origin/pkg/test/preconditions"
 )
 
-const (
-	// Dump pod displacements with at least 3 instances
-	minChainLen = 3
-
-	setupEvent       = "Setup"
-	upgradeEvent     = "Upgrade"
-	postUpgradeEvent = "PostUpgrade"
-)
-
 // GinkgoRunSuiteOptions is used to run a suite of tests by invoking each test
 // as a call to a child worker (the run-tests command).
 type GinkgoRunSuiteOptions struct {
@@ -82,6 +75,9 @@ type GinkgoRunSuiteOptions struct {
 
 	ClusterStabilityDuringTest string
 
+	// StabilityGateCriteria are what the cluster has to meet before the suite starts, as NAME or NAME=TIMEOUT.
+	StabilityGateCriteria []string
+
 	IncludeSuccessOutput bool
 
 	CommandEnv []string
@@ -109,10 +105,16 @@ func NewGinkgoRunSuiteOptions(streams genericclioptions.IOStreams) *GinkgoRunSui
 		panic(fmt.Sprintf("failed to create default retry strategy: %v", err))
 	}
 
+	var stabilityGateCriteria []string
+	for _, criterion := range clusterstability.DefaultCriteria {
+		stabilityGateCriteria = append(stabilityGateCriteria, fmt.Sprintf("%s=%s", criterion.Name, criterion.Timeout))
+	}
+
 	return &GinkgoRunSuiteOptions{
-		IOStreams:     streams,
-		ShardStrategy: "hash",
-		RetryStrategy: defaultStrategy,
+		IOStreams:             streams,
+		ShardStrategy:         "hash",
+		RetryStrategy:         defaultStrategy,
+		StabilityGateCriteria: stabilityGateCriteria,
 	}
 }
 
@@ -123,6 +125,8 @@ func (o *GinkgoRunSuiteOptions) BindFlags(flags *pflag.FlagSet) {
 	flags.BoolVar(&o.DryRun, "dry-run", o.DryRun, "Print the tests to run without executing them.")
 	flags.BoolVar(&o.PrintCommands, "print-commands", o.PrintCommands, "Print the sub-commands that would be executed instead.")
 	flags.StringVar(&o.ClusterStabilityDuringTest, "cluster-stability", o.ClusterStabilityDuringTest, "cluster stability during test, usually dependent on the job: Stable or Disruptive. Empty default will be treated as Stable.")
+	flags.StringSliceVar(&o.StabilityGateCriteria, "stability-gate", o.StabilityGateCriteria,
+		fmt.Sprintf("criteria the cluster has to meet before tests start, as NAME or NAME=TIMEOUT. Current criteria are: [%s]", strings.Join(clusterstability.KnownCriteria(), ", ")))
 	flags.StringVar(&o.JUnitDir, "junit-dir", o.JUnitDir, "The directory to write test reports to.")
 	flags.IntVar(&o.Count, "count", o.Count, "Run each test a specified number of times. Defaults to 1 or the suite's preferred value. -1 will run forever.")
 	flags.BoolVar(&o.FailFast, "fail-fast", o.FailFast, "If a test fails, exit immediately.")
@@ -147,6 +151,9 @@ func (o *GinkgoRunSuiteOptions) Validate() error {
 	default:
 		return fmt.Errorf("unknown --cluster-stability, %q, expected Stable, Disruptive, or SpotCheck", o.ClusterStabilityDuringTest)
 	}
+	if _, err := clusterstability.ParseCriteria(o.StabilityGateCriteria); err != nil {
+		return fmt.Errorf("invalid --stability-gate: %w", err)
+	}
 	return nil
 }
 
@@ -506,10 +513,14 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, clusterConfig *clusterdisc
 	// This is useful in development for rapid iteration where cluster stability
 	// verification may be unnecessary and time-consuming.
 	var stableClusterTestResults []*junitapi.JUnitTestCase
+	var stabilityGateResult *clusterstability.Result
 	if os.Getenv("OPENSHIFT_TESTS_SKIP_STABLE_CLUSTER") == "" {
-		logrus.Infof("Waiting for all cluster operators to become stable")
-		var err error
-		stableClusterTestResults, err = clusterinfo.WaitForStableCluster(ctx, restConfig)
+		logrus.Infof("Waiting for the cluster to meet the stability criteria: %s", strings.Join(o.StabilityGateCriteria, ", "))
+		criteria, err := clusterstability.ParseCriteria(o.StabilityGateCriteria)
+		if err != nil {
+			return err
+		}
+		stabilityGateResult, stableClusterTestResults, err = clusterinfo.WaitAtStabilityGate(ctx, restConfig, criteria)
 		if err != nil {
 			logrus.Errorf("Error waiting for stable cluster: %v", err)
 		}
@@ -532,15 +543,11 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, clusterConfig *clusterdisc
 	if err := m.Start(ctx); err != nil {
 		return err
 	}
-
-	pc, err := SetupNewPodCollector(ctx)
-	if err != nil {
-		return err
+	if stabilityGateResult != nil {
+		// the gate waited before the monitor started, so its intervals are moved to when the monitor did
+		monitorEventRecorder.AddIntervals(stabilityGateResult.Intervals(time.Now())...)
 	}
 
-	pc.SetEvents([]string{setupEvent})
-	pc.Run(ctx)
-
 	// if we run a single test, always include success output
 	includeSuccess := o.IncludeSuccessOutput
 	if len(tests) == 1 && count == 1 {
@@ -653,9 +660,6 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, clusterConfig *clusterdisc
 	logrus.Infof("Completed Early test bucket in %v", time.Since(earlyStartTime))
 	tests = append(tests, early...)
 
-	// TODO: will move to the monitor
-	pc.SetEvents([]string{upgradeEvent})
-
 	// Run kube, storage, openshift, and must-gather tests. If user specified a count of -1,
 	// we loop indefinitely.
 	for i := 0; (i < 1 || count == -1) && testCtx.Err() == nil; i++ {
@@ -730,9 +734,6 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, clusterConfig *clusterdisc
 		tests = append(tests, mustGatherTestsCopy...)
 	}
 
-	// TODO: will move to the monitor
-	pc.SetEvents([]string{postUpgradeEvent})
-
 	// run Late test suits after everything else
 	lateIntervalID, lateStartTime := recordTestBucketInterval(monitorEventRecorder, "Late")
 	q.Execute(testCtx, late, parallelism, testOutputConfig, abortFn)
@@ -740,23 +741,6 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, clusterConfig *clusterdisc
 	logrus.Infof("Completed Late test bucket in %v", time.Since(lateStartTime))
 	tests = append(tests, late...)
 
-	// TODO: will move to the monitor
-	if len(o.JUnitDir) > 0 {
-		pc.ComputePodTransitions()
-		data, err := pc.JsonDump()
-		if err != nil {
-			logrus.Errorf("Unable to dump pod placement data: %v", err)
-		} else {
-			if err := ioutil.WriteFile(filepath.Join(o.JUnitDir, "pod-placement-data.json"), data, 0644); err != nil {
-				logrus.Errorf("Unable to write pod placement data: %v", err)
-			}
-		}
-		chains := pc.PodDisplacements().Dump(minChainLen)
-		if err := ioutil.WriteFile(filepath.Join(o.JUnitDir, "pod-transitions.txt"), []byte(chains), 0644); err != nil {
-			logrus.Errorf("Unable to write pod placement data: %v", err)
-		}
-	}
-
 	// calculate the effective test set we ran, excluding any incompletes
 	tests, _ = splitTests(tests, func(t *testCase) bool { return t.success || t.flake || t.failed || t.skipped })
 
@@ -946,6 +930,16 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, clusterConfig *clusterdisc
 		}
 
 		writeRunSuiteOptions(seed, totalNodes, workerNodes, parallelism, monitorTestInfo, o.JUnitDir, timeSuffix)
+		if err := otlpexport.WriteRun(ctx, o.JUnitDir, timeSuffix, otlpexport.Run{
+			SuiteName:         junitSuiteName,
+			Start:             start,
+			End:               endWithRetries,
+			TestAttempts:      otlpTestAttempts(tests),
+			MonitorTestPhases: m.MonitorTestPhaseTimings(),
+			Intervals:         monitorEventRecorder.Intervals(start, endWithRetries),
+		}); err != nil {
+			fmt.Fprintf(o.ErrOut, "error: Unable to export OTLP traces: %v\n", err)
+		}
 		e2e_analysis.WriteDurations("e2e", map[string]time.Duration{"e2e": duration, "e2e_with_retries": durationWithRetries}, o.JUnitDir, timeSuffix)
 	}
 
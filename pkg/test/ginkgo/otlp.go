@@ -0,0 +1,47 @@
+package ginkgo
+
+import (
+	"github.com/openshift/origin/pkg/monitor/otlpexport"
+)
+
+// otlpTestAttempts lists every attempt of every test, including the earlier attempts of retried tests.
+func otlpTestAttempts(tests []*testCase) []otlpexport.TestAttempt {
+	seen := map[*testCase]bool{}
+	ret := []otlpexport.TestAttempt{}
+	for _, test := range tests {
+		for curr := test; curr != nil; curr = curr.previous {
+			if seen[curr] {
+				continue
+			}
+			seen[curr] = true
+
+			attempt := 1
+			for previous := curr.previous; previous != nil; previous = previous.previous {
+				attempt++
+			}
+			ret = append(ret, otlpexport.TestAttempt{
+				Name:    curr.name,
+				Attempt: attempt,
+				Start:   curr.start,
+				End:     curr.end,
+				Result:  otlpTestResult(curr),
+			})
+		}
+	}
+	return ret
+}
+
+func otlpTestResult(test *testCase) string {
+	switch {
+	case test.timedOut:
+		return "timedout"
+	case test.flake:
+		return "flaked"
+	case test.failed:
+		return "failed"
+	case test.skipped:
+		return "skipped"
+	default:
+		return "passed"
+	}
+}
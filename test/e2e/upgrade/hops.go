@@ -0,0 +1,184 @@
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	configv1client "github.com/openshift/client-go/config/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/kubernetes/test/e2e/framework"
+	"k8s.io/kubernetes/test/e2e/upgrades"
+
+	"github.com/openshift/origin/test/extended/util/disruption"
+)
+
+var machineConfigPoolsResource = schema.GroupVersionResource{
+	Group:    "machineconfiguration.openshift.io",
+	Version:  "v1",
+	Resource: "machineconfigpools",
+}
+
+// hopOptions control a single hop of a multi-hop upgrade.
+type hopOptions struct {
+	// pauseWorkerPool keeps the worker pool paused during the hop, so workers skip it.
+	pauseWorkerPool bool
+	// ackAdminGates acknowledges every admin gate before the hop starts.
+	ackAdminGates bool
+}
+
+// upgradeHopOptions are the options of each hop by its position, starting at 1.
+var upgradeHopOptions = map[int]hopOptions{}
+
+// SetUpgradeHopOptions sets the KEY=VALUE options of the hop at the given position, starting at 1.  Allowed options
+// are:
+//
+// * pause-worker-pool=true|false - keep the worker machine config pool paused during this hop
+// * ack-admin-gates=true|false - acknowledge every admin gate before this hop starts
+func SetUpgradeHopOptions(hop int, options []string) error {
+	opts := hopOptions{}
+	for _, opt := range options {
+		parts := strings.SplitN(opt, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("expected hop %d option of the form KEY=VALUE instead of %q", hop, opt)
+		}
+		value, err := strconv.ParseBool(parts[1])
+		if err != nil {
+			return fmt.Errorf("hop %d option %s must be true or false", hop, parts[0])
+		}
+		switch parts[0] {
+		case "pause-worker-pool":
+			opts.pauseWorkerPool = value
+		case "ack-admin-gates":
+			opts.ackAdminGates = value
+		default:
+			return fmt.Errorf("unrecognized upgrade hop option: %s", parts[0])
+		}
+	}
+	upgradeHopOptions[hop] = opts
+	return nil
+}
+
+// upgradeHop identifies one upgrade of a multi-hop upgrade.
+type upgradeHop struct {
+	number int
+	count  int
+	hopOptions
+}
+
+// testName qualifies name with the hop when there is more than one, so every hop gets a result of its own and the
+// unqualified name is left for the result of the whole upgrade.
+func (h upgradeHop) testName(name string) string {
+	if h.count <= 1 {
+		return name
+	}
+	return fmt.Sprintf("%s [hop %d/%d]", name, h.number, h.count)
+}
+
+// upgradeThroughHops upgrades to each of the versions in order.  The worker pool is paused and unpaused as the hops
+// ask, and when the last hop leaves it paused it is unpaused and the workers are waited on.
+func upgradeThroughHops(f *framework.Framework, c configv1client.Interface, dc dynamic.Interface, config *rest.Config, versions []upgrades.VersionContext) error {
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	workersPaused := false
+	for i, version := range versions {
+		hop := upgradeHop{number: i + 1, count: len(versions), hopOptions: upgradeHopOptions[i+1]}
+
+		if hop.pauseWorkerPool != workersPaused {
+			if err := setWorkerPoolPaused(dc, hop.pauseWorkerPool); err != nil {
+				return fmt.Errorf("before upgrade to %s: %w", version.NodeImage, err)
+			}
+			workersPaused = hop.pauseWorkerPool
+		}
+		if hop.ackAdminGates {
+			if err := ackAdminGates(kubeClient); err != nil {
+				return fmt.Errorf("before upgrade to %s: %w", version.NodeImage, err)
+			}
+		}
+
+		if err := clusterUpgrade(f, c, dc, config, version, hop); err != nil {
+			return fmt.Errorf("during upgrade to %s: %w", version.NodeImage, err)
+		}
+	}
+
+	if !workersPaused {
+		return nil
+	}
+	if err := setWorkerPoolPaused(dc, false); err != nil {
+		return err
+	}
+	return disruption.RecordJUnit(
+		f,
+		"[sig-mco] Worker machine config pool completes upgrade after it is unpaused",
+		func() (error, bool) {
+			if err := waitForPoolUpdated(dc, "worker", 60*time.Minute); err != nil {
+				return fmt.Errorf("Worker pool did not complete upgrade: %v", err), false
+			}
+			return nil, false
+		},
+	)
+}
+
+func setWorkerPoolPaused(dc dynamic.Interface, paused bool) error {
+	framework.Logf("Setting worker machine config pool paused=%v", paused)
+	patch := []byte(fmt.Sprintf(`{"spec":{"paused":%v}}`, paused))
+	_, err := dc.Resource(machineConfigPoolsResource).Patch(context.Background(), "worker", types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to set worker machine config pool paused=%v: %w", paused, err)
+	}
+	return nil
+}
+
+// ackAdminGates acknowledges every gate in openshift-config-managed/admin-gates in openshift-config/admin-acks.
+func ackAdminGates(client kubernetes.Interface) error {
+	gates, err := client.CoreV1().ConfigMaps("openshift-config-managed").Get(context.Background(), "admin-gates", metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to get configmap openshift-config-managed/admin-gates: %w", err)
+	}
+	if len(gates.Data) == 0 {
+		framework.Logf("No admin gates to acknowledge")
+		return nil
+	}
+
+	acks := map[string]string{}
+	for gate := range gates.Data {
+		acks[gate] = "true"
+	}
+	patch, err := json.Marshal(map[string]interface{}{"data": acks})
+	if err != nil {
+		return err
+	}
+	if _, err := client.CoreV1().ConfigMaps("openshift-config").Patch(context.Background(), "admin-acks", types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("unable to update configmap openshift-config/admin-acks: %w", err)
+	}
+	framework.Logf("Acknowledged %d admin gates", len(acks))
+	return nil
+}
+
+// waitForPoolUpdated waits until the pool has observed its latest spec and finished updating to it.  Checking the
+// observed generation keeps us from trusting the Updated condition from before a spec change.
+func waitForPoolUpdated(dc dynamic.Interface, name string, timeout time.Duration) error {
+	mcps := dc.Resource(machineConfigPoolsResource)
+	return wait.PollImmediate(10*time.Second, timeout, func() (bool, error) {
+		pool, err := mcps.Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			framework.Logf("error getting pool %s: %v", name, err)
+			return false, nil
+		}
+		observedGeneration, _, _ := unstructured.NestedInt64(pool.Object, "status", "observedGeneration")
+		if observedGeneration < pool.GetGeneration() {
+			return false, nil
+		}
+		updated, _ := IsPoolUpdated(mcps, name)
+		return updated, nil
+	})
+}
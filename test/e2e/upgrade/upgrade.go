@@ -188,11 +188,17 @@ var _ = g.Describe("[sig-arch][Feature:ClusterUpgrade]", func() {
 			},
 			upgradeTests,
 			func() {
-				for i := 1; i < len(upgCtx.Versions); i++ {
-					framework.ExpectNoError(
-						clusterUpgrade(f, client, dynamicClient, config, upgCtx.Versions[i]),
-						fmt.Sprintf("during upgrade to %s", upgCtx.Versions[i].NodeImage))
+				start := time.Now()
+				err := upgradeThroughHops(f, client, dynamicClient, config, upgCtx.Versions[1:])
+				// every hop records its own results, this one covers all of them.
+				if hops := len(upgCtx.Versions) - 1; hops > 1 {
+					failure := ""
+					if err != nil {
+						failure = fmt.Sprintf("Cluster did not complete all %d upgrade hops: %v", hops, err)
+					}
+					disruption.RecordJUnitResult(f, clusterCompletesUpgradeTestName, time.Since(start), failure)
 				}
+				framework.ExpectNoError(err)
 				// Wait for all operators to stabilize after upgrade. MCO may
 				// report upgrade complete before the last worker node finishes
 				// rebooting, and workloads need additional time to start after
@@ -327,14 +333,17 @@ func getUpgradeContext(c configv1client.Interface, upgradeImage string) (*upgrad
 
 var errControlledAbort = fmt.Errorf("beginning abort")
 
-func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynamic.Interface, config *rest.Config, version upgrades.VersionContext) error {
+// clusterCompletesUpgradeTestName is recorded for every hop and, when there is more than one, for the whole upgrade.
+const clusterCompletesUpgradeTestName = "[sig-cluster-lifecycle] Cluster completes upgrade"
+
+func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynamic.Interface, config *rest.Config, version upgrades.VersionContext, hop upgradeHop) error {
 	fmt.Fprintf(os.Stderr, "\n\n\n")
 	defer func() { fmt.Fprintf(os.Stderr, "\n\n\n") }()
 
 	// ignore the failure here, we don't want this to fail the upgrade, we want it to fail this particular test.
 	_ = disruption.RecordJUnit(
 		f,
-		"[bz-Routing] console is not available via ingress",
+		hop.testName("[bz-Routing] console is not available via ingress"),
 		func() (error, bool) {
 			pollErr := wait.PollImmediateWithContext(context.TODO(), 1*time.Second, 10*time.Minute, func(ctx context.Context) (bool, error) {
 				consoleSampler := disruptioningress.CreateConsoleRouteAvailableWithNewConnections(config)
@@ -443,7 +452,7 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 	framework.Logf("Upgrade time limit set as %0.2f", upgradeDurationLimit.Minutes())
 
 	framework.Logf("Starting upgrade to version=%s image=%s attempt=%s", version.Version.String(), version.NodeImage, uid)
-	recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeStartedReason, fmt.Sprintf("version/%s image/%s", version.Version.String(), version.NodeImage), false)
+	recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeStartedReason, fmt.Sprintf("version/%s image/%s", version.Version.String(), version.NodeImage), false, hop)
 
 	// decide whether to abort at a percent
 	abortAt := upgradeAbortAt
@@ -473,12 +482,12 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 	defer monitor.Describe(f)
 
 	//used below in separate paths
-	clusterCompletesUpgradeTestName := "[sig-cluster-lifecycle] Cluster completes upgrade"
+	clusterCompletesUpgradeTestName := hop.testName(clusterCompletesUpgradeTestName)
 
 	// trigger the update and record verification as an independent step
 	if err := disruption.RecordJUnit(
 		f,
-		"[sig-cluster-lifecycle] Cluster version operator acknowledges upgrade",
+		hop.testName("[sig-cluster-lifecycle] Cluster version operator acknowledges upgrade"),
 		func() (error, bool) {
 			cv, err := c.ConfigV1().ClusterVersions().Get(context.Background(), "version", metav1.GetOptions{})
 			if err != nil {
@@ -544,7 +553,7 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 			framework.Logf("Cluster version operator failed to acknowledge upgrade request")
 			return fmt.Errorf("Cluster did not complete upgrade: operator failed to acknowledge upgrade request"), false
 		})
-		recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeFailedReason, fmt.Sprintf("failed to acknowledge version: %v", err), true)
+		recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeFailedReason, fmt.Sprintf("failed to acknowledge version: %v", err), true, hop)
 		return err
 	}
 
@@ -592,7 +601,7 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 					}); err != nil {
 						return false, err
 					}
-					recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeRollbackReason, fmt.Sprintf("version/%s image/%s", original.Status.Desired.Version, original.Status.Desired.Version), false)
+					recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeRollbackReason, fmt.Sprintf("version/%s image/%s", original.Status.Desired.Version, original.Status.Desired.Version), false, hop)
 					aborted = true
 					action = "aborted upgrade"
 					return false, nil
@@ -608,13 +617,13 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 			}
 
 			framework.Logf("Completed %s to %s", action, versionString(desired))
-			recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeVersionReason, fmt.Sprintf("version/%s image/%s", updated.Status.Desired.Version, updated.Status.Desired.Version), false)
+			recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeVersionReason, fmt.Sprintf("version/%s image/%s", updated.Status.Desired.Version, updated.Status.Desired.Version), false, hop)
 
 			// record whether the cluster was fast or slow upgrading.  Don't fail the test, we still want signal on the actual tests themselves.
 			upgradeEnded := time.Now()
 			upgradeDuration := upgradeEnded.Sub(upgradeStarted)
 			e2e_analysis.WriteDurations("upgrade", map[string]time.Duration{"upgrade": upgradeDuration}, framework.TestContext.ReportDir, fmt.Sprintf("_%s", upgradeStarted.UTC().Format("20060102-150405")))
-			testCaseName := fmt.Sprintf("[sig-cluster-lifecycle] cluster upgrade should complete in a reasonable time")
+			testCaseName := hop.testName("[sig-cluster-lifecycle] cluster upgrade should complete in a reasonable time")
 			failure := ""
 			if upgradeDuration > upgradeDurationLimit {
 				failure = fmt.Sprintf("%s to %s took too long: %0.2f minutes (for this platform/network, it should be less than %0.2f minutes)", action, versionString(desired), upgradeDuration.Minutes(), upgradeDurationLimit.Minutes())
@@ -624,7 +633,7 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 			return nil, false
 		},
 	); err != nil {
-		recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeFailedReason, fmt.Sprintf("failed to reach cluster version: %v", err), true)
+		recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeFailedReason, fmt.Sprintf("failed to reach cluster version: %v", err), true, hop)
 		return err
 	}
 
@@ -634,7 +643,7 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 
 	if err := disruption.RecordJUnit(
 		f,
-		"[sig-mco] Machine config pools complete upgrade",
+		hop.testName("[sig-mco] Machine config pools complete upgrade"),
 		func() (error, bool) {
 			framework.Logf("Waiting on pools to be upgraded")
 			if err := wait.PollImmediate(10*time.Second, 30*time.Minute, func() (bool, error) {
@@ -692,18 +701,18 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 			return nil, false
 		},
 	); err != nil {
-		recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeFailedReason, fmt.Sprintf("failed to upgrade nodes: %v", err), true)
+		recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeFailedReason, fmt.Sprintf("failed to upgrade nodes: %v", err), true, hop)
 		return err
 	}
 
 	if errMasterUpdating != nil {
-		recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeFailedReason, fmt.Sprintf("master was updating after cluster version reached level: %v", errMasterUpdating), true)
+		recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeFailedReason, fmt.Sprintf("master was updating after cluster version reached level: %v", errMasterUpdating), true, hop)
 		return errMasterUpdating
 	}
 
 	if err := disruption.RecordJUnit(
 		f,
-		"[sig-cluster-lifecycle] ClusterOperators are available and not degraded after upgrade",
+		hop.testName("[sig-cluster-lifecycle] ClusterOperators are available and not degraded after upgrade"),
 		func() (error, bool) {
 			if err := operator.WaitForOperatorsToSettle(context.TODO(), c, 5); err != nil {
 				return err, false
@@ -711,17 +720,17 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 			return nil, false
 		},
 	); err != nil {
-		recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeFailedReason, fmt.Sprintf("failed to settle operators: %v", err), true)
+		recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeFailedReason, fmt.Sprintf("failed to settle operators: %v", err), true, hop)
 		return err
 	}
 
-	recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeCompleteReason, fmt.Sprintf("version/%s image/%s", updated.Status.Desired.Version, updated.Status.Desired.Image), false)
+	recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeCompleteReason, fmt.Sprintf("version/%s image/%s", updated.Status.Desired.Version, updated.Status.Desired.Image), false, hop)
 	return nil
 }
 
 // recordClusterEvent attempts to record an event to the cluster to indicate actions taken during an
 // upgrade for timeline review.
-func recordClusterEvent(client kubernetes.Interface, uid, action string, reason monitorapi.IntervalReason, note string, warning bool) {
+func recordClusterEvent(client kubernetes.Interface, uid, action string, reason monitorapi.IntervalReason, note string, warning bool, hop upgradeHop) {
 	currentTime := metav1.MicroTime{Time: time.Now()}
 	t := v1.EventTypeNormal
 	if warning {
@@ -733,6 +742,10 @@ func recordClusterEvent(client kubernetes.Interface, uid, action string, reason
 	_, err := client.EventsV1().Events(ns).Create(ctx, &eventsv1.Event{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: fmt.Sprintf("%v.%x", "cluster", currentTime.UnixNano()),
+			// tags the interval of the event with the hop, so the timeline shows which hop was active
+			Annotations: map[string]string{
+				monitorapi.UpgradeHopEventAnnotation: strconv.Itoa(hop.number),
+			},
 		},
 		Regarding:           v1.ObjectReference{Kind: "ClusterVersion", Name: "cluster", Namespace: ns, APIVersion: configv1.GroupVersion.String()},
 		Action:              action,
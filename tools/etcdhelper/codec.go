@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"io"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	jsonserializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"k8s.io/kubectl/pkg/scheme"
+
+	"github.com/openshift/api"
+)
+
+func init() {
+	api.Install(scheme.Scheme)
+	api.InstallKube(scheme.Scheme)
+	apiextensionsv1.AddToScheme(scheme.Scheme)
+	apiregistrationv1.AddToScheme(scheme.Scheme)
+}
+
+// codec decodes values stored in etcd and encodes them as JSON.  Kubernetes and OpenShift types are stored as
+// protobuf or JSON and are decoded with the scheme.  Custom resources are stored as JSON the scheme has no types
+// for, so they are decoded as unstructured.
+type codec struct {
+	decoder runtime.Decoder
+}
+
+func newCodec() *codec {
+	return &codec{decoder: scheme.Codecs.UniversalDeserializer()}
+}
+
+func (c *codec) decode(value []byte) (runtime.Object, *schema.GroupVersionKind, error) {
+	obj, gvk, err := c.decoder.Decode(value, nil, nil)
+	if err == nil {
+		return obj, gvk, nil
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(value), []byte("{")) {
+		return nil, nil, err
+	}
+
+	u := &unstructured.Unstructured{}
+	if jsonErr := u.UnmarshalJSON(value); jsonErr != nil {
+		// the scheme error says more about what the value might be.
+		return nil, nil, err
+	}
+	unstructuredGVK := u.GroupVersionKind()
+	return u, &unstructuredGVK, nil
+}
+
+func (c *codec) encode(obj runtime.Object, w io.Writer, pretty bool) error {
+	encoder := jsonserializer.NewSerializer(jsonserializer.DefaultMetaFactory, scheme.Scheme, scheme.Scheme, pretty)
+	return encoder.Encode(obj, w)
+}
@@ -7,22 +7,18 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
-	jsonserializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
-	"k8s.io/kubectl/pkg/scheme"
-
 	"go.etcd.io/etcd/client/pkg/v3/transport"
 	"go.etcd.io/etcd/client/v3"
-
-	"github.com/openshift/api"
 )
 
-func init() {
-	api.Install(scheme.Scheme)
-	api.InstallKube(scheme.Scheme)
-}
+const usage = `ERROR: you need to specify action: dump or ls [<key>] or get [-rev <revision>] <key> or history [-rev <revision>] <key> or stats [<prefix>] or watch [-rev <revision>] [<prefix>]
+`
 
 func main() {
 	var endpoint, keyFile, certFile, caFile string
@@ -33,21 +29,32 @@ func main() {
 	flag.Parse()
 
 	if flag.NArg() == 0 {
-		fmt.Fprint(os.Stderr, "ERROR: you need to specify action: dump or ls [<key>] or get <key>\n")
+		fmt.Fprint(os.Stderr, usage)
 		os.Exit(1)
 	}
-	if flag.Arg(0) == "get" && flag.NArg() == 1 {
-		fmt.Fprint(os.Stderr, "ERROR: you need to specify <key> for get operation\n")
-		os.Exit(1)
+	action := flag.Arg(0)
+
+	actionFlags := flag.NewFlagSet(action, flag.ExitOnError)
+	var rev int64
+	if action == "get" || action == "history" || action == "watch" {
+		actionFlags.Int64Var(&rev, "rev", 0, "Revision to read at, walk back from, or watch from. Defaults to the current revision.")
 	}
-	if flag.Arg(0) == "dump" && flag.NArg() != 1 {
+	args := parseInterspersed(actionFlags, flag.Args()[1:])
+
+	switch {
+	case (action == "get" || action == "history") && len(args) != 1:
+		fmt.Fprintf(os.Stderr, "ERROR: you need to specify <key> for %s operation\n", action)
+		os.Exit(1)
+	case action == "dump" && len(args) != 0:
 		fmt.Fprint(os.Stderr, "ERROR: you cannot specify positional arguments with dump\n")
 		os.Exit(1)
+	case len(args) > 1:
+		fmt.Fprintf(os.Stderr, "ERROR: you can specify at most one key or prefix with %s\n", action)
+		os.Exit(1)
 	}
-	action := flag.Arg(0)
 	key := ""
-	if flag.NArg() > 1 {
-		key = flag.Arg(1)
+	if len(args) > 0 {
+		key = args[0]
 	}
 
 	var tlsConfig *tls.Config
@@ -77,13 +84,23 @@ func main() {
 	}
 	defer client.Close()
 
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	codec := newCodec()
 	switch action {
 	case "ls":
-		err = listKeys(client, key)
+		err = listKeys(ctx, client, key, os.Stdout)
 	case "get":
-		err = getKey(client, key)
+		err = getKey(ctx, client, codec, key, rev, os.Stdout)
 	case "dump":
-		err = dump(client)
+		err = dump(ctx, client, codec, os.Stdout)
+	case "history":
+		err = history(ctx, client, codec, key, rev, os.Stdout)
+	case "stats":
+		err = stats(ctx, client, key, os.Stdout)
+	case "watch":
+		err = watch(ctx, client, codec, key, rev, os.Stdout)
 	default:
 		fmt.Fprintf(os.Stderr, "ERROR: invalid action: %s\n", action)
 		os.Exit(1)
@@ -94,70 +111,92 @@ func main() {
 	}
 }
 
-func listKeys(client *clientv3.Client, key string) error {
+// parseInterspersed parses flags that may come before or after the positional arguments, so both
+// `get -rev 5 <key>` and `get <key> -rev 5` work.
+func parseInterspersed(flags *flag.FlagSet, args []string) []string {
+	positional := []string{}
+	for {
+		flags.Parse(args)
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func listKeys(ctx context.Context, client *clientv3.Client, key string, out io.Writer) error {
 	var resp *clientv3.GetResponse
 	var err error
 	if len(key) == 0 {
-		resp, err = clientv3.NewKV(client).Get(context.Background(), "/", clientv3.WithFromKey(), clientv3.WithKeysOnly())
+		resp, err = clientv3.NewKV(client).Get(ctx, "/", clientv3.WithFromKey(), clientv3.WithKeysOnly())
 	} else {
-		resp, err = clientv3.NewKV(client).Get(context.Background(), key, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+		resp, err = clientv3.NewKV(client).Get(ctx, key, clientv3.WithPrefix(), clientv3.WithKeysOnly())
 	}
 	if err != nil {
 		return err
 	}
 
 	for _, kv := range resp.Kvs {
-		fmt.Println(string(kv.Key))
+		fmt.Fprintln(out, string(kv.Key))
 	}
 
 	return nil
 }
 
-func getKey(client *clientv3.Client, key string) error {
-	resp, err := clientv3.NewKV(client).Get(context.Background(), key)
+// getKey prints the value of key at rev, or at the current revision when rev is 0.
+func getKey(ctx context.Context, client *clientv3.Client, codec *codec, key string, rev int64, out io.Writer) error {
+	opts := []clientv3.OpOption{}
+	if rev > 0 {
+		opts = append(opts, clientv3.WithRev(rev))
+	}
+	resp, err := clientv3.NewKV(client).Get(ctx, key, opts...)
 	if err != nil {
 		return err
 	}
 
-	decoder := scheme.Codecs.UniversalDeserializer()
-	encoder := jsonserializer.NewSerializer(jsonserializer.DefaultMetaFactory, scheme.Scheme, scheme.Scheme, true)
-
 	for _, kv := range resp.Kvs {
-		obj, gvk, err := decoder.Decode(kv.Value, nil, nil)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "WARN: unable to decode %s: %v\n", kv.Key, err)
-			continue
-		}
-		fmt.Println(gvk)
-		err = encoder.Encode(obj, os.Stdout)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "WARN: unable to encode %s: %v\n", kv.Key, err)
-			continue
-		}
+		printValue(codec, kv.Key, kv.Value, out)
 	}
 
 	return nil
 }
 
-func dump(client *clientv3.Client) error {
-	response, err := clientv3.NewKV(client).Get(context.Background(), "/", clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend))
+// printValue prints the kind of a value followed by the value as JSON.  Values that cannot be decoded are reported
+// and skipped, so one bad value does not hide the rest.
+func printValue(codec *codec, key, value []byte, out io.Writer) {
+	obj, gvk, err := codec.decode(value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARN: unable to decode %s: %v\n", key, err)
+		return
+	}
+	fmt.Fprintln(out, gvk)
+	if err := codec.encode(obj, out, true); err != nil {
+		fmt.Fprintf(os.Stderr, "WARN: unable to encode %s: %v\n", key, err)
+		return
+	}
+	// pretty JSON does not end with a newline, and the next value or revision needs to start on its own line.
+	fmt.Fprintln(out)
+}
+
+func dump(ctx context.Context, client *clientv3.Client, codec *codec, out io.Writer) error {
+	response, err := clientv3.NewKV(client).Get(ctx, "/", clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend))
 	if err != nil {
 		return err
 	}
 
 	kvData := []etcd3kv{}
-	decoder := scheme.Codecs.UniversalDeserializer()
-	encoder := jsonserializer.NewSerializer(jsonserializer.DefaultMetaFactory, scheme.Scheme, scheme.Scheme, false)
 	objJSON := &bytes.Buffer{}
 
 	for _, kv := range response.Kvs {
-		obj, _, err := decoder.Decode(kv.Value, nil, nil)
+		obj, _, err := codec.decode(kv.Value)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "WARN: error decoding value %q: %v\n", string(kv.Value), err)
 			continue
 		}
 		objJSON.Reset()
-		if err := encoder.Encode(obj, objJSON); err != nil {
+		if err := codec.encode(obj, objJSON, false); err != nil {
 			fmt.Fprintf(os.Stderr, "WARN: error encoding object %#v as JSON: %v", obj, err)
 			continue
 		}
@@ -179,7 +218,7 @@ func dump(client *clientv3.Client) error {
 		return err
 	}
 
-	fmt.Println(string(jsonData))
+	fmt.Fprintln(out, string(jsonData))
 
 	return nil
 }
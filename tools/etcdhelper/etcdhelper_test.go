@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubectl/pkg/scheme"
+
+	routev1 "github.com/openshift/api/route/v1"
+)
+
+func freeURL(t *testing.T) url.URL {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return url.URL{Scheme: "http", Host: l.Addr().String()}
+}
+
+// startEtcd starts an embedded single member etcd and returns a client for it.
+func startEtcd(t *testing.T) *clientv3.Client {
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	// the server logs errors when it is closed at the end of every test.
+	cfg.ZapLoggerBuilder = embed.NewZapLoggerBuilder(zap.NewNop())
+	clientURL, peerURL := freeURL(t), freeURL(t)
+	cfg.ListenClientUrls, cfg.AdvertiseClientUrls = []url.URL{clientURL}, []url.URL{clientURL}
+	cfg.ListenPeerUrls, cfg.AdvertisePeerUrls = []url.URL{peerURL}, []url.URL{peerURL}
+	cfg.InitialCluster = fmt.Sprintf("%s=%s", cfg.Name, peerURL.String())
+
+	server, err := embed.StartEtcd(cfg)
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+	select {
+	case <-server.Server.ReadyNotify():
+	case <-time.After(30 * time.Second):
+		t.Fatal("etcd did not become ready")
+	}
+
+	client, err := clientv3.New(clientv3.Config{Endpoints: []string{clientURL.String()}, DialTimeout: 5 * time.Second})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// protobuf encodes obj the way the apiserver stores it.
+func protobuf(t *testing.T, obj runtime.Object) string {
+	info, ok := runtime.SerializerInfoForMediaType(scheme.Codecs.SupportedMediaTypes(), runtime.ContentTypeProtobuf)
+	require.True(t, ok)
+	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+	require.NoError(t, err)
+	encoder := scheme.Codecs.EncoderForVersion(info.Serializer, gvks[0].GroupVersion())
+	buf := &bytes.Buffer{}
+	require.NoError(t, encoder.Encode(obj, buf))
+	return buf.String()
+}
+
+func configMap(data string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "ns"},
+		Data:       map[string]string{"key": data},
+	}
+}
+
+func put(t *testing.T, client *clientv3.Client, key, value string) int64 {
+	resp, err := client.Put(context.Background(), key, value)
+	require.NoError(t, err)
+	return resp.Header.Revision
+}
+
+func Test_getKey(t *testing.T) {
+	client := startEtcd(t)
+	codec := newCodec()
+
+	route := &routev1.Route{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "route.openshift.io/v1", Kind: "Route"},
+		ObjectMeta: metav1.ObjectMeta{Name: "console", Namespace: "openshift-console"},
+		Spec:       routev1.RouteSpec{Host: "console.example.com"},
+	}
+	put(t, client, "/openshift.io/routes/openshift-console/console", protobuf(t, route))
+	put(t, client, "/kubernetes.io/example.com/widgets/ns/widget", `{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"widget","namespace":"ns"},"spec":{"size":3}}`)
+	first := put(t, client, "/kubernetes.io/configmaps/ns/config", protobuf(t, configMap("first")))
+	put(t, client, "/kubernetes.io/configmaps/ns/config", protobuf(t, configMap("second")))
+
+	tests := []struct {
+		name     string
+		key      string
+		rev      int64
+		expected []string
+	}{
+		{
+			name:     "openshift type from protobuf",
+			key:      "/openshift.io/routes/openshift-console/console",
+			expected: []string{"route.openshift.io/v1, Kind=Route", `"host": "console.example.com"`},
+		},
+		{
+			name:     "custom resource from json",
+			key:      "/kubernetes.io/example.com/widgets/ns/widget",
+			expected: []string{"example.com/v1, Kind=Widget", `"size": 3`},
+		},
+		{
+			name:     "current revision",
+			key:      "/kubernetes.io/configmaps/ns/config",
+			expected: []string{"/v1, Kind=ConfigMap", `"key": "second"`},
+		},
+		{
+			name:     "previous revision",
+			key:      "/kubernetes.io/configmaps/ns/config",
+			rev:      first,
+			expected: []string{"/v1, Kind=ConfigMap", `"key": "first"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			require.NoError(t, getKey(context.Background(), client, codec, tt.key, tt.rev, out))
+			for _, expected := range tt.expected {
+				assert.Contains(t, out.String(), expected)
+			}
+		})
+	}
+}
+
+func Test_history(t *testing.T) {
+	client := startEtcd(t)
+	codec := newCodec()
+	key := "/kubernetes.io/configmaps/ns/config"
+
+	put(t, client, key, protobuf(t, configMap("old")))
+	_, err := client.Delete(context.Background(), key)
+	require.NoError(t, err)
+	created := put(t, client, key, protobuf(t, configMap("first")))
+	put(t, client, "/kubernetes.io/configmaps/ns/other", protobuf(t, configMap("other")))
+	put(t, client, key, protobuf(t, configMap("second")))
+	latest := put(t, client, key, protobuf(t, configMap("third")))
+
+	out := &bytes.Buffer{}
+	require.NoError(t, history(context.Background(), client, codec, key, 0, out))
+	revisions := []string{}
+	for _, line := range strings.Split(out.String(), "\n") {
+		if strings.HasPrefix(line, "# revision") {
+			revisions = append(revisions, line)
+		}
+	}
+	assert.Equal(t, []string{
+		fmt.Sprintf("# revision %d (version 3, created at revision %d)", latest, created),
+		fmt.Sprintf("# revision %d (version 2, created at revision %d)", latest-1, created),
+		fmt.Sprintf("# revision %d (version 1, created at revision %d)", created, created),
+	}, revisions)
+	assert.Less(t, strings.Index(out.String(), `"key": "third"`), strings.Index(out.String(), `"key": "first"`), "newest first")
+	assert.NotContains(t, out.String(), `"key": "old"`, "the deleted incarnation is not part of this history")
+
+	_, err = client.Compact(context.Background(), latest-1)
+	require.NoError(t, err)
+	out.Reset()
+	require.NoError(t, history(context.Background(), client, codec, key, 0, out))
+	assert.Contains(t, out.String(), fmt.Sprintf("# revision %d and older were compacted", latest-2))
+
+	err = history(context.Background(), client, codec, "/kubernetes.io/configmaps/ns/missing", 0, out)
+	assert.ErrorContains(t, err, "key does not exist")
+}
+
+func Test_stats(t *testing.T) {
+	client := startEtcd(t)
+
+	put(t, client, "/kubernetes.io/pods/ns/a", "12345")
+	put(t, client, "/kubernetes.io/pods/ns/b", "123")
+	put(t, client, "/kubernetes.io/namespaces/ns", "1")
+	put(t, client, "/kubernetes.io/example.com/widgets/ns/w", "1234567890")
+	put(t, client, "/openshift.io/routes/ns/r", "12")
+	put(t, client, "/other", "1")
+
+	out := &bytes.Buffer{}
+	require.NoError(t, stats(context.Background(), client, "", out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	fields := [][]string{}
+	for _, line := range lines {
+		fields = append(fields, strings.Fields(line))
+	}
+	assert.Equal(t, [][]string{
+		{"PREFIX", "KEYS", "BYTES"},
+		{"/kubernetes.io/example.com/widgets", "1", "10"},
+		{"/kubernetes.io/pods", "2", "8"},
+		{"/openshift.io/routes", "1", "2"},
+		{"/kubernetes.io/namespaces", "1", "1"},
+		{"/other", "1", "1"},
+		{"TOTAL", "6", "22"},
+	}, fields)
+
+	out.Reset()
+	require.NoError(t, stats(context.Background(), client, "/openshift.io/", out))
+	assert.Contains(t, out.String(), "/openshift.io/routes")
+	assert.NotContains(t, out.String(), "/kubernetes.io/pods")
+}
+
+func Test_stats_pages(t *testing.T) {
+	client := startEtcd(t)
+	for i := 0; i < statsPageSize+5; i++ {
+		put(t, client, fmt.Sprintf("/kubernetes.io/secrets/ns/%05d", i), "1")
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, stats(context.Background(), client, "", out))
+	assert.Equal(t, []string{"/kubernetes.io/secrets", fmt.Sprint(statsPageSize + 5), fmt.Sprint(statsPageSize + 5)}, strings.Fields(strings.Split(out.String(), "\n")[1]))
+}
+
+// syncBuffer lets the test read what the watch goroutine writes.
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+func Test_watch(t *testing.T) {
+	client := startEtcd(t)
+	codec := newCodec()
+	start := put(t, client, "/kubernetes.io/configmaps/ns/config", protobuf(t, configMap("before")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := &syncBuffer{}
+	done := make(chan error)
+	go func() {
+		done <- watch(ctx, client, codec, "/kubernetes.io/configmaps/", start+1, out)
+	}()
+
+	put(t, client, "/kubernetes.io/configmaps/ns/config", protobuf(t, configMap("after")))
+	put(t, client, "/kubernetes.io/secrets/ns/ignored", "ignored")
+	deleted, err := client.Delete(context.Background(), "/kubernetes.io/configmaps/ns/config")
+	require.NoError(t, err)
+
+	deleteLine := fmt.Sprintf("DELETE /kubernetes.io/configmaps/ns/config (revision %d)", deleted.Header.Revision)
+	require.Eventually(t, func() bool {
+		return strings.Contains(out.String(), deleteLine)
+	}, 10*time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.Contains(t, out.String(), fmt.Sprintf("PUT /kubernetes.io/configmaps/ns/config (revision %d)", start+1))
+	assert.Contains(t, out.String(), `"key": "after"`)
+	assert.NotContains(t, out.String(), `"key": "before"`)
+	assert.NotContains(t, out.String(), "ignored")
+}
+
+func Test_resourcePrefixOf(t *testing.T) {
+	for key, expected := range map[string]string{
+		"/kubernetes.io/pods/ns/name":                                       "/kubernetes.io/pods",
+		"/kubernetes.io/namespaces/name":                                    "/kubernetes.io/namespaces",
+		"/kubernetes.io/apiextensions.k8s.io/customresourcedefinitions/crd": "/kubernetes.io/apiextensions.k8s.io/customresourcedefinitions",
+		"/kubernetes.io/example.com/widgets/ns/name":                        "/kubernetes.io/example.com/widgets",
+		"/openshift.io/routes/ns/name":                                      "/openshift.io/routes",
+		"/kubernetes.io/masterleases/10.0.0.1":                              "/kubernetes.io/masterleases",
+		"/other":                                                            "/other",
+	} {
+		assert.Equal(t, expected, resourcePrefixOf(key), key)
+	}
+}
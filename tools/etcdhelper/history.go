@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// history prints every revision of key still in etcd, newest first, starting at rev or at the current revision when
+// rev is 0.  It walks back through the previous modifications of the key until the key was created or the older
+// revisions were compacted.  A deleted key has no current value, so start it at a revision from before the delete.
+func history(ctx context.Context, client *clientv3.Client, codec *codec, key string, rev int64, out io.Writer) error {
+	kv := clientv3.NewKV(client)
+	found := false
+	for {
+		opts := []clientv3.OpOption{}
+		if rev > 0 {
+			opts = append(opts, clientv3.WithRev(rev))
+		}
+		resp, err := kv.Get(ctx, key, opts...)
+		if errors.Is(err, rpctypes.ErrCompacted) {
+			fmt.Fprintf(out, "# revision %d and older were compacted\n", rev)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(resp.Kvs) == 0 {
+			if !found {
+				return fmt.Errorf("key does not exist at revision %d", resp.Header.Revision)
+			}
+			return nil
+		}
+		found = true
+
+		current := resp.Kvs[0]
+		fmt.Fprintf(out, "# revision %d (version %d, created at revision %d)\n", current.ModRevision, current.Version, current.CreateRevision)
+		printValue(codec, current.Key, current.Value, out)
+
+		if current.ModRevision == current.CreateRevision {
+			return nil
+		}
+		rev = current.ModRevision - 1
+	}
+}
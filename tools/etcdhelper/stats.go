@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"go.etcd.io/etcd/client/v3"
+)
+
+// statsPageSize is how many keys are read at once, so large clusters do not need a single huge response.
+const statsPageSize = 1000
+
+type prefixStats struct {
+	prefix string
+	keys   int
+	bytes  int64
+}
+
+// stats prints the number of keys and total value size of every resource prefix under prefix, largest first.
+func stats(ctx context.Context, client *clientv3.Client, prefix string, out io.Writer) error {
+	if len(prefix) == 0 {
+		prefix = "/"
+	}
+
+	kv := clientv3.NewKV(client)
+	byPrefix := map[string]*prefixStats{}
+	rangeEnd := clientv3.GetPrefixRangeEnd(prefix)
+	var rev int64
+	for key := prefix; ; {
+		opts := []clientv3.OpOption{clientv3.WithRange(rangeEnd), clientv3.WithLimit(statsPageSize)}
+		// every page reads the same revision as the first, so the totals are consistent.
+		if rev > 0 {
+			opts = append(opts, clientv3.WithRev(rev))
+		}
+		resp, err := kv.Get(ctx, key, opts...)
+		if err != nil {
+			return err
+		}
+		rev = resp.Header.Revision
+
+		for _, kv := range resp.Kvs {
+			resourcePrefix := resourcePrefixOf(string(kv.Key))
+			if _, ok := byPrefix[resourcePrefix]; !ok {
+				byPrefix[resourcePrefix] = &prefixStats{prefix: resourcePrefix}
+			}
+			byPrefix[resourcePrefix].keys++
+			byPrefix[resourcePrefix].bytes += int64(len(kv.Value))
+		}
+		if !resp.More || len(resp.Kvs) == 0 {
+			break
+		}
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+
+	sorted := []*prefixStats{}
+	total := prefixStats{prefix: "TOTAL"}
+	for _, s := range byPrefix {
+		sorted = append(sorted, s)
+		total.keys += s.keys
+		total.bytes += s.bytes
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].bytes != sorted[j].bytes {
+			return sorted[i].bytes > sorted[j].bytes
+		}
+		return sorted[i].prefix < sorted[j].prefix
+	})
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "PREFIX\tKEYS\tBYTES")
+	for _, s := range append(sorted, &total) {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", s.prefix, s.keys, s.bytes)
+	}
+	return w.Flush()
+}
+
+// resourcePrefixOf returns the part of a key that names the resource.  Core resources are stored under
+// /<root>/<resource>/..., resources of other groups may be stored under /<root>/<group>/<resource>/...,
+// and groups always contain a dot.
+func resourcePrefixOf(key string) string {
+	segments := strings.Split(strings.TrimPrefix(key, "/"), "/")
+	length := 2
+	if len(segments) > 2 && strings.Contains(segments[1], ".") {
+		length = 3
+	}
+	// the last segment is the name of the object, not part of the resource.
+	if length >= len(segments) {
+		length = len(segments) - 1
+	}
+	if length < 1 {
+		return key
+	}
+	return "/" + strings.Join(segments[:length], "/")
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.etcd.io/etcd/client/v3"
+)
+
+// watch prints every change to the keys under prefix as it happens, starting after rev or at the current revision
+// when rev is 0, until ctx is done.  Puts are followed by the decoded value.
+func watch(ctx context.Context, client *clientv3.Client, codec *codec, prefix string, rev int64, out io.Writer) error {
+	if len(prefix) == 0 {
+		prefix = "/"
+	}
+	opts := []clientv3.OpOption{clientv3.WithPrefix()}
+	if rev > 0 {
+		opts = append(opts, clientv3.WithRev(rev))
+	}
+
+	for resp := range client.Watch(ctx, prefix, opts...) {
+		if err := resp.Err(); err != nil {
+			return err
+		}
+		for _, event := range resp.Events {
+			fmt.Fprintf(out, "%s %s (revision %d)\n", event.Type, event.Kv.Key, event.Kv.ModRevision)
+			if event.Type == clientv3.EventTypePut {
+				printValue(codec, event.Kv.Key, event.Kv.Value, out)
+			}
+		}
+	}
+	return nil
+}
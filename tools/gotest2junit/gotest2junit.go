@@ -1,35 +1,20 @@
 package main
 
 import (
-	"bufio"
-	"encoding/json"
 	"encoding/xml"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"sort"
-	"strings"
 	"time"
 
-	"github.com/openshift/origin/tools/gotest2junit/pkg/api"
+	"github.com/openshift/origin/tools/junitreport/pkg/api"
+	"github.com/openshift/origin/tools/junitreport/pkg/builder/flat"
+	"github.com/openshift/origin/tools/junitreport/pkg/parser"
+	"github.com/openshift/origin/tools/junitreport/pkg/parser/gotestjson"
 )
 
-type Record struct {
-	Package string
-	Test    string
-
-	Time    time.Time
-	Action  string
-	Output  string
-	Elapsed float64
-}
-
-type testSuite struct {
-	suite *api.TestSuite
-	tests map[string]*api.TestCase
-}
-
 func main() {
 	summarize := false
 	verbose := false
@@ -44,7 +29,13 @@ func main() {
 }
 
 func process(r io.Reader, summarize, verbose bool) error {
-	suites, err := stream(r, summarize, verbose)
+	input := parser.NewScanner(r)
+	testParser := gotestjson.NewParserWithListener(flat.NewTestSuitesBuilder(), &summarizer{
+		summarize: summarize,
+		verbose:   verbose,
+		out:       os.Stderr,
+	})
+	suites, err := testParser.Parse(input)
 	if err != nil {
 		return err
 	}
@@ -57,155 +48,53 @@ func process(r io.Reader, summarize, verbose bool) error {
 	return nil
 }
 
-func newTestSuites(suites map[string]*testSuite) *api.TestSuites {
-	all := &api.TestSuites{}
-	for _, suite := range suites {
-		for _, test := range suite.suite.TestCases {
-			suite.suite.NumTests++
-			if test.SkipMessage != nil {
-				suite.suite.NumSkipped++
-				continue
-			}
-			if test.FailureOutput != nil {
-				suite.suite.NumFailed++
-				continue
-			}
-		}
-		// suites with no tests are usually empty packages, ignore them
-		if suite.suite.NumTests == 0 {
-			continue
+func newTestSuites(all *api.TestSuites) *api.TestSuites {
+	// the output of go test that is not an event is always reported, so the report shows it passed when there was none
+	hasUnparsed := false
+	for _, suite := range all.Suites {
+		if suite.Name == gotestjson.UnparsedSuiteName {
+			hasUnparsed = true
 		}
 		// always return the test cases in consistent order
-		sort.Slice(suite.suite.TestCases, func(i, j int) bool {
-			return suite.suite.TestCases[i].Name < suite.suite.TestCases[j].Name
+		sort.Slice(suite.TestCases, func(i, j int) bool {
+			return suite.TestCases[i].Name < suite.TestCases[j].Name
 		})
-		all.Suites = append(all.Suites, suite.suite)
+	}
+	if !hasUnparsed {
+		suite := &api.TestSuite{Name: gotestjson.UnparsedSuiteName}
+		suite.AddTestCase(&api.TestCase{Name: gotestjson.PackageTestName})
+		all.Suites = append(all.Suites, suite)
 	}
 	// always return the test suites in consistent order
-	sort.Slice(all.Suites, func(i, j int) bool {
-		return all.Suites[i].Name < all.Suites[j].Name
-	})
+	sort.Sort(api.ByName(all.Suites))
 	return all
 }
 
-func stream(r io.Reader, summarize, verbose bool) (map[string]*testSuite, error) {
-	suites := make(map[string]*testSuite)
-	defaultTest := &api.TestCase{
-		Name: "build and execution",
-	}
-	defaultSuite := &testSuite{
-		suite: &api.TestSuite{Name: "go test", TestCases: []*api.TestCase{defaultTest}},
-	}
-	suites[""] = defaultSuite
-
-	rdr := bufio.NewReader(r)
-	for {
-		// some output from go test -json is not valid JSON - read the line to see whether it
-		// starts with { - if not, just mirror it to stderr and continue.
-		line, err := rdr.ReadString('\n')
-		if err != nil {
-			if err != io.EOF {
-				return suites, err
-			}
-			break
-		}
-		if len(line) == 0 || line[0] != '{' {
-			defaultTest.SystemOut += line
-			if strings.HasPrefix(line, "FAIL") {
-				defaultTest.FailureOutput = &api.FailureOutput{}
-			}
-			fmt.Fprint(os.Stderr, line)
-			continue
-		}
-		var r Record
-		if err := json.Unmarshal([]byte(line), &r); err != nil {
-			if err == io.EOF {
-				return suites, nil
-			}
-			fmt.Fprintf(os.Stderr, "error: Unable to parse remainder of output %v\n", err)
-			return suites, nil
-		}
-
-		suite, ok := suites[r.Package]
-		if !ok {
-			suite = &testSuite{
-				suite: &api.TestSuite{
-					Name: r.Package,
-				},
-				tests: make(map[string]*api.TestCase),
-			}
-			suites[r.Package] = suite
-		}
-
-		// if this is package level output, we only care about pass/fail duration
-		if len(r.Test) == 0 {
-			switch r.Action {
-			case "pass", "fail":
-				suite.suite.Duration = r.Elapsed
-			}
-			continue
-		}
-
-		test, ok := suite.tests[r.Test]
-		if !ok {
-			test = &api.TestCase{
-				Name: r.Test,
-			}
-			suite.suite.TestCases = append(suite.suite.TestCases, test)
-			suite.tests[r.Test] = test
-		}
+// summarizer prints a line for every test that failed or was skipped, and for every test that passed when verbose,
+// as the results are parsed. Output that is not an event is mirrored as is.
+type summarizer struct {
+	summarize bool
+	verbose   bool
+	out       io.Writer
+}
 
-		switch r.Action {
-		case "run":
-		case "pause":
-		case "cont":
-		case "bench":
-		case "skip":
-			if summarize {
-				fmt.Fprintf(os.Stderr, "SKIP: %s %s\n", r.Package, r.Test)
-			}
-			test.SkipMessage = &api.SkipMessage{
-				Message: r.Output,
-			}
-		case "pass":
-			if summarize && verbose {
-				fmt.Fprintf(os.Stderr, "PASS: %s %s %s\n", r.Package, r.Test, time.Duration(r.Elapsed*float64(time.Second)))
-			}
-			test.SystemOut = ""
-			test.Duration = r.Elapsed
-		case "fail":
-			if summarize {
-				fmt.Fprintf(os.Stderr, "FAIL: %s %s %s\n", r.Package, r.Test, time.Duration(r.Elapsed*float64(time.Second)))
-			}
-			test.Duration = r.Elapsed
-			if len(r.Output) == 0 {
-				r.Output = test.SystemOut
-				if len(r.Output) > 50 {
-					r.Output = r.Output[:50] + " ..."
-				}
-			}
-			test.FailureOutput = &api.FailureOutput{
-				Message: r.Output,
-				Output:  r.Output,
-			}
-		case "output":
-			test.SystemOut += r.Output
-		default:
-			// usually a bug in go test -json
-			out := fmt.Sprintf("error: Unrecognized go test action %s: %#v\n", r.Action, r)
-			defaultTest.SystemOut += line
-			defaultTest.SystemOut += out
-			defaultTest.FailureOutput = &api.FailureOutput{}
-			fmt.Fprint(os.Stderr, out)
-		}
+func (s *summarizer) TestFinished(suite string, test *api.TestCase) {
+	if !s.summarize {
+		return
 	}
-
-	// if we recorded any failure output
-	if defaultTest.FailureOutput != nil {
-		defaultTest.FailureOutput.Message = "Some packages failed during test execution"
-		defaultTest.FailureOutput.Output = defaultTest.SystemOut
-		defaultTest.SystemOut = ""
+	duration := time.Duration(test.Duration * float64(time.Second))
+	switch {
+	case test.SkipMessage != nil:
+		fmt.Fprintf(s.out, "SKIP: %s %s\n", suite, test.Name)
+	case test.FailureOutput != nil:
+		fmt.Fprintf(s.out, "FAIL: %s %s %s\n", suite, test.Name, duration)
+	case s.verbose:
+		fmt.Fprintf(s.out, "PASS: %s %s %s\n", suite, test.Name, duration)
 	}
+}
+
+func (s *summarizer) SuiteFinished(suite *api.TestSuite) {}
 
-	return suites, nil
+func (s *summarizer) Unparsed(line string) {
+	fmt.Fprintln(s.out, line)
 }
@@ -0,0 +1,64 @@
+package gotestjson
+
+import (
+	"strings"
+	"time"
+)
+
+// Event is a single record of the `go test -json` event stream, as documented by `go doc test2json`.
+type Event struct {
+	Time    time.Time
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+
+	// ImportPath identifies the package being built in build-output and build-fail events, which precede the
+	// events of the test binary and carry no Package.
+	ImportPath string
+	// FailedBuild is set on the failing package event when the test binary could not be built, and matches the
+	// ImportPath of the build events for it.
+	FailedBuild string
+}
+
+// the actions found in a `go test -json` event stream
+const (
+	actionStart       = "start"
+	actionRun         = "run"
+	actionPause       = "pause"
+	actionCont        = "cont"
+	actionPass        = "pass"
+	actionBench       = "bench"
+	actionFail        = "fail"
+	actionOutput      = "output"
+	actionSkip        = "skip"
+	actionBuildOutput = "build-output"
+	actionBuildFail   = "build-fail"
+)
+
+// framePrefixes are the prefixes of the lines `go test -v` writes to mark where a test starts, stops and resumes.
+// They are repeated as events, so they are not kept as output.
+var framePrefixes = []string{"=== RUN", "=== PAUSE", "=== CONT", "=== NAME", "--- PASS", "--- FAIL", "--- SKIP", "--- BENCH"}
+
+// IsFrame determines if a line of output only marks the progress of a test.
+func IsFrame(line string) bool {
+	line = strings.TrimLeft(line, " ")
+	for _, prefix := range framePrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractPanic returns the first line of the panic in the output of a test, including the panic `go test` raises
+// when a test runs past its timeout.
+func ExtractPanic(output []string) (string, bool) {
+	for _, line := range output {
+		if strings.HasPrefix(line, "panic: ") {
+			return line, true
+		}
+	}
+	return "", false
+}
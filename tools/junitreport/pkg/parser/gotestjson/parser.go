@@ -0,0 +1,295 @@
+package gotestjson
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/openshift/origin/tools/junitreport/pkg/api"
+	"github.com/openshift/origin/tools/junitreport/pkg/builder"
+	"github.com/openshift/origin/tools/junitreport/pkg/parser"
+	"github.com/openshift/origin/tools/junitreport/pkg/parser/gotest"
+)
+
+const (
+	// PackageTestName is the name of the test case that records a failure of a package or of `go test` itself
+	// that happened outside of any test
+	PackageTestName = "build and execution"
+
+	// UnparsedSuiteName is the name of the test suite holding the output of `go test` that is not an event
+	UnparsedSuiteName = "go test"
+)
+
+// Listener is told about results as they are parsed, before the test suites are built.
+type Listener interface {
+	// TestFinished is called once the result of a test case is known
+	TestFinished(suite string, test *api.TestCase)
+
+	// SuiteFinished is called once every test case of a test suite is known
+	SuiteFinished(suite *api.TestSuite)
+
+	// Unparsed is called with every line of input that is not an event
+	Unparsed(line string)
+}
+
+// NewParser returns a new parser that's capable of parsing `go test -json` output. If stream is set, a result line
+// is printed for every package as it finishes.
+func NewParser(builder builder.TestSuitesBuilder, stream bool) parser.TestOutputParser {
+	var listener Listener = noopListener{}
+	if stream {
+		listener = &resultPrinter{out: os.Stdout}
+	}
+	return NewParserWithListener(builder, listener)
+}
+
+// NewParserWithListener returns a new parser that's capable of parsing `go test -json` output and that tells the
+// listener about results as they are parsed.
+func NewParserWithListener(builder builder.TestSuitesBuilder, listener Listener) parser.TestOutputParser {
+	return &testOutputParser{
+		builder:  builder,
+		listener: listener,
+	}
+}
+
+type testOutputParser struct {
+	builder  builder.TestSuitesBuilder
+	listener Listener
+}
+
+// packageState collects the events of a package until its result is known. Tests of a package that run in parallel
+// interleave their events, so every test collects its own output until its own result is known.
+type packageState struct {
+	suite  *api.TestSuite
+	tests  map[string]*testState
+	order  []string
+	output []string
+}
+
+func (s *packageState) test(name string) *testState {
+	test, ok := s.tests[name]
+	if !ok {
+		test = &testState{test: &api.TestCase{Name: name}}
+		s.tests[name] = test
+		s.order = append(s.order, name)
+	}
+	return test
+}
+
+type testState struct {
+	test   *api.TestCase
+	output []string
+	done   bool
+}
+
+// finish records the result of the test. A test that panics still reports a failure, so the panic is used as the
+// failure message. Output keeps the indentation `go test` gave it, except in skip messages.
+func (s *testState) finish(action string, elapsed float64) {
+	s.done = true
+	// we round to the millisecond on duration
+	s.test.Duration = float64(int(elapsed*1000)) / 1000
+
+	switch action {
+	case actionSkip:
+		message := make([]string, 0, len(s.output))
+		for _, line := range s.output {
+			message = append(message, strings.TrimLeft(line, " "))
+		}
+		s.test.MarkSkipped(strings.Join(message, "\n"))
+	case actionFail:
+		message, _ := ExtractPanic(s.output)
+		s.test.MarkFailed(message, strings.Join(s.output, "\n"))
+	}
+}
+
+// Parse parses the `go test -json` event stream into test suites, one for every package with tests. Since the
+// events of tests running in parallel interleave, events are collected by package and test until each result is
+// known. A test that never reports a result was running when the test binary exited because of a panic, a timeout
+// or a call to os.Exit, and fails. A package that fails without a failing test, for instance because it did not
+// build, gets a failing test case for the package itself.
+func (p *testOutputParser) Parse(input *bufio.Scanner) (*api.TestSuites, error) {
+	packages := map[string]*packageState{}
+	var packageOrder []string
+	buildOutput := map[string][]string{}
+
+	unparsed := &api.TestCase{Name: PackageTestName}
+	var unparsedOutput []string
+	recordUnparsed := func(line string, failed bool) {
+		unparsedOutput = append(unparsedOutput, line)
+		if failed {
+			unparsed.FailureOutput = &api.FailureOutput{}
+		}
+		p.listener.Unparsed(line)
+	}
+
+	for input.Scan() {
+		line := input.Text()
+
+		// older releases of `go test` write build errors and results that are not events
+		var event Event
+		if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &event) != nil {
+			recordUnparsed(line, strings.HasPrefix(line, "FAIL"))
+			continue
+		}
+
+		switch event.Action {
+		case actionBuildOutput:
+			buildOutput[event.ImportPath] = append(buildOutput[event.ImportPath], strings.TrimSuffix(event.Output, "\n"))
+			continue
+		case actionBuildFail:
+			// the package event that follows has the result
+			continue
+		}
+
+		pkg, ok := packages[event.Package]
+		if !ok {
+			pkg = &packageState{
+				suite: &api.TestSuite{Name: event.Package},
+				tests: map[string]*testState{},
+			}
+			packages[event.Package] = pkg
+			packageOrder = append(packageOrder, event.Package)
+		}
+
+		if len(event.Test) == 0 {
+			switch event.Action {
+			case actionStart:
+			case actionOutput:
+				output := strings.TrimSuffix(event.Output, "\n")
+				pkg.output = append(pkg.output, output)
+				if props, ok := gotest.ExtractProperties(output); ok {
+					for k, v := range props {
+						pkg.suite.AddProperty(k, v)
+					}
+				}
+			case actionPass, actionFail, actionSkip:
+				p.finishPackage(pkg, event, buildOutput[event.FailedBuild])
+				delete(packages, event.Package)
+			default:
+				recordUnparsed(fmt.Sprintf("error: Unrecognized go test action %s: %s", event.Action, line), true)
+			}
+			continue
+		}
+
+		test := pkg.test(event.Test)
+		switch event.Action {
+		case actionRun, actionPause, actionCont, actionBench:
+		case actionOutput:
+			if output := strings.TrimSuffix(event.Output, "\n"); !IsFrame(output) {
+				test.output = append(test.output, output)
+			}
+		case actionPass, actionFail, actionSkip:
+			test.finish(event.Action, event.Elapsed)
+			p.listener.TestFinished(pkg.suite.Name, test.test)
+		default:
+			recordUnparsed(fmt.Sprintf("error: Unrecognized go test action %s: %s", event.Action, line), true)
+		}
+	}
+	if err := input.Err(); err != nil {
+		return nil, fmt.Errorf("error reading test output: %v", err)
+	}
+
+	// a package without a result was cut short, for instance because `go test` was killed
+	for _, name := range packageOrder {
+		if pkg, ok := packages[name]; ok {
+			p.finishPackage(pkg, Event{Action: actionFail, Package: name}, nil)
+		}
+	}
+
+	if len(unparsedOutput) > 0 {
+		output := strings.Join(unparsedOutput, "\n")
+		if unparsed.FailureOutput != nil {
+			unparsed.MarkFailed("Some packages failed during test execution", output)
+		} else {
+			unparsed.SystemOut = output
+		}
+		suite := &api.TestSuite{Name: UnparsedSuiteName}
+		suite.AddTestCase(unparsed)
+		p.builder.AddSuite(suite)
+		p.listener.SuiteFinished(suite)
+	}
+
+	return p.builder.Build(), nil
+}
+
+// finishPackage adds the suite of the package once its result is known. Packages without tests are left out.
+func (p *testOutputParser) finishPackage(pkg *packageState, result Event, buildOutput []string) {
+	// the result lines of the package are not useful as output
+	var packageOutput []string
+	buildFailed := len(result.FailedBuild) > 0
+	for _, line := range pkg.output {
+		if _, _, _, ok := gotest.ExtractPackage(line); ok || line == "PASS" || line == "FAIL" {
+			continue
+		}
+		if strings.HasSuffix(line, "[build failed]") {
+			buildFailed = true
+		}
+		packageOutput = append(packageOutput, line)
+	}
+
+	testFailed := false
+	for _, name := range pkg.order {
+		test := pkg.tests[name]
+		if !test.done {
+			// the test was running when the test binary exited, and older releases of `go test` write the panic
+			// that ended it as output of the package
+			output := append(append([]string{}, test.output...), packageOutput...)
+			message, ok := ExtractPanic(output)
+			if !ok {
+				message = "test did not complete"
+			}
+			test.done = true
+			test.test.MarkFailed(message, strings.Join(output, "\n"))
+			p.listener.TestFinished(pkg.suite.Name, test.test)
+		}
+		if test.test.FailureOutput != nil {
+			testFailed = true
+		}
+		pkg.suite.AddTestCase(test.test)
+	}
+
+	if result.Action == actionFail && !testFailed {
+		message := "package failed outside of any test"
+		output := packageOutput
+		if buildFailed {
+			message = "package failed to build"
+			output = append(append([]string{}, buildOutput...), packageOutput...)
+		}
+		test := &api.TestCase{Name: PackageTestName}
+		test.MarkFailed(message, strings.Join(output, "\n"))
+		p.listener.TestFinished(pkg.suite.Name, test)
+		pkg.suite.AddTestCase(test)
+	}
+
+	if len(pkg.suite.TestCases) == 0 {
+		return
+	}
+	if result.Elapsed > 0 {
+		// tests run in parallel, so the package takes less time than the sum of its tests
+		pkg.suite.Duration = float64(int(result.Elapsed*1000)) / 1000
+	}
+	p.builder.AddSuite(pkg.suite)
+	p.listener.SuiteFinished(pkg.suite)
+}
+
+type noopListener struct{}
+
+func (noopListener) TestFinished(string, *api.TestCase) {}
+func (noopListener) SuiteFinished(*api.TestSuite)       {}
+func (noopListener) Unparsed(string)                    {}
+
+// resultPrinter prints a line with the result of every package, like `go test` does.
+type resultPrinter struct {
+	noopListener
+	out io.Writer
+}
+
+func (p *resultPrinter) SuiteFinished(suite *api.TestSuite) {
+	result := "ok  "
+	if suite.NumFailed > 0 {
+		result = "FAIL"
+	}
+	fmt.Fprintf(p.out, "%s\t%s\t%.3fs\n", result, suite.Name, suite.Duration)
+}
@@ -0,0 +1,308 @@
+package gotestjson
+
+import (
+	"bufio"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/openshift/origin/tools/junitreport/pkg/api"
+	"github.com/openshift/origin/tools/junitreport/pkg/builder/flat"
+)
+
+// TestFlatParse tests that parsing the `go test -json` output in the test directory with a flat builder works as expected
+func TestFlatParse(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		testFile       string
+		expectedSuites *api.TestSuites
+	}{
+		{
+			name:     "basic with coverage and a package without tests",
+			testFile: "1.txt",
+			expectedSuites: &api.TestSuites{
+				Suites: []*api.TestSuite{
+					{
+						Name:     "github.com/openshift/example/ok",
+						NumTests: 2,
+						Duration: 0.012,
+						Properties: []*api.TestSuiteProperty{
+							{
+								Name:  "coverage.statements.pct",
+								Value: "66.7",
+							},
+						},
+						TestCases: []*api.TestCase{
+							{
+								Name:     "TestOne",
+								Duration: 0.004,
+							},
+							{
+								Name:     "TestTwo",
+								Duration: 0.006,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "interleaved parallel subtests",
+			testFile: "2.txt",
+			expectedSuites: &api.TestSuites{
+				Suites: []*api.TestSuite{
+					{
+						Name:       "github.com/openshift/example/par",
+						NumTests:   4,
+						NumFailed:  2,
+						NumSkipped: 1,
+						Duration:   0.071,
+						TestCases: []*api.TestCase{
+							{
+								Name:          "TestPar",
+								FailureOutput: &api.FailureOutput{},
+							},
+							{
+								Name:     "TestPar/a",
+								Duration: 0.06,
+								FailureOutput: &api.FailureOutput{
+									Output: "    par_test.go:8: start a\n    par_test.go:10: broken a\n        second line\n    par_test.go:12: end a",
+								},
+							},
+							{
+								Name:     "TestPar/b",
+								Duration: 0.02,
+							},
+							{
+								Name:        "TestSkip",
+								SkipMessage: &api.SkipMessage{Message: "par_test.go:16: not today"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "panic",
+			testFile: "3.txt",
+			expectedSuites: &api.TestSuites{
+				Suites: []*api.TestSuite{
+					{
+						Name:      "github.com/openshift/example/pan",
+						NumTests:  3,
+						NumFailed: 2,
+						Duration:  0.005,
+						TestCases: []*api.TestCase{
+							{
+								Name: "TestOK",
+							},
+							{
+								Name: "TestPanic",
+								FailureOutput: &api.FailureOutput{
+									Message: "panic: here [recovered, repanicked]",
+									Output:  "panic: here [recovered, repanicked]\n\ngoroutine 8 [running]:\ngithub.com/openshift/example/pan.TestPanic.func1(0x139f7acc6c8?)\n\t/go/src/github.com/openshift/example/pan/pan_test.go:4 +0x25",
+								},
+							},
+							{
+								Name:          "TestPanic/sub",
+								FailureOutput: &api.FailureOutput{},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "timeout",
+			testFile: "4.txt",
+			expectedSuites: &api.TestSuites{
+				Suites: []*api.TestSuite{
+					{
+						Name:      "github.com/openshift/example/tmo",
+						NumTests:  1,
+						NumFailed: 1,
+						Duration:  1.006,
+						TestCases: []*api.TestCase{
+							{
+								Name: "TestSlow",
+								FailureOutput: &api.FailureOutput{
+									Message: "panic: test timed out after 1s",
+									Output:  "    tmo_test.go:3: waiting\npanic: test timed out after 1s\n\trunning tests:\n\t\tTestSlow (1s)\ngoroutine 6 [sleep]:\ngithub.com/openshift/example/tmo.TestSlow(0x295787a04248?)\n\t/go/src/github.com/openshift/example/tmo/tmo_test.go:3 +0x48",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "build failure",
+			testFile: "5.txt",
+			expectedSuites: &api.TestSuites{
+				Suites: []*api.TestSuite{
+					{
+						Name:      "github.com/openshift/example/bld",
+						NumTests:  1,
+						NumFailed: 1,
+						TestCases: []*api.TestCase{
+							{
+								Name: "build and execution",
+								FailureOutput: &api.FailureOutput{
+									Message: "package failed to build",
+									Output:  "# github.com/openshift/example/bld [github.com/openshift/example/bld.test]\nbld/bld_test.go:3:28: undefined: undefined\nFAIL\tgithub.com/openshift/example/bld [build failed]",
+								},
+							},
+						},
+					},
+					{
+						Name:     "github.com/openshift/example/ok",
+						NumTests: 2,
+						Duration: 0.012,
+						Properties: []*api.TestSuiteProperty{
+							{
+								Name:  "coverage.statements.pct",
+								Value: "66.7",
+							},
+						},
+						TestCases: []*api.TestCase{
+							{
+								Name:     "TestOne",
+								Duration: 0.004,
+							},
+							{
+								Name:     "TestTwo",
+								Duration: 0.006,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "package failure outside of any test",
+			testFile: "6.txt",
+			expectedSuites: &api.TestSuites{
+				Suites: []*api.TestSuite{
+					{
+						Name:      "github.com/openshift/example/mainexit",
+						NumTests:  2,
+						NumFailed: 1,
+						Duration:  0.004,
+						TestCases: []*api.TestCase{
+							{
+								Name: "TestFine",
+							},
+							{
+								Name: "build and execution",
+								FailureOutput: &api.FailureOutput{
+									Message: "package failed outside of any test",
+									Output:  "coverage: [no statements]\nleaked goroutines",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "output that is not an event",
+			testFile: "7.txt",
+			expectedSuites: &api.TestSuites{
+				Suites: []*api.TestSuite{
+					{
+						Name:      "github.com/openshift/example/old",
+						NumTests:  1,
+						NumFailed: 1,
+						Duration:  0.013,
+						TestCases: []*api.TestCase{
+							{
+								Name:     "TestOld",
+								Duration: 0.01,
+								FailureOutput: &api.FailureOutput{
+									Output: "    old_test.go:5: failed",
+								},
+							},
+						},
+					},
+					{
+						Name:      "go test",
+						NumTests:  1,
+						NumFailed: 1,
+						TestCases: []*api.TestCase{
+							{
+								Name: "build and execution",
+								FailureOutput: &api.FailureOutput{
+									Message: "Some packages failed during test execution",
+									Output:  "# github.com/openshift/example/bld\nbld/bld_test.go:3:28: undefined: undefined\nFAIL\tgithub.com/openshift/example/bld [build failed]",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "tests without a result",
+			testFile: "8.txt",
+			expectedSuites: &api.TestSuites{
+				Suites: []*api.TestSuite{
+					{
+						Name:      "github.com/openshift/example/panics",
+						NumTests:  1,
+						NumFailed: 1,
+						Duration:  0.008,
+						TestCases: []*api.TestCase{
+							{
+								Name: "TestPanics",
+								FailureOutput: &api.FailureOutput{
+									Message: "panic: boom",
+									Output:  "    panics_test.go:6: about to panic\npanic: boom\n\ngoroutine 7 [running]:\ngithub.com/openshift/example/panics.TestPanics(0xc000082600)\n\t/go/src/github.com/openshift/example/panics/panics_test.go:7 +0x39",
+								},
+							},
+						},
+					},
+					{
+						Name:      "github.com/openshift/example/hangs",
+						NumTests:  2,
+						NumFailed: 1,
+						Duration:  0.2,
+						TestCases: []*api.TestCase{
+							{
+								Name:     "TestDone",
+								Duration: 0.2,
+							},
+							{
+								Name: "TestHangs",
+								FailureOutput: &api.FailureOutput{
+									Message: "test did not complete",
+									Output:  "    hangs_test.go:9: waiting forever",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			parser := NewParser(flat.NewTestSuitesBuilder(), false)
+
+			testFile := "./../../../test/gotestjson/testdata/" + testCase.testFile
+
+			reader, err := os.Open(testFile)
+			if err != nil {
+				t.Fatalf("unexpected error opening file %q: %v", testFile, err)
+			}
+			testSuites, err := parser.Parse(bufio.NewScanner(reader))
+			if err != nil {
+				t.Fatalf("unexpected error parsing file: %v", err)
+			}
+
+			if !reflect.DeepEqual(testSuites, testCase.expectedSuites) {
+				t.Errorf("did not produce the correct test suites from file:\n%#v\n%#v", testCase.expectedSuites, testSuites)
+			}
+		})
+	}
+}
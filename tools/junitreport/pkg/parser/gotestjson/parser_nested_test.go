@@ -0,0 +1,123 @@
+package gotestjson
+
+import (
+	"bufio"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/openshift/origin/tools/junitreport/pkg/api"
+	"github.com/openshift/origin/tools/junitreport/pkg/builder/nested"
+)
+
+// TestNestedParse tests that parsing the `go test -json` output in the test directory with a nested builder works as expected
+func TestNestedParse(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		testFile       string
+		rootSuiteNames []string
+		expectedSuites *api.TestSuites
+	}{
+		{
+			name:           "build failure",
+			testFile:       "5.txt",
+			rootSuiteNames: []string{"github.com/openshift/example"},
+			expectedSuites: &api.TestSuites{
+				Suites: []*api.TestSuite{
+					{
+						Name:      "github.com/openshift/example",
+						NumTests:  3,
+						NumFailed: 1,
+						Duration:  0.012,
+						Children: []*api.TestSuite{
+							{
+								Name:      "github.com/openshift/example/bld",
+								NumTests:  1,
+								NumFailed: 1,
+								TestCases: []*api.TestCase{
+									{
+										Name: "build and execution",
+										FailureOutput: &api.FailureOutput{
+											Message: "package failed to build",
+											Output:  "# github.com/openshift/example/bld [github.com/openshift/example/bld.test]\nbld/bld_test.go:3:28: undefined: undefined\nFAIL\tgithub.com/openshift/example/bld [build failed]",
+										},
+									},
+								},
+							},
+							{
+								Name:     "github.com/openshift/example/ok",
+								NumTests: 2,
+								Duration: 0.012,
+								Properties: []*api.TestSuiteProperty{
+									{
+										Name:  "coverage.statements.pct",
+										Value: "66.7",
+									},
+								},
+								TestCases: []*api.TestCase{
+									{
+										Name:     "TestOne",
+										Duration: 0.004,
+									},
+									{
+										Name:     "TestTwo",
+										Duration: 0.006,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:           "tests without a result with restricted root",
+			testFile:       "8.txt",
+			rootSuiteNames: []string{"github.com/openshift/example/hangs"},
+			expectedSuites: &api.TestSuites{
+				Suites: []*api.TestSuite{
+					{
+						Name:      "github.com/openshift/example/hangs",
+						NumTests:  2,
+						NumFailed: 1,
+						Duration:  0.2,
+						TestCases: []*api.TestCase{
+							{
+								Name:     "TestDone",
+								Duration: 0.2,
+							},
+							{
+								Name: "TestHangs",
+								FailureOutput: &api.FailureOutput{
+									Message: "test did not complete",
+									Output:  "    hangs_test.go:9: waiting forever",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			parser := NewParser(nested.NewTestSuitesBuilder(testCase.rootSuiteNames), false)
+
+			testFile := "./../../../test/gotestjson/testdata/" + testCase.testFile
+
+			reader, err := os.Open(testFile)
+			if err != nil {
+				t.Fatalf("unexpected error opening file %q: %v", testFile, err)
+			}
+			testSuites, err := parser.Parse(bufio.NewScanner(reader))
+			if err != nil {
+				t.Fatalf("unexpected error parsing file: %v", err)
+			}
+
+			if !reflect.DeepEqual(testSuites, testCase.expectedSuites) {
+				t.Errorf("did not produce the correct test suites from file:\n%#v\n%#v", testCase.expectedSuites, testSuites)
+			}
+		})
+	}
+}
@@ -2,10 +2,22 @@ package parser
 
 import (
 	"bufio"
+	"io"
 
 	"github.com/openshift/origin/tools/junitreport/pkg/api"
 )
 
+// MaxLineLength is the longest line of test output that is read.  `go test -json` puts everything a test writes
+// without a newline into a single event, which easily exceeds the 64KiB bufio.Scanner allows by default.
+const MaxLineLength = 64 * 1024 * 1024
+
+// NewScanner returns a scanner over the lines of r that allows lines up to MaxLineLength.
+func NewScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), MaxLineLength)
+	return scanner
+}
+
 // TestOutputParser knows how to parse test output to create a collection of test suites
 type TestOutputParser interface {
 	Parse(input *bufio.Scanner) (*api.TestSuites, error)